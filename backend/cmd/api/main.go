@@ -1,8 +1,13 @@
 package main
 
 import (
+	"database/sql"
+	"io/fs"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -12,14 +17,20 @@ import (
 	"github.com/yourusername/v-backend/internal/api"
 	"github.com/yourusername/v-backend/internal/auth"
 	"github.com/yourusername/v-backend/internal/config"
+	"github.com/yourusername/v-backend/internal/mail"
+	"github.com/yourusername/v-backend/internal/migrate"
 	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/oidc"
 	"github.com/yourusername/v-backend/internal/repository"
 	"github.com/yourusername/v-backend/internal/repository/memory"
+	"github.com/yourusername/v-backend/internal/repository/postgres"
+	"github.com/yourusername/v-backend/internal/repository/sqlite"
 	"github.com/yourusername/v-backend/internal/service"
 )
 
 type Server struct {
 	router *chi.Mux
+	store  *memory.Store // nil unless running on in-memory repositories
 }
 
 // initializeDefaultUsers creates default users for development/demo purposes
@@ -157,6 +168,124 @@ func stringPtr(s string) *string {
 	return &s
 }
 
+// newRepositories picks the storage backend based on configuration
+// For in-memory storage it also returns the underlying store, restored from
+// the last snapshot when SNAPSHOT_PATH is set.
+func newRepositories(cfg *config.Config) (*repository.Repositories, *memory.Store) {
+	switch cfg.StorageDriver {
+	case "memory":
+		store := memory.NewStore()
+		if cfg.SnapshotPath == "" {
+			log.Println("💾 Storage: in-memory (data is lost on restart)")
+			return store.Repositories(), store
+		}
+
+		snap, err := store.LoadSnapshot(cfg.SnapshotPath)
+		if err != nil {
+			log.Fatal("❌ Failed to restore snapshot:", err)
+		}
+		if snap != nil {
+			log.Printf("💾 Restored snapshot from %s (taken %s)", cfg.SnapshotPath, snap.CreatedAt.Format(time.RFC3339))
+		}
+		go store.SnapshotEvery(cfg.SnapshotPath, cfg.SnapshotInterval)
+		log.Printf("💾 Storage: in-memory, snapshotted to %s every %s", cfg.SnapshotPath, cfg.SnapshotInterval)
+		return store.Repositories(), store
+
+	case "postgres":
+		if cfg.DatabaseURL == "" {
+			log.Fatal("❌ STORAGE_DRIVER=postgres requires DATABASE_URL")
+		}
+		db, err := postgres.Open(cfg.DatabaseURL)
+		if err != nil {
+			log.Fatal("❌ Failed to connect to database:", err)
+		}
+		ensureSchema(db, postgres.Migrations, cfg.AutoMigrate)
+		log.Println("💾 Storage: PostgreSQL")
+		return postgres.NewRepositories(db), nil
+
+	case "sqlite":
+		db, err := sqlite.Open(cfg.SQLitePath)
+		if err != nil {
+			log.Fatal("❌ Failed to open database:", err)
+		}
+		ensureSchema(db, sqlite.Migrations, cfg.AutoMigrate)
+		log.Println("💾 Storage: SQLite (" + cfg.SQLitePath + ")")
+		return sqlite.NewRepositories(db), nil
+
+	default:
+		log.Fatalf("❌ Unknown STORAGE_DRIVER %q (expected memory, postgres or sqlite)", cfg.StorageDriver)
+		return nil, nil
+	}
+}
+
+// ensureSchema refuses to start against a database with pending migrations
+// unless autoMigrate is set, in which case it applies them first
+func ensureSchema(db *sql.DB, migrations fs.FS, autoMigrate bool) {
+	migrator, err := migrate.New(db, migrations)
+	if err != nil {
+		log.Fatal("❌ Failed to load migrations:", err)
+	}
+
+	pending, err := migrator.Pending()
+	if err != nil {
+		log.Fatal("❌ Failed to check schema version:", err)
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	if !autoMigrate {
+		log.Fatalf("❌ Database schema is behind by %d migration(s) (next: %04d_%s). Run `go run ./cmd/migrate up` or set AUTO_MIGRATE=true",
+			len(pending), pending[0].Version, pending[0].Name)
+	}
+
+	applied, err := migrator.Up()
+	for _, m := range applied {
+		log.Printf("✅ Applied migration %04d_%s", m.Version, m.Name)
+	}
+	if err != nil {
+		log.Fatal("❌ Migration failed:", err)
+	}
+}
+
+// newMailer sends through SMTP when it is configured, and otherwise writes
+// mail to MAIL_DIR or the log so links can be followed in development
+func newMailer(cfg *config.Config) mail.Mailer {
+	switch {
+	case cfg.SMTPHost != "":
+		log.Printf("📧 Mail: SMTP via %s:%d", cfg.SMTPHost, cfg.SMTPPort)
+		return mail.NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.MailFrom)
+	case cfg.MailDir != "":
+		log.Printf("📧 Mail: written to %s", cfg.MailDir)
+		return mail.NewFileMailer(cfg.MailDir, cfg.MailFrom)
+	default:
+		log.Println("📧 Mail: printed to the log (set SMTP_HOST to send it)")
+		return mail.LogMailer{}
+	}
+}
+
+// newOIDCProviders sets up the configured sign-in providers, skipping ones
+// that are missing settings
+func newOIDCProviders(cfg *config.Config) []*oidc.Provider {
+	var providers []*oidc.Provider
+	for _, p := range cfg.OIDCProviders {
+		if p.Issuer == "" || p.ClientID == "" {
+			log.Printf("⚠️  Sign-in provider %s needs an issuer and client ID, skipping it", p.Name)
+			continue
+		}
+		providers = append(providers, oidc.NewProvider(oidc.Config{
+			Name:         p.Name,
+			Issuer:       p.Issuer,
+			ClientID:     p.ClientID,
+			ClientSecret: p.ClientSecret,
+			RedirectURL:  p.RedirectURL,
+			Scopes:       p.Scopes,
+		}, nil))
+		log.Printf("🔑 Sign in with %s (%s)", p.Name, p.Issuer)
+	}
+	return providers
+}
+
 func main() {
 	// Load .env file if it exists (ignore error if file doesn't exist)
 	if err := godotenv.Load(); err != nil {
@@ -167,10 +296,29 @@ func main() {
 	cfg := config.LoadConfig()
 
 	// Set JWT secret
+	if cfg.Environment == "production" && cfg.JWTSecret == config.DefaultJWTSecret {
+		log.Fatal("❌ JWT_SECRET must be set in production; the default is public")
+	}
 	auth.SetJWTSecret(cfg.JWTSecret)
+	auth.SetAccessTokenTTL(cfg.AccessTokenTTL)
 
 	server := NewServer(cfg)
 
+	// Take a final snapshot on shutdown so a deploy doesn't lose recent writes
+	if server.store != nil && cfg.SnapshotPath != "" {
+		go func() {
+			stop := make(chan os.Signal, 1)
+			signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+			<-stop
+			if _, err := server.store.SaveSnapshot(cfg.SnapshotPath); err != nil {
+				log.Println("❌ Failed to save snapshot on shutdown:", err)
+				os.Exit(1)
+			}
+			log.Println("💾 Saved snapshot to " + cfg.SnapshotPath)
+			os.Exit(0)
+		}()
+	}
+
 	log.Println("🚀 Server starting on :" + cfg.Port)
 	log.Println("🌍 Environment:", cfg.Environment)
 	log.Println("📍 API Documentation: http://localhost:" + cfg.Port + "/api")
@@ -183,9 +331,14 @@ func main() {
 func NewServer(cfg *config.Config) *Server {
 	r := chi.NewRouter()
 
+	proxies, err := api.ParseTrustedProxies(cfg.TrustedProxies)
+	if err != nil {
+		log.Fatal("❌ Invalid TRUSTED_PROXIES:", err)
+	}
+
 	// Middleware
 	r.Use(middleware.RequestID)
-	r.Use(middleware.RealIP)
+	r.Use(api.RealIP(proxies))
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
 	r.Use(middleware.Timeout(60 * time.Second))
@@ -194,22 +347,23 @@ func NewServer(cfg *config.Config) *Server {
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   cfg.CORSOrigins,
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "Cache-Control", "Pragma", "Expires"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "Cache-Control", "Pragma", "Expires", "If-Match"},
+		ExposedHeaders:   []string{"Link", "ETag"},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
 
-	// Initialize repositories (in-memory for now, easy to swap with Postgres later)
-	authRepo := memory.NewAuthMemoryRepository()
-	userRepo := memory.NewUserMemoryRepository()
-	postRepo := memory.NewPostMemoryRepository()
-	messageRepo := memory.NewMessageMemoryRepository()
-	hashtagRepo := memory.NewHashtagMemoryRepository(postRepo)
-	debateRepo := memory.NewDebateMemoryRepository()
-	debateStatsRepo := memory.NewDebateStatsMemoryRepository()
-	notifRepo := memory.NewNotificationMemoryRepository()
-	analyticsRepo := memory.NewAnalyticsMemoryRepository(postRepo)
+	// Initialize repositories (Postgres when DATABASE_URL is set, in-memory otherwise)
+	repos, store := newRepositories(cfg)
+	authRepo := repos.Auth
+	userRepo := repos.User
+	postRepo := repos.Post
+	messageRepo := repos.Message
+	hashtagRepo := repos.Hashtag
+	debateRepo := repos.Debate
+	debateStatsRepo := repos.DebateStats
+	notifRepo := repos.Notification
+	analyticsRepo := repos.Analytics
 
 	// Initialize default users (for development/demo)
 	// initializeDefaultUsers(userRepo)
@@ -221,22 +375,63 @@ func NewServer(cfg *config.Config) *Server {
 	pointsService := service.NewPointsService(userRepo)
 	moderationService := service.NewModerationService(postRepo, userRepo, pointsService)
 	translationService := service.NewTranslationService(cfg.LibreTranslateURL, cfg.LibreTranslateAPIKey)
+	sessionService := service.NewSessionService(repos.Session, userRepo, cfg.RefreshTokenTTL)
+	apiKeyService := service.NewAPIKeyService(repos.APIKey, userRepo)
+	authMiddleware := api.NewAuthMiddleware(sessionService, apiKeyService)
+	signingKeyService := service.NewSigningKeyService(repos.SigningKey, cfg.JWTAlgorithm, cfg.JWTKeyRotation)
+	if err := signingKeyService.Rotate(); err != nil {
+		log.Fatal("❌ Failed to load signing keys:", err)
+	}
+	go signingKeyService.Run(time.Minute)
+	impersonationService := service.NewImpersonationService(cfg.Environment, cfg.DevImpersonation, userRepo, sessionService)
+	if impersonationService.Enabled() {
+		log.Println("⚠️  DEV IMPERSONATION ENABLED: anyone can sign in as any user at POST /api/dev/impersonate")
+		initializeDefaultUsers(userRepo)
+	} else if cfg.DevImpersonation {
+		log.Println("⚠️  DEV_IMPERSONATION is ignored outside development")
+	}
+	roleService := service.NewRoleService(userRepo, repos.Session)
+	roleService.BootstrapAdmins(cfg.AdminEmails)
+	accountService := service.NewAccountService(authRepo, userRepo, repos.EmailToken, repos.Session, newMailer(cfg), cfg.FrontendURL)
+	twoFactorService := service.NewTwoFactorService(repos.TwoFactor, cfg.TOTPIssuer)
+	loginGuard := service.NewLoginGuard(repos.LoginThrottle, authRepo, notifRepo, cfg.LoginMaxFailures, cfg.LoginIPMaxFailures, cfg.LoginLockout)
+	go loginGuard.Run(cfg.PurgeInterval)
+	oidcService := service.NewOIDCService(newOIDCProviders(cfg), repos.Identity, authRepo, userRepo)
 
 	// Initialize WebSocket Hub
-	hub := service.NewHub()
+	roomAccess := service.NewDebateRoomAccess(debateRepo, userRepo, repos.Community)
+	hub := service.NewHub(roomAccess)
 	go hub.Run()
+	wsHandler := api.NewWebSocketHandler(hub, authMiddleware, cfg.CORSOrigins)
 
 	// Initialize Community components
-	communityRepo := memory.NewCommunityMemoryRepository()
-	communityHandlers := api.NewCommunityHandlers(communityRepo, userRepo, pointsService, notifRepo)
+	communityRepo := repos.Community
+	communityHandlers := api.NewCommunityHandlers(communityRepo, userRepo, pointsService, notifRepo, repos.UnitOfWork, cfg.RestoreWindow)
+
+	// Hard-delete soft-deleted content once it is past retention
+	purger := service.NewPurger(postRepo, communityRepo, cfg.DeletedRetention)
+	go purger.Run(cfg.PurgeInterval)
+	log.Printf("🗑️  Deleted content restorable for %s, purged after %s", cfg.RestoreWindow, cfg.DeletedRetention)
 
 	// Initialize handlers
-	authHandlers := api.NewAuthHandlers(authRepo, userRepo, pointsService, notifRepo)
+	authHandlers := api.NewAuthHandlers(authRepo, userRepo, pointsService, notifRepo, sessionService, accountService, twoFactorService, loginGuard, oidcService)
 	userHandlers := api.NewUserHandlers(userRepo)
-	postHandlers := api.NewPostHandlers(postRepo, userRepo, notifRepo, analyticsRepo, pointsService, moderationService, translationService, hashtagRepo, communityRepo)
+	postHandlers := api.NewPostHandlers(postRepo, userRepo, notifRepo, analyticsRepo, pointsService, moderationService, translationService, hashtagRepo, communityRepo, repos.UnitOfWork, cfg.RestoreWindow)
 	messageHandlers := api.NewMessageHandlers(messageRepo)
 	hashtagHandlers := api.NewHashtagHandlers(hashtagRepo, userRepo, postRepo, hub)
-	debateHandlers := api.NewDebateHandlers(debateRepo, userRepo, pointsService, hub)
+	// Start and end debates on time, run their rounds and polls and end
+	// speakers' turns, picking up where the last run left off
+	debatePoll := service.NewDebatePollService(debateRepo, userRepo, roomAccess, pointsService)
+	debateScheduler := service.NewDebateScheduler(debateRepo, debateStatsRepo, debatePoll, hub)
+	speakerQueue := service.NewSpeakerQueueService(debateRepo, hub)
+	debateScheduler.SetSpeakerQueue(speakerQueue)
+	debateHandlers := api.NewDebateHandlers(debateRepo, userRepo, pointsService, hub, debateScheduler, speakerQueue, debatePoll, roomAccess)
+	if err := debateScheduler.Start(); err != nil {
+		log.Fatal("❌ Failed to schedule debates:", err)
+	}
+	if err := speakerQueue.Start(); err != nil {
+		log.Fatal("❌ Failed to schedule speaker turns:", err)
+	}
 	debateStatsHandlers := api.NewDebateStatsHandlers(debateStatsRepo)
 
 	// Register debate WebSocket message handlers
@@ -256,7 +451,10 @@ func NewServer(cfg *config.Config) *Server {
 	notifHandlers := api.NewNotificationHandlers(notifRepo, userRepo)
 	analyticsHandlers := api.NewAnalyticsHandlers(analyticsRepo)
 	moderationHandlers := api.NewModerationHandlers(moderationService)
-	livekitHandlers := api.NewLiveKitHandlers()
+	livekitHandlers := api.NewLiveKitHandlers(debateRepo, roomAccess)
+	adminHandlers := api.NewAdminHandlers(store, cfg.SnapshotPath, roleService, loginGuard)
+	devHandlers := api.NewDevHandlers(impersonationService)
+	apiKeyHandlers := api.NewAPIKeyHandlers(apiKeyService)
 
 	// Root route - API information
 	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
@@ -282,6 +480,9 @@ func NewServer(cfg *config.Config) *Server {
 		})
 	})
 
+	// Public keys for checking access tokens, for other services
+	r.Get("/.well-known/jwks.json", api.JWKS)
+
 	// API routes
 	r.Route("/api", func(r chi.Router) {
 		// Health check
@@ -296,61 +497,119 @@ func NewServer(cfg *config.Config) *Server {
 		// Auth routes (public)
 		r.Post("/auth/signup", authHandlers.Signup)
 		r.Post("/auth/login", authHandlers.Login)
+		r.Post("/auth/refresh", authHandlers.Refresh)
+		r.Post("/auth/verify-email", authHandlers.VerifyEmail)
+		r.Post("/auth/forgot-password", authHandlers.ForgotPassword)
+		r.Post("/auth/reset-password", authHandlers.ResetPassword)
+		r.Post("/auth/2fa/verify", authHandlers.VerifyTwoFactorLogin)
+		r.Get("/auth/oidc/providers", authHandlers.ListOIDCProviders)
+		r.Get("/auth/oidc/{provider}/authorize", authHandlers.BeginOIDC)
+		r.Post("/auth/oidc/{provider}/callback", authHandlers.OIDCCallback)
+		r.Post("/auth/oidc/signup", authHandlers.OIDCSignup)
 
 		// Protected auth routes
 		r.Group(func(r chi.Router) {
-			r.Use(api.RequireAuth)
+			r.Use(authMiddleware.RequireAuth)
 			r.Get("/auth/me", authHandlers.GetCurrentUser)
 			r.Post("/auth/change-password", authHandlers.ChangePassword)
+			r.Post("/auth/verify-email/resend", authHandlers.ResendVerification)
+			r.Post("/auth/logout", authHandlers.Logout)
+			r.Get("/auth/sessions", authHandlers.ListSessions)
+			r.Delete("/auth/sessions", authHandlers.RevokeOtherSessions)
+			r.Delete("/auth/sessions/{id}", authHandlers.RevokeSession)
+			r.Get("/auth/2fa", authHandlers.TwoFactorStatus)
+			r.Post("/auth/2fa/setup", authHandlers.SetupTwoFactor)
+			r.Post("/auth/2fa/enable", authHandlers.EnableTwoFactor)
+			r.Post("/auth/2fa/disable", authHandlers.DisableTwoFactor)
+			r.Post("/auth/2fa/recovery-codes", authHandlers.RegenerateRecoveryCodes)
+			r.Get("/auth/identities", authHandlers.ListIdentities)
+			r.Post("/auth/oidc/{provider}/link", authHandlers.LinkOIDC)
+			r.Delete("/auth/identities/{provider}", authHandlers.UnlinkOIDC)
+			r.Get("/auth/api-keys", apiKeyHandlers.List)
+			r.Post("/auth/api-keys", apiKeyHandlers.Create)
+			r.Delete("/auth/api-keys/{keyId}", apiKeyHandlers.Revoke)
+		})
+
+		// Bot routes (protected; bots act through their API keys)
+		r.Route("/bots", func(r chi.Router) {
+			r.Use(authMiddleware.RequireAuth)
+			r.Get("/", apiKeyHandlers.ListBots)
+			r.Post("/", apiKeyHandlers.CreateBot)
+			r.Get("/{id}/api-keys", apiKeyHandlers.List)
+			r.Post("/{id}/api-keys", apiKeyHandlers.Create)
+			r.Delete("/{id}/api-keys/{keyId}", apiKeyHandlers.Revoke)
 		})
 
 		// User routes
 		r.Route("/users", func(r chi.Router) {
+			r.Use(api.APIKeyScopes(models.ScopeReadUsers, ""))
+
 			r.Get("/", userHandlers.List)
-			r.Post("/", userHandlers.Create)
 			r.Get("/{id}", userHandlers.GetByID)
 			r.Get("/handle/{handle}", userHandlers.GetByHandle)
-			r.Put("/{id}", userHandlers.Update)
-			r.Delete("/{id}", userHandlers.Delete)
-
-			// Follow routes
-			r.Post("/{id}/follow", userHandlers.Follow)
-			r.Delete("/{id}/follow", userHandlers.Unfollow)
 			r.Get("/{id}/followers", userHandlers.GetFollowers)
 			r.Get("/{id}/following", userHandlers.GetFollowing)
+
+			// Protected routes (require authentication)
+			r.Group(func(r chi.Router) {
+				r.Use(authMiddleware.RequireAuth)
+				r.Put("/{id}", userHandlers.Update)
+				r.Delete("/{id}", userHandlers.Delete)
+				// Accounts are made through signup; admins may add one directly
+				r.With(api.RequireRole(models.PlatformRoleAdmin)).Post("/", userHandlers.Create)
+
+				// Follow routes
+				r.Post("/{id}/follow", userHandlers.Follow)
+				r.Delete("/{id}/follow", userHandlers.Unfollow)
+			})
 		})
 
 		// Post routes
 		r.Route("/posts", func(r chi.Router) {
-			r.Get("/", postHandlers.List)
-			r.Post("/", postHandlers.Create)
-			r.Get("/{id}", postHandlers.Get)
-			r.Put("/{id}", postHandlers.Update)
-			r.Delete("/{id}", postHandlers.Delete)
-
-			// Comment routes
-			r.Post("/{id}/comments", postHandlers.CreateComment)
-			r.Get("/{id}/comments", postHandlers.GetComments)
-			r.Delete("/{id}/comments/{commentId}", postHandlers.DeleteComment)
-
-			// Reaction routes
-			r.Post("/{id}/react", postHandlers.React)
-			r.Delete("/{id}/react", postHandlers.Unreact)
-
-			// Save routes
-			r.Post("/{id}/save", postHandlers.Save)
-			r.Delete("/{id}/save", postHandlers.Unsave)
-			r.Get("/saved", postHandlers.GetSavedPosts)
-
-			// Translation route
-			r.Get("/{id}/translate", postHandlers.TranslatePost)
-
-			// Reporting route
-			r.Post("/{id}/report", moderationHandlers.ReportPost)
+			r.Use(api.APIKeyScopes(models.ScopeReadPosts, models.ScopeWritePosts))
+
+			// Public routes, personalised when a token is sent
+			r.Group(func(r chi.Router) {
+				r.Use(authMiddleware.OptionalAuth)
+				r.Get("/", postHandlers.List)
+				r.Get("/{id}", postHandlers.Get)
+				r.Get("/{id}/comments", postHandlers.GetComments)
+
+				// Translation route
+				r.Get("/{id}/translate", postHandlers.TranslatePost)
+			})
+
+			// Protected routes (require authentication)
+			r.Group(func(r chi.Router) {
+				r.Use(authMiddleware.RequireAuth)
+				r.Post("/", postHandlers.Create)
+				r.Put("/{id}", postHandlers.Update)
+				r.Delete("/{id}", postHandlers.Delete)
+				r.Post("/{id}/restore", postHandlers.Restore)
+
+				// Comment routes
+				r.Post("/{id}/comments", postHandlers.CreateComment)
+				r.Delete("/{id}/comments/{commentId}", postHandlers.DeleteComment)
+				r.Post("/{id}/comments/{commentId}/restore", postHandlers.RestoreComment)
+
+				// Reaction routes
+				r.Post("/{id}/react", postHandlers.React)
+				r.Delete("/{id}/react", postHandlers.Unreact)
+
+				// Save routes
+				r.Post("/{id}/save", postHandlers.Save)
+				r.Delete("/{id}/save", postHandlers.Unsave)
+				r.Get("/saved", postHandlers.GetSavedPosts)
+
+				// Reporting route
+				r.Post("/{id}/report", moderationHandlers.ReportPost)
+			})
 		})
 
-		// Message routes
+		// Message routes (protected)
 		r.Route("/messages", func(r chi.Router) {
+			r.Use(authMiddleware.RequireAuth)
+
 			// Conversation routes
 			r.Get("/conversations", messageHandlers.ListConversations)
 			r.Post("/conversations", messageHandlers.CreateConversation)
@@ -365,33 +624,50 @@ func NewServer(cfg *config.Config) *Server {
 
 		// Hashtag routes
 		r.Route("/hashtags", func(r chi.Router) {
-			r.Get("/", hashtagHandlers.List)
-			r.Get("/trending", hashtagHandlers.GetTrending)
-			r.Post("/", hashtagHandlers.Create)
-			r.Get("/{slug}", hashtagHandlers.GetBySlug)
-			r.Delete("/{slug}", hashtagHandlers.Delete)
-
-			// Follow/Unfollow routes
-			r.Post("/{slug}/follow", hashtagHandlers.Follow)
-			r.Delete("/{slug}/follow", hashtagHandlers.Unfollow)
-
-			// Post linking
-			r.Post("/{slug}/posts", hashtagHandlers.AddPost)
-			r.Get("/{slug}/posts", hashtagHandlers.GetPosts)
+			r.Use(api.APIKeyScopes(models.ScopeReadHashtags, models.ScopeWriteHashtags))
+
+			// Public routes, personalised when a token is sent
+			r.Group(func(r chi.Router) {
+				r.Use(authMiddleware.OptionalAuth)
+				r.Get("/", hashtagHandlers.List)
+				r.Get("/trending", hashtagHandlers.GetTrending)
+				r.Get("/{slug}", hashtagHandlers.GetBySlug)
+				r.Get("/{slug}/posts", hashtagHandlers.GetPosts)
+			})
+
+			// Protected routes (require authentication)
+			r.Group(func(r chi.Router) {
+				r.Use(authMiddleware.RequireAuth)
+				r.Post("/", hashtagHandlers.Create)
+				r.Delete("/{slug}", hashtagHandlers.Delete)
+
+				// Follow/Unfollow routes
+				r.Post("/{slug}/follow", hashtagHandlers.Follow)
+				r.Delete("/{slug}/follow", hashtagHandlers.Unfollow)
+
+				// Post linking
+				r.Post("/{slug}/posts", hashtagHandlers.AddPost)
+			})
 		})
 
 		// Debate routes
 		r.Route("/debates", func(r chi.Router) {
+			r.Use(api.APIKeyScopes(models.ScopeReadDebates, models.ScopeWriteDebates))
+
 			r.Get("/", debateHandlers.List)
+			r.Get("/formats", debateHandlers.Formats)
 			r.Get("/{id}", debateHandlers.Get)
+			r.Get("/{id}/round", debateHandlers.Round)
+			r.With(authMiddleware.OptionalAuth).Get("/{id}/poll", debateHandlers.Poll)
 
 			// Protected routes (require authentication)
 			r.Group(func(r chi.Router) {
-				r.Use(api.RequireAuth)
+				r.Use(authMiddleware.RequireAuth)
 				r.Post("/", debateHandlers.Create)
-				r.Delete("/clear-all", debateHandlers.ClearAllDebates) // Clear all debates
+				r.With(api.RequireRole(models.PlatformRoleAdmin)).Delete("/clear-all", debateHandlers.ClearAllDebates) // Clear all debates
 				r.Put("/{id}", debateHandlers.Update)
 				r.Delete("/{id}", debateHandlers.Delete)
+				r.Post("/{id}/unlock", debateHandlers.Unlock) // Open early access to everyone
 
 				// Participant routes
 				r.Post("/{id}/join", debateHandlers.JoinDebate)
@@ -404,22 +680,41 @@ func NewServer(cfg *config.Config) *Server {
 				// Speak request routes
 				r.Post("/{id}/speak-requests", debateHandlers.CreateSpeakRequest)
 				r.Get("/{id}/speak-requests", debateHandlers.GetSpeakRequests)
+				r.Get("/{id}/speaker-queue", debateHandlers.GetSpeakerQueue)
+				r.Put("/{id}/speaker-queue", debateHandlers.ReorderSpeakerQueue)
 				r.Patch("/speak-requests/{requestId}", debateHandlers.UpdateSpeakRequest)
 				r.Delete("/speak-requests/{requestId}", debateHandlers.DeleteSpeakRequest)
 
-				// Award debate win
-				r.Post("/{id}/award-win", debateHandlers.AwardDebateWin)
+				// Audience polls
+				r.Post("/{id}/votes", debateHandlers.Vote)
 			})
 		})
 
-		// Debate stats routes
-		r.Route("/debate-stats", func(r chi.Router) {
-			r.Post("/", debateStatsHandlers.RecordStats)
-			r.Get("/", debateStatsHandlers.GetAllStats)
+		// Debate stats routes. The debate scheduler records a debate's stats
+		// when it ends.
+		r.Get("/debate-stats", debateStatsHandlers.GetAllStats)
+
+		// Admin routes
+		r.Group(func(r chi.Router) {
+			r.Use(authMiddleware.RequireAuth)
+			r.Use(api.RequireRole(models.PlatformRoleAdmin))
+			r.Post("/admin/snapshot", adminHandlers.Snapshot)
+			r.Put("/admin/users/{id}/role", adminHandlers.GrantRole)
+			r.Delete("/admin/users/{id}/role", adminHandlers.RevokeRole)
 		})
+		// Support staff help users back into their accounts
+		r.With(authMiddleware.RequireAuth, api.RequireRole(models.PlatformRoleSupport, models.PlatformRoleAdmin)).
+			Delete("/admin/users/{id}/lockout", adminHandlers.UnlockLogin)
+
+		// Development-only routes
+		if impersonationService.Enabled() {
+			r.Post("/dev/impersonate", devHandlers.Impersonate)
+		}
 
-		// Moderation routes (admin only - in production, add admin middleware)
+		// Moderation routes (moderators and admins)
 		r.Route("/moderation", func(r chi.Router) {
+			r.Use(authMiddleware.RequireAuth)
+			r.Use(api.RequireRole(models.PlatformRoleModerator, models.PlatformRoleAdmin))
 			r.Get("/queue", moderationHandlers.GetModerationQueue)
 			r.Post("/posts/{id}/approve", moderationHandlers.ApprovePost)
 			r.Post("/posts/{id}/reject", moderationHandlers.RejectPost)
@@ -427,9 +722,10 @@ func NewServer(cfg *config.Config) *Server {
 
 		// Notification routes (protected)
 		r.Group(func(r chi.Router) {
-			r.Use(api.RequireAuth)
+			r.Use(api.APIKeyScopes(models.ScopeReadNotifications, ""))
+			r.Use(authMiddleware.RequireAuth)
 			r.Get("/notifications", notifHandlers.List)
-			r.Post("/notifications", notifHandlers.Create)
+			r.With(api.RequireRole(models.PlatformRoleModerator, models.PlatformRoleAdmin)).Post("/notifications", notifHandlers.Create)
 			r.Get("/notifications/{id}", notifHandlers.Get)
 			r.Patch("/notifications/{id}/read", notifHandlers.MarkAsRead)
 			r.Post("/notifications/read-all", notifHandlers.MarkAllAsRead)
@@ -438,17 +734,15 @@ func NewServer(cfg *config.Config) *Server {
 		})
 
 		// Analytics routes
-		r.Post("/analytics/impression", analyticsHandlers.RecordImpression)
+		r.With(authMiddleware.RequireAuth).Post("/analytics/impression", analyticsHandlers.RecordImpression)
 		r.Get("/posts/{id}/metrics", analyticsHandlers.GetPostMetrics)
 		r.Get("/posts/{id}/analytics", analyticsHandlers.GetPostAnalytics)
 
 		// WebSocket route
-		r.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
-			api.ServeWs(hub, w, r)
-		})
+		r.HandleFunc("/ws", wsHandler.ServeWs)
 
-		// LiveKit token route
-		r.Get("/livekit-token", livekitHandlers.GetToken)
+		// LiveKit token route (protected; roomName is the debate ID)
+		r.With(authMiddleware.RequireAuth).Get("/livekit-token", livekitHandlers.GetToken)
 
 		// Community routes
 		r.Route("/communities", func(r chi.Router) {
@@ -460,17 +754,18 @@ func NewServer(cfg *config.Config) *Server {
 			// Protected routes
 			// Protected routes
 			r.Group(func(r chi.Router) {
-				r.Use(api.RequireAuth)
+				r.Use(authMiddleware.RequireAuth)
 				r.Get("/joined", communityHandlers.ListJoined) // New protected route
 				r.Post("/", communityHandlers.Create)
 				r.Post("/{id}/join", communityHandlers.Join)
 				r.Post("/{id}/leave", communityHandlers.Leave)
 				r.Delete("/{id}", communityHandlers.Delete)
+				r.Post("/{id}/restore", communityHandlers.Restore)
 				r.Put("/{id}/members/{userId}", communityHandlers.UpdateMemberStatus)
 				r.Delete("/{id}/members/{userId}", communityHandlers.KickMember)
 			})
 		})
 	})
 
-	return &Server{router: r}
+	return &Server{router: r, store: store}
 }
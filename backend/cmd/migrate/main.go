@@ -0,0 +1,148 @@
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/joho/godotenv"
+	"github.com/yourusername/v-backend/internal/config"
+	"github.com/yourusername/v-backend/internal/migrate"
+	"github.com/yourusername/v-backend/internal/repository/postgres"
+	"github.com/yourusername/v-backend/internal/repository/sqlite"
+)
+
+const usage = `Usage: migrate [-dir path] <command>
+
+Migrates the database selected by STORAGE_DRIVER (postgres or sqlite).
+
+Commands:
+  up              Apply all pending migrations
+  down N          Roll back the N most recently applied migrations
+  status          List migrations and whether they have been applied
+  create <name>   Write a new, empty up/down migration pair to -dir
+`
+
+func main() {
+	dir := flag.String("dir", "", "directory for new migrations (create only, defaults to the STORAGE_DRIVER's)")
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// Load .env file if it exists (ignore error if file doesn't exist)
+	godotenv.Load()
+	cfg := config.LoadConfig()
+
+	// create only touches the filesystem
+	if args[0] == "create" {
+		if len(args) != 2 {
+			flag.Usage()
+			os.Exit(2)
+		}
+		if *dir == "" {
+			if cfg.StorageDriver != "postgres" && cfg.StorageDriver != "sqlite" {
+				log.Fatalf("❌ STORAGE_DRIVER %q has no migrations; pass -dir", cfg.StorageDriver)
+			}
+			*dir = filepath.Join("internal/repository", cfg.StorageDriver, "migrations")
+		}
+		up, down, err := migrate.Create(*dir, args[1])
+		if err != nil {
+			log.Fatal("❌ Failed to create migration:", err)
+		}
+		fmt.Println("Created", up)
+		fmt.Println("Created", down)
+		return
+	}
+
+	db, migrations := openDatabase(cfg)
+	defer db.Close()
+
+	migrator, err := migrate.New(db, migrations)
+	if err != nil {
+		log.Fatal("❌ Failed to load migrations:", err)
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up()
+		for _, m := range applied {
+			fmt.Printf("Applied %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Fatal("❌ ", err)
+		}
+		if len(applied) == 0 {
+			fmt.Println("Schema is up to date")
+		}
+
+	case "down":
+		if len(args) != 2 {
+			flag.Usage()
+			os.Exit(2)
+		}
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n <= 0 {
+			log.Fatalf("❌ Invalid number of migrations %q", args[1])
+		}
+		reverted, err := migrator.Down(n)
+		for _, m := range reverted {
+			fmt.Printf("Reverted %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Fatal("❌ ", err)
+		}
+
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			log.Fatal("❌ ", err)
+		}
+		for _, s := range statuses {
+			state := "pending"
+			if s.Applied {
+				state = "applied " + s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d  %-40s %s\n", s.Version, s.Name, state)
+		}
+
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+// openDatabase connects to the configured database and returns its migrations
+func openDatabase(cfg *config.Config) (*sql.DB, fs.FS) {
+	switch cfg.StorageDriver {
+	case "postgres":
+		if cfg.DatabaseURL == "" {
+			log.Fatal("❌ DATABASE_URL is not set")
+		}
+		db, err := postgres.Open(cfg.DatabaseURL)
+		if err != nil {
+			log.Fatal("❌ Failed to connect to database:", err)
+		}
+		return db, postgres.Migrations
+
+	case "sqlite":
+		db, err := sqlite.Open(cfg.SQLitePath)
+		if err != nil {
+			log.Fatal("❌ Failed to open database:", err)
+		}
+		return db, sqlite.Migrations
+
+	default:
+		log.Fatalf("❌ STORAGE_DRIVER %q has no migrations (set DATABASE_URL or STORAGE_DRIVER=sqlite)", cfg.StorageDriver)
+		return nil, nil
+	}
+}
@@ -0,0 +1,127 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository/memory"
+	"github.com/yourusername/v-backend/internal/service"
+)
+
+type AdminHandlers struct {
+	store        *memory.Store
+	snapshotPath string
+	roleService  *service.RoleService
+	loginGuard   *service.LoginGuard
+}
+
+// NewAdminHandlers creates admin handlers. store is nil when the server is not
+// running on in-memory repositories.
+func NewAdminHandlers(store *memory.Store, snapshotPath string, roleService *service.RoleService, loginGuard *service.LoginGuard) *AdminHandlers {
+	return &AdminHandlers{
+		store:        store,
+		snapshotPath: snapshotPath,
+		roleService:  roleService,
+		loginGuard:   loginGuard,
+	}
+}
+
+// UnlockLogin handles DELETE /api/admin/users/{id}/lockout, lifting a lockout
+// after failed sign-ins. Support staff may do this as well as admins.
+func (h *AdminHandlers) UnlockLogin(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "id")
+	staffID := r.Context().Value("userID").(string)
+
+	if err := h.loginGuard.Unlock(userID); err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			Error(w, http.StatusNotFound, "User not found")
+			return
+		}
+		Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	log.Printf("[LoginGuard] %s unlocked sign-in for %s", staffID, userID)
+	Success(w, "Sign-in unlocked")
+}
+
+// GrantRole handles PUT /api/admin/users/{id}/role
+func (h *AdminHandlers) GrantRole(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Role models.PlatformRole `json:"role"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	h.setRole(w, r, req.Role)
+}
+
+// RevokeRole handles DELETE /api/admin/users/{id}/role, making the user a
+// regular user again
+func (h *AdminHandlers) RevokeRole(w http.ResponseWriter, r *http.Request) {
+	h.setRole(w, r, models.PlatformRoleUser)
+}
+
+func (h *AdminHandlers) setRole(w http.ResponseWriter, r *http.Request, role models.PlatformRole) {
+	userID := chi.URLParam(r, "id")
+	adminID := r.Context().Value("userID").(string)
+
+	// Keeps the last admin from locking everyone out
+	if userID == adminID {
+		Error(w, http.StatusBadRequest, "You cannot change your own role")
+		return
+	}
+
+	user, err := h.roleService.SetRole(userID, role)
+	if errors.Is(err, service.ErrInvalidRole) {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if errors.Is(err, service.ErrUserNotFound) {
+		Error(w, http.StatusNotFound, "User not found")
+		return
+	}
+	if err != nil {
+		UpdateError(w, err)
+		return
+	}
+
+	log.Printf("[Roles] Admin %s set the role of %s to %s", adminID, userID, role)
+	JSON(w, http.StatusOK, user)
+}
+
+// Snapshot handles POST /api/admin/snapshot
+func (h *AdminHandlers) Snapshot(w http.ResponseWriter, r *http.Request) {
+	if h.store == nil || h.snapshotPath == "" {
+		Error(w, http.StatusConflict, "Snapshots require in-memory storage with SNAPSHOT_PATH set")
+		return
+	}
+
+	snap, err := h.store.SaveSnapshot(h.snapshotPath)
+	if err != nil {
+		log.Printf("[Snapshot] Failed to save snapshot to %s: %v", h.snapshotPath, err)
+		Error(w, http.StatusInternalServerError, "Failed to save snapshot")
+		return
+	}
+
+	log.Printf("[Snapshot] Saved snapshot to %s on request", h.snapshotPath)
+	JSON(w, http.StatusOK, map[string]interface{}{
+		"path":      h.snapshotPath,
+		"version":   memory.SnapshotVersion,
+		"createdAt": snap.CreatedAt.Format(time.RFC3339),
+		"counts": map[string]int{
+			"users":         len(snap.Users),
+			"posts":         len(snap.Posts),
+			"comments":      len(snap.Comments),
+			"debates":       len(snap.Debates),
+			"notifications": len(snap.Notifications),
+			"communities":   len(snap.Communities),
+		},
+	})
+}
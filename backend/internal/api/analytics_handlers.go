@@ -32,12 +32,11 @@ func (h *AnalyticsHandlers) RecordImpression(w http.ResponseWriter, r *http.Requ
 		Error(w, http.StatusBadRequest, err.Error())
 		return
 	}
-	if err := ValidateRequired(req.UserID, "userId"); err != nil {
-		Error(w, http.StatusBadRequest, err.Error())
-		return
-	}
 
-	if err := h.analyticsRepo.RecordImpression(req.PostID, req.UserID); err != nil {
+	// The viewer is whoever is signed in, not whatever the body claims
+	userID := r.Context().Value("userID").(string)
+
+	if err := h.analyticsRepo.RecordImpression(req.PostID, userID); err != nil {
 		Error(w, http.StatusInternalServerError, "Failed to record impression")
 		return
 	}
@@ -0,0 +1,133 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/service"
+)
+
+// APIKeyHandlers manage API keys and the bots that act through them. Their
+// routes only accept signed-in sessions, so a key cannot mint more keys.
+type APIKeyHandlers struct {
+	apiKeyService *service.APIKeyService
+}
+
+func NewAPIKeyHandlers(apiKeyService *service.APIKeyService) *APIKeyHandlers {
+	return &APIKeyHandlers{apiKeyService: apiKeyService}
+}
+
+// apiKeyError writes the response for an error from APIKeyService
+func apiKeyError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrUserNotFound), errors.Is(err, service.ErrAPIKeyNotFound):
+		Error(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrNotBotOwner), errors.Is(err, service.ErrBotOwner):
+		Error(w, http.StatusForbidden, err.Error())
+	case errors.Is(err, service.ErrInvalidScope), errors.Is(err, service.ErrNoScopes),
+		errors.Is(err, service.ErrTooManyAPIKeys), errors.Is(err, service.ErrTooManyBots):
+		Error(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrHandleTaken):
+		Error(w, http.StatusConflict, "Handle already exists")
+	default:
+		Error(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// accountID is the account a key route is for: a bot from the URL, or the
+// signed-in user
+func accountID(r *http.Request) string {
+	if botID := chi.URLParam(r, "id"); botID != "" {
+		return botID
+	}
+	return r.Context().Value("userID").(string)
+}
+
+// List handles GET /api/auth/api-keys and GET /api/bots/{id}/api-keys
+func (h *APIKeyHandlers) List(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(string)
+
+	keys, err := h.apiKeyService.List(userID, accountID(r))
+	if err != nil {
+		apiKeyError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, keys)
+}
+
+// Create handles POST /api/auth/api-keys and POST /api/bots/{id}/api-keys. The
+// key is only in this response.
+func (h *APIKeyHandlers) Create(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(string)
+
+	var req models.CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := ValidateRequired(req.Name, "name"); err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	created, err := h.apiKeyService.Create(userID, accountID(r), req)
+	if err != nil {
+		apiKeyError(w, err)
+		return
+	}
+
+	Created(w, created)
+}
+
+// Revoke handles DELETE /api/auth/api-keys/{keyId} and
+// DELETE /api/bots/{id}/api-keys/{keyId}
+func (h *APIKeyHandlers) Revoke(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(string)
+
+	if err := h.apiKeyService.Revoke(userID, accountID(r), chi.URLParam(r, "keyId")); err != nil {
+		apiKeyError(w, err)
+		return
+	}
+
+	NoContent(w)
+}
+
+// ListBots handles GET /api/bots, the signed-in user's bots
+func (h *APIKeyHandlers) ListBots(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(string)
+
+	bots, err := h.apiKeyService.Bots(userID)
+	if err != nil {
+		apiKeyError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, bots)
+}
+
+// CreateBot handles POST /api/bots
+func (h *APIKeyHandlers) CreateBot(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(string)
+
+	var req models.CreateBotRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := ValidateHandle(req.Handle); err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	bot, err := h.apiKeyService.CreateBot(userID, req)
+	if err != nil {
+		apiKeyError(w, err)
+		return
+	}
+
+	Created(w, bot)
+}
@@ -2,9 +2,15 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
 	"net/http"
+	"strconv"
 	"time"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/yourusername/v-backend/internal/auth"
 	"github.com/yourusername/v-backend/internal/models"
@@ -13,18 +19,28 @@ import (
 )
 
 type AuthHandlers struct {
-	authRepo      repository.AuthRepository
-	userRepo      repository.UserRepository
-	pointsService *service.PointsService
-	notifRepo     repository.NotificationRepository
+	authRepo       repository.AuthRepository
+	userRepo       repository.UserRepository
+	pointsService  *service.PointsService
+	notifRepo      repository.NotificationRepository
+	sessionService *service.SessionService
+	accountService *service.AccountService
+	twoFactor      *service.TwoFactorService
+	loginGuard     *service.LoginGuard
+	oidcService    *service.OIDCService
 }
 
-func NewAuthHandlers(authRepo repository.AuthRepository, userRepo repository.UserRepository, pointsService *service.PointsService, notifRepo repository.NotificationRepository) *AuthHandlers {
+func NewAuthHandlers(authRepo repository.AuthRepository, userRepo repository.UserRepository, pointsService *service.PointsService, notifRepo repository.NotificationRepository, sessionService *service.SessionService, accountService *service.AccountService, twoFactor *service.TwoFactorService, loginGuard *service.LoginGuard, oidcService *service.OIDCService) *AuthHandlers {
 	return &AuthHandlers{
-		authRepo:      authRepo,
-		userRepo:      userRepo,
-		pointsService: pointsService,
-		notifRepo:     notifRepo,
+		authRepo:       authRepo,
+		userRepo:       userRepo,
+		pointsService:  pointsService,
+		notifRepo:      notifRepo,
+		sessionService: sessionService,
+		accountService: accountService,
+		twoFactor:      twoFactor,
+		loginGuard:     loginGuard,
+		oidcService:    oidcService,
 	}
 }
 
@@ -95,9 +111,8 @@ func (h *AuthHandlers) Signup(w http.ResponseWriter, r *http.Request) {
 	// Robot avatars don't need facial hair adjustments based on age
 
 	// Create user
-	userID := uuid.New().String()
 	user := &models.User{
-		ID:          userID,
+		ID:          uuid.New().String(),
 		Name:        req.Name,
 		Handle:      req.Handle,
 		Email:       req.Email,
@@ -111,18 +126,47 @@ func (h *AuthHandlers) Signup(w http.ResponseWriter, r *http.Request) {
 		UpdatedAt:   time.Now(),
 	}
 
+	if err := h.createAccount(user, passwordHash); err != nil {
+		Error(w, http.StatusInternalServerError, "Failed to create account")
+		return
+	}
+
+	// Start a session for this device
+	tokens, err := h.sessionService.Start(user, r.UserAgent(), clientIP(r))
+	if err != nil {
+		Error(w, http.StatusInternalServerError, "Failed to generate token")
+		return
+	}
+
+	// Ask the user to confirm their email address; they can request another link later
+	if err := h.accountService.SendVerification(user); err != nil {
+		log.Printf("[Auth] Failed to send verification email to %s: %v", user.Email, err)
+	}
+
+	response := models.AuthResponse{
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    tokens.ExpiresIn,
+		User:         user,
+	}
+
+	Created(w, response)
+}
+
+// createAccount stores a new user along with their credentials and welcomes
+// them. passwordHash is empty for users who sign in with a provider.
+func (h *AuthHandlers) createAccount(user *models.User, passwordHash string) error {
 	// Initialize user with default tier/points values
 	h.pointsService.InitializeUser(user)
 
 	if err := h.userRepo.Create(user); err != nil {
-		Error(w, http.StatusInternalServerError, "Failed to create user")
-		return
+		return err
 	}
 
 	// Create auth
 	authModel := &models.Auth{
-		UserID:       userID,
-		Email:        req.Email,
+		UserID:       user.ID,
+		Email:        user.Email,
 		PasswordHash: passwordHash,
 		CreatedAt:    time.Now(),
 		UpdatedAt:    time.Now(),
@@ -130,22 +174,14 @@ func (h *AuthHandlers) Signup(w http.ResponseWriter, r *http.Request) {
 
 	if err := h.authRepo.CreateAuth(authModel); err != nil {
 		// Rollback user creation
-		h.userRepo.Delete(userID)
-		Error(w, http.StatusInternalServerError, "Failed to create auth")
-		return
-	}
-
-	// Generate JWT token
-	token, err := auth.GenerateToken(user.ID, user.Email, user.Handle)
-	if err != nil {
-		Error(w, http.StatusInternalServerError, "Failed to generate token")
-		return
+		h.userRepo.Delete(user.ID)
+		return err
 	}
 
 	// Create welcome notification for new user
 	welcomeNotification := &models.Notification{
 		ID:        uuid.New().String(),
-		UserID:    userID,
+		UserID:    user.ID,
 		Type:      "general",
 		Title:     "Welcome to the App! 🎉",
 		Message:   "Thanks for joining! Start exploring debates, connect with others, and share your thoughts.",
@@ -157,13 +193,7 @@ func (h *AuthHandlers) Signup(w http.ResponseWriter, r *http.Request) {
 		// Log error but don't fail signup
 		// In production, you'd want proper logging here
 	}
-
-	response := models.AuthResponse{
-		Token: token,
-		User:  user,
-	}
-
-	Created(w, response)
+	return nil
 }
 
 func (h *AuthHandlers) Login(w http.ResponseWriter, r *http.Request) {
@@ -184,54 +214,200 @@ func (h *AuthHandlers) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Refuse before checking the password while the account or address is
+	// backing off
+	attempt, err := h.loginGuard.Begin(req.Email, clientIP(r))
+	if err != nil {
+		loginBlocked(w, err)
+		return
+	}
+	defer attempt.End()
+
 	// Get auth by email
 	authModel, err := h.authRepo.GetByEmail(req.Email)
 	if err != nil {
+		attempt.Failed()
 		Error(w, http.StatusUnauthorized, "Invalid email or password")
 		return
 	}
 
 	// Check password
 	if !auth.CheckPassword(authModel.PasswordHash, req.Password) {
+		attempt.Failed()
 		Error(w, http.StatusUnauthorized, "Invalid email or password")
 		return
 	}
 
-	// Get user
-	user, err := h.userRepo.GetByID(authModel.UserID)
-	if err != nil {
-		Error(w, http.StatusInternalServerError, "Failed to get user")
+	// With two-factor on, failures are cleared once the code is right too;
+	// clearing them now would let anyone with the password guess codes forever
+	if !h.twoFactor.Enabled(authModel.UserID) {
+		attempt.Succeeded()
+	}
+	h.beginLogin(w, r, authModel.UserID)
+}
+
+// beginLogin signs in a user who proved who they are, or asks for their
+// second factor first if they have two-factor on
+func (h *AuthHandlers) beginLogin(w http.ResponseWriter, r *http.Request, userID string) {
+	// The client trades the challenge and a code for tokens at /auth/2fa/verify
+	if h.twoFactor.Enabled(userID) {
+		challenge, expiresIn, err := auth.GenerateChallengeToken(userID)
+		if err != nil {
+			Error(w, http.StatusInternalServerError, "Failed to generate token")
+			return
+		}
+		JSON(w, http.StatusOK, models.TwoFactorChallenge{
+			TwoFactorRequired: true,
+			ChallengeToken:    challenge,
+			ExpiresIn:         expiresIn,
+		})
+		return
+	}
+
+	h.completeLogin(w, r, userID)
+}
+
+// loginBlocked tells a client that is signing in too often when to try again
+func loginBlocked(w http.ResponseWriter, err error) {
+	var blocked *service.LoginBlockedError
+	if !errors.As(err, &blocked) {
+		Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	seconds := int(math.Ceil(blocked.RetryAfter.Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	if blocked.Locked {
+		Error(w, http.StatusTooManyRequests, fmt.Sprintf("Too many failed sign-ins; signing in is locked for %d minutes", (seconds+59)/60))
 		return
 	}
+	Error(w, http.StatusTooManyRequests, fmt.Sprintf("Too many failed sign-ins; try again in %d seconds", seconds))
+}
 
+// completeLogin signs in a user whose credentials have been checked
+func (h *AuthHandlers) completeLogin(w http.ResponseWriter, r *http.Request, userID string) {
 	// Award daily login streak points
-	if err := h.pointsService.UpdateUserPoints(user.ID, service.ActionDailyStreak); err != nil {
+	if err := h.pointsService.UpdateUserPoints(userID, service.ActionDailyStreak); err != nil {
 		// Log error but don't fail login
 		// In production, you'd want proper logging here
 	}
 
-	// Refresh user data after points update
-	user, err = h.userRepo.GetByID(authModel.UserID)
+	// Get user after points update
+	user, err := h.userRepo.GetByID(userID)
 	if err != nil {
 		Error(w, http.StatusInternalServerError, "Failed to get user")
 		return
 	}
 
-	// Generate JWT token
-	token, err := auth.GenerateToken(user.ID, user.Email, user.Handle)
+	// Start a session for this device
+	tokens, err := h.sessionService.Start(user, r.UserAgent(), clientIP(r))
 	if err != nil {
 		Error(w, http.StatusInternalServerError, "Failed to generate token")
 		return
 	}
 
 	response := models.AuthResponse{
-		Token: token,
-		User:  user,
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    tokens.ExpiresIn,
+		User:         user,
 	}
 
 	JSON(w, http.StatusOK, response)
 }
 
+// Refresh trades a refresh token for a new access token and refresh token
+func (h *AuthHandlers) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req models.RefreshRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := ValidateRequired(req.RefreshToken, "refreshToken"); err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tokens, user, err := h.sessionService.Refresh(req.RefreshToken)
+	if errors.Is(err, repository.ErrRefreshTokenReused) {
+		Error(w, http.StatusUnauthorized, "Refresh token was already used; the session has been revoked")
+		return
+	}
+	if err != nil {
+		Error(w, http.StatusUnauthorized, "Invalid or expired refresh token")
+		return
+	}
+
+	response := models.AuthResponse{
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    tokens.ExpiresIn,
+		User:         user,
+	}
+
+	JSON(w, http.StatusOK, response)
+}
+
+// Logout revokes the session the request was made with
+func (h *AuthHandlers) Logout(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(string)
+	sessionID := r.Context().Value("sessionID").(string)
+
+	if sessionID != "" {
+		if err := h.sessionService.Revoke(userID, sessionID); err != nil {
+			Error(w, http.StatusInternalServerError, "Failed to log out")
+			return
+		}
+	}
+
+	Success(w, "Logged out")
+}
+
+// ListSessions returns the user's active sessions
+func (h *AuthHandlers) ListSessions(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(string)
+	sessionID := r.Context().Value("sessionID").(string)
+
+	sessions, err := h.sessionService.List(userID, sessionID)
+	if err != nil {
+		Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	JSON(w, http.StatusOK, sessions)
+}
+
+// RevokeSession signs one of the user's devices out
+func (h *AuthHandlers) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(string)
+	sessionID := chi.URLParam(r, "id")
+
+	if err := h.sessionService.Revoke(userID, sessionID); err != nil {
+		if errors.Is(err, service.ErrSessionNotFound) {
+			Error(w, http.StatusNotFound, "Session not found")
+			return
+		}
+		Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	NoContent(w)
+}
+
+// RevokeOtherSessions signs out every device except the one making the request
+func (h *AuthHandlers) RevokeOtherSessions(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(string)
+	sessionID := r.Context().Value("sessionID").(string)
+
+	if err := h.sessionService.RevokeOthers(userID, sessionID); err != nil {
+		Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	Success(w, "Signed out of all other sessions")
+}
+
 func (h *AuthHandlers) GetCurrentUser(w http.ResponseWriter, r *http.Request) {
 	// Get user from context (set by auth middleware)
 	userID := r.Context().Value("userID").(string)
@@ -289,5 +465,119 @@ func (h *AuthHandlers) ChangePassword(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Anyone signed in with the old password is signed out
+	sessionID := r.Context().Value("sessionID").(string)
+	if err := h.sessionService.RevokeOthers(userID, sessionID); err != nil {
+		Error(w, http.StatusInternalServerError, "Password changed, but failed to sign out other sessions")
+		return
+	}
+
 	Success(w, "Password changed successfully")
 }
+
+// VerifyEmail redeems the token from a verification email
+func (h *AuthHandlers) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	var req models.VerifyEmailRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := ValidateRequired(req.Token, "token"); err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user, err := h.accountService.VerifyEmail(req.Token)
+	if errors.Is(err, repository.ErrInvalidEmailToken) {
+		Error(w, http.StatusBadRequest, "Invalid or expired verification link")
+		return
+	}
+	if err != nil {
+		UpdateError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, user)
+}
+
+// ResendVerification mails the signed-in user a new verification link
+func (h *AuthHandlers) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(string)
+
+	user, err := h.userRepo.GetByID(userID)
+	if err != nil {
+		Error(w, http.StatusNotFound, "User not found")
+		return
+	}
+
+	if err := h.accountService.SendVerification(user); err != nil {
+		if errors.Is(err, service.ErrEmailAlreadyVerified) {
+			Error(w, http.StatusConflict, err.Error())
+			return
+		}
+		log.Printf("[Auth] Failed to send verification email to %s: %v", user.Email, err)
+		Error(w, http.StatusInternalServerError, "Failed to send verification email")
+		return
+	}
+
+	Success(w, "Verification email sent")
+}
+
+// ForgotPassword mails a password reset link. It answers the same whether or
+// not the email has an account.
+func (h *AuthHandlers) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req models.ForgotPasswordRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := ValidateEmail(req.Email); err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.accountService.RequestPasswordReset(req.Email); err != nil {
+		log.Printf("[Auth] Failed to send password reset email to %s: %v", req.Email, err)
+	}
+
+	Success(w, "If an account exists for that email, a reset link is on its way")
+}
+
+// ResetPassword sets a new password using the token from a reset email
+func (h *AuthHandlers) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req models.ResetPasswordRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := ValidateRequired(req.Token, "token"); err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(req.NewPassword) < 6 {
+		Error(w, http.StatusBadRequest, "New password must be at least 6 characters")
+		return
+	}
+
+	if err := h.accountService.ResetPassword(req.Token, req.NewPassword); err != nil {
+		if errors.Is(err, repository.ErrInvalidEmailToken) {
+			Error(w, http.StatusBadRequest, "Invalid or expired reset link")
+			return
+		}
+		Error(w, http.StatusInternalServerError, "Failed to reset password")
+		return
+	}
+
+	Success(w, "Password reset; sign in with your new password")
+}
+
+// JWKS serves the public keys access tokens are signed with, including keys
+// about to be used and retired keys whose tokens have not expired yet
+func JWKS(w http.ResponseWriter, r *http.Request) {
+	// Short enough that a new key is picked up before it starts signing
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	JSON(w, http.StatusOK, auth.PublicKeys())
+}
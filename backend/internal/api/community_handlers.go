@@ -2,6 +2,7 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
@@ -18,14 +19,18 @@ type CommunityHandlers struct {
 	userRepo      repository.UserRepository
 	pointsService *service.PointsService
 	notifRepo     repository.NotificationRepository
+	uow           repository.UnitOfWork
+	restoreWindow time.Duration
 }
 
-func NewCommunityHandlers(communityRepo repository.CommunityRepository, userRepo repository.UserRepository, pointsService *service.PointsService, notifRepo repository.NotificationRepository) *CommunityHandlers {
+func NewCommunityHandlers(communityRepo repository.CommunityRepository, userRepo repository.UserRepository, pointsService *service.PointsService, notifRepo repository.NotificationRepository, uow repository.UnitOfWork, restoreWindow time.Duration) *CommunityHandlers {
 	return &CommunityHandlers{
 		communityRepo: communityRepo,
 		userRepo:      userRepo,
 		pointsService: pointsService,
 		notifRepo:     notifRepo,
+		uow:           uow,
+		restoreWindow: restoreWindow,
 	}
 }
 
@@ -70,11 +75,6 @@ func (h *CommunityHandlers) Create(w http.ResponseWriter, r *http.Request) {
 		UpdatedAt:   time.Now(),
 	}
 
-	if err := h.communityRepo.Create(community); err != nil {
-		http.Error(w, "Failed to create community", http.StatusInternalServerError)
-		return
-	}
-
 	// Add creator as admin member
 	member := &models.CommunityMember{
 		CommunityID:   community.ID,
@@ -84,10 +84,21 @@ func (h *CommunityHandlers) Create(w http.ResponseWriter, r *http.Request) {
 		PointsAwarded: true,
 		JoinedAt:      time.Now(),
 	}
-	h.communityRepo.AddMember(member)
 
-	// Award points for creating community
-	h.pointsService.UpdateUserPoints(userID, service.ActionCommunityCreate)
+	err := h.uow.Do(func(tx *repository.Repositories) error {
+		if err := tx.Community.Create(community); err != nil {
+			return err
+		}
+		if err := tx.Community.AddMember(member); err != nil {
+			return err
+		}
+		// Award points for creating community
+		return h.pointsService.WithTx(tx).UpdateUserPoints(userID, service.ActionCommunityCreate)
+	})
+	if err != nil {
+		http.Error(w, "Failed to create community", http.StatusInternalServerError)
+		return
+	}
 
 	JSON(w, http.StatusCreated, community)
 }
@@ -152,26 +163,29 @@ func (h *CommunityHandlers) Join(w http.ResponseWriter, r *http.Request) {
 	// Check history to see if they EVER joined before (Anti-farming)
 	hasJoinedBefore, _ := h.communityRepo.HasJoinedBefore(communityID, userID)
 
-	pointsAwarded := false
-	if !hasJoinedBefore {
-		// Award points
-		h.pointsService.UpdateUserPoints(userID, service.ActionCommunityJoin)
-		pointsAwarded = true
-	}
-
 	member := &models.CommunityMember{
 		CommunityID:   communityID,
 		UserID:        userID,
 		Role:          models.RoleMember,
 		Status:        "pending", // Default to pending
-		PointsAwarded: pointsAwarded,
+		PointsAwarded: !hasJoinedBefore,
 		JoinedAt:      time.Now(),
 	}
 
 	// LOGGING FOR DEBUGGING
 	fmt.Printf("JOIN: Adding member %s to community %s with status %s\n", userID, communityID, member.Status)
 
-	if err := h.communityRepo.AddMember(member); err != nil {
+	// The membership and its points are recorded together
+	err := h.uow.Do(func(tx *repository.Repositories) error {
+		if err := tx.Community.AddMember(member); err != nil {
+			return err
+		}
+		if member.PointsAwarded {
+			return h.pointsService.WithTx(tx).UpdateUserPoints(userID, service.ActionCommunityJoin)
+		}
+		return nil
+	})
+	if err != nil {
 		http.Error(w, "Failed to join community", http.StatusInternalServerError)
 		return
 	}
@@ -190,18 +204,18 @@ func (h *CommunityHandlers) Leave(w http.ResponseWriter, r *http.Request) {
 	communityID := chi.URLParam(r, "id")
 	userID := r.Context().Value("userID").(string)
 
-	if err := h.communityRepo.RemoveMember(communityID, userID); err != nil {
+	err := h.uow.Do(func(tx *repository.Repositories) error {
+		if err := tx.Community.RemoveMember(communityID, userID); err != nil {
+			return err
+		}
+		// Decrement member count
+		return adjustMemberCount(tx, communityID, -1)
+	})
+	if err != nil {
 		http.Error(w, "Failed to leave community", http.StatusInternalServerError)
 		return
 	}
 
-	// Decrement member count
-	comm, _ := h.communityRepo.GetByID(communityID)
-	if comm != nil {
-		comm.MemberCount--
-		h.communityRepo.Update(comm)
-	}
-
 	JSON(w, http.StatusOK, map[string]string{"status": "left"})
 }
 
@@ -230,6 +244,35 @@ func (h *CommunityHandlers) Delete(w http.ResponseWriter, r *http.Request) {
 	JSON(w, http.StatusOK, map[string]string{"status": "deleted"})
 }
 
+// Restore community deleted within the restore window
+func (h *CommunityHandlers) Restore(w http.ResponseWriter, r *http.Request) {
+	communityID := chi.URLParam(r, "id")
+	userID := r.Context().Value("userID").(string)
+
+	community, err := h.communityRepo.GetDeletedByID(communityID)
+	if err != nil {
+		http.Error(w, "Deleted community not found", http.StatusNotFound)
+		return
+	}
+
+	// Same rule as deleting: only the creator
+	if community.CreatorID != userID {
+		http.Error(w, "Only the creator can restore this community", http.StatusForbidden)
+		return
+	}
+
+	if err := h.communityRepo.Restore(communityID, time.Now().Add(-h.restoreWindow)); err != nil {
+		if errors.Is(err, repository.ErrRestoreWindowExpired) {
+			http.Error(w, err.Error(), http.StatusGone)
+			return
+		}
+		http.Error(w, "Failed to restore community", http.StatusInternalServerError)
+		return
+	}
+
+	JSON(w, http.StatusOK, map[string]string{"status": "restored"})
+}
+
 // Get Members
 func (h *CommunityHandlers) GetMembers(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -316,16 +359,23 @@ func (h *CommunityHandlers) UpdateMemberStatus(w http.ResponseWriter, r *http.Re
 	// 3. Update Status
 	oldStatus := member.Status
 	member.Status = req.Status
-	if err := h.communityRepo.UpdateMember(member); err != nil {
+	activated := oldStatus != "active" && member.Status == "active"
+	err = h.uow.Do(func(tx *repository.Repositories) error {
+		if err := tx.Community.UpdateMember(member); err != nil {
+			return err
+		}
+		// 4. Update Member Count if status changed to active
+		if activated {
+			return adjustMemberCount(tx, communityID, 1)
+		}
+		return nil
+	})
+	if err != nil {
 		http.Error(w, "Failed to update member", http.StatusInternalServerError)
 		return
 	}
 
-	// 4. Update Member Count if status changed to active
-	if oldStatus != "active" && member.Status == "active" {
-		community.MemberCount++
-		h.communityRepo.Update(community)
-
+	if activated {
 		// Create notification for the user
 		notification := &models.Notification{
 			ID:            uuid.New().String(),
@@ -366,17 +416,20 @@ func (h *CommunityHandlers) KickMember(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// 2. Remove Member
-	if err := h.communityRepo.RemoveMember(communityID, targetUserID); err != nil {
-		http.Error(w, "Failed to remove member", http.StatusInternalServerError)
-		return
-	}
-
 	// Decrement count if they were active or pending?
 	// Usually RemoveMember doesn't handle count automatically in repo logic in some patterns,
 	// checking repo logic: RemoveMember in memory repo just removes from slice.
 	// So we should decrement count.
-	community.MemberCount--
-	h.communityRepo.Update(community)
+	err = h.uow.Do(func(tx *repository.Repositories) error {
+		if err := tx.Community.RemoveMember(communityID, targetUserID); err != nil {
+			return err
+		}
+		return adjustMemberCount(tx, communityID, -1)
+	})
+	if err != nil {
+		http.Error(w, "Failed to remove member", http.StatusInternalServerError)
+		return
+	}
 
 	JSON(w, http.StatusOK, map[string]string{"status": "removed"})
 }
@@ -439,3 +492,13 @@ func getRandomImageForCategory(category models.CommunityCategory) string {
 		return "https://images.unsplash.com/photo-1529156069898-49953e39b3ac?q=80&w=2832&auto=format&fit=crop" // Friends/Group
 	}
 }
+
+// adjustMemberCount re-reads the community inside tx and moves its member count by delta
+func adjustMemberCount(tx *repository.Repositories, communityID string, delta int) error {
+	community, err := tx.Community.GetByID(communityID)
+	if err != nil {
+		return err
+	}
+	community.MemberCount += delta
+	return tx.Community.Update(community)
+}
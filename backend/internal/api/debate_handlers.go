@@ -2,9 +2,9 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
-	"strconv"
 	"strings"
 	"time"
 
@@ -15,32 +15,60 @@ import (
 	"github.com/yourusername/v-backend/internal/service"
 )
 
+// debateStatusOrder ranks the statuses a debate moves through; it never goes back
+var debateStatusOrder = map[string]int{"SCHEDULED": 0, "ACTIVE": 1, "ENDED": 2}
+
+// maxConflictRetries bounds how often a change to a debate is reapplied after
+// losing a race with another write to it
+const maxConflictRetries = 3
+
 type DebateHandlers struct {
 	repo          repository.DebateRepository
 	userRepo      repository.UserRepository
 	pointsService *service.PointsService
 	hub           *service.Hub
+	scheduler     *service.DebateScheduler
+	queue         *service.SpeakerQueueService
+	poll          *service.DebatePollService
+	access        *service.DebateRoomAccess
 }
 
-func NewDebateHandlers(repo repository.DebateRepository, userRepo repository.UserRepository, pointsService *service.PointsService, hub *service.Hub) *DebateHandlers {
-	return &DebateHandlers{
+func NewDebateHandlers(repo repository.DebateRepository, userRepo repository.UserRepository, pointsService *service.PointsService, hub *service.Hub, scheduler *service.DebateScheduler, queue *service.SpeakerQueueService, poll *service.DebatePollService, access *service.DebateRoomAccess) *DebateHandlers {
+	h := &DebateHandlers{
 		repo:          repo,
 		userRepo:      userRepo,
 		pointsService: pointsService,
 		hub:           hub,
-	}
+		scheduler:     scheduler,
+		queue:         queue,
+		poll:          poll,
+		access:        access,
+	}
+	// The round clock and the speaker queue mute and unmute speakers; rooms
+	// see it like a host mute
+	scheduler.SetParticipantsNotifier(h.broadcastParticipantsUpdate)
+	queue.SetParticipantsNotifier(h.broadcastParticipantsUpdate)
+	return h
 }
 
 func (h *DebateHandlers) Create(w http.ResponseWriter, r *http.Request) {
+	hostID := r.Context().Value("userID").(string)
+
 	var req struct {
 		Title           string `json:"title"`
 		Description     string `json:"description"`
 		Category        string `json:"category"`
-		HostID          string `json:"hostId"`
 		Type            string `json:"type"`            // "PUBLIC" or "PRIVATE"
 		StartTime       string `json:"startTime"`       // RFC3339 format
 		DurationMinutes int    `json:"durationMinutes"` // 30, 60, 360, 1440
+		Format          string `json:"format"`          // See GET /debates/formats; open floor by default
+		QueueOrder      string `json:"queueOrder"`      // "fifo" (default) or "alternating"
+		MaxTurnSeconds  int    `json:"maxTurnSeconds"`  // 0 for no limit
 		ShowInPulse     bool   `json:"showInPulse"`
+		// Who may join before the debate opens to everyone: "followers",
+		// "platinum" or "community:<id>". Public from the start when empty.
+		EarlyAccessRoles []string `json:"earlyAccessRoles"`
+		UnlockAt         string   `json:"unlockAt"` // RFC3339; the start time by default
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -53,10 +81,6 @@ func (h *DebateHandlers) Create(w http.ResponseWriter, r *http.Request) {
 		Error(w, http.StatusBadRequest, err.Error())
 		return
 	}
-	if err := ValidateRequired(req.HostID, "hostId"); err != nil {
-		Error(w, http.StatusBadRequest, err.Error())
-		return
-	}
 	if err := ValidateRequired(req.Type, "type"); err != nil {
 		Error(w, http.StatusBadRequest, err.Error())
 		return
@@ -74,15 +98,38 @@ func (h *DebateHandlers) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Validate duration
-	validDurations := map[int]bool{30: true, 60: true, 360: true, 1440: true}
-	if !validDurations[req.DurationMinutes] {
-		Error(w, http.StatusBadRequest, "Duration must be 30, 60, 360, or 1440 minutes")
+	// Validate format. A structured format lasts as long as its rounds; an
+	// open floor lasts the chosen duration.
+	format, ok := models.FindDebateFormat(req.Format)
+	if !ok {
+		Error(w, http.StatusBadRequest, "Unknown debate format")
+		return
+	}
+	duration := format.Duration()
+	if len(format.Rounds) > 0 {
+		req.DurationMinutes = int((duration + time.Minute - 1) / time.Minute)
+	} else {
+		// Validate duration
+		validDurations := map[int]bool{30: true, 60: true, 360: true, 1440: true}
+		if !validDurations[req.DurationMinutes] {
+			Error(w, http.StatusBadRequest, "Duration must be 30, 60, 360, or 1440 minutes")
+			return
+		}
+		duration = time.Duration(req.DurationMinutes) * time.Minute
+	}
+
+	if err := service.ValidateQueueSettings(req.QueueOrder, req.MaxTurnSeconds); err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.access.ValidateEarlyAccessRoles(req.EarlyAccessRoles); err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	// Check if user is muted
-	userPoints, err := h.pointsService.GetUserPoints(req.HostID)
+	userPoints, err := h.pointsService.GetUserPoints(hostID)
 	if err != nil {
 		Error(w, http.StatusInternalServerError, "Failed to check user status")
 		return
@@ -95,7 +142,7 @@ func (h *DebateHandlers) Create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Check hosting limits
-	canHost, err := h.pointsService.CanHostDebate(req.HostID)
+	canHost, err := h.pointsService.CanHostDebate(hostID)
 	if err != nil {
 		Error(w, http.StatusInternalServerError, "Failed to check hosting permission")
 		return
@@ -119,7 +166,7 @@ func (h *DebateHandlers) Create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Calculate end time
-	endTime := startTime.Add(time.Duration(req.DurationMinutes) * time.Minute)
+	endTime := startTime.Add(duration)
 
 	// Determine status based on start time
 	status := "ACTIVE"
@@ -138,12 +185,15 @@ func (h *DebateHandlers) Create(w http.ResponseWriter, r *http.Request) {
 		Title:           req.Title,
 		Description:     req.Description,
 		Category:        req.Category,
-		HostID:          req.HostID,
+		HostID:          hostID,
 		Type:            req.Type,
 		Status:          status,
 		StartTime:       startTime,
 		EndTime:         &endTime,
 		DurationMinutes: req.DurationMinutes,
+		Format:          format.ID,
+		QueueOrder:      req.QueueOrder,
+		MaxTurnSeconds:  req.MaxTurnSeconds,
 		ShowInPulse:     showInPulse,
 		AgreeCount:      0,
 		DisagreeCount:   0,
@@ -151,13 +201,30 @@ func (h *DebateHandlers) Create(w http.ResponseWriter, r *http.Request) {
 		UpdatedAt:       time.Now(),
 	}
 
+	// With early access the debate starts locked, and the scheduler opens it
+	// to everyone at its unlock time
+	if len(req.EarlyAccessRoles) > 0 {
+		unlockAt := startTime
+		if req.UnlockAt != "" {
+			if unlockAt, err = time.Parse(time.RFC3339, req.UnlockAt); err != nil {
+				Error(w, http.StatusBadRequest, "Invalid unlockAt format (use RFC3339)")
+				return
+			}
+		}
+		debate.IsLocked = true
+		debate.UnlockPhase = models.UnlockPhaseEarlyAccess
+		debate.EarlyAccessRoles = req.EarlyAccessRoles
+		debate.UnlockAt = &unlockAt
+	}
+
 	if err := h.repo.Create(debate); err != nil {
 		Error(w, http.StatusInternalServerError, err.Error())
 		return
 	}
+	h.scheduler.Schedule(debate)
 
 	// Record debate hosting
-	if err := h.pointsService.RecordDebateHost(req.HostID); err != nil {
+	if err := h.pointsService.RecordDebateHost(hostID); err != nil {
 		// Log error but don't fail debate creation
 		// In production, you'd want proper logging here
 	}
@@ -186,27 +253,6 @@ func (h *DebateHandlers) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Update debate status based on current time
-	now := time.Now()
-	updated := false
-
-	// SCHEDULED -> ACTIVE when start time arrives
-	if debate.Status == "SCHEDULED" && now.After(debate.StartTime) {
-		debate.Status = "ACTIVE"
-		updated = true
-	}
-
-	// ACTIVE -> ENDED when end time arrives
-	if debate.Status == "ACTIVE" && debate.EndTime != nil && now.After(*debate.EndTime) {
-		debate.Status = "ENDED"
-		updated = true
-	}
-
-	// Save the updated status
-	if updated {
-		h.repo.Update(debate)
-	}
-
 	// Fetch Host User
 	hostUser, err := h.userRepo.GetByID(debate.HostID)
 	if err != nil {
@@ -240,51 +286,49 @@ func (h *DebateHandlers) Get(w http.ResponseWriter, r *http.Request) {
 		Host:   hostUser,
 	}
 
+	SetETag(w, debate.Version)
 	JSON(w, http.StatusOK, response)
 }
 
-func (h *DebateHandlers) List(w http.ResponseWriter, r *http.Request) {
-	status := r.URL.Query().Get("status")
-	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
-	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+// Formats lists the formats a debate can be created in
+func (h *DebateHandlers) Formats(w http.ResponseWriter, r *http.Request) {
+	JSON(w, http.StatusOK, models.DebateFormats)
+}
+
+// Round returns the round a debate is in and how long it has left
+func (h *DebateHandlers) Round(w http.ResponseWriter, r *http.Request) {
+	debate, err := h.repo.GetByID(chi.URLParam(r, "id"))
+	if err != nil {
+		Error(w, http.StatusNotFound, "Debate not found")
+		return
+	}
 
-	if limit <= 0 || limit > 100 {
-		limit = 50
+	round, ok := service.CurrentRound(debate, time.Now())
+	if !ok {
+		Error(w, http.StatusNotFound, "Debate is not in a round")
+		return
 	}
-	if offset < 0 {
-		offset = 0
+	JSON(w, http.StatusOK, round)
+}
+
+func (h *DebateHandlers) List(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	page, err := ParsePage(r)
+	if err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
 	}
 
-	debates, err := h.repo.List(status, limit, offset)
+	debates, nextCursor, err := h.repo.List(status, page)
 	if err != nil {
 		Error(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	// Update debate statuses based on current time and fetch host info
-	now := time.Now()
+	// Fetch host info. Statuses are kept current by the debate scheduler.
 	debatesWithHosts := make([]map[string]interface{}, 0, len(debates))
 
 	for _, debate := range debates {
-		updated := false
-
-		// SCHEDULED -> ACTIVE when start time arrives
-		if debate.Status == "SCHEDULED" && now.After(debate.StartTime) {
-			debate.Status = "ACTIVE"
-			updated = true
-		}
-
-		// ACTIVE -> ENDED when end time arrives
-		if debate.Status == "ACTIVE" && debate.EndTime != nil && now.After(*debate.EndTime) {
-			debate.Status = "ENDED"
-			updated = true
-		}
-
-		// Save the updated status
-		if updated {
-			h.repo.Update(debate)
-		}
-
 		// Fetch Host User
 		hostUser, err := h.userRepo.GetByID(debate.HostID)
 		if err != nil {
@@ -323,6 +367,15 @@ func (h *DebateHandlers) List(w http.ResponseWriter, r *http.Request) {
 			"startTime":       debate.StartTime,
 			"endTime":         debate.EndTime,
 			"durationMinutes": debate.DurationMinutes,
+			"format":          debate.Format,
+			"currentRound":    debate.CurrentRound,
+			"queueOrder":      debate.QueueOrder,
+			"maxTurnSeconds":  debate.MaxTurnSeconds,
+			"pollClosesAt":    debate.PollClosesAt,
+			"result":          debate.Result,
+			"isLocked":        debate.IsLocked,
+			"unlockPhase":     debate.UnlockPhase,
+			"unlockAt":        debate.UnlockAt,
 			"showInPulse":     debate.ShowInPulse,
 			"agreeCount":      debate.AgreeCount,
 			"disagreeCount":   debate.DisagreeCount,
@@ -347,9 +400,9 @@ func (h *DebateHandlers) List(w http.ResponseWriter, r *http.Request) {
 	}
 
 	JSON(w, http.StatusOK, map[string]interface{}{
-		"debates": debatesWithHosts,
-		"limit":   limit,
-		"offset":  offset,
+		"debates":    debatesWithHosts,
+		"limit":      page.Limit,
+		"nextCursor": nextCursor,
 	})
 }
 
@@ -369,9 +422,24 @@ func (h *DebateHandlers) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if debate.HostID != userID {
+		Error(w, http.StatusForbidden, "Only the debate host can update the debate")
+		return
+	}
+
+	// Only overwrite the version the client read, when it says which one
+	if version, ok, err := ParseIfMatch(r); err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	} else if ok {
+		debate.Version = version
+	}
+
 	var updates struct {
-		Status  *string `json:"status"`
-		EndTime *string `json:"endTime"`
+		Status         *string `json:"status"`
+		EndTime        *string `json:"endTime"`
+		QueueOrder     *string `json:"queueOrder"`
+		MaxTurnSeconds *int    `json:"maxTurnSeconds"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&updates); err != nil {
@@ -379,12 +447,19 @@ func (h *DebateHandlers) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// CRITICAL: Only host can change status to ENDED
-	if updates.Status != nil && *updates.Status == "ENDED" {
-		if debate.HostID != userID {
-			Error(w, http.StatusForbidden, "Only the debate host can end the debate")
+	if updates.Status != nil && *updates.Status != debate.Status {
+		next, ok := debateStatusOrder[*updates.Status]
+		if !ok {
+			Error(w, http.StatusBadRequest, "Status must be 'SCHEDULED', 'ACTIVE', or 'ENDED'")
 			return
 		}
+		if next < debateStatusOrder[debate.Status] {
+			Error(w, http.StatusConflict, "A debate can only move on from "+debate.Status+", not back")
+			return
+		}
+	}
+
+	if updates.Status != nil && *updates.Status == "ENDED" {
 		log.Printf("[Debate Update] Host %s ending debate %s", userID, debate.ID)
 
 		// Log user points before ending (for debugging points decrease issue)
@@ -412,9 +487,20 @@ func (h *DebateHandlers) Update(w http.ResponseWriter, r *http.Request) {
 		}
 		debate.EndTime = &t
 	}
+	queueChanged := updates.QueueOrder != nil || updates.MaxTurnSeconds != nil
+	if updates.QueueOrder != nil {
+		debate.QueueOrder = *updates.QueueOrder
+	}
+	if updates.MaxTurnSeconds != nil {
+		debate.MaxTurnSeconds = *updates.MaxTurnSeconds // Turns already under way keep their limit
+	}
+	if err := service.ValidateQueueSettings(debate.QueueOrder, debate.MaxTurnSeconds); err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
 	if err := h.repo.Update(debate); err != nil {
-		Error(w, http.StatusInternalServerError, err.Error())
+		UpdateError(w, err)
 		return
 	}
 
@@ -426,22 +512,13 @@ func (h *DebateHandlers) Update(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// Broadcast debate status change to all connected clients
-	if oldStatus != debate.Status {
-		payload, _ := json.Marshal(map[string]interface{}{
-			"type":      "debate:status_changed",
-			"debateId":  debate.ID,
-			"status":    debate.Status,
-			"oldStatus": oldStatus,
-		})
-
-		h.hub.Broadcast <- service.Message{
-			RoomID:  debate.ID,
-			Payload: payload,
-		}
-		log.Printf("[DEBUG] Broadcasting debate status change: %s -> %s for debate %s", oldStatus, debate.Status, debate.ID)
+	// Announce a debate the host started or ended early, and move its timer
+	h.scheduler.Changed(debate, oldStatus)
+	if queueChanged {
+		h.queue.Changed(debate.ID)
 	}
 
+	SetETag(w, debate.Version)
 	JSON(w, http.StatusOK, debate)
 }
 
@@ -455,10 +532,17 @@ func (h *DebateHandlers) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if debate.HostID != r.Context().Value("userID").(string) {
+		Error(w, http.StatusForbidden, "Only the debate host can delete the debate")
+		return
+	}
+
 	if err := h.repo.Delete(id); err != nil {
 		Error(w, http.StatusInternalServerError, err.Error())
 		return
 	}
+	h.scheduler.Cancel(id)
+	h.queue.CancelDebate(id)
 
 	// Refund hosting limit if debate was scheduled (not started yet)
 	if debate.Status == "SCHEDULED" {
@@ -474,9 +558,10 @@ func (h *DebateHandlers) Delete(w http.ResponseWriter, r *http.Request) {
 func (h *DebateHandlers) JoinDebate(w http.ResponseWriter, r *http.Request) {
 	debateID := chi.URLParam(r, "id")
 
+	userID := r.Context().Value("userID").(string)
+
 	var req struct {
-		UserID string `json:"userId"`
-		Side   string `json:"side"`
+		Side string `json:"side"`
 	}
 
 	log.Printf("[JoinDebate] ENTRY: debateID=%s", debateID)
@@ -487,13 +572,7 @@ func (h *DebateHandlers) JoinDebate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	log.Printf("[JoinDebate] Request decoded: userId=%s, side=%s", req.UserID, req.Side)
-
-	if err := ValidateRequired(req.UserID, "userId"); err != nil {
-		log.Printf("[JoinDebate] ERROR: Missing userId: %v", err)
-		Error(w, http.StatusBadRequest, err.Error())
-		return
-	}
+	log.Printf("[JoinDebate] Request decoded: userId=%s, side=%s", userID, req.Side)
 
 	if req.Side != "agree" && req.Side != "disagree" && req.Side != "neutral" {
 		log.Printf("[JoinDebate] ERROR: Invalid side: %s", req.Side)
@@ -510,6 +589,12 @@ func (h *DebateHandlers) JoinDebate(w http.ResponseWriter, r *http.Request) {
 	}
 	log.Printf("[JoinDebate] Debate found: id=%s, status=%s", debate.ID, debate.Status)
 
+	if err := h.access.CanJoinDebate(debate, userID); err != nil {
+		log.Printf("[JoinDebate] ERROR: %s may not join %s: %v", userID, debateID, err)
+		Error(w, http.StatusForbidden, err.Error())
+		return
+	}
+
 	// Check ALL participants (including those who left) to determine if user should get points
 	// We need to check the full history to avoid awarding points multiple times for the same debate
 	allParticipants, err := h.repo.GetAllParticipants(debateID)
@@ -519,7 +604,7 @@ func (h *DebateHandlers) JoinDebate(w http.ResponseWriter, r *http.Request) {
 	} else {
 		log.Printf("[JoinDebate] Total participants (including left): %d", len(allParticipants))
 		for _, p := range allParticipants {
-			if p.UserID == req.UserID {
+			if p.UserID == userID {
 				log.Printf("[JoinDebate] User has participated before: userId=%s, side=%s, leftAt=%v", p.UserID, p.Side, p.LeftAt)
 				// User participated in this debate before (even if they left and are rejoining)
 				// Don't award points again for the same debate
@@ -531,19 +616,19 @@ func (h *DebateHandlers) JoinDebate(w http.ResponseWriter, r *http.Request) {
 
 	participant := &models.DebateParticipant{
 		DebateID:      debateID,
-		UserID:        req.UserID,
+		UserID:        userID,
 		Side:          req.Side,
 		IsSelfMuted:   true,  // Start muted by default
 		IsMutedByHost: false, // Host hasn't muted them
 		JoinedAt:      time.Now(),
 	}
 
-	log.Printf("[JoinDebate] User %s joining debate %s (side: %s)", req.UserID, debateID, participant.Side)
+	log.Printf("[JoinDebate] User %s joining debate %s (side: %s)", userID, debateID, participant.Side)
 
 	// IMMEDIATELY broadcast user-joined signal BEFORE adding to repo (fastest possible)
 	userJoinedPayload, _ := json.Marshal(map[string]interface{}{
 		"type":   "user-joined",
-		"userId": req.UserID,
+		"userId": userID,
 		"side":   req.Side,
 	})
 
@@ -561,6 +646,11 @@ func (h *DebateHandlers) JoinDebate(w http.ResponseWriter, r *http.Request) {
 	}
 	log.Printf("[JoinDebate] AddParticipant succeeded")
 
+	// Joining mid-round out of turn means joining muted
+	if _, err := h.scheduler.EnforceTurn(debate, userID); err != nil {
+		log.Printf("[JoinDebate] WARNING: Failed to enforce the current round: %v", err)
+	}
+
 	// Fetch updated participants list
 	participants, err := h.repo.GetParticipants(debateID)
 	if err != nil {
@@ -626,14 +716,14 @@ func (h *DebateHandlers) JoinDebate(w http.ResponseWriter, r *http.Request) {
 	// Only award points if this is the first time joining this specific debate
 	if !userAlreadyParticipated {
 		// First time joining this debate - award points
-		if err := h.pointsService.UpdateUserPoints(req.UserID, service.ActionDebateJoin); err != nil {
+		if err := h.pointsService.UpdateUserPoints(userID, service.ActionDebateJoin); err != nil {
 			log.Printf("[JoinDebate] WARNING: Failed to award points for joining debate: %v", err)
 			// Don't fail the join if points award fails
 		} else {
-			log.Printf("[JoinDebate] ✅ Awarded 5 points to user %s for joining debate %s", req.UserID, debateID)
+			log.Printf("[JoinDebate] ✅ Awarded 5 points to user %s for joining debate %s", userID, debateID)
 		}
 	} else {
-		log.Printf("[JoinDebate] User %s already participated in debate %s, skipping points award", req.UserID, debateID)
+		log.Printf("[JoinDebate] User %s already participated in debate %s, skipping points award", userID, debateID)
 	}
 
 	// Return full participants list in response
@@ -646,25 +736,9 @@ func (h *DebateHandlers) JoinDebate(w http.ResponseWriter, r *http.Request) {
 func (h *DebateHandlers) LeaveDebate(w http.ResponseWriter, r *http.Request) {
 	debateID := chi.URLParam(r, "id")
 
-	var req struct {
-		UserID string `json:"userId"`
-	}
-
-	log.Printf("[LeaveDebate] ENTRY: debateID=%s", debateID)
-
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		log.Printf("[LeaveDebate] ERROR: Invalid request body: %v", err)
-		Error(w, http.StatusBadRequest, "Invalid request body")
-		return
-	}
-
-	log.Printf("[LeaveDebate] Request decoded: userId=%s", req.UserID)
+	userID := r.Context().Value("userID").(string)
 
-	if err := ValidateRequired(req.UserID, "userId"); err != nil {
-		log.Printf("[LeaveDebate] ERROR: Missing userId: %v", err)
-		Error(w, http.StatusBadRequest, err.Error())
-		return
-	}
+	log.Printf("[LeaveDebate] ENTRY: debateID=%s, userId=%s", debateID, userID)
 
 	// Instead of removing, mark as left (set LeftAt) to allow rejoin
 	// Get active participants first (those who haven't left)
@@ -672,7 +746,7 @@ func (h *DebateHandlers) LeaveDebate(w http.ResponseWriter, r *http.Request) {
 	var foundParticipant *models.DebateParticipant
 	if err == nil {
 		for _, p := range activeParticipants {
-			if p.UserID == req.UserID {
+			if p.UserID == userID {
 				foundParticipant = p
 				break
 			}
@@ -686,36 +760,44 @@ func (h *DebateHandlers) LeaveDebate(w http.ResponseWriter, r *http.Request) {
 		if err := h.repo.UpdateParticipant(foundParticipant); err != nil {
 			log.Printf("[LeaveDebate] WARNING: Failed to update participant (will try remove): %v", err)
 			// Fallback to remove if update fails
-			if err := h.repo.RemoveParticipant(debateID, req.UserID); err != nil {
+			if err := h.repo.RemoveParticipant(debateID, userID); err != nil {
 				log.Printf("[LeaveDebate] ERROR: RemoveParticipant failed: %v", err)
 				Error(w, http.StatusNotFound, err.Error())
 				return
 			}
-			log.Printf("[LeaveDebate] Removed participant (fallback): userId=%s, debateID=%s", req.UserID, debateID)
+			log.Printf("[LeaveDebate] Removed participant (fallback): userId=%s, debateID=%s", userID, debateID)
 		} else {
-			log.Printf("[LeaveDebate] Marked participant as left: userId=%s, debateID=%s", req.UserID, debateID)
-			// Update debate counts
-			debate, err := h.repo.GetByID(debateID)
-			if err == nil {
-				side := strings.ToLower(strings.TrimSpace(foundParticipant.Side))
+			log.Printf("[LeaveDebate] Marked participant as left: userId=%s, debateID=%s", userID, debateID)
+			// Update debate counts, re-reading the debate if a join or vote saved it first
+			side := strings.ToLower(strings.TrimSpace(foundParticipant.Side))
+			err := repository.RetryOnConflict(maxConflictRetries, func() error {
+				debate, err := h.repo.GetByID(debateID)
+				if err != nil {
+					return err
+				}
 				if side == "agree" && debate.AgreeCount > 0 {
 					debate.AgreeCount--
 					log.Printf("[LeaveDebate] Decremented AgreeCount: debateID=%s, new count=%d", debateID, debate.AgreeCount)
 				} else if side == "disagree" && debate.DisagreeCount > 0 {
 					debate.DisagreeCount--
 					log.Printf("[LeaveDebate] Decremented DisagreeCount: debateID=%s, new count=%d", debateID, debate.DisagreeCount)
+				} else {
+					return nil
 				}
-				h.repo.Update(debate)
+				return h.repo.Update(debate)
+			})
+			if err != nil {
+				log.Printf("[LeaveDebate] ERROR: Failed to update debate counts: %v", err)
 			}
 		}
 	} else {
 		// Participant not found in active list - they might have already left or been removed
 		// Try remove anyway (idempotent operation)
-		if err := h.repo.RemoveParticipant(debateID, req.UserID); err != nil {
+		if err := h.repo.RemoveParticipant(debateID, userID); err != nil {
 			log.Printf("[LeaveDebate] WARNING: Participant not found in active list and RemoveParticipant failed: %v (this is OK if they already left)", err)
 			// Don't return error - user might have already left
 		} else {
-			log.Printf("[LeaveDebate] Removed participant: userId=%s, debateID=%s", req.UserID, debateID)
+			log.Printf("[LeaveDebate] Removed participant: userId=%s, debateID=%s", userID, debateID)
 		}
 	}
 
@@ -862,6 +944,10 @@ func (h *DebateHandlers) UpdateParticipant(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	if _, ok := h.hostedDebate(w, debateID, r.Context().Value("userID").(string)); !ok {
+		return
+	}
+
 	// Get existing participant
 	participants, err := h.repo.GetParticipants(debateID)
 	if err != nil {
@@ -899,9 +985,10 @@ func (h *DebateHandlers) UpdateParticipant(w http.ResponseWriter, r *http.Reques
 func (h *DebateHandlers) UpdateSelfMute(w http.ResponseWriter, r *http.Request) {
 	debateID := chi.URLParam(r, "id")
 
+	userID := r.Context().Value("userID").(string)
+
 	var req struct {
-		UserID      string `json:"userId"`
-		IsSelfMuted bool   `json:"isSelfMuted"`
+		IsSelfMuted bool `json:"isSelfMuted"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -909,11 +996,6 @@ func (h *DebateHandlers) UpdateSelfMute(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if err := ValidateRequired(req.UserID, "userId"); err != nil {
-		Error(w, http.StatusBadRequest, err.Error())
-		return
-	}
-
 	// Get existing participant
 	participants, err := h.repo.GetParticipants(debateID)
 	if err != nil {
@@ -923,7 +1005,7 @@ func (h *DebateHandlers) UpdateSelfMute(w http.ResponseWriter, r *http.Request)
 
 	var participant *models.DebateParticipant
 	for _, p := range participants {
-		if p.UserID == req.UserID {
+		if p.UserID == userID {
 			participant = p
 			break
 		}
@@ -953,6 +1035,49 @@ func (h *DebateHandlers) UpdateSelfMute(w http.ResponseWriter, r *http.Request)
 	Success(w, "Self-mute updated successfully")
 }
 
+// Unlock lets the host open a locked debate to everyone before its unlock time
+func (h *DebateHandlers) Unlock(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(string)
+	debate, ok := h.hostedDebate(w, chi.URLParam(r, "id"), userID)
+	if !ok {
+		return
+	}
+	if !debate.IsLocked {
+		Error(w, http.StatusConflict, "Debate is already open to everyone")
+		return
+	}
+
+	// The scheduler opens it and tells the room
+	now := time.Now()
+	debate.UnlockAt = &now
+	if err := h.repo.Update(debate); err != nil {
+		UpdateError(w, err)
+		return
+	}
+	h.scheduler.Changed(debate, debate.Status)
+
+	debate, err := h.repo.GetByID(debate.ID)
+	if err != nil {
+		Error(w, http.StatusNotFound, "Debate not found")
+		return
+	}
+	JSON(w, http.StatusOK, debate)
+}
+
+// hostedDebate loads a debate and writes 404 or 403 unless userID hosts it
+func (h *DebateHandlers) hostedDebate(w http.ResponseWriter, debateID, userID string) (*models.Debate, bool) {
+	debate, err := h.repo.GetByID(debateID)
+	if err != nil {
+		Error(w, http.StatusNotFound, "Debate not found")
+		return nil, false
+	}
+	if debate.HostID != userID {
+		Error(w, http.StatusForbidden, "Only the debate host can do this")
+		return nil, false
+	}
+	return debate, true
+}
+
 // Helper function to broadcast participants update
 func (h *DebateHandlers) broadcastParticipantsUpdate(debateID string) {
 	participants, err := h.repo.GetParticipants(debateID)
@@ -1009,53 +1134,97 @@ func (h *DebateHandlers) broadcastParticipantsUpdate(debateID string) {
 }
 
 // Speak request handlers
+
+// speakRequestError writes the response for a speaker queue error
+func speakRequestError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrDebateNotFound), errors.Is(err, service.ErrSpeakRequestNotFound):
+		Error(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrNotInDebate):
+		Error(w, http.StatusForbidden, err.Error())
+	case errors.Is(err, service.ErrAlreadyQueued), errors.Is(err, service.ErrNotWaiting), errors.Is(err, service.ErrNotSpeaking),
+		errors.Is(err, service.ErrOutOfTurn):
+		Error(w, http.StatusConflict, err.Error())
+	case errors.Is(err, service.ErrQueueOrder):
+		Error(w, http.StatusBadRequest, err.Error())
+	default:
+		Error(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
 func (h *DebateHandlers) CreateSpeakRequest(w http.ResponseWriter, r *http.Request) {
 	debateID := chi.URLParam(r, "id")
 
-	var req struct {
-		UserID string `json:"userId"`
-	}
+	userID := r.Context().Value("userID").(string)
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		Error(w, http.StatusBadRequest, "Invalid request body")
+	speakRequest, err := h.queue.Request(debateID, userID)
+	if err != nil {
+		speakRequestError(w, err)
 		return
 	}
 
-	if err := ValidateRequired(req.UserID, "userId"); err != nil {
-		Error(w, http.StatusBadRequest, err.Error())
+	Created(w, speakRequest)
+}
+
+func (h *DebateHandlers) GetSpeakRequests(w http.ResponseWriter, r *http.Request) {
+	debateID := chi.URLParam(r, "id")
+
+	requests, err := h.repo.GetSpeakRequests(debateID)
+	if err != nil {
+		Error(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	speakRequest := &models.SpeakRequest{
-		ID:        uuid.New().String(),
-		DebateID:  debateID,
-		UserID:    req.UserID,
-		Status:    "pending",
-		CreatedAt: time.Now(),
-	}
+	JSON(w, http.StatusOK, requests)
+}
 
-	if err := h.repo.CreateSpeakRequest(speakRequest); err != nil {
-		Error(w, http.StatusInternalServerError, err.Error())
+// GetSpeakerQueue returns who has the floor and who is up next
+func (h *DebateHandlers) GetSpeakerQueue(w http.ResponseWriter, r *http.Request) {
+	queue, err := h.queue.Get(chi.URLParam(r, "id"))
+	if err != nil {
+		speakRequestError(w, err)
 		return
 	}
 
-	Created(w, speakRequest)
+	JSON(w, http.StatusOK, queue)
 }
 
-func (h *DebateHandlers) GetSpeakRequests(w http.ResponseWriter, r *http.Request) {
+// ReorderSpeakerQueue lets the host put the waiting requests in a new order
+func (h *DebateHandlers) ReorderSpeakerQueue(w http.ResponseWriter, r *http.Request) {
 	debateID := chi.URLParam(r, "id")
 
-	requests, err := h.repo.GetSpeakRequests(debateID)
+	var req struct {
+		RequestIDs []string `json:"requestIds"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if _, ok := h.hostedDebate(w, debateID, r.Context().Value("userID").(string)); !ok {
+		return
+	}
+
+	if err := h.queue.Reorder(debateID, req.RequestIDs); err != nil {
+		speakRequestError(w, err)
+		return
+	}
+
+	queue, err := h.queue.Get(debateID)
 	if err != nil {
-		Error(w, http.StatusInternalServerError, err.Error())
+		speakRequestError(w, err)
 		return
 	}
 
-	JSON(w, http.StatusOK, requests)
+	JSON(w, http.StatusOK, queue)
 }
 
+// UpdateSpeakRequest approves or denies a waiting request, or ends a turn.
+// Only the host approves and denies; a speaker may also end their own turn.
 func (h *DebateHandlers) UpdateSpeakRequest(w http.ResponseWriter, r *http.Request) {
 	requestID := chi.URLParam(r, "requestId")
+	userID := r.Context().Value("userID").(string)
 
 	var req struct {
 		Status string `json:"status"`
@@ -1066,30 +1235,70 @@ func (h *DebateHandlers) UpdateSpeakRequest(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	if req.Status != "approved" && req.Status != "denied" {
-		Error(w, http.StatusBadRequest, "Status must be 'approved' or 'denied'")
+	if req.Status != "approved" && req.Status != "denied" && req.Status != "done" {
+		Error(w, http.StatusBadRequest, "Status must be 'approved', 'denied' or 'done'")
 		return
 	}
 
-	speakRequest := &models.SpeakRequest{
-		ID:     requestID,
-		Status: req.Status,
+	speakRequest, err := h.repo.GetSpeakRequest(requestID)
+	if err != nil {
+		Error(w, http.StatusNotFound, "Speak request not found")
+		return
+	}
+	if req.Status != "done" || speakRequest.UserID != userID {
+		if _, ok := h.hostedDebate(w, speakRequest.DebateID, userID); !ok {
+			return
+		}
 	}
 
-	if err := h.repo.UpdateSpeakRequest(speakRequest); err != nil {
-		Error(w, http.StatusInternalServerError, err.Error())
+	switch req.Status {
+	case "approved":
+		speakRequest, err = h.queue.Approve(requestID)
+	case "denied":
+		speakRequest, err = h.queue.Deny(requestID)
+	default:
+		speakRequest, err = h.queue.EndTurn(requestID)
+	}
+	if err != nil {
+		speakRequestError(w, err)
 		return
 	}
 
-	Success(w, "Speak request updated")
+	JSON(w, http.StatusOK, speakRequest)
 }
 
-// Award points for winning debate (called when debate ends)
-func (h *DebateHandlers) AwardDebateWin(w http.ResponseWriter, r *http.Request) {
-	_ = chi.URLParam(r, "id") // debateID available if needed for validation
+func (h *DebateHandlers) DeleteSpeakRequest(w http.ResponseWriter, r *http.Request) {
+	requestID := chi.URLParam(r, "requestId")
+	userID := r.Context().Value("userID").(string)
+
+	speakRequest, err := h.repo.GetSpeakRequest(requestID)
+	if err != nil {
+		Error(w, http.StatusNotFound, "Speak request not found")
+		return
+	}
+	// Users withdraw their own requests, the host dismisses anyone's
+	if speakRequest.UserID != userID {
+		if _, ok := h.hostedDebate(w, speakRequest.DebateID, userID); !ok {
+			return
+		}
+	}
+
+	if err := h.queue.Withdraw(requestID); err != nil {
+		speakRequestError(w, err)
+		return
+	}
+
+	NoContent(w)
+}
+
+// Vote handles POST /api/debates/{id}/votes, a vote in the pre or post poll
+func (h *DebateHandlers) Vote(w http.ResponseWriter, r *http.Request) {
+	debateID := chi.URLParam(r, "id")
+	userID := r.Context().Value("userID").(string)
 
 	var req struct {
-		WinnerID string `json:"winnerId"`
+		Phase string `json:"phase"` // "pre" or "post"
+		Side  string `json:"side"`  // "agree", "disagree" or "undecided"
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -1097,29 +1306,46 @@ func (h *DebateHandlers) AwardDebateWin(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if err := ValidateRequired(req.WinnerID, "winnerId"); err != nil {
-		Error(w, http.StatusBadRequest, err.Error())
+	if err := h.poll.Vote(debateID, userID, req.Phase, req.Side); err != nil {
+		switch {
+		case errors.Is(err, service.ErrDebateNotFound):
+			Error(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, service.ErrInvalidPollPhase), errors.Is(err, service.ErrInvalidVote):
+			Error(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, service.ErrBotVote), errors.Is(err, service.ErrRoomForbidden), errors.Is(err, service.ErrDebateLocked):
+			Error(w, http.StatusForbidden, err.Error())
+		case errors.Is(err, service.ErrPollClosed), errors.Is(err, repository.ErrAlreadyVoted):
+			Error(w, http.StatusConflict, err.Error())
+		default:
+			Error(w, http.StatusInternalServerError, err.Error())
+		}
 		return
 	}
 
-	// Award points for winning debate
-	if err := h.pointsService.UpdateUserPoints(req.WinnerID, service.ActionDebateWin); err != nil {
-		Error(w, http.StatusInternalServerError, "Failed to award points")
+	poll, err := h.poll.Poll(debateID, userID)
+	if err != nil {
+		Error(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	Success(w, "Points awarded for winning debate")
+	Created(w, poll)
 }
 
-func (h *DebateHandlers) DeleteSpeakRequest(w http.ResponseWriter, r *http.Request) {
-	requestID := chi.URLParam(r, "requestId")
+// Poll handles GET /api/debates/{id}/poll, the count of both polls so far
+func (h *DebateHandlers) Poll(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value("userID").(string)
 
-	if err := h.repo.DeleteSpeakRequest(requestID); err != nil {
-		Error(w, http.StatusInternalServerError, err.Error())
+	poll, err := h.poll.Poll(chi.URLParam(r, "id"), userID)
+	if err != nil {
+		if errors.Is(err, service.ErrDebateNotFound) {
+			Error(w, http.StatusNotFound, err.Error())
+		} else {
+			Error(w, http.StatusInternalServerError, err.Error())
+		}
 		return
 	}
 
-	NoContent(w)
+	JSON(w, http.StatusOK, poll)
 }
 
 // ClearAllDebates clears all debates, participants, and speak requests
@@ -1133,6 +1359,8 @@ func (h *DebateHandlers) ClearAllDebates(w http.ResponseWriter, r *http.Request)
 		Error(w, http.StatusInternalServerError, err.Error())
 		return
 	}
+	h.scheduler.CancelAll()
+	h.queue.CancelAll()
 
 	log.Printf("[DEBUG] All debates cleared")
 	JSON(w, http.StatusOK, map[string]string{"message": "All debates cleared successfully"})
@@ -47,6 +47,12 @@ func (h *DebateHandlers) handleJoinRoom(debateID, userID string) {
 		return
 	}
 
+	// Users kept out of a locked debate may listen in the room, but not join
+	if err := h.access.CanJoinDebate(debate, userID); err != nil {
+		log.Printf("[WARN] %s may not join debate %s: %v", userID, debateID, err)
+		return
+	}
+
 	// Check if participant already exists
 	participants, err := h.repo.GetParticipants(debateID)
 	if err != nil {
@@ -101,6 +107,10 @@ func (h *DebateHandlers) handleJoinRoom(debateID, userID string) {
 		}
 	}
 
+	if _, err := h.scheduler.EnforceTurn(debate, userID); err != nil {
+		log.Printf("[ERROR] Failed to enforce the current round: %v", err)
+	}
+
 	// Broadcast updated participants list
 	h.broadcastParticipantsUpdate(debateID)
 }
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/service"
+)
+
+// DevHandlers serve development-only helpers. Their routes are only mounted in
+// development, and the service refuses everywhere else too.
+type DevHandlers struct {
+	impersonation *service.ImpersonationService
+}
+
+func NewDevHandlers(impersonation *service.ImpersonationService) *DevHandlers {
+	return &DevHandlers{impersonation: impersonation}
+}
+
+// Impersonate handles POST /api/dev/impersonate, signing in as any user
+func (h *DevHandlers) Impersonate(w http.ResponseWriter, r *http.Request) {
+	var req models.ImpersonateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := ValidateRequired(req.User, "user"); err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user, tokens, err := h.impersonation.Impersonate(req.User, r.UserAgent(), clientIP(r))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrImpersonationDisabled):
+			Error(w, http.StatusNotFound, "Not found")
+		case errors.Is(err, service.ErrUserNotFound):
+			Error(w, http.StatusNotFound, "User not found")
+		default:
+			Error(w, http.StatusInternalServerError, "Failed to generate token")
+		}
+		return
+	}
+
+	JSON(w, http.StatusOK, models.AuthResponse{
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    tokens.ExpiresIn,
+		User:         user,
+	})
+}
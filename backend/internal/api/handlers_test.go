@@ -0,0 +1,318 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
+	"github.com/yourusername/v-backend/internal/repository/memory"
+	"github.com/yourusername/v-backend/internal/service"
+)
+
+// testServer serves the user and post routes the way main.go does, over
+// in-memory repositories. alice and bob are users, mod is a moderator and root
+// is an admin.
+type testServer struct {
+	repos     *repository.Repositories
+	sessions  *service.SessionService
+	apiKeys   *service.APIKeyService
+	scheduler *service.DebateScheduler
+	queue     *service.SpeakerQueueService
+	router    http.Handler
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+	repos := memory.NewRepositories()
+	for _, user := range []*models.User{
+		{ID: "alice", Handle: "alice", Name: "Alice", Email: "alice@example.com", Role: models.PlatformRoleUser},
+		{ID: "bob", Handle: "bob", Name: "Bob", Email: "bob@example.com", Role: models.PlatformRoleUser},
+		{ID: "mod", Handle: "mod", Name: "Mod", Email: "mod@example.com", Role: models.PlatformRoleModerator},
+		{ID: "root", Handle: "root", Name: "Root", Email: "root@example.com", Role: models.PlatformRoleAdmin},
+	} {
+		if err := repos.User.Create(user); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := &testServer{
+		repos:    repos,
+		sessions: service.NewSessionService(repos.Session, repos.User, time.Hour),
+		apiKeys:  service.NewAPIKeyService(repos.APIKey, repos.User),
+	}
+	authMiddleware := NewAuthMiddleware(s.sessions, s.apiKeys)
+	pointsService := service.NewPointsService(repos.User)
+	userHandlers := NewUserHandlers(repos.User)
+	postHandlers := NewPostHandlers(repos.Post, repos.User, repos.Notification, repos.Analytics, pointsService,
+		service.NewModerationService(repos.Post, repos.User, pointsService), service.NewTranslationService("", ""),
+		repos.Hashtag, repos.Community, repos.UnitOfWork, time.Hour)
+
+	roomAccess := service.NewDebateRoomAccess(repos.Debate, repos.User, repos.Community)
+	hub := service.NewHub(roomAccess)
+	go hub.Run()
+	debatePoll := service.NewDebatePollService(repos.Debate, repos.User, roomAccess, pointsService)
+	s.scheduler = service.NewDebateScheduler(repos.Debate, repos.DebateStats, debatePoll, hub)
+	s.queue = service.NewSpeakerQueueService(repos.Debate, hub)
+	s.scheduler.SetSpeakerQueue(s.queue)
+	debateHandlers := NewDebateHandlers(repos.Debate, repos.User, pointsService, hub, s.scheduler, s.queue, debatePoll, roomAccess)
+
+	r := chi.NewRouter()
+	r.Route("/api/users", func(r chi.Router) {
+		r.Use(APIKeyScopes(models.ScopeReadUsers, ""))
+		r.Get("/{id}", userHandlers.GetByID)
+		r.Group(func(r chi.Router) {
+			r.Use(authMiddleware.RequireAuth)
+			r.Put("/{id}", userHandlers.Update)
+			r.Delete("/{id}", userHandlers.Delete)
+			r.With(RequireRole(models.PlatformRoleAdmin)).Post("/", userHandlers.Create)
+		})
+	})
+	r.Route("/api/posts", func(r chi.Router) {
+		r.Use(APIKeyScopes(models.ScopeReadPosts, models.ScopeWritePosts))
+		r.With(authMiddleware.OptionalAuth).Get("/{id}", postHandlers.Get)
+		r.Group(func(r chi.Router) {
+			r.Use(authMiddleware.RequireAuth)
+			r.Put("/{id}", postHandlers.Update)
+			r.Delete("/{id}", postHandlers.Delete)
+			r.Post("/{id}/react", postHandlers.React)
+			r.Delete("/{id}/react", postHandlers.Unreact)
+		})
+	})
+	r.Route("/api/debates", func(r chi.Router) {
+		r.Group(func(r chi.Router) {
+			r.Use(authMiddleware.RequireAuth)
+			r.With(RequireRole(models.PlatformRoleAdmin)).Delete("/clear-all", debateHandlers.ClearAllDebates)
+			r.Put("/{id}", debateHandlers.Update)
+			r.Delete("/{id}", debateHandlers.Delete)
+		})
+	})
+	s.router = r
+	return s
+}
+
+// bearer signs userID in and returns their Authorization header
+func (s *testServer) bearer(t *testing.T, userID string) string {
+	t.Helper()
+	user, err := s.repos.User.GetByID(userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokens, err := s.sessions.Start(user, "test", "127.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return "Bearer " + tokens.AccessToken
+}
+
+// apiKey issues userID a key with scopes and returns its Authorization header
+func (s *testServer) apiKey(t *testing.T, userID string, scopes ...models.APIScope) string {
+	t.Helper()
+	created, err := s.apiKeys.Create(userID, userID, models.CreateAPIKeyRequest{Name: "test", Scopes: scopes})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return "ApiKey " + created.Key
+}
+
+// do sends a request with an optional JSON body and returns the status code
+func (s *testServer) do(t *testing.T, method, path, authorization string, body any) int {
+	t.Helper()
+	var payload bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&payload).Encode(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r := httptest.NewRequest(method, path, &payload)
+	if authorization != "" {
+		r.Header.Set("Authorization", authorization)
+	}
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, r)
+	return w.Code
+}
+
+func TestUserHandlersOwnership(t *testing.T) {
+	s := newTestServer(t)
+	alice, bob, root := s.bearer(t, "alice"), s.bearer(t, "bob"), s.bearer(t, "root")
+
+	for _, tc := range []struct {
+		name, method, path, auth string
+		body                     any
+		want                     int
+	}{
+		{"edit without signing in", http.MethodPut, "/api/users/alice", "", map[string]string{"name": "Mallory"}, http.StatusUnauthorized},
+		{"edit someone else", http.MethodPut, "/api/users/alice", bob, map[string]string{"name": "Mallory"}, http.StatusForbidden},
+		{"delete someone else", http.MethodDelete, "/api/users/alice", bob, nil, http.StatusForbidden},
+		{"edit yourself", http.MethodPut, "/api/users/alice", alice, map[string]string{"name": "Alice A."}, http.StatusOK},
+		{"admin deletes someone", http.MethodDelete, "/api/users/bob", root, nil, http.StatusNoContent},
+	} {
+		if got := s.do(t, tc.method, tc.path, tc.auth, tc.body); got != tc.want {
+			t.Errorf("%s: status %d, want %d", tc.name, got, tc.want)
+		}
+	}
+
+	if user, _ := s.repos.User.GetByID("alice"); user == nil || user.Name != "Alice A." {
+		t.Errorf("alice = %+v, want only her own edit applied", user)
+	}
+}
+
+func TestCreateUserRequiresAdmin(t *testing.T) {
+	s := newTestServer(t)
+	body := map[string]string{"name": "Carol", "handle": "carol", "email": "carol@example.com"}
+
+	if got := s.do(t, http.MethodPost, "/api/users", "", body); got != http.StatusUnauthorized {
+		t.Errorf("without signing in: status %d, want 401", got)
+	}
+	if got := s.do(t, http.MethodPost, "/api/users", s.bearer(t, "alice"), body); got != http.StatusForbidden {
+		t.Errorf("as a user: status %d, want 403", got)
+	}
+	if _, err := s.repos.User.GetByHandle("carol"); err == nil {
+		t.Fatal("user created without the admin role")
+	}
+	if got := s.do(t, http.MethodPost, "/api/users", s.bearer(t, "root"), body); got != http.StatusCreated {
+		t.Errorf("as an admin: status %d, want 201", got)
+	}
+}
+
+func TestPostHandlersOwnership(t *testing.T) {
+	s := newTestServer(t)
+	if err := s.repos.Post.Create(&models.Post{ID: "p1", AuthorID: "alice", Content: "hello"}); err != nil {
+		t.Fatal(err)
+	}
+	alice, bob := s.bearer(t, "alice"), s.bearer(t, "bob")
+
+	if got := s.do(t, http.MethodPut, "/api/posts/p1", bob, map[string]string{"content": "defaced"}); got != http.StatusForbidden {
+		t.Errorf("edit someone else's post: status %d, want 403", got)
+	}
+	if got := s.do(t, http.MethodDelete, "/api/posts/p1", bob, nil); got != http.StatusForbidden {
+		t.Errorf("delete someone else's post: status %d, want 403", got)
+	}
+	if post, err := s.repos.Post.GetByID("p1"); err != nil || post.Content != "hello" {
+		t.Fatalf("post = %+v, %v; want it untouched", post, err)
+	}
+
+	if got := s.do(t, http.MethodPut, "/api/posts/p1", alice, map[string]string{"content": "hello again"}); got != http.StatusOK {
+		t.Errorf("edit own post: status %d, want 200", got)
+	}
+	if got := s.do(t, http.MethodDelete, "/api/posts/p1", alice, nil); got != http.StatusNoContent {
+		t.Errorf("delete own post: status %d, want 204", got)
+	}
+	if _, err := s.repos.Post.GetByID("p1"); err == nil {
+		t.Error("post survived its author deleting it")
+	}
+}
+
+func TestModeratorsRemovePosts(t *testing.T) {
+	s := newTestServer(t)
+	if err := s.repos.Post.Create(&models.Post{ID: "p1", AuthorID: "alice", Content: "spam"}); err != nil {
+		t.Fatal(err)
+	}
+	mod := s.bearer(t, "mod")
+
+	// Moderators remove posts but do not rewrite them
+	if got := s.do(t, http.MethodPut, "/api/posts/p1", mod, map[string]string{"content": "edited"}); got != http.StatusForbidden {
+		t.Errorf("edit as a moderator: status %d, want 403", got)
+	}
+	if got := s.do(t, http.MethodDelete, "/api/posts/p1", mod, nil); got != http.StatusNoContent {
+		t.Errorf("delete as a moderator: status %d, want 204", got)
+	}
+	if _, err := s.repos.Post.GetByID("p1"); err == nil {
+		t.Error("post survived a moderator removing it")
+	}
+}
+
+func TestReactionsActAsCaller(t *testing.T) {
+	s := newTestServer(t)
+	if err := s.repos.Post.Create(&models.Post{ID: "p1", AuthorID: "alice", Content: "hello"}); err != nil {
+		t.Fatal(err)
+	}
+	alice, bob := s.bearer(t, "alice"), s.bearer(t, "bob")
+
+	// A user ID in the body is ignored
+	if got := s.do(t, http.MethodPost, "/api/posts/p1/react", bob, map[string]string{"userId": "alice"}); got != http.StatusOK {
+		t.Fatalf("react: status %d, want 200", got)
+	}
+	if reacted, _ := s.repos.Post.HasReacted("bob", "p1", nil); !reacted {
+		t.Error("reaction not recorded for bob")
+	}
+	if reacted, _ := s.repos.Post.HasReacted("alice", "p1", nil); reacted {
+		t.Error("reaction recorded for alice")
+	}
+
+	// Nor can anyone take back someone else's reaction
+	if got := s.do(t, http.MethodDelete, "/api/posts/p1/react", alice, map[string]string{"userId": "bob"}); got != http.StatusNotFound {
+		t.Errorf("unreact as alice: status %d, want 404", got)
+	}
+	if reacted, _ := s.repos.Post.HasReacted("bob", "p1", nil); !reacted {
+		t.Error("bob's reaction removed by alice")
+	}
+}
+
+func TestAPIKeyScopes(t *testing.T) {
+	s := newTestServer(t)
+	if err := s.repos.Post.Create(&models.Post{ID: "p1", AuthorID: "alice", Content: "hello"}); err != nil {
+		t.Fatal(err)
+	}
+	readPosts := s.apiKey(t, "alice", models.ScopeReadPosts)
+	writePosts := s.apiKey(t, "alice", models.ScopeWritePosts)
+	readUsers := s.apiKey(t, "alice", models.ScopeReadUsers)
+
+	for _, tc := range []struct {
+		name, method, path, auth string
+		body                     any
+		want                     int
+	}{
+		{"write without the scope", http.MethodPut, "/api/posts/p1", readPosts, map[string]string{"content": "by key"}, http.StatusForbidden},
+		{"scope of another resource", http.MethodPost, "/api/posts/p1/react", readUsers, nil, http.StatusForbidden},
+		{"route closed to keys", http.MethodPut, "/api/users/alice", writePosts, map[string]string{"name": "Key"}, http.StatusForbidden},
+		{"admin route", http.MethodPost, "/api/users", writePosts, map[string]string{"name": "Carol", "handle": "carol", "email": "carol@example.com"}, http.StatusForbidden},
+		{"write within scope", http.MethodPut, "/api/posts/p1", writePosts, map[string]string{"content": "by key"}, http.StatusOK},
+	} {
+		if got := s.do(t, tc.method, tc.path, tc.auth, tc.body); got != tc.want {
+			t.Errorf("%s: status %d, want %d", tc.name, got, tc.want)
+		}
+	}
+
+	if post, _ := s.repos.Post.GetByID("p1"); post == nil || post.Content != "by key" {
+		t.Errorf("post = %+v, want only the scoped write applied", post)
+	}
+	if user, _ := s.repos.User.GetByID("alice"); user == nil || user.Name != "Alice" {
+		t.Errorf("alice = %+v, want her profile untouched", user)
+	}
+}
+
+func TestDebateStatusOnlyMovesForward(t *testing.T) {
+	s := newTestServer(t)
+	start := time.Now().Add(time.Hour)
+	if err := s.repos.Debate.Create(&models.Debate{ID: "d1", HostID: "alice", Type: "PUBLIC", Status: "SCHEDULED", StartTime: start}); err != nil {
+		t.Fatal(err)
+	}
+	alice := s.bearer(t, "alice")
+
+	for _, tc := range []struct {
+		status string
+		want   int
+	}{
+		{"PAUSED", http.StatusBadRequest},
+		{"ACTIVE", http.StatusOK},
+		{"SCHEDULED", http.StatusConflict},
+		{"ENDED", http.StatusOK},
+		{"ACTIVE", http.StatusConflict},
+		{"ENDED", http.StatusOK},
+	} {
+		if got := s.do(t, http.MethodPut, "/api/debates/d1", alice, map[string]string{"status": tc.status}); got != tc.want {
+			t.Errorf("set status %s: status %d, want %d", tc.status, got, tc.want)
+		}
+	}
+
+	if debate, _ := s.repos.Debate.GetByID("d1"); debate == nil || debate.Status != "ENDED" {
+		t.Errorf("debate = %+v, want it ended", debate)
+	}
+}
@@ -5,13 +5,11 @@ import (
 	"log"
 	"net/http"
 	"regexp"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
-	"github.com/yourusername/v-backend/internal/auth"
 	"github.com/yourusername/v-backend/internal/models"
 	"github.com/yourusername/v-backend/internal/repository"
 	"github.com/yourusername/v-backend/internal/service"
@@ -34,11 +32,12 @@ func NewHashtagHandlers(repo repository.HashtagRepository, userRepo repository.U
 }
 
 func (h *HashtagHandlers) Create(w http.ResponseWriter, r *http.Request) {
+	createdBy := r.Context().Value("userID").(string)
+
 	var req struct {
-		Name      string `json:"name"`
-		Slug      string `json:"slug"`
-		Category  string `json:"category"`
-		CreatedBy string `json:"createdBy"`
+		Name     string `json:"name"`
+		Slug     string `json:"slug"`
+		Category string `json:"category"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -50,10 +49,6 @@ func (h *HashtagHandlers) Create(w http.ResponseWriter, r *http.Request) {
 		Error(w, http.StatusBadRequest, err.Error())
 		return
 	}
-	if err := ValidateRequired(req.CreatedBy, "createdBy"); err != nil {
-		Error(w, http.StatusBadRequest, err.Error())
-		return
-	}
 
 	// Validate Category
 	validCategories := map[string]bool{
@@ -105,7 +100,7 @@ func (h *HashtagHandlers) Create(w http.ResponseWriter, r *http.Request) {
 		Name:      req.Name,
 		Slug:      req.Slug,
 		Category:  category,
-		CreatedBy: req.CreatedBy,
+		CreatedBy: createdBy,
 		CreatedAt: time.Now(),
 	}
 
@@ -138,15 +133,8 @@ func (h *HashtagHandlers) GetBySlug(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Check for optional authentication to determine following status
-	var currentUserID string
-	authHeader := r.Header.Get("Authorization")
-	if authHeader != "" && len(authHeader) > 7 && authHeader[:7] == "Bearer " {
-		tokenString := authHeader[7:]
-		if claims, err := auth.ValidateToken(tokenString); err == nil {
-			currentUserID = claims.UserID
-		}
-	}
+	// Signed-in viewers (OptionalAuth) also get their following status
+	currentUserID, _ := r.Context().Value("userID").(string)
 
 	isFollowing := false
 	if currentUserID != "" {
@@ -169,31 +157,20 @@ func (h *HashtagHandlers) GetBySlug(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *HashtagHandlers) List(w http.ResponseWriter, r *http.Request) {
-	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
-	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
-
-	if limit <= 0 || limit > 100 {
-		limit = 50
-	}
-	if offset < 0 {
-		offset = 0
+	page, err := ParsePage(r)
+	if err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
 	}
 
-	hashtags, err := h.repo.List(limit, offset)
+	hashtags, nextCursor, err := h.repo.List(page)
 	if err != nil {
 		Error(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	// Check for optional authentication
-	var currentUserID string
-	authHeader := r.Header.Get("Authorization")
-	if authHeader != "" && len(authHeader) > 7 && authHeader[:7] == "Bearer " {
-		tokenString := authHeader[7:]
-		if claims, err := auth.ValidateToken(tokenString); err == nil {
-			currentUserID = claims.UserID
-		}
-	}
+	// Signed-in viewers (OptionalAuth) also get their following status
+	currentUserID, _ := r.Context().Value("userID").(string)
 
 	hashtagsWithStats := make([]map[string]interface{}, 0, len(hashtags))
 	for _, hashtag := range hashtags {
@@ -215,9 +192,9 @@ func (h *HashtagHandlers) List(w http.ResponseWriter, r *http.Request) {
 	}
 
 	JSON(w, http.StatusOK, map[string]interface{}{
-		"hashtags": hashtagsWithStats,
-		"limit":    limit,
-		"offset":   offset,
+		"hashtags":   hashtagsWithStats,
+		"limit":      page.Limit,
+		"nextCursor": nextCursor,
 	})
 }
 
@@ -230,6 +207,12 @@ func (h *HashtagHandlers) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Moderators clean up hashtags anyone created
+	if hashtag.CreatedBy != r.Context().Value("userID").(string) && !hasRole(r, models.PlatformRoleModerator, models.PlatformRoleAdmin) {
+		Error(w, http.StatusForbidden, "Only the creator or a moderator can delete this hashtag")
+		return
+	}
+
 	if err := h.repo.Delete(hashtag.ID); err != nil {
 		Error(w, http.StatusInternalServerError, err.Error())
 		return
@@ -256,6 +239,17 @@ func (h *HashtagHandlers) AddPost(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Only the author decides which hashtags their post is in
+	post, err := h.postRepo.GetByID(req.PostID)
+	if err != nil {
+		Error(w, http.StatusNotFound, "Post not found")
+		return
+	}
+	if post.AuthorID != r.Context().Value("userID").(string) {
+		Error(w, http.StatusForbidden, "You can only add your own posts to a hashtag")
+		return
+	}
+
 	hashtag, err := h.repo.GetBySlug(slug)
 	if err != nil {
 		Error(w, http.StatusNotFound, "Hashtag not found")
@@ -285,6 +279,9 @@ func (h *HashtagHandlers) GetPosts(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Signed-in viewers (OptionalAuth) also get their reaction status
+	currentUserID, _ := r.Context().Value("userID").(string)
+
 	// Populate author data for each post
 	enrichedPosts := make([]map[string]interface{}, 0, len(posts))
 	for _, post := range posts {
@@ -294,16 +291,6 @@ func (h *HashtagHandlers) GetPosts(w http.ResponseWriter, r *http.Request) {
 			continue
 		}
 
-		// Check for optional authentication to determine reaction status
-		var currentUserID string
-		authHeader := r.Header.Get("Authorization")
-		if authHeader != "" && len(authHeader) > 7 && authHeader[:7] == "Bearer " {
-			tokenString := authHeader[7:]
-			if claims, err := auth.ValidateToken(tokenString); err == nil {
-				currentUserID = claims.UserID
-			}
-		}
-
 		isLiked := false
 		isSaved := false
 		if currentUserID != "" {
@@ -345,20 +332,7 @@ func (h *HashtagHandlers) GetPosts(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *HashtagHandlers) Follow(w http.ResponseWriter, r *http.Request) {
-	// Extract User ID from token manually since auth.GetUserID is not available
-	var userID string
-	authHeader := r.Header.Get("Authorization")
-	if authHeader != "" && len(authHeader) > 7 && authHeader[:7] == "Bearer " {
-		tokenString := authHeader[7:]
-		if claims, err := auth.ValidateToken(tokenString); err == nil {
-			userID = claims.UserID
-		}
-	}
-
-	if userID == "" {
-		Error(w, http.StatusUnauthorized, "Unauthorized")
-		return
-	}
+	userID := r.Context().Value("userID").(string)
 
 	slug := chi.URLParam(r, "slug")
 
@@ -377,20 +351,7 @@ func (h *HashtagHandlers) Follow(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *HashtagHandlers) Unfollow(w http.ResponseWriter, r *http.Request) {
-	// Extract User ID from token manually
-	var userID string
-	authHeader := r.Header.Get("Authorization")
-	if authHeader != "" && len(authHeader) > 7 && authHeader[:7] == "Bearer " {
-		tokenString := authHeader[7:]
-		if claims, err := auth.ValidateToken(tokenString); err == nil {
-			userID = claims.UserID
-		}
-	}
-
-	if userID == "" {
-		Error(w, http.StatusUnauthorized, "Unauthorized")
-		return
-	}
+	userID := r.Context().Value("userID").(string)
 
 	slug := chi.URLParam(r, "slug")
 
@@ -409,15 +370,8 @@ func (h *HashtagHandlers) Unfollow(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *HashtagHandlers) GetTrending(w http.ResponseWriter, r *http.Request) {
-	// Check for optional authentication
-	var currentUserID string
-	authHeader := r.Header.Get("Authorization")
-	if authHeader != "" && len(authHeader) > 7 && authHeader[:7] == "Bearer " {
-		tokenString := authHeader[7:]
-		if claims, err := auth.ValidateToken(tokenString); err == nil {
-			currentUserID = claims.UserID
-		}
-	}
+	// Signed-in viewers (OptionalAuth) also get their following status
+	currentUserID, _ := r.Context().Value("userID").(string)
 
 	enrich := func(hashtags []*models.Hashtag) []map[string]interface{} {
 		enriched := make([]map[string]interface{}, 0, len(hashtags))
@@ -5,27 +5,40 @@ import (
 	"os"
 
 	"github.com/livekit/protocol/auth"
+	"github.com/yourusername/v-backend/internal/repository"
+	"github.com/yourusername/v-backend/internal/service"
 )
 
 // LiveKitHandlers handles LiveKit-related endpoints
-type LiveKitHandlers struct{}
+type LiveKitHandlers struct {
+	debateRepo repository.DebateRepository
+	access     *service.DebateRoomAccess
+}
 
 // NewLiveKitHandlers creates a new LiveKit handlers instance
-func NewLiveKitHandlers() *LiveKitHandlers {
-	return &LiveKitHandlers{}
+func NewLiveKitHandlers(debateRepo repository.DebateRepository, access *service.DebateRoomAccess) *LiveKitHandlers {
+	return &LiveKitHandlers{debateRepo: debateRepo, access: access}
 }
 
-// GetToken generates a LiveKit access token for a user to join a room
+// GetToken generates a LiveKit access token for the signed-in user to join a
+// debate's media room. The room is named after the debate, and the user must
+// be allowed to join it.
 func (h *LiveKitHandlers) GetToken(w http.ResponseWriter, r *http.Request) {
-	// Get query parameters
+	userId := r.Context().Value("userID").(string)
 	roomName := r.URL.Query().Get("roomName")
-	userId := r.URL.Query().Get("userId")
+	if err := ValidateRequired(roomName, "roomName"); err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
-	if roomName == "" {
-		roomName = "default-room"
+	debate, err := h.debateRepo.GetByID(roomName)
+	if err != nil {
+		Error(w, http.StatusNotFound, "Debate not found")
+		return
 	}
-	if userId == "" {
-		userId = "anonymous"
+	if err := h.access.CanJoinDebate(debate, userId); err != nil {
+		Error(w, http.StatusForbidden, err.Error())
+		return
 	}
 
 	// Get LiveKit credentials from environment
@@ -3,7 +3,6 @@ package api
 import (
 	"encoding/json"
 	"net/http"
-	"strconv"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -20,6 +19,17 @@ func NewMessageHandlers(repo repository.MessageRepository) *MessageHandlers {
 	return &MessageHandlers{repo: repo}
 }
 
+// participantConversation loads a conversation the user takes part in. Anyone
+// else gets the same 404 as for a missing conversation.
+func (h *MessageHandlers) participantConversation(w http.ResponseWriter, id, userID string) (*models.Conversation, bool) {
+	conversation, err := h.repo.GetConversation(id)
+	if err != nil || (conversation.Participant1ID != userID && conversation.Participant2ID != userID) {
+		Error(w, http.StatusNotFound, "Conversation not found")
+		return nil, false
+	}
+	return conversation, true
+}
+
 func (h *MessageHandlers) CreateConversation(w http.ResponseWriter, r *http.Request) {
 	var req struct {
 		Participant1ID string `json:"participant1Id"`
@@ -31,22 +41,25 @@ func (h *MessageHandlers) CreateConversation(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	if err := ValidateRequired(req.Participant1ID, "participant1Id"); err != nil {
-		Error(w, http.StatusBadRequest, err.Error())
-		return
+	// The caller is always one side; the other is whichever participant isn't them
+	userID := r.Context().Value("userID").(string)
+	otherID := req.Participant2ID
+	if otherID == "" || otherID == userID {
+		otherID = req.Participant1ID
 	}
-	if err := ValidateRequired(req.Participant2ID, "participant2Id"); err != nil {
+
+	if err := ValidateRequired(otherID, "participant2Id"); err != nil {
 		Error(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	if req.Participant1ID == req.Participant2ID {
+	if otherID == userID {
 		Error(w, http.StatusBadRequest, "Cannot create conversation with yourself")
 		return
 	}
 
 	// Check if conversation already exists
-	existing, _ := h.repo.GetConversationByParticipants(req.Participant1ID, req.Participant2ID)
+	existing, _ := h.repo.GetConversationByParticipants(userID, otherID)
 	if existing != nil {
 		JSON(w, http.StatusOK, existing)
 		return
@@ -54,8 +67,8 @@ func (h *MessageHandlers) CreateConversation(w http.ResponseWriter, r *http.Requ
 
 	conversation := &models.Conversation{
 		ID:             uuid.New().String(),
-		Participant1ID: req.Participant1ID,
-		Participant2ID: req.Participant2ID,
+		Participant1ID: userID,
+		Participant2ID: otherID,
 		CreatedAt:      time.Now(),
 		UpdatedAt:      time.Now(),
 	}
@@ -71,9 +84,8 @@ func (h *MessageHandlers) CreateConversation(w http.ResponseWriter, r *http.Requ
 func (h *MessageHandlers) GetConversation(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	conversation, err := h.repo.GetConversation(id)
-	if err != nil {
-		Error(w, http.StatusNotFound, "Conversation not found")
+	conversation, ok := h.participantConversation(w, id, r.Context().Value("userID").(string))
+	if !ok {
 		return
 	}
 
@@ -81,12 +93,7 @@ func (h *MessageHandlers) GetConversation(w http.ResponseWriter, r *http.Request
 }
 
 func (h *MessageHandlers) ListConversations(w http.ResponseWriter, r *http.Request) {
-	userID := r.URL.Query().Get("userId")
-
-	if userID == "" {
-		Error(w, http.StatusBadRequest, "userId is required")
-		return
-	}
+	userID := r.Context().Value("userID").(string)
 
 	conversations, err := h.repo.ListConversations(userID)
 	if err != nil {
@@ -99,10 +106,14 @@ func (h *MessageHandlers) ListConversations(w http.ResponseWriter, r *http.Reque
 
 func (h *MessageHandlers) SendMessage(w http.ResponseWriter, r *http.Request) {
 	conversationID := chi.URLParam(r, "id")
+	senderID := r.Context().Value("userID").(string)
+
+	if _, ok := h.participantConversation(w, conversationID, senderID); !ok {
+		return
+	}
 
 	var req struct {
-		SenderID string `json:"senderId"`
-		Content  string `json:"content"`
+		Content string `json:"content"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -110,10 +121,6 @@ func (h *MessageHandlers) SendMessage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := ValidateRequired(req.SenderID, "senderId"); err != nil {
-		Error(w, http.StatusBadRequest, err.Error())
-		return
-	}
 	if err := ValidateContentLength(req.Content, 5000); err != nil {
 		Error(w, http.StatusBadRequest, err.Error())
 		return
@@ -122,7 +129,7 @@ func (h *MessageHandlers) SendMessage(w http.ResponseWriter, r *http.Request) {
 	message := &models.Message{
 		ID:             uuid.New().String(),
 		ConversationID: conversationID,
-		SenderID:       req.SenderID,
+		SenderID:       senderID,
 		Content:        req.Content,
 		Read:           false,
 		CreatedAt:      time.Now(),
@@ -138,31 +145,47 @@ func (h *MessageHandlers) SendMessage(w http.ResponseWriter, r *http.Request) {
 
 func (h *MessageHandlers) GetMessages(w http.ResponseWriter, r *http.Request) {
 	conversationID := chi.URLParam(r, "id")
-	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
-	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
 
-	if limit <= 0 || limit > 100 {
-		limit = 50
+	if _, ok := h.participantConversation(w, conversationID, r.Context().Value("userID").(string)); !ok {
+		return
 	}
-	if offset < 0 {
-		offset = 0
+
+	page, err := ParsePage(r)
+	if err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
 	}
 
-	messages, err := h.repo.ListMessages(conversationID, limit, offset)
+	messages, nextCursor, err := h.repo.ListMessages(conversationID, page)
 	if err != nil {
 		Error(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
 	JSON(w, http.StatusOK, map[string]interface{}{
-		"messages": messages,
-		"limit":    limit,
-		"offset":   offset,
+		"messages":   messages,
+		"limit":      page.Limit,
+		"nextCursor": nextCursor,
 	})
 }
 
 func (h *MessageHandlers) MarkAsRead(w http.ResponseWriter, r *http.Request) {
 	messageID := chi.URLParam(r, "messageId")
+	userID := r.Context().Value("userID").(string)
+
+	// Only the recipient reads a message
+	message, err := h.repo.GetMessage(messageID)
+	if err != nil {
+		Error(w, http.StatusNotFound, "Message not found")
+		return
+	}
+	if _, ok := h.participantConversation(w, message.ConversationID, userID); !ok {
+		return
+	}
+	if message.SenderID == userID {
+		Error(w, http.StatusForbidden, "You cannot mark your own message as read")
+		return
+	}
 
 	if err := h.repo.MarkAsRead(messageID); err != nil {
 		Error(w, http.StatusInternalServerError, err.Error())
@@ -174,10 +197,9 @@ func (h *MessageHandlers) MarkAsRead(w http.ResponseWriter, r *http.Request) {
 
 func (h *MessageHandlers) GetUnreadCount(w http.ResponseWriter, r *http.Request) {
 	conversationID := chi.URLParam(r, "id")
-	userID := r.URL.Query().Get("userId")
+	userID := r.Context().Value("userID").(string)
 
-	if userID == "" {
-		Error(w, http.StatusBadRequest, "userId is required")
+	if _, ok := h.participantConversation(w, conversationID, userID); !ok {
 		return
 	}
 
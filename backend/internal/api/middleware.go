@@ -2,60 +2,128 @@ package api
 
 import (
 	"context"
+	"fmt"
+	"net"
 	"net/http"
 	"strings"
 
 	"github.com/yourusername/v-backend/internal/auth"
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/service"
 )
 
-// RequireAuth is middleware that validates JWT tokens
-func RequireAuth(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" {
-			Error(w, http.StatusUnauthorized, "Authorization header required")
-			return
-		}
+// AuthMiddleware validates access tokens and rejects those whose session has
+// been revoked or has expired. API keys are accepted instead on routes that
+// say which scopes they need with APIKeyScopes.
+type AuthMiddleware struct {
+	sessionService *service.SessionService
+	apiKeyService  *service.APIKeyService
+}
 
-		// Extract token from "Bearer <token>"
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			Error(w, http.StatusUnauthorized, "Invalid authorization header format")
-			return
+func NewAuthMiddleware(sessionService *service.SessionService, apiKeyService *service.APIKeyService) *AuthMiddleware {
+	return &AuthMiddleware{sessionService: sessionService, apiKeyService: apiKeyService}
+}
+
+// authenticate returns the claims of a valid token bound to a live session
+func (m *AuthMiddleware) authenticate(token string) (*auth.Claims, bool) {
+	claims, err := auth.ValidateToken(token)
+	if err != nil {
+		return nil, false
+	}
+	if !m.sessionService.IsActive(claims.SessionID) {
+		return nil, false
+	}
+	return claims, true
+}
+
+func withClaims(ctx context.Context, claims *auth.Claims) context.Context {
+	ctx = context.WithValue(ctx, "userID", claims.UserID)
+	ctx = context.WithValue(ctx, "email", claims.Email)
+	ctx = context.WithValue(ctx, "handle", claims.Handle)
+	ctx = context.WithValue(ctx, "role", claims.Role)
+	ctx = context.WithValue(ctx, "sessionID", claims.SessionID)
+	return ctx
+}
+
+// authError is why a request could not be authenticated
+type authError struct {
+	status  int
+	message string
+}
+
+// authenticateRequest checks the Authorization header, which holds either
+// "Bearer <access token>" or "ApiKey <key>", and returns the context to serve
+// the request with
+func (m *AuthMiddleware) authenticateRequest(r *http.Request) (context.Context, *authError) {
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		return nil, &authError{http.StatusUnauthorized, "Authorization header required"}
+	}
+
+	scheme, credential, ok := strings.Cut(authHeader, " ")
+	if !ok || credential == "" {
+		return nil, &authError{http.StatusUnauthorized, "Invalid authorization header format"}
+	}
+	switch scheme {
+	case "Bearer":
+		claims, ok := m.authenticate(credential)
+		if !ok {
+			return nil, &authError{http.StatusUnauthorized, "Invalid or expired token"}
 		}
+		return withClaims(r.Context(), claims), nil
+	case "ApiKey":
+		return m.authenticateAPIKey(r, credential)
+	default:
+		return nil, &authError{http.StatusUnauthorized, "Invalid authorization header format"}
+	}
+}
 
-		token := parts[1]
+// authenticateAPIKey lets a key act as its user within its scopes. Keys never
+// carry staff roles, and have no session, so session-only routes stay closed
+// to them.
+func (m *AuthMiddleware) authenticateAPIKey(r *http.Request, key string) (context.Context, *authError) {
+	apiKey, user, err := m.apiKeyService.Authenticate(key)
+	if err != nil {
+		return nil, &authError{http.StatusUnauthorized, err.Error()}
+	}
 
-		// Validate token
-		claims, err := auth.ValidateToken(token)
-		if err != nil {
-			Error(w, http.StatusUnauthorized, "Invalid or expired token")
+	scope, _ := r.Context().Value("apiKeyScope").(models.APIScope)
+	if scope == "" {
+		return nil, &authError{http.StatusForbidden, "API keys cannot be used here"}
+	}
+	if !apiKey.HasScope(scope) {
+		return nil, &authError{http.StatusForbidden, "API key is missing the " + string(scope) + " scope"}
+	}
+
+	ctx := withClaims(r.Context(), &auth.Claims{
+		UserID: user.ID,
+		Email:  user.Email,
+		Handle: user.Handle,
+		Role:   string(models.PlatformRoleUser),
+	})
+	return context.WithValue(ctx, "apiKeyID", apiKey.ID), nil
+}
+
+// RequireAuth is middleware that validates JWT tokens or API keys
+func (m *AuthMiddleware) RequireAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, authErr := m.authenticateRequest(r)
+		if authErr != nil {
+			Error(w, authErr.status, authErr.message)
 			return
 		}
 
-		// Add user info to context
-		ctx := context.WithValue(r.Context(), "userID", claims.UserID)
-		ctx = context.WithValue(ctx, "email", claims.Email)
-		ctx = context.WithValue(ctx, "handle", claims.Handle)
-
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
-// OptionalAuth is middleware that validates JWT tokens but doesn't require them
-func OptionalAuth(next http.Handler) http.Handler {
+// OptionalAuth is middleware that validates JWT tokens or API keys but
+// doesn't require them
+func (m *AuthMiddleware) OptionalAuth(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		authHeader := r.Header.Get("Authorization")
-		if authHeader != "" {
-			parts := strings.Split(authHeader, " ")
-			if len(parts) == 2 && parts[0] == "Bearer" {
-				token := parts[1]
-				if claims, err := auth.ValidateToken(token); err == nil {
-					ctx := context.WithValue(r.Context(), "userID", claims.UserID)
-					ctx = context.WithValue(ctx, "email", claims.Email)
-					ctx = context.WithValue(ctx, "handle", claims.Handle)
-					r = r.WithContext(ctx)
-				}
+		if r.Header.Get("Authorization") != "" {
+			if ctx, authErr := m.authenticateRequest(r); authErr == nil {
+				r = r.WithContext(ctx)
 			}
 		}
 
@@ -63,3 +131,128 @@ func OptionalAuth(next http.Handler) http.Handler {
 	})
 }
 
+// APIKeyScopes is middleware that lets API keys through the auth middleware
+// after it: keys need read for GET and HEAD requests and write for the rest.
+// An empty scope keeps keys out of those requests.
+func APIKeyScopes(read, write models.APIScope) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			scope := write
+			if r.Method == http.MethodGet || r.Method == http.MethodHead {
+				scope = read
+			}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), "apiKeyScope", scope)))
+		})
+	}
+}
+
+// TrustedProxies are the proxies in front of the API, whose forwarded headers
+// are believed
+type TrustedProxies []*net.IPNet
+
+// ParseTrustedProxies parses IP addresses and CIDR ranges such as 10.0.0.0/8
+func ParseTrustedProxies(values []string) (TrustedProxies, error) {
+	proxies := make(TrustedProxies, 0, len(values))
+	for _, value := range values {
+		if !strings.Contains(value, "/") {
+			ip := net.ParseIP(value)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid proxy address %q", value)
+			}
+			proxies = append(proxies, &net.IPNet{IP: ip, Mask: net.CIDRMask(len(ip)*8, len(ip)*8)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy range %q", value)
+		}
+		proxies = append(proxies, network)
+	}
+	return proxies, nil
+}
+
+func (p TrustedProxies) contains(ip net.IP) bool {
+	for _, network := range p {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// forwardedClient returns the client address a trusted proxy forwarded.
+// X-Forwarded-For is read from the right, past the proxies' own entries,
+// since anything further left came from the client.
+func (p TrustedProxies) forwardedClient(r *http.Request) string {
+	var hops []string
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		for _, hop := range strings.Split(header, ",") {
+			hops = append(hops, strings.TrimSpace(hop))
+		}
+	}
+	for i := len(hops) - 1; i >= 0; i-- {
+		ip := net.ParseIP(hops[i])
+		if ip == nil {
+			return ""
+		}
+		if i == 0 || !p.contains(ip) {
+			return ip.String()
+		}
+	}
+	if ip := net.ParseIP(strings.TrimSpace(r.Header.Get("X-Real-IP"))); ip != nil {
+		return ip.String()
+	}
+	return ""
+}
+
+// RealIP is middleware that replaces RemoteAddr with the client address
+// forwarded by a trusted proxy. Requests from anywhere else keep their own
+// address, so clients cannot choose the address sign-ins are counted against.
+func RealIP(proxies TrustedProxies) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if peer := net.ParseIP(clientIP(r)); peer != nil && proxies.contains(peer) {
+				if client := proxies.forwardedClient(r); client != "" {
+					r.RemoteAddr = client
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// clientIP returns the address of the client without the port. The RealIP
+// middleware has already replaced RemoteAddr with the forwarded address when
+// the request came through a trusted proxy.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// RequireRole is middleware that only lets through users with one of roles.
+// It goes after RequireAuth, which puts the role from the token in the context.
+func RequireRole(roles ...models.PlatformRole) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !hasRole(r, roles...) {
+				Error(w, http.StatusForbidden, "Insufficient permissions")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// hasRole reports whether the signed-in user has one of roles
+func hasRole(r *http.Request, roles ...models.PlatformRole) bool {
+	role, _ := r.Context().Value("role").(string)
+	for _, allowed := range roles {
+		if models.PlatformRole(role) == allowed {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRealIPTrustsOnlyProxies(t *testing.T) {
+	proxies, err := ParseTrustedProxies([]string{"10.0.0.0/8", "192.168.1.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseTrustedProxies([]string{"10.0.0.0/33"}); err == nil {
+		t.Error("invalid range accepted")
+	}
+
+	var got string
+	handler := RealIP(proxies)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = clientIP(r)
+	}))
+	for _, tc := range []struct {
+		name, remote, forwardedFor, realIP, want string
+	}{
+		{"direct client", "203.0.113.9:4000", "1.2.3.4", "1.2.3.4", "203.0.113.9"},
+		{"through proxy", "10.0.0.5:4000", "198.51.100.7", "", "198.51.100.7"},
+		{"client spoofs the header", "10.0.0.5:4000", "1.2.3.4, 198.51.100.7", "", "198.51.100.7"},
+		{"chain of proxies", "192.168.1.1:4000", "198.51.100.7, 10.1.1.1", "", "198.51.100.7"},
+		{"real IP header", "10.0.0.5:4000", "", "198.51.100.7", "198.51.100.7"},
+		{"garbled header", "10.0.0.5:4000", "not-an-ip", "", "10.0.0.5"},
+	} {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.RemoteAddr = tc.remote
+		if tc.forwardedFor != "" {
+			r.Header.Set("X-Forwarded-For", tc.forwardedFor)
+		}
+		if tc.realIP != "" {
+			r.Header.Set("X-Real-IP", tc.realIP)
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+		if got != tc.want {
+			t.Errorf("%s: client = %s, want %s", tc.name, got, tc.want)
+		}
+	}
+}
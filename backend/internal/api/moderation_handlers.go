@@ -22,22 +22,9 @@ func NewModerationHandlers(modService *service.ModerationService) *ModerationHan
 // Report a post
 func (h *ModerationHandlers) ReportPost(w http.ResponseWriter, r *http.Request) {
 	postID := chi.URLParam(r, "id")
+	reporterID := r.Context().Value("userID").(string)
 
-	var req struct {
-		ReporterID string `json:"reporterId"`
-	}
-
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		Error(w, http.StatusBadRequest, "Invalid request body")
-		return
-	}
-
-	if err := ValidateRequired(req.ReporterID, "reporterId"); err != nil {
-		Error(w, http.StatusBadRequest, err.Error())
-		return
-	}
-
-	if err := h.modService.ReportPost(postID, req.ReporterID); err != nil {
+	if err := h.modService.ReportPost(postID, reporterID); err != nil {
 		Error(w, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -3,7 +3,6 @@ package api
 import (
 	"encoding/json"
 	"net/http"
-	"strconv"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -28,33 +27,28 @@ func (h *NotificationHandlers) List(w http.ResponseWriter, r *http.Request) {
 	userID := r.Context().Value("userID").(string)
 
 	// Parse query params
-	limitStr := r.URL.Query().Get("limit")
-	offsetStr := r.URL.Query().Get("offset")
-
-	limit := 50
-	offset := 0
-
-	if limitStr != "" {
-		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
-			limit = l
-		}
-	}
-	if offsetStr != "" {
-		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
-			offset = o
-		}
+	page, err := ParsePage(r)
+	if err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
 	}
 
-	notifications, err := h.notifRepo.GetByUserID(userID, limit, offset)
+	notifications, nextCursor, err := h.notifRepo.GetByUserID(userID, page)
 	if err != nil {
 		Error(w, http.StatusInternalServerError, "Failed to get notifications")
 		return
 	}
 
-	JSON(w, http.StatusOK, notifications)
+	JSON(w, http.StatusOK, map[string]interface{}{
+		"notifications": notifications,
+		"limit":         page.Limit,
+		"nextCursor":    nextCursor,
+	})
 }
 
+// Create sends a notification from staff. The sender is always the caller.
 func (h *NotificationHandlers) Create(w http.ResponseWriter, r *http.Request) {
+	actorID := r.Context().Value("userID").(string)
 	var req models.CreateNotificationRequest
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -80,14 +74,16 @@ func (h *NotificationHandlers) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get actor details if provided
+	if _, err := h.userRepo.GetByID(req.UserID); err != nil {
+		Error(w, http.StatusNotFound, "User not found")
+		return
+	}
+
+	// Get actor details
 	var actorName, actorHandle *string
-	if req.ActorID != nil {
-		actor, err := h.userRepo.GetByID(*req.ActorID)
-		if err == nil {
-			actorName = &actor.Name
-			actorHandle = &actor.Handle
-		}
+	if actor, err := h.userRepo.GetByID(actorID); err == nil {
+		actorName = &actor.Name
+		actorHandle = &actor.Handle
 	}
 
 	notification := &models.Notification{
@@ -99,7 +95,7 @@ func (h *NotificationHandlers) Create(w http.ResponseWriter, r *http.Request) {
 		DebateID:    req.DebateID,
 		DebateTitle: req.DebateTitle,
 		PostID:      req.PostID,
-		ActorID:     req.ActorID,
+		ActorID:     &actorID,
 		ActorName:   actorName,
 		ActorHandle: actorHandle,
 		Read:        false,
@@ -0,0 +1,214 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/yourusername/v-backend/internal/auth"
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/oidc"
+	"github.com/yourusername/v-backend/internal/repository"
+	"github.com/yourusername/v-backend/internal/service"
+)
+
+// oidcError writes the response for an error from OIDCService
+func oidcError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrUnknownProvider):
+		Error(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrInvalidOIDCFlow):
+		Error(w, http.StatusUnauthorized, err.Error())
+	case errors.Is(err, service.ErrOIDCNoEmail), errors.Is(err, service.ErrLastSignInMethod):
+		Error(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrOIDCEmailInUse), errors.Is(err, repository.ErrIdentityLinked):
+		Error(w, http.StatusConflict, err.Error())
+	default:
+		Error(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// ListOIDCProviders returns the providers users can sign in with
+func (h *AuthHandlers) ListOIDCProviders(w http.ResponseWriter, r *http.Request) {
+	JSON(w, http.StatusOK, h.oidcService.Providers())
+}
+
+// BeginOIDC returns the provider URL to send the user to. The client keeps
+// the flow token and posts it back with the code the provider redirects with.
+func (h *AuthHandlers) BeginOIDC(w http.ResponseWriter, r *http.Request) {
+	begin, err := h.oidcService.Begin(r.Context(), chi.URLParam(r, "provider"))
+	if err != nil {
+		oidcError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, begin)
+}
+
+// OIDCCallback finishes a sign-in with a provider. It returns tokens, a
+// two-factor challenge, or a signup token when the identity has no account.
+func (h *AuthHandlers) OIDCCallback(w http.ResponseWriter, r *http.Request) {
+	provider := chi.URLParam(r, "provider")
+
+	var req models.OIDCCallbackRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	claims, err := h.oidcService.Authenticate(r.Context(), provider, req)
+	if err != nil {
+		oidcError(w, err)
+		return
+	}
+	userID, signup, err := h.oidcService.Resolve(provider, claims)
+	if err != nil {
+		oidcError(w, err)
+		return
+	}
+	if signup != nil {
+		JSON(w, http.StatusOK, signup)
+		return
+	}
+
+	h.beginLogin(w, r, userID)
+}
+
+// OIDCSignup creates the account for an identity from OIDCCallback with the
+// handle the user picked
+func (h *AuthHandlers) OIDCSignup(w http.ResponseWriter, r *http.Request) {
+	var req models.OIDCSignupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	signup, err := auth.ParseOIDCSignupToken(req.SignupToken)
+	if err != nil {
+		Error(w, http.StatusUnauthorized, "Invalid or expired signup; sign in with the provider again")
+		return
+	}
+	if err := ValidateHandle(req.Handle); err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if existing, _ := h.userRepo.GetByHandle(req.Handle); existing != nil {
+		Error(w, http.StatusConflict, "Handle already exists")
+		return
+	}
+	if existing, _ := h.authRepo.GetByEmail(signup.Email); existing != nil {
+		Error(w, http.StatusConflict, "Email already exists")
+		return
+	}
+
+	name := signup.Name
+	if name == "" {
+		name = req.Handle
+	}
+	user := &models.User{
+		ID:            uuid.New().String(),
+		Name:          name,
+		Handle:        req.Handle,
+		Email:         signup.Email,
+		EmailVerified: signup.EmailVerified,
+		Languages:     []string{"English"},
+		AvatarURL:     "https://api.dicebear.com/9.x/bottts/svg?seed=" + req.Handle + "&backgroundColor=b6e3f4,c0aede,ffd5dc,ffdfbf",
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+	// No password; the user can set one later with a reset link
+	if err := h.createAccount(user, ""); err != nil {
+		Error(w, http.StatusInternalServerError, "Failed to create account")
+		return
+	}
+
+	err = h.oidcService.Link(user.ID, signup.Provider, &oidc.Claims{Subject: signup.Subject, Email: signup.Email})
+	if err != nil {
+		// Someone finished the same signup first
+		h.authRepo.DeleteAuth(user.ID)
+		h.userRepo.Delete(user.ID)
+		oidcError(w, err)
+		return
+	}
+	if !user.EmailVerified {
+		if err := h.accountService.SendVerification(user); err != nil {
+			log.Printf("[Auth] Failed to send verification email to %s: %v", user.Email, err)
+		}
+	}
+
+	tokens, err := h.sessionService.Start(user, r.UserAgent(), clientIP(r))
+	if err != nil {
+		Error(w, http.StatusInternalServerError, "Failed to generate token")
+		return
+	}
+
+	Created(w, models.AuthResponse{
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    tokens.ExpiresIn,
+		User:         user,
+	})
+}
+
+// LinkOIDC links a provider account to the signed-in user, finishing a flow
+// started with BeginOIDC
+func (h *AuthHandlers) LinkOIDC(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(string)
+	provider := chi.URLParam(r, "provider")
+
+	var req models.OIDCCallbackRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	claims, err := h.oidcService.Authenticate(r.Context(), provider, req)
+	if err != nil {
+		oidcError(w, err)
+		return
+	}
+	if err := h.oidcService.Link(userID, provider, claims); err != nil {
+		oidcError(w, err)
+		return
+	}
+
+	identities, err := h.oidcService.Identities(userID)
+	if err != nil {
+		Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	JSON(w, http.StatusOK, identities)
+}
+
+// ListIdentities returns the provider accounts linked to the signed-in user
+func (h *AuthHandlers) ListIdentities(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(string)
+
+	identities, err := h.oidcService.Identities(userID)
+	if err != nil {
+		Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	JSON(w, http.StatusOK, identities)
+}
+
+// UnlinkOIDC removes the signed-in user's identity at a provider
+func (h *AuthHandlers) UnlinkOIDC(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(string)
+
+	if err := h.oidcService.Unlink(userID, chi.URLParam(r, "provider")); err != nil {
+		if errors.Is(err, service.ErrLastSignInMethod) {
+			oidcError(w, err)
+			return
+		}
+		Error(w, http.StatusNotFound, "Identity not found")
+		return
+	}
+
+	NoContent(w)
+}
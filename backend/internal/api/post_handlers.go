@@ -2,16 +2,17 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log"
 	"net/http"
 	"regexp"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
-	"github.com/yourusername/v-backend/internal/auth"
 	"github.com/yourusername/v-backend/internal/models"
 	"github.com/yourusername/v-backend/internal/repository"
 	"github.com/yourusername/v-backend/internal/service"
@@ -27,9 +28,11 @@ type PostHandlers struct {
 	translationService *service.TranslationService
 	hashtagRepo        repository.HashtagRepository
 	communityRepo      repository.CommunityRepository
+	uow                repository.UnitOfWork
+	restoreWindow      time.Duration
 }
 
-func NewPostHandlers(repo repository.PostRepository, userRepo repository.UserRepository, notifRepo repository.NotificationRepository, analyticsRepo repository.AnalyticsRepository, pointsService *service.PointsService, modService *service.ModerationService, translationService *service.TranslationService, hashtagRepo repository.HashtagRepository, communityRepo repository.CommunityRepository) *PostHandlers {
+func NewPostHandlers(repo repository.PostRepository, userRepo repository.UserRepository, notifRepo repository.NotificationRepository, analyticsRepo repository.AnalyticsRepository, pointsService *service.PointsService, modService *service.ModerationService, translationService *service.TranslationService, hashtagRepo repository.HashtagRepository, communityRepo repository.CommunityRepository, uow repository.UnitOfWork, restoreWindow time.Duration) *PostHandlers {
 	return &PostHandlers{
 		repo:               repo,
 		userRepo:           userRepo,
@@ -40,12 +43,15 @@ func NewPostHandlers(repo repository.PostRepository, userRepo repository.UserRep
 		translationService: translationService,
 		hashtagRepo:        hashtagRepo,
 		communityRepo:      communityRepo,
+		uow:                uow,
+		restoreWindow:      restoreWindow,
 	}
 }
 
 func (h *PostHandlers) Create(w http.ResponseWriter, r *http.Request) {
+	authorID := r.Context().Value("userID").(string)
+
 	var req struct {
-		AuthorID         string `json:"authorId"`
 		Content          string `json:"content"`
 		MediaType        string `json:"mediaType,omitempty"`
 		MediaURL         string `json:"mediaUrl,omitempty"`
@@ -63,17 +69,13 @@ func (h *PostHandlers) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := ValidateRequired(req.AuthorID, "authorId"); err != nil {
-		Error(w, http.StatusBadRequest, err.Error())
-		return
-	}
 	if err := ValidateContentLength(req.Content, 5000); err != nil {
 		Error(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	// Check if user is temporarily muted
-	user, err := h.userRepo.GetByID(req.AuthorID)
+	user, err := h.userRepo.GetByID(authorID)
 	if err != nil {
 		Error(w, http.StatusNotFound, "User not found")
 		return
@@ -163,7 +165,8 @@ func (h *PostHandlers) Create(w http.ResponseWriter, r *http.Request) {
 
 	post := &models.Post{
 		ID:                uuid.New().String(),
-		AuthorID:          req.AuthorID,
+		AuthorID:          authorID,
+		AuthorIsBot:       user.IsBot,
 		Content:           req.Content,
 		MediaType:         req.MediaType,
 		MediaURL:          req.MediaURL,
@@ -180,29 +183,28 @@ func (h *PostHandlers) Create(w http.ResponseWriter, r *http.Request) {
 		ResponseToPostID:  req.ResponseToPostID,
 	}
 
+	// Points and the post are written together so a failure leaves neither behind
+	actionType := service.ActionCleanPost
 	if isAbusive {
-		// Mark as abusive
+		// Mark as abusive and apply point penalty
 		post.Status = models.PostStatusAbusiveFlag
+		actionType = service.ActionAbusivePost
+	} else if req.IsHashtagPost {
+		actionType = service.ActionHashtagPost
+	}
 
-		// Apply point penalty
-		if err := h.pointsService.UpdateUserPoints(req.AuthorID, service.ActionAbusivePost); err != nil {
-			Error(w, http.StatusInternalServerError, "Failed to update points")
-			return
-		}
-	} else {
-		// Award points for clean post
-		actionType := service.ActionCleanPost
-		if req.IsHashtagPost {
-			actionType = service.ActionHashtagPost
-		}
-
-		if err := h.pointsService.UpdateUserPoints(req.AuthorID, actionType); err != nil {
-			Error(w, http.StatusInternalServerError, "Failed to update points")
-			return
+	var pointsErr error
+	err = h.uow.Do(func(tx *repository.Repositories) error {
+		if pointsErr = h.pointsService.WithTx(tx).UpdateUserPoints(authorID, actionType); pointsErr != nil {
+			return pointsErr
 		}
+		return tx.Post.Create(post)
+	})
+	if pointsErr != nil {
+		Error(w, http.StatusInternalServerError, "Failed to update points")
+		return
 	}
-
-	if err := h.repo.Create(post); err != nil {
+	if err != nil {
 		Error(w, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -235,31 +237,29 @@ func (h *PostHandlers) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	SetETag(w, post.Version)
 	JSON(w, http.StatusOK, post)
 }
 
 func (h *PostHandlers) List(w http.ResponseWriter, r *http.Request) {
-	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
-	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
 	authorID := r.URL.Query().Get("authorId")
 	communityID := r.URL.Query().Get("communityId")
 
-	if limit <= 0 || limit > 100 {
-		limit = 50
-	}
-	if offset < 0 {
-		offset = 0
+	page, err := ParsePage(r)
+	if err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
 	}
 
 	var posts []*models.Post
-	var err error
+	var nextCursor string
 
 	if authorID != "" {
-		posts, err = h.repo.ListByAuthor(authorID, limit, offset)
+		posts, nextCursor, err = h.repo.ListByAuthor(authorID, page)
 	} else if communityID != "" {
-		posts, err = h.repo.ListByCommunity(communityID, limit, offset)
+		posts, nextCursor, err = h.repo.ListByCommunity(communityID, page)
 	} else {
-		posts, err = h.repo.List(limit, offset)
+		posts, nextCursor, err = h.repo.List(page)
 	}
 
 	if err != nil {
@@ -278,15 +278,8 @@ func (h *PostHandlers) List(w http.ResponseWriter, r *http.Request) {
 	// Enrich posts with author data and reaction status
 	enrichedPosts := make([]map[string]interface{}, 0, len(posts))
 
-	// Check for optional authentication to determine reaction status
-	var currentUserID string
-	authHeader := r.Header.Get("Authorization")
-	if authHeader != "" && len(authHeader) > 7 && authHeader[:7] == "Bearer " {
-		tokenString := authHeader[7:]
-		if claims, err := auth.ValidateToken(tokenString); err == nil {
-			currentUserID = claims.UserID
-		}
-	}
+	// Signed-in viewers (OptionalAuth) also get their reaction status
+	currentUserID, _ := r.Context().Value("userID").(string)
 
 	for _, post := range posts {
 		author, err := h.userRepo.GetByID(post.AuthorID)
@@ -324,8 +317,9 @@ func (h *PostHandlers) List(w http.ResponseWriter, r *http.Request) {
 		}
 
 		enrichedPosts = append(enrichedPosts, map[string]interface{}{
-			"id":       post.ID,
-			"authorId": post.AuthorID,
+			"id":          post.ID,
+			"authorId":    post.AuthorID,
+			"authorIsBot": post.AuthorIsBot,
 			"author": map[string]interface{}{
 				"id":          author.ID,
 				"name":        author.Name,
@@ -333,6 +327,7 @@ func (h *PostHandlers) List(w http.ResponseWriter, r *http.Request) {
 				"handle":      author.Handle,
 				"avatar":      author.AvatarURL,
 				"avatarUrl":   author.AvatarURL,
+				"isBot":       author.IsBot,
 			},
 			"content":           post.Content,
 			"mediaType":         post.MediaType,
@@ -357,9 +352,9 @@ func (h *PostHandlers) List(w http.ResponseWriter, r *http.Request) {
 	}
 
 	JSON(w, http.StatusOK, map[string]interface{}{
-		"posts":  enrichedPosts,
-		"limit":  limit,
-		"offset": offset,
+		"posts":      enrichedPosts,
+		"limit":      page.Limit,
+		"nextCursor": nextCursor,
 	})
 }
 
@@ -372,6 +367,19 @@ func (h *PostHandlers) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if post.AuthorID != r.Context().Value("userID").(string) {
+		Error(w, http.StatusForbidden, "You can only edit your own posts")
+		return
+	}
+
+	// Only overwrite the version the client read, when it says which one
+	if version, ok, err := ParseIfMatch(r); err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	} else if ok {
+		post.Version = version
+	}
+
 	var updates struct {
 		Content          *string `json:"content"`
 		CommentsDisabled *bool   `json:"commentsDisabled"`
@@ -402,10 +410,11 @@ func (h *PostHandlers) Update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.repo.Update(post); err != nil {
-		Error(w, http.StatusInternalServerError, err.Error())
+		UpdateError(w, err)
 		return
 	}
 
+	SetETag(w, post.Version)
 	JSON(w, http.StatusOK, post)
 }
 
@@ -421,6 +430,16 @@ func (h *PostHandlers) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Moderators and admins remove posts that break the rules
+	userID := r.Context().Value("userID").(string)
+	if post.AuthorID != userID {
+		if !hasRole(r, models.PlatformRoleModerator, models.PlatformRoleAdmin) {
+			Error(w, http.StatusForbidden, "You can only delete your own posts")
+			return
+		}
+		log.Printf("[Moderation] %s removed post %s by %s", userID, id, post.AuthorID)
+	}
+
 	// Determine point deduction action
 	// Note: We check if it was a hashtag post (though we don't strictly track IsHashtagPost in the model,
 	// we can infer or assume standard post for now. If we tracked it, we'd use ActionDeleteHashtagPost)
@@ -432,32 +451,70 @@ func (h *PostHandlers) Delete(w http.ResponseWriter, r *http.Request) {
 	// In a real app, we'd store the type or check the join table
 	// For this implementation, let's just use ActionDeletePost to be safe/simple
 
-	if err := h.repo.Delete(id); err != nil {
+	// The post, its hashtag links and the point deduction go together
+	err = h.uow.Do(func(tx *repository.Repositories) error {
+		if err := tx.Post.Delete(id); err != nil {
+			return err
+		}
+		// Remove from hashtags if any
+		if err := tx.Hashtag.RemovePostFromHashtag(id); err != nil {
+			return err
+		}
+		return h.pointsService.WithTx(tx).UpdateUserPoints(post.AuthorID, action)
+	})
+	if err != nil {
 		fmt.Printf("DEBUG: Failed to delete post ID: %s, error: %v\n", id, err)
 		Error(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	// Remove from hashtags if any
-	if err := h.hashtagRepo.RemovePostFromHashtag(id); err != nil {
-		fmt.Printf("DEBUG: Failed to remove post from hashtags: %v\n", err)
-		// Don't fail the request, just log it
+	fmt.Printf("DEBUG: Successfully deleted post ID: %s\n", id)
+	NoContent(w)
+}
+
+// Restore brings back a post deleted within the restore window and refunds
+// the points its deletion cost. Hashtag links dropped on delete stay dropped.
+func (h *PostHandlers) Restore(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	deleted, err := h.repo.GetDeletedByID(id)
+	if err != nil {
+		Error(w, http.StatusNotFound, "Deleted post not found")
+		return
 	}
 
-	// Deduct points after successful deletion
-	// We ignore errors here as the post is already deleted
-	_ = h.pointsService.UpdateUserPoints(post.AuthorID, action)
+	if deleted.AuthorID != r.Context().Value("userID").(string) {
+		Error(w, http.StatusForbidden, "You can only restore your own posts")
+		return
+	}
 
-	fmt.Printf("DEBUG: Successfully deleted post ID: %s\n", id)
-	NoContent(w)
+	err = h.uow.Do(func(tx *repository.Repositories) error {
+		if err := tx.Post.Restore(id, time.Now().Add(-h.restoreWindow)); err != nil {
+			return err
+		}
+		return h.pointsService.WithTx(tx).UpdateUserPoints(deleted.AuthorID, service.ActionRestorePost)
+	})
+	if err != nil {
+		RestoreError(w, err)
+		return
+	}
+
+	post, err := h.repo.GetByID(id)
+	if err != nil {
+		Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	SetETag(w, post.Version)
+	JSON(w, http.StatusOK, post)
 }
 
 // Comment handlers
 func (h *PostHandlers) CreateComment(w http.ResponseWriter, r *http.Request) {
 	postID := chi.URLParam(r, "id")
+	authorID := r.Context().Value("userID").(string)
 
 	var req struct {
-		AuthorID string  `json:"authorId"`
 		Content  string  `json:"content"`
 		ParentID *string `json:"parentId,omitempty"`
 	}
@@ -467,10 +524,6 @@ func (h *PostHandlers) CreateComment(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := ValidateRequired(req.AuthorID, "authorId"); err != nil {
-		Error(w, http.StatusBadRequest, err.Error())
-		return
-	}
 	if err := ValidateContentLength(req.Content, 2000); err != nil {
 		Error(w, http.StatusBadRequest, err.Error())
 		return
@@ -479,7 +532,7 @@ func (h *PostHandlers) CreateComment(w http.ResponseWriter, r *http.Request) {
 	comment := &models.Comment{
 		ID:        uuid.New().String(),
 		PostID:    postID,
-		AuthorID:  req.AuthorID,
+		AuthorID:  authorID,
 		ParentID:  req.ParentID,
 		Content:   req.Content,
 		CreatedAt: time.Now(),
@@ -493,9 +546,9 @@ func (h *PostHandlers) CreateComment(w http.ResponseWriter, r *http.Request) {
 
 	// Create notification for post author (if not commenting on own post)
 	post, err := h.repo.GetByID(postID)
-	if err == nil && post.AuthorID != req.AuthorID {
+	if err == nil && post.AuthorID != authorID {
 		// Get commenter info
-		commenter, err := h.userRepo.GetByID(req.AuthorID)
+		commenter, err := h.userRepo.GetByID(authorID)
 		if err == nil {
 			notification := &models.Notification{
 				ID:          uuid.New().String(),
@@ -504,7 +557,7 @@ func (h *PostHandlers) CreateComment(w http.ResponseWriter, r *http.Request) {
 				Title:       "New Comment",
 				Message:     commenter.Name + " commented on your post",
 				PostID:      &postID,
-				ActorID:     &req.AuthorID,
+				ActorID:     &authorID,
 				ActorName:   &commenter.Name,
 				ActorHandle: &commenter.Handle,
 				Read:        false,
@@ -529,9 +582,32 @@ func (h *PostHandlers) GetComments(w http.ResponseWriter, r *http.Request) {
 	JSON(w, http.StatusOK, comments)
 }
 
+// canModerateComment reports whether the user may delete or restore a comment:
+// its author can, and so can the author of the post it is on
+func (h *PostHandlers) canModerateComment(comment *models.Comment, userID string) bool {
+	if comment.AuthorID == userID {
+		return true
+	}
+	post, err := h.repo.GetByID(comment.PostID)
+	if err != nil {
+		post, err = h.repo.GetDeletedByID(comment.PostID)
+	}
+	return err == nil && post.AuthorID == userID
+}
+
 func (h *PostHandlers) DeleteComment(w http.ResponseWriter, r *http.Request) {
 	commentID := chi.URLParam(r, "commentId")
 
+	comment, err := h.repo.GetCommentByID(commentID)
+	if err != nil || comment.DeletedAt != nil {
+		Error(w, http.StatusNotFound, "Comment not found")
+		return
+	}
+	if !h.canModerateComment(comment, r.Context().Value("userID").(string)) {
+		Error(w, http.StatusForbidden, "You can only delete your own comments or comments on your posts")
+		return
+	}
+
 	if err := h.repo.DeleteComment(commentID); err != nil {
 		Error(w, http.StatusInternalServerError, err.Error())
 		return
@@ -540,44 +616,62 @@ func (h *PostHandlers) DeleteComment(w http.ResponseWriter, r *http.Request) {
 	NoContent(w)
 }
 
+// RestoreComment brings back a comment deleted within the restore window
+func (h *PostHandlers) RestoreComment(w http.ResponseWriter, r *http.Request) {
+	commentID := chi.URLParam(r, "commentId")
+
+	comment, err := h.repo.GetCommentByID(commentID)
+	if err != nil {
+		Error(w, http.StatusNotFound, "Comment not found")
+		return
+	}
+	if !h.canModerateComment(comment, r.Context().Value("userID").(string)) {
+		Error(w, http.StatusForbidden, "You can only restore your own comments or comments on your posts")
+		return
+	}
+
+	if err := h.repo.RestoreComment(commentID, time.Now().Add(-h.restoreWindow)); err != nil {
+		RestoreError(w, err)
+		return
+	}
+
+	Success(w, "Comment restored")
+}
+
 // Reaction handlers
 func (h *PostHandlers) React(w http.ResponseWriter, r *http.Request) {
 	postID := chi.URLParam(r, "id")
+	userID := r.Context().Value("userID").(string)
 
 	var req struct {
-		UserID    string  `json:"userId"`
 		CommentID *string `json:"commentId,omitempty"`
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	// The body is optional; it only says which comment is meant
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
 		Error(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
-	if err := ValidateRequired(req.UserID, "userId"); err != nil {
-		Error(w, http.StatusBadRequest, err.Error())
-		return
-	}
-
 	reaction := &models.Reaction{
-		UserID:    req.UserID,
+		UserID:    userID,
 		PostID:    postID,
 		CommentID: req.CommentID,
 		CreatedAt: time.Now(),
 	}
 
 	if err := h.repo.AddReaction(reaction); err != nil {
-		fmt.Printf("DEBUG: Failed to add reaction for post %s user %s: %v\n", postID, req.UserID, err)
+		fmt.Printf("DEBUG: Failed to add reaction for post %s user %s: %v\n", postID, userID, err)
 		Error(w, http.StatusConflict, "Already reacted")
 		return
 	}
-	fmt.Printf("DEBUG: Successfully added reaction for post %s user %s\n", postID, req.UserID)
+	fmt.Printf("DEBUG: Successfully added reaction for post %s user %s\n", postID, userID)
 
 	// Create notification for post author (if not reacting to own post)
 	post, err := h.repo.GetByID(postID)
-	if err == nil && post.AuthorID != req.UserID {
+	if err == nil && post.AuthorID != userID {
 		// Get reactor info
-		reactor, err := h.userRepo.GetByID(req.UserID)
+		reactor, err := h.userRepo.GetByID(userID)
 		if err == nil {
 			notification := &models.Notification{
 				ID:          uuid.New().String(),
@@ -586,7 +680,7 @@ func (h *PostHandlers) React(w http.ResponseWriter, r *http.Request) {
 				Title:       "New Reaction",
 				Message:     reactor.Name + " reacted to your post",
 				PostID:      &postID,
-				ActorID:     &req.UserID,
+				ActorID:     &userID,
 				ActorName:   &reactor.Name,
 				ActorHandle: &reactor.Handle,
 				Read:        false,
@@ -611,23 +705,19 @@ func (h *PostHandlers) React(w http.ResponseWriter, r *http.Request) {
 
 func (h *PostHandlers) Unreact(w http.ResponseWriter, r *http.Request) {
 	postID := chi.URLParam(r, "id")
+	userID := r.Context().Value("userID").(string)
 
 	var req struct {
-		UserID    string  `json:"userId"`
 		CommentID *string `json:"commentId,omitempty"`
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	// The body is optional; it only says which comment is meant
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
 		Error(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
-	if err := ValidateRequired(req.UserID, "userId"); err != nil {
-		Error(w, http.StatusBadRequest, err.Error())
-		return
-	}
-
-	if err := h.repo.RemoveReaction(req.UserID, postID, req.CommentID); err != nil {
+	if err := h.repo.RemoveReaction(userID, postID, req.CommentID); err != nil {
 		Error(w, http.StatusNotFound, "Reaction not found")
 		return
 	}
@@ -648,22 +738,9 @@ func (h *PostHandlers) Unreact(w http.ResponseWriter, r *http.Request) {
 // Save handlers
 func (h *PostHandlers) Save(w http.ResponseWriter, r *http.Request) {
 	postID := chi.URLParam(r, "id")
+	userID := r.Context().Value("userID").(string)
 
-	var req struct {
-		UserID string `json:"userId"`
-	}
-
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		Error(w, http.StatusBadRequest, "Invalid request body")
-		return
-	}
-
-	if err := ValidateRequired(req.UserID, "userId"); err != nil {
-		Error(w, http.StatusBadRequest, err.Error())
-		return
-	}
-
-	if err := h.repo.SavePost(req.UserID, postID); err != nil {
+	if err := h.repo.SavePost(userID, postID); err != nil {
 		Error(w, http.StatusConflict, "Post already saved")
 		return
 	}
@@ -673,22 +750,9 @@ func (h *PostHandlers) Save(w http.ResponseWriter, r *http.Request) {
 
 func (h *PostHandlers) Unsave(w http.ResponseWriter, r *http.Request) {
 	postID := chi.URLParam(r, "id")
+	userID := r.Context().Value("userID").(string)
 
-	var req struct {
-		UserID string `json:"userId"`
-	}
-
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		Error(w, http.StatusBadRequest, "Invalid request body")
-		return
-	}
-
-	if err := ValidateRequired(req.UserID, "userId"); err != nil {
-		Error(w, http.StatusBadRequest, err.Error())
-		return
-	}
-
-	if err := h.repo.UnsavePost(req.UserID, postID); err != nil {
+	if err := h.repo.UnsavePost(userID, postID); err != nil {
 		Error(w, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -697,32 +761,24 @@ func (h *PostHandlers) Unsave(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *PostHandlers) GetSavedPosts(w http.ResponseWriter, r *http.Request) {
-	userID := r.URL.Query().Get("userId")
-	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
-	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	userID := r.Context().Value("userID").(string)
 
-	if userID == "" {
-		Error(w, http.StatusBadRequest, "userId is required")
+	page, err := ParsePage(r)
+	if err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	if limit <= 0 || limit > 100 {
-		limit = 50
-	}
-	if offset < 0 {
-		offset = 0
-	}
-
-	posts, err := h.repo.GetSavedPosts(userID, limit, offset)
+	posts, nextCursor, err := h.repo.GetSavedPosts(userID, page)
 	if err != nil {
 		Error(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
 	JSON(w, http.StatusOK, map[string]interface{}{
-		"posts":  posts,
-		"limit":  limit,
-		"offset": offset,
+		"posts":      posts,
+		"limit":      page.Limit,
+		"nextCursor": nextCursor,
 	})
 }
 
@@ -2,7 +2,11 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
+
+	"github.com/yourusername/v-backend/internal/repository"
 )
 
 type Response struct {
@@ -52,3 +56,28 @@ func NoContent(w http.ResponseWriter) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// SetETag sets the ETag header to an entity version, which clients send back
+// in If-Match to update only the version they read
+func SetETag(w http.ResponseWriter, version int) {
+	w.Header().Set("ETag", strconv.Quote(strconv.Itoa(version)))
+}
+
+// UpdateError sends a 409 Conflict when an update lost a race with another
+// write, and a 500 otherwise
+func UpdateError(w http.ResponseWriter, err error) {
+	if repository.IsConflict(err) {
+		Error(w, http.StatusConflict, err.Error())
+		return
+	}
+	Error(w, http.StatusInternalServerError, err.Error())
+}
+
+// RestoreError sends a 410 Gone when the restore window has passed, and a 404
+// when there is nothing deleted to restore
+func RestoreError(w http.ResponseWriter, err error) {
+	if errors.Is(err, repository.ErrRestoreWindowExpired) {
+		Error(w, http.StatusGone, err.Error())
+		return
+	}
+	Error(w, http.StatusNotFound, err.Error())
+}
@@ -0,0 +1,179 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/yourusername/v-backend/internal/auth"
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/service"
+)
+
+// twoFactorError writes the response for an error from TwoFactorService
+func twoFactorError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidTwoFactorCode):
+		Error(w, http.StatusUnauthorized, "Invalid two-factor code")
+	case errors.Is(err, service.ErrTwoFactorNotSetUp), errors.Is(err, service.ErrTwoFactorNotEnabled):
+		Error(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrTwoFactorAlreadyEnabled):
+		Error(w, http.StatusConflict, err.Error())
+	default:
+		Error(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// VerifyTwoFactorLogin finishes a login by trading the challenge token from
+// Login and a two-factor code for an access token
+func (h *AuthHandlers) VerifyTwoFactorLogin(w http.ResponseWriter, r *http.Request) {
+	var req models.TwoFactorLoginRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := ValidateRequired(req.Code, "code"); err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userID, err := auth.ValidateChallengeToken(req.ChallengeToken)
+	if err != nil {
+		Error(w, http.StatusUnauthorized, "Invalid or expired challenge; sign in again")
+		return
+	}
+	authModel, err := h.authRepo.GetByUserID(userID)
+	if err != nil {
+		Error(w, http.StatusUnauthorized, "Invalid or expired challenge; sign in again")
+		return
+	}
+
+	// Wrong codes count as failed sign-ins, so codes cannot be guessed either
+	attempt, err := h.loginGuard.Begin(authModel.Email, clientIP(r))
+	if err != nil {
+		loginBlocked(w, err)
+		return
+	}
+	defer attempt.End()
+	if err := h.twoFactor.Verify(userID, req.Code); err != nil {
+		if errors.Is(err, service.ErrInvalidTwoFactorCode) {
+			attempt.Failed()
+		}
+		twoFactorError(w, err)
+		return
+	}
+
+	attempt.Succeeded()
+	h.completeLogin(w, r, userID)
+}
+
+// TwoFactorStatus reports whether the signed-in user has two-factor on
+func (h *AuthHandlers) TwoFactorStatus(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(string)
+
+	JSON(w, http.StatusOK, h.twoFactor.Status(userID))
+}
+
+// SetupTwoFactor returns a new secret for the user's authenticator app.
+// Two-factor stays off until EnableTwoFactor confirms a code from it.
+func (h *AuthHandlers) SetupTwoFactor(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(string)
+
+	user, err := h.userRepo.GetByID(userID)
+	if err != nil {
+		Error(w, http.StatusNotFound, "User not found")
+		return
+	}
+
+	setup, err := h.twoFactor.Setup(user)
+	if err != nil {
+		twoFactorError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, setup)
+}
+
+// EnableTwoFactor turns two-factor on and returns the recovery codes, which
+// are never shown again
+func (h *AuthHandlers) EnableTwoFactor(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(string)
+
+	var req struct {
+		Code string `json:"code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	codes, err := h.twoFactor.Enable(userID, req.Code)
+	if err != nil {
+		twoFactorError(w, err)
+		return
+	}
+
+	// Sessions opened with only a password are signed out
+	sessionID := r.Context().Value("sessionID").(string)
+	if err := h.sessionService.RevokeOthers(userID, sessionID); err != nil {
+		Error(w, http.StatusInternalServerError, "Two-factor enabled, but failed to sign out other sessions")
+		return
+	}
+
+	JSON(w, http.StatusOK, models.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// DisableTwoFactor turns two-factor off. It takes the password as well as a
+// code, so a stolen session alone cannot remove it.
+func (h *AuthHandlers) DisableTwoFactor(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(string)
+
+	var req struct {
+		Password string `json:"password"`
+		Code     string `json:"code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	authModel, err := h.authRepo.GetByUserID(userID)
+	if err != nil {
+		Error(w, http.StatusNotFound, "Auth not found")
+		return
+	}
+	if !auth.CheckPassword(authModel.PasswordHash, req.Password) {
+		Error(w, http.StatusUnauthorized, "Password is incorrect")
+		return
+	}
+
+	if err := h.twoFactor.Disable(userID, req.Code); err != nil {
+		twoFactorError(w, err)
+		return
+	}
+
+	Success(w, "Two-factor authentication disabled")
+}
+
+// RegenerateRecoveryCodes replaces the user's recovery codes, invalidating
+// the old ones
+func (h *AuthHandlers) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(string)
+
+	var req struct {
+		Code string `json:"code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	codes, err := h.twoFactor.RegenerateRecoveryCodes(userID, req.Code)
+	if err != nil {
+		twoFactorError(w, err)
+		return
+	}
+
+	JSON(w, http.StatusOK, models.RecoveryCodesResponse{RecoveryCodes: codes})
+}
@@ -3,7 +3,6 @@ package api
 import (
 	"encoding/json"
 	"net/http"
-	"strconv"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -86,6 +85,7 @@ func (h *UserHandlers) GetByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	SetETag(w, user.Version)
 	JSON(w, http.StatusOK, user)
 }
 
@@ -98,18 +98,32 @@ func (h *UserHandlers) GetByHandle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	SetETag(w, user.Version)
 	JSON(w, http.StatusOK, user)
 }
 
 func (h *UserHandlers) Update(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
+	if id != r.Context().Value("userID").(string) {
+		Error(w, http.StatusForbidden, "You can only edit your own profile")
+		return
+	}
+
 	user, err := h.repo.GetByID(id)
 	if err != nil {
 		Error(w, http.StatusNotFound, "User not found")
 		return
 	}
 
+	// Only overwrite the version the client read, when it says which one
+	if version, ok, err := ParseIfMatch(r); err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	} else if ok {
+		user.Version = version
+	}
+
 	var updates struct {
 		Name                  *string `json:"name"`
 		Bio                   *string `json:"bio"`
@@ -182,6 +196,7 @@ func (h *UserHandlers) Update(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		user.Email = *updates.Email
+		user.EmailVerified = false
 	}
 
 	// Password updates (hashing) - Note: In a real app we'd verify old password first
@@ -204,16 +219,22 @@ func (h *UserHandlers) Update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.repo.Update(user); err != nil {
-		Error(w, http.StatusInternalServerError, err.Error())
+		UpdateError(w, err)
 		return
 	}
 
+	SetETag(w, user.Version)
 	JSON(w, http.StatusOK, user)
 }
 
 func (h *UserHandlers) Delete(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
+	if id != r.Context().Value("userID").(string) && !hasRole(r, models.PlatformRoleAdmin) {
+		Error(w, http.StatusForbidden, "You can only delete your own account")
+		return
+	}
+
 	if err := h.repo.Delete(id); err != nil {
 		Error(w, http.StatusInternalServerError, err.Error())
 		return
@@ -224,27 +245,14 @@ func (h *UserHandlers) Delete(w http.ResponseWriter, r *http.Request) {
 
 func (h *UserHandlers) Follow(w http.ResponseWriter, r *http.Request) {
 	followingID := chi.URLParam(r, "id")
+	followerID := r.Context().Value("userID").(string)
 
-	var req struct {
-		FollowerID string `json:"followerId"`
-	}
-
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		Error(w, http.StatusBadRequest, "Invalid request body")
-		return
-	}
-
-	if req.FollowerID == "" {
-		Error(w, http.StatusBadRequest, "followerId is required")
-		return
-	}
-
-	if req.FollowerID == followingID {
+	if followerID == followingID {
 		Error(w, http.StatusBadRequest, "Cannot follow yourself")
 		return
 	}
 
-	if err := h.repo.Follow(req.FollowerID, followingID); err != nil {
+	if err := h.repo.Follow(followerID, followingID); err != nil {
 		Error(w, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -254,22 +262,9 @@ func (h *UserHandlers) Follow(w http.ResponseWriter, r *http.Request) {
 
 func (h *UserHandlers) Unfollow(w http.ResponseWriter, r *http.Request) {
 	followingID := chi.URLParam(r, "id")
+	followerID := r.Context().Value("userID").(string)
 
-	var req struct {
-		FollowerID string `json:"followerId"`
-	}
-
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		Error(w, http.StatusBadRequest, "Invalid request body")
-		return
-	}
-
-	if req.FollowerID == "" {
-		Error(w, http.StatusBadRequest, "followerId is required")
-		return
-	}
-
-	if err := h.repo.Unfollow(req.FollowerID, followingID); err != nil {
+	if err := h.repo.Unfollow(followerID, followingID); err != nil {
 		Error(w, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -280,75 +275,63 @@ func (h *UserHandlers) Unfollow(w http.ResponseWriter, r *http.Request) {
 func (h *UserHandlers) GetFollowers(w http.ResponseWriter, r *http.Request) {
 	userID := chi.URLParam(r, "id")
 
-	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
-	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
-
-	if limit <= 0 || limit > 100 {
-		limit = 50
-	}
-	if offset < 0 {
-		offset = 0
+	page, err := ParsePage(r)
+	if err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
 	}
 
-	followers, err := h.repo.GetFollowers(userID, limit, offset)
+	followers, nextCursor, err := h.repo.GetFollowers(userID, page)
 	if err != nil {
 		Error(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
 	JSON(w, http.StatusOK, map[string]interface{}{
-		"users":  followers,
-		"limit":  limit,
-		"offset": offset,
+		"users":      followers,
+		"limit":      page.Limit,
+		"nextCursor": nextCursor,
 	})
 }
 
 func (h *UserHandlers) GetFollowing(w http.ResponseWriter, r *http.Request) {
 	userID := chi.URLParam(r, "id")
 
-	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
-	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
-
-	if limit <= 0 || limit > 100 {
-		limit = 50
-	}
-	if offset < 0 {
-		offset = 0
+	page, err := ParsePage(r)
+	if err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
 	}
 
-	following, err := h.repo.GetFollowing(userID, limit, offset)
+	following, nextCursor, err := h.repo.GetFollowing(userID, page)
 	if err != nil {
 		Error(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
 	JSON(w, http.StatusOK, map[string]interface{}{
-		"users":  following,
-		"limit":  limit,
-		"offset": offset,
+		"users":      following,
+		"limit":      page.Limit,
+		"nextCursor": nextCursor,
 	})
 }
 
 func (h *UserHandlers) List(w http.ResponseWriter, r *http.Request) {
-	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
-	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
-
-	if limit <= 0 || limit > 100 {
-		limit = 50
-	}
-	if offset < 0 {
-		offset = 0
+	page, err := ParsePage(r)
+	if err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
 	}
 
-	users, err := h.repo.List(limit, offset)
+	users, nextCursor, err := h.repo.List(page)
 	if err != nil {
 		Error(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
 	JSON(w, http.StatusOK, map[string]interface{}{
-		"users":  users,
-		"limit":  limit,
-		"offset": offset,
+		"users":      users,
+		"limit":      page.Limit,
+		"nextCursor": nextCursor,
 	})
 }
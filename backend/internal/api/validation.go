@@ -2,7 +2,11 @@ package api
 
 import (
 	"errors"
+	"net/http"
+	"strconv"
 	"strings"
+
+	"github.com/yourusername/v-backend/internal/repository"
 )
 
 var (
@@ -10,6 +14,7 @@ var (
 	ErrEmptyField     = errors.New("required field is empty")
 	ErrInvalidHandle  = errors.New("invalid handle format")
 	ErrInvalidContent = errors.New("content too long or invalid")
+	ErrInvalidIfMatch = errors.New("If-Match must be an ETag returned by this API")
 )
 
 // ValidateEmail checks if email is valid
@@ -59,3 +64,31 @@ func ValidateContentLength(content string, maxLength int) error {
 	return nil
 }
 
+// ParsePage reads the limit and cursor query parameters of a list request
+func ParsePage(r *http.Request) (repository.Page, error) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+
+	page := repository.Page{Limit: limit, Cursor: r.URL.Query().Get("cursor")}
+	if _, err := repository.DecodeCursor(page.Cursor); err != nil {
+		return page, err
+	}
+	return page, nil
+}
+
+// ParseIfMatch reads the entity version a client sent back in the If-Match
+// header. ok is false when the header is absent or "*", in which case the
+// update applies to whatever version is current.
+func ParseIfMatch(r *http.Request) (version int, ok bool, err error) {
+	etag := strings.TrimPrefix(r.Header.Get("If-Match"), "W/")
+	if etag == "" || etag == "*" {
+		return 0, false, nil
+	}
+	version, err = strconv.Atoi(strings.Trim(etag, `"`))
+	if err != nil {
+		return 0, false, ErrInvalidIfMatch
+	}
+	return version, true, nil
+}
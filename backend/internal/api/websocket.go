@@ -1,44 +1,110 @@
 package api
 
 import (
+	"errors"
 	"log"
 	"net/http"
+	"strings"
 
 	"github.com/gorilla/websocket"
 	"github.com/yourusername/v-backend/internal/service"
 )
 
-var upgrader = websocket.Upgrader{
-	ReadBufferSize:  1024,
-	WriteBufferSize: 1024,
-	CheckOrigin: func(r *http.Request) bool {
-		return true // Allow all origins for now
-	},
+// Browsers cannot set headers on WebSocket requests, so the access token is
+// sent as a subprotocol pair, ["bearer", "<token>"], or as the token query
+// parameter. The subprotocol keeps it out of request logs.
+const wsTokenProtocol = "bearer"
+
+// WebSocketHandler upgrades signed-in connections and hands them to the hub
+type WebSocketHandler struct {
+	hub      *service.Hub
+	auth     *AuthMiddleware
+	upgrader websocket.Upgrader
 }
 
-// ServeWs handles websocket requests from the peer.
-func ServeWs(hub *service.Hub, w http.ResponseWriter, r *http.Request) {
-	conn, err := upgrader.Upgrade(w, r, nil)
-	if err != nil {
-		log.Println(err)
+// NewWebSocketHandler only accepts browser connections from allowedOrigins,
+// the same origins CORS allows
+func NewWebSocketHandler(hub *service.Hub, auth *AuthMiddleware, allowedOrigins []string) *WebSocketHandler {
+	return &WebSocketHandler{
+		hub:  hub,
+		auth: auth,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			Subprotocols:    []string{wsTokenProtocol},
+			CheckOrigin: func(r *http.Request) bool {
+				return originAllowed(r.Header.Get("Origin"), allowedOrigins)
+			},
+		},
+	}
+}
+
+// originAllowed reports whether a handshake from origin may go ahead. Clients
+// that are not browsers send no origin; they still need a token.
+func originAllowed(origin string, allowedOrigins []string) bool {
+	if origin == "" {
+		return true
+	}
+	for _, allowed := range allowedOrigins {
+		allowed = strings.TrimSpace(allowed)
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// wsToken returns the access token from the subprotocols or the query string
+func wsToken(r *http.Request) string {
+	protocols := websocket.Subprotocols(r)
+	for i, protocol := range protocols {
+		if protocol == wsTokenProtocol && i+1 < len(protocols) {
+			return protocols[i+1]
+		}
+	}
+	return r.URL.Query().Get("token")
+}
+
+// ServeWs handles GET /api/ws?roomId=..., joining the signed-in user to a room
+func (h *WebSocketHandler) ServeWs(w http.ResponseWriter, r *http.Request) {
+	if !h.upgrader.CheckOrigin(r) {
+		log.Printf("[WebSocket] Refused origin %q", r.Header.Get("Origin"))
+		Error(w, http.StatusForbidden, "Origin not allowed")
+		return
+	}
+
+	claims, ok := h.auth.authenticate(wsToken(r))
+	if !ok {
+		Error(w, http.StatusUnauthorized, "Invalid or expired token")
 		return
 	}
 
 	roomID := r.URL.Query().Get("roomId")
-	userID := r.URL.Query().Get("userId")
+	if err := ValidateRequired(roomID, "roomId"); err != nil {
+		Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := h.hub.Authorize(roomID, claims.UserID); err != nil {
+		if errors.Is(err, service.ErrRoomNotFound) {
+			Error(w, http.StatusNotFound, err.Error())
+		} else {
+			Error(w, http.StatusForbidden, err.Error())
+		}
+		return
+	}
 
-	if roomID == "" || userID == "" {
-		log.Println("Missing roomId or userId")
-		conn.Close()
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Println(err)
 		return
 	}
 
 	client := &service.Client{
-		Hub:    hub,
+		Hub:    h.hub,
 		Conn:   conn,
 		Send:   make(chan []byte, 256),
 		RoomID: roomID,
-		UserID: userID,
+		UserID: claims.UserID,
 	}
 
 	client.Hub.Register <- client
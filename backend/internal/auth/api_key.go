@@ -0,0 +1,32 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+// API keys start with this so they are easy to spot, for instance by secret
+// scanners, and cannot be mistaken for access tokens
+const apiKeyPrefix = "vk_"
+
+// NewAPIKey returns a random API key with the key ID in it, along with the
+// hash to store for it
+func NewAPIKey(keyID string) (key, hash string, err error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", err
+	}
+	key = apiKeyPrefix + keyID + "." + base64.RawURLEncoding.EncodeToString(secret)
+	return key, HashToken(key), nil
+}
+
+// ParseAPIKey returns the ID of the key and its hash
+func ParseAPIKey(key string) (keyID, hash string, err error) {
+	keyID, secret, ok := strings.Cut(strings.TrimPrefix(key, apiKeyPrefix), ".")
+	if !strings.HasPrefix(key, apiKeyPrefix) || !ok || keyID == "" || secret == "" {
+		return "", "", errors.New("malformed API key")
+	}
+	return keyID, HashToken(key), nil
+}
@@ -7,67 +7,119 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
-var jwtSecret = []byte("your-secret-key-change-in-production") // Will be replaced with env var
+// jwtSecret signs the tokens only this server reads, such as login challenges,
+// and seals the stored signing keys. Access tokens are signed with the keys in
+// keys.go so other services can check them.
+var jwtSecret = []byte("your-secret-key-change-in-production")
+
+var accessTokenTTL = 15 * time.Minute
+
+// Challenge tokens stand between the password and the second factor of a login
+const (
+	challengeTTL      = 5 * time.Minute
+	challengeAudience = "2fa-challenge"
+)
 
 type Claims struct {
-	UserID string `json:"userId"`
-	Email  string `json:"email"`
-	Handle string `json:"handle"`
+	UserID    string `json:"userId"`
+	Email     string `json:"email"`
+	Handle    string `json:"handle"`
+	Role      string `json:"role,omitempty"`
+	SessionID string `json:"sid,omitempty"`
 	jwt.RegisteredClaims
 }
 
-// GenerateToken creates a new JWT token for a user
-func GenerateToken(userID, email, handle string) (string, error) {
+// GenerateToken creates a short-lived access token for a user, bound to the
+// session it was issued for. The role is read from the user at issue time, so
+// a role change shows up in tokens from the next refresh on.
+func GenerateToken(userID, email, handle, role, sessionID string) (string, error) {
 	claims := Claims{
-		UserID: userID,
-		Email:  email,
-		Handle: handle,
+		UserID:    userID,
+		Email:     email,
+		Handle:    handle,
+		Role:      role,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * 7 * time.Hour)), // 7 days
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(accessTokenTTL)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtSecret)
+	key, err := currentSigningKey()
+	if err != nil {
+		return "", err
+	}
+	token := jwt.NewWithClaims(key.method(), claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.Private)
 }
 
 // ValidateToken validates a JWT token and returns the claims
 func ValidateToken(tokenString string) (*Claims, error) {
-	// Allow demo-token for development/testing
-	if tokenString == "demo-token" {
-		return &Claims{
-			UserID: "demo-user",
-			Email:  "demo@example.com",
-			Handle: "you", // Changed to match main.go default user handle
-			RegisteredClaims: jwt.RegisteredClaims{
-				ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
-			},
-		}, nil
-	}
-
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("unexpected signing method")
-		}
-		return jwtSecret, nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, verificationKey,
+		jwt.WithValidMethods([]string{AlgorithmRS256, AlgorithmEdDSA}))
 
 	if err != nil {
 		return nil, err
 	}
 
 	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
+		// Tokens from before sessions existed cannot be revoked, so they are not
+		// accepted; neither are login challenges, which have no session either
+		if claims.SessionID == "" {
+			return nil, errors.New("token is not bound to a session")
+		}
 		return claims, nil
 	}
 
 	return nil, errors.New("invalid token")
 }
 
+// GenerateChallengeToken returns a token proving the user got their password
+// right, to be traded for a session along with their second factor. It also
+// returns how many seconds it is valid for.
+func GenerateChallengeToken(userID string) (string, int, error) {
+	claims := jwt.RegisteredClaims{
+		Subject:   userID,
+		Audience:  jwt.ClaimStrings{challengeAudience},
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(challengeTTL)),
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(jwtSecret)
+	return token, int(challengeTTL.Seconds()), err
+}
+
+// ValidateChallengeToken returns the user a challenge token was issued to
+func ValidateChallengeToken(tokenString string) (string, error) {
+	claims := &jwt.RegisteredClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		return jwtSecret, nil
+	}, jwt.WithAudience(challengeAudience))
+	if err != nil || !token.Valid || claims.Subject == "" {
+		return "", errors.New("invalid or expired challenge token")
+	}
+	return claims.Subject, nil
+}
+
 // SetJWTSecret sets the JWT secret from environment variable
 func SetJWTSecret(secret string) {
 	if secret != "" {
 		jwtSecret = []byte(secret)
 	}
 }
+
+// SetAccessTokenTTL sets how long access tokens are valid for
+func SetAccessTokenTTL(ttl time.Duration) {
+	if ttl > 0 {
+		accessTokenTTL = ttl
+	}
+}
+
+// AccessTokenTTL returns how long access tokens are valid for
+func AccessTokenTTL() time.Duration {
+	return accessTokenTTL
+}
@@ -0,0 +1,10 @@
+package auth
+
+import "testing"
+
+// The token that used to sign anyone in as demo-user is an ordinary bad token
+func TestValidateTokenRejectsDemoToken(t *testing.T) {
+	if claims, err := ValidateToken("demo-token"); err == nil {
+		t.Errorf("demo-token accepted as %s", claims.UserID)
+	}
+}
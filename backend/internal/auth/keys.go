@@ -0,0 +1,232 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// Algorithms access tokens can be signed with
+const (
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+// Key is a private key access tokens are signed with
+type Key struct {
+	ID        string // The kid in token headers
+	Algorithm string
+	Private   crypto.Signer
+}
+
+// The keys in use. Access tokens are signed with signingKey and accepted when
+// signed with any of verifyKeys, so tokens outlive the key that signed them
+// being rotated out.
+var (
+	keysMu     sync.RWMutex
+	signingKey *Key
+	verifyKeys map[string]*Key
+)
+
+// GenerateKey creates a key for algorithm with a random ID
+func GenerateKey(algorithm string) (*Key, error) {
+	var private crypto.Signer
+	var err error
+	switch algorithm {
+	case AlgorithmRS256:
+		private, err = rsa.GenerateKey(rand.Reader, 2048)
+	case AlgorithmEdDSA:
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm %q", algorithm)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Key{ID: uuid.New().String(), Algorithm: algorithm, Private: private}, nil
+}
+
+func (k *Key) method() jwt.SigningMethod {
+	if k.Algorithm == AlgorithmEdDSA {
+		return jwt.SigningMethodEdDSA
+	}
+	return jwt.SigningMethodRS256
+}
+
+// Seal encrypts the private key with the JWT secret for storage
+func (k *Key) Seal() ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(k.Private)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := sealingCipher()
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	// The key ID is authenticated too, so sealed keys cannot be swapped around
+	return aead.Seal(nonce, nonce, der, []byte(k.ID)), nil
+}
+
+// OpenKey decrypts a key sealed with Seal. It fails if the JWT secret changed
+// since.
+func OpenKey(id, algorithm string, sealed []byte) (*Key, error) {
+	aead, err := sealingCipher()
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("sealed key is too short")
+	}
+	der, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(id))
+	if err != nil {
+		return nil, errors.New("cannot open sealed key; was JWT_SECRET changed?")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &Key{ID: id, Algorithm: algorithm}
+	switch private := parsed.(type) {
+	case *rsa.PrivateKey:
+		if algorithm == AlgorithmRS256 {
+			key.Private = private
+		}
+	case ed25519.PrivateKey:
+		if algorithm == AlgorithmEdDSA {
+			key.Private = private
+		}
+	}
+	if key.Private == nil {
+		return nil, fmt.Errorf("key %s is not an %s key", id, algorithm)
+	}
+	return key, nil
+}
+
+// sealingCipher derives the key that stored signing keys are encrypted with
+// from the JWT secret
+func sealingCipher() (cipher.AEAD, error) {
+	mac := hmac.New(sha256.New, jwtSecret)
+	mac.Write([]byte("signing-key-sealing"))
+	block, err := aes.NewCipher(mac.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// SetKeys replaces the keys in use. Tokens are signed with signing, which is
+// accepted along with verify.
+func SetKeys(signing *Key, verify []*Key) {
+	keys := make(map[string]*Key, len(verify)+1)
+	for _, key := range verify {
+		keys[key.ID] = key
+	}
+	keys[signing.ID] = signing
+
+	keysMu.Lock()
+	defer keysMu.Unlock()
+	signingKey = signing
+	verifyKeys = keys
+}
+
+// currentSigningKey returns the key to sign with. Until keys are set, as in
+// tests, a throwaway key is made up.
+func currentSigningKey() (*Key, error) {
+	keysMu.RLock()
+	key := signingKey
+	keysMu.RUnlock()
+	if key != nil {
+		return key, nil
+	}
+
+	keysMu.Lock()
+	defer keysMu.Unlock()
+	if signingKey == nil {
+		key, err := GenerateKey(AlgorithmRS256)
+		if err != nil {
+			return nil, err
+		}
+		signingKey = key
+		verifyKeys = map[string]*Key{key.ID: key}
+	}
+	return signingKey, nil
+}
+
+// verificationKey returns the public key for a token's kid header
+func verificationKey(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	keysMu.RLock()
+	key, ok := verifyKeys[kid]
+	keysMu.RUnlock()
+	if !ok {
+		return nil, errors.New("unknown signing key")
+	}
+	if token.Method.Alg() != key.Algorithm {
+		return nil, errors.New("unexpected signing method")
+	}
+	return key.Private.Public(), nil
+}
+
+// JSONWebKey is a public key in a JWK set (RFC 7517)
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JSONWebKeySet is what /.well-known/jwks.json serves
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// PublicKeys returns the keys other services can check access tokens against
+func PublicKeys() JSONWebKeySet {
+	if _, err := currentSigningKey(); err != nil {
+		return JSONWebKeySet{Keys: []JSONWebKey{}}
+	}
+
+	keysMu.RLock()
+	defer keysMu.RUnlock()
+
+	set := JSONWebKeySet{Keys: make([]JSONWebKey, 0, len(verifyKeys))}
+	for _, key := range verifyKeys {
+		jwk := JSONWebKey{Kid: key.ID, Use: "sig", Alg: key.Algorithm}
+		switch public := key.Private.Public().(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
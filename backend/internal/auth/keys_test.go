@@ -0,0 +1,82 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestRotatedKeysStillVerify(t *testing.T) {
+	for _, algorithm := range []string{AlgorithmRS256, AlgorithmEdDSA} {
+		old, err := GenerateKey(algorithm)
+		if err != nil {
+			t.Fatalf("generate %s: %v", algorithm, err)
+		}
+		SetKeys(old, nil)
+		token, err := GenerateToken("user-1", "a@example.com", "alice", "", "session-1")
+		if err != nil {
+			t.Fatalf("sign with %s: %v", algorithm, err)
+		}
+
+		next, _ := GenerateKey(algorithm)
+		SetKeys(next, []*Key{old})
+		if claims, err := ValidateToken(token); err != nil || claims.UserID != "user-1" {
+			t.Errorf("%s token from the previous key = %v, %v", algorithm, claims, err)
+		}
+		if set := PublicKeys(); len(set.Keys) != 2 {
+			t.Errorf("%s key set has %d keys, want 2", algorithm, len(set.Keys))
+		}
+
+		// Once the old key is dropped its tokens are done for
+		SetKeys(next, nil)
+		if _, err := ValidateToken(token); err == nil {
+			t.Errorf("%s token from a dropped key accepted", algorithm)
+		}
+	}
+}
+
+func TestValidateTokenRejectsSecretSignedTokens(t *testing.T) {
+	key, _ := GenerateKey(AlgorithmRS256)
+	SetKeys(key, nil)
+
+	// Signed with the shared secret, or with an RSA public key used as an
+	// HMAC secret, under a key ID we publish
+	claims := Claims{
+		UserID:           "user-1",
+		SessionID:        "session-1",
+		RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))},
+	}
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	forged.Header["kid"] = key.ID
+	signed, _ := forged.SignedString(jwtSecret)
+	if _, err := ValidateToken(signed); err == nil {
+		t.Error("HS256 access token accepted")
+	}
+}
+
+func TestSealedKeys(t *testing.T) {
+	key, _ := GenerateKey(AlgorithmEdDSA)
+	sealed, err := key.Seal()
+	if err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+
+	opened, err := OpenKey(key.ID, key.Algorithm, sealed)
+	if err != nil || opened.ID != key.ID {
+		t.Fatalf("open = %v, %v", opened, err)
+	}
+	if _, err := OpenKey("another-id", key.Algorithm, sealed); err == nil {
+		t.Error("sealed key opened under another ID")
+	}
+	if _, err := OpenKey(key.ID, AlgorithmRS256, sealed); err == nil {
+		t.Error("Ed25519 key opened as RS256")
+	}
+
+	previous := jwtSecret
+	defer func() { jwtSecret = previous }()
+	SetJWTSecret("a different secret")
+	if _, err := OpenKey(key.ID, key.Algorithm, sealed); err == nil {
+		t.Error("sealed key opened with another secret")
+	}
+}
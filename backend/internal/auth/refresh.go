@@ -0,0 +1,39 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// NewRefreshToken returns a random refresh token for a session, along with the
+// hash to store for it. The session ID is part of the token so the session can
+// be found without storing the token itself.
+func NewRefreshToken(sessionID string) (token, hash string, err error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", err
+	}
+	token = sessionID + "." + base64.RawURLEncoding.EncodeToString(secret)
+	return token, HashToken(token), nil
+}
+
+// ParseRefreshToken returns the session ID a refresh token belongs to and the
+// token's hash
+func ParseRefreshToken(token string) (sessionID, hash string, err error) {
+	sessionID, secret, ok := strings.Cut(token, ".")
+	if !ok || sessionID == "" || secret == "" {
+		return "", "", errors.New("malformed refresh token")
+	}
+	return sessionID, HashToken(token), nil
+}
+
+// HashToken returns the SHA-256 hex digest of an opaque token. Tokens carry
+// enough entropy that a fast unsalted hash is sufficient.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,35 @@
+package auth
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+)
+
+// Test vectors from RFC 6238 appendix B (SHA-1), truncated to six digits
+func TestTOTPCodeMatchesRFC6238(t *testing.T) {
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+	vectors := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1234567890, "005924"},
+		{20000000000, "353130"},
+	}
+	for _, v := range vectors {
+		code, err := TOTPCode(secret, TOTPStep(time.Unix(v.unix, 0)))
+		if err != nil || code != v.code {
+			t.Errorf("code at %d = %s, %v; want %s", v.unix, code, err, v.code)
+		}
+	}
+
+	at := time.Unix(1111111109, 0)
+	if step, ok := ValidateTOTP(secret, "081804", at.Add(totpPeriod*time.Second)); !ok || step != TOTPStep(at) {
+		t.Errorf("code one step old = %d, %v; want accepted", step, ok)
+	}
+	if _, ok := ValidateTOTP(secret, "081804", at.Add(3*totpPeriod*time.Second)); ok {
+		t.Error("code three steps old was accepted")
+	}
+}
@@ -2,34 +2,125 @@ package config
 
 import (
 	"os"
+	"strconv"
 	"strings"
+	"time"
 )
 
+// DefaultJWTSecret is the JWT secret used when JWT_SECRET is not set. It is
+// public, so the server refuses to run with it in production.
+const DefaultJWTSecret = "your-secret-key-change-in-production"
+
 type Config struct {
 	Port                 string
 	JWTSecret            string
+	JWTAlgorithm         string        // RS256 or EdDSA, for signing access tokens
+	JWTKeyRotation       time.Duration // How long each signing key is used before the next takes over
+	AccessTokenTTL       time.Duration
+	RefreshTokenTTL      time.Duration // How long a session lasts without being refreshed
 	Environment          string
+	DevImpersonation     bool     // Let anyone sign in as any user; only honoured in development
+	AdminEmails          []string // Users made admins on startup, so someone can grant roles
 	CORSOrigins          []string
+	TrustedProxies       []string // Addresses or CIDR ranges of proxies whose X-Forwarded-For is believed
+	StorageDriver        string   // memory, postgres or sqlite
 	DatabaseURL          string
-	LibreTranslateURL    string // URL to LibreTranslate instance
-	LibreTranslateAPIKey string // Optional API key for public instance
+	SQLitePath           string // Database file used when StorageDriver is sqlite
+	AutoMigrate          bool   // Apply pending migrations on startup instead of refusing to start
+	SnapshotPath         string // File the in-memory repositories are snapshotted to; empty disables snapshots
+	SnapshotInterval     time.Duration
+	RestoreWindow        time.Duration // How long deleted posts, comments and communities can be restored
+	DeletedRetention     time.Duration // How long deleted content is kept before it is purged
+	PurgeInterval        time.Duration
+	FrontendURL          string // Base of the links sent in emails
+	SMTPHost             string // Mail server; without it mail goes to MailDir or the log
+	SMTPPort             int
+	SMTPUsername         string
+	SMTPPassword         string
+	MailFrom             string
+	MailDir              string // Directory emails are written to when SMTPHost is empty
+	TOTPIssuer           string // Name authenticator apps show for two-factor codes
+	LoginMaxFailures     int    // Failed sign-ins to an account before it is locked out
+	LoginIPMaxFailures   int    // Failed sign-ins from an IP address before it is locked out
+	LoginLockout         time.Duration
+	OIDCProviders        []OIDCProvider // Identity providers users can sign in with
+	LibreTranslateURL    string         // URL to LibreTranslate instance
+	LibreTranslateAPIKey string         // Optional API key for public instance
 }
 
 // LoadConfig loads configuration from environment variables
 func LoadConfig() *Config {
 	config := &Config{
 		Port:                 getEnv("PORT", "8080"),
-		JWTSecret:            getEnv("JWT_SECRET", "your-secret-key-change-in-production"),
+		JWTSecret:            getEnv("JWT_SECRET", DefaultJWTSecret),
+		JWTAlgorithm:         getEnv("JWT_ALGORITHM", "RS256"),
+		JWTKeyRotation:       time.Duration(getEnvInt("JWT_KEY_ROTATION_DAYS", 30)) * 24 * time.Hour,
+		AccessTokenTTL:       time.Duration(getEnvInt("ACCESS_TOKEN_TTL_MIN", 15)) * time.Minute,
+		RefreshTokenTTL:      time.Duration(getEnvInt("REFRESH_TOKEN_TTL_DAYS", 30)) * 24 * time.Hour,
 		Environment:          getEnv("ENVIRONMENT", "development"),
+		DevImpersonation:     getEnv("DEV_IMPERSONATION", "false") == "true",
+		AdminEmails:          getList("ADMIN_EMAILS"),
+		StorageDriver:        getStorageDriver(),
 		DatabaseURL:          getEnv("DATABASE_URL", ""),
+		SQLitePath:           getEnv("SQLITE_PATH", "data/v.db"),
+		AutoMigrate:          getEnv("AUTO_MIGRATE", "false") == "true",
+		SnapshotPath:         getEnv("SNAPSHOT_PATH", ""),
+		SnapshotInterval:     time.Duration(getEnvInt("SNAPSHOT_INTERVAL_SEC", 300)) * time.Second,
+		RestoreWindow:        time.Duration(getEnvInt("RESTORE_WINDOW_HOURS", 72)) * time.Hour,
+		DeletedRetention:     time.Duration(getEnvInt("DELETED_RETENTION_DAYS", 30)) * 24 * time.Hour,
+		PurgeInterval:        time.Duration(getEnvInt("PURGE_INTERVAL_MIN", 60)) * time.Minute,
+		FrontendURL:          strings.TrimSuffix(getEnv("FRONTEND_URL", "http://localhost:3000"), "/"),
+		SMTPHost:             getEnv("SMTP_HOST", ""),
+		SMTPPort:             getEnvInt("SMTP_PORT", 587),
+		SMTPUsername:         getEnv("SMTP_USERNAME", ""),
+		SMTPPassword:         getEnv("SMTP_PASSWORD", ""),
+		MailFrom:             getEnv("MAIL_FROM", "no-reply@localhost"),
+		MailDir:              getEnv("MAIL_DIR", ""),
+		TOTPIssuer:           getEnv("TOTP_ISSUER", "V"),
+		LoginMaxFailures:     getEnvInt("LOGIN_MAX_FAILURES", 10),
+		LoginIPMaxFailures:   getEnvInt("LOGIN_IP_MAX_FAILURES", 100),
+		LoginLockout:         time.Duration(getEnvInt("LOGIN_LOCKOUT_MIN", 15)) * time.Minute,
 		LibreTranslateURL:    getEnv("LIBRETRANSLATE_URL", "https://libretranslate.com"),
 		LibreTranslateAPIKey: getEnv("LIBRETRANSLATE_API_KEY", ""),
 		CORSOrigins:          getCORSOrigins(),
+		TrustedProxies:       getList("TRUSTED_PROXIES"),
 	}
+	config.OIDCProviders = getOIDCProviders(config.FrontendURL)
 
 	return config
 }
 
+// OIDCProvider is an OpenID Connect provider registered with this backend
+type OIDCProvider struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// getOIDCProviders reads the providers named in OIDC_PROVIDERS, each from
+// OIDC_<NAME>_ISSUER, _CLIENT_ID, _CLIENT_SECRET and optionally _SCOPES and
+// _REDIRECT_URL. Providers redirect back to the frontend, which posts the code
+// to the API.
+func getOIDCProviders(frontendURL string) []OIDCProvider {
+	var providers []OIDCProvider
+	for _, name := range getList("OIDC_PROVIDERS") {
+		name = strings.ToLower(name)
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		providers = append(providers, OIDCProvider{
+			Name:         name,
+			Issuer:       getEnv(prefix+"ISSUER", ""),
+			ClientID:     getEnv(prefix+"CLIENT_ID", ""),
+			ClientSecret: getEnv(prefix+"CLIENT_SECRET", ""),
+			RedirectURL:  getEnv(prefix+"REDIRECT_URL", frontendURL+"/auth/callback/"+name),
+			Scopes:       getList(prefix + "SCOPES"),
+		})
+	}
+	return providers
+}
+
 func getEnv(key, defaultValue string) string {
 	value := os.Getenv(key)
 	if value == "" {
@@ -38,6 +129,36 @@ func getEnv(key, defaultValue string) string {
 	return value
 }
 
+func getEnvInt(key string, defaultValue int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil && value > 0 {
+		return value
+	}
+	return defaultValue
+}
+
+// getStorageDriver defaults to postgres when DATABASE_URL is set and to
+// in-memory storage otherwise
+func getStorageDriver() string {
+	if driver := os.Getenv("STORAGE_DRIVER"); driver != "" {
+		return strings.ToLower(driver)
+	}
+	if os.Getenv("DATABASE_URL") != "" {
+		return "postgres"
+	}
+	return "memory"
+}
+
+// getList splits a comma separated variable, dropping empty entries
+func getList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
 func getCORSOrigins() []string {
 	origins := os.Getenv("CORS_ORIGINS")
 	if origins == "" {
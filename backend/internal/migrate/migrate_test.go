@@ -0,0 +1,77 @@
+package migrate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadOrdersByVersion(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0002_add_index.up.sql":        {Data: []byte("CREATE INDEX i ON t (a);")},
+		"0002_add_index.down.sql":      {Data: []byte("DROP INDEX i;")},
+		"0001_initial_schema.up.sql":   {Data: []byte("CREATE TABLE t (a INT);")},
+		"0001_initial_schema.down.sql": {Data: []byte("DROP TABLE t;")},
+		"0010_no_down.up.sql":          {Data: []byte("SELECT 1;")},
+		"README.md":                    {Data: []byte("ignored")},
+	}
+
+	migrations, err := Load(fsys)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(migrations) != 3 {
+		t.Fatalf("got %d migrations, want 3", len(migrations))
+	}
+
+	want := []struct {
+		version int64
+		name    string
+	}{{1, "initial_schema"}, {2, "add_index"}, {10, "no_down"}}
+	for i, w := range want {
+		if migrations[i].Version != w.version || migrations[i].Name != w.name {
+			t.Errorf("migration %d = %d_%s, want %d_%s", i, migrations[i].Version, migrations[i].Name, w.version, w.name)
+		}
+	}
+	if migrations[0].Down != "DROP TABLE t;" || migrations[2].Down != "" {
+		t.Errorf("down scripts not paired correctly")
+	}
+}
+
+func TestLoadRejectsInvalidFiles(t *testing.T) {
+	cases := map[string]fstest.MapFS{
+		"bad name":         {"initial.sql": {Data: []byte("SELECT 1;")}},
+		"missing up":       {"0001_initial.down.sql": {Data: []byte("SELECT 1;")}},
+		"conflicting name": {"0001_a.up.sql": {Data: []byte("SELECT 1;")}, "0001_b.down.sql": {Data: []byte("SELECT 1;")}},
+	}
+	for name, fsys := range cases {
+		if _, err := Load(fsys); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestCreateNumbersAfterExisting(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "0007_existing.up.sql"), []byte("SELECT 1;"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	up, down, err := Create(dir, "Add Sessions Table")
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if filepath.Base(up) != "0008_add_sessions_table.up.sql" || filepath.Base(down) != "0008_add_sessions_table.down.sql" {
+		t.Errorf("created %s, %s", up, down)
+	}
+
+	// The new pair must itself load
+	if _, err := Load(os.DirFS(dir)); err != nil {
+		t.Errorf("load after create: %v", err)
+	}
+
+	if _, _, err := Create(dir, "!!!"); err == nil {
+		t.Error("expected error for empty name")
+	}
+}
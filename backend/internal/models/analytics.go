@@ -29,6 +29,5 @@ type PostMetrics struct {
 
 type ImpressionRequest struct {
 	PostID string `json:"postId" validate:"required"`
-	UserID string `json:"userId" validate:"required"`
 }
 
@@ -10,14 +10,145 @@ type Auth struct {
 	UpdatedAt    time.Time `json:"updatedAt"`
 }
 
-type Session struct {
-	ID        string    `json:"id"`
+// Identity links an account to a user at an OpenID Connect provider
+type Identity struct {
+	Provider  string    `json:"provider"`
+	Subject   string    `json:"subject"` // The provider's ID for the user
 	UserID    string    `json:"userId"`
-	Token     string    `json:"token"`
-	ExpiresAt time.Time `json:"expiresAt"`
+	Email     string    `json:"email"` // As the provider reported it when linked
 	CreatedAt time.Time `json:"createdAt"`
 }
 
+// Session is one signed-in device. It holds the hash of the refresh token
+// most recently issued to it; every refresh rotates the token.
+type Session struct {
+	ID                string     `json:"id"`
+	UserID            string     `json:"userId"`
+	TokenHash         string     `json:"-"` // SHA-256 of the current refresh token
+	PreviousTokenHash string     `json:"-"` // SHA-256 of the refresh token rotated away from last
+	UserAgent         string     `json:"userAgent"`
+	IPAddress         string     `json:"ipAddress"`
+	ExpiresAt         time.Time  `json:"expiresAt"`
+	CreatedAt         time.Time  `json:"createdAt"`
+	LastUsedAt        time.Time  `json:"lastUsedAt"`
+	RevokedAt         *time.Time `json:"revokedAt,omitempty"`
+	Current           bool       `json:"current"` // Set on the session making the request when listing
+}
+
+// Active reports whether the session can still be used at t
+func (s *Session) Active(t time.Time) bool {
+	return s.RevokedAt == nil && t.Before(s.ExpiresAt)
+}
+
+// TwoFactor is a user's TOTP enrollment. It is pending until the user confirms
+// it with a code from their authenticator app.
+type TwoFactor struct {
+	UserID        string
+	Secret        string // Base32 TOTP secret
+	Enabled       bool
+	RecoveryCodes []string // bcrypt hashes of the unused recovery codes
+	LastUsedStep  int64    // Time step of the last accepted code, so codes cannot be replayed
+	CreatedAt     time.Time
+	EnabledAt     *time.Time
+}
+
+// LoginThrottle counts the recent failed sign-ins of an account or an IP
+// address. Key is "account:<email>" or "ip:<address>".
+type LoginThrottle struct {
+	Key           string
+	Failures      int
+	LastFailureAt time.Time
+	BlockedUntil  *time.Time // No sign-in attempts are accepted before this
+}
+
+// SigningKey is a key access tokens are signed with. PrivateKey is sealed with
+// the JWT secret. A key is published before it activates, signs tokens until it
+// retires, and is published until every token it signed has expired.
+type SigningKey struct {
+	ID          string // The kid in token headers
+	Algorithm   string // RS256 or EdDSA
+	PrivateKey  []byte
+	CreatedAt   time.Time
+	ActivatesAt time.Time
+	RetiresAt   time.Time
+	ExpiresAt   time.Time
+}
+
+// APIScope is something an API key may do
+type APIScope string
+
+const (
+	ScopeReadPosts         APIScope = "read:posts"
+	ScopeWritePosts        APIScope = "write:posts" // Posts, comments, reactions and saves
+	ScopeReadDebates       APIScope = "read:debates"
+	ScopeWriteDebates      APIScope = "write:debates"
+	ScopeReadHashtags      APIScope = "read:hashtags"
+	ScopeWriteHashtags     APIScope = "write:hashtags"
+	ScopeReadUsers         APIScope = "read:users"
+	ScopeReadNotifications APIScope = "read:notifications"
+)
+
+// APIScopes lists every scope an API key can be given
+var APIScopes = []APIScope{
+	ScopeReadPosts, ScopeWritePosts, ScopeReadDebates, ScopeWriteDebates,
+	ScopeReadHashtags, ScopeWriteHashtags, ScopeReadUsers, ScopeReadNotifications,
+}
+
+// Valid reports whether s is one of APIScopes
+func (s APIScope) Valid() bool {
+	for _, scope := range APIScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKey lets an integration act as a user or bot within its scopes. Only the
+// hash of the key is stored.
+type APIKey struct {
+	ID         string     `json:"id"`
+	UserID     string     `json:"userId"`
+	Name       string     `json:"name"`
+	KeyHash    string     `json:"-"`
+	Scopes     []APIScope `json:"scopes"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+}
+
+// HasScope reports whether the key was given scope
+func (k *APIKey) HasScope(scope APIScope) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// EmailTokenPurpose says what an EmailToken may be used for
+type EmailTokenPurpose string
+
+const (
+	EmailTokenVerify EmailTokenPurpose = "verify_email"
+	EmailTokenReset  EmailTokenPurpose = "reset_password"
+)
+
+// EmailToken is a single-use token mailed to a user to prove they own their
+// email address. Only its hash is stored.
+type EmailToken struct {
+	ID        string
+	UserID    string
+	Purpose   EmailTokenPurpose
+	Email     string // Address the token was sent to
+	TokenHash string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	UsedAt    *time.Time
+}
+
 type LoginRequest struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
@@ -36,6 +167,102 @@ type SignupRequest struct {
 }
 
 type AuthResponse struct {
+	Token        string `json:"token"` // Short-lived access token
+	RefreshToken string `json:"refreshToken"`
+	ExpiresIn    int    `json:"expiresIn"` // Seconds until Token expires
+	User         *User  `json:"user"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
+type VerifyEmailRequest struct {
 	Token string `json:"token"`
-	User  *User  `json:"user"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email"`
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"newPassword"`
+}
+
+// TwoFactorChallenge is returned by login instead of an AuthResponse when the
+// account has two-factor authentication on
+type TwoFactorChallenge struct {
+	TwoFactorRequired bool   `json:"twoFactorRequired"`
+	ChallengeToken    string `json:"challengeToken"`
+	ExpiresIn         int    `json:"expiresIn"` // Seconds until ChallengeToken expires
+}
+
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challengeToken"`
+	Code           string `json:"code"` // Code from the authenticator app, or a recovery code
+}
+
+type CreateAPIKeyRequest struct {
+	Name          string     `json:"name"`
+	Scopes        []APIScope `json:"scopes"`
+	ExpiresInDays int        `json:"expiresInDays,omitempty"` // Never expires when 0
+}
+
+// CreateAPIKeyResponse carries the key itself, which is only ever shown once
+type CreateAPIKeyResponse struct {
+	Key    string  `json:"key"`
+	APIKey *APIKey `json:"apiKey"`
+}
+
+type CreateBotRequest struct {
+	Name   string `json:"name"`
+	Handle string `json:"handle"`
+	Bio    string `json:"bio"`
+}
+
+// ImpersonateRequest is the body of the development-only impersonation endpoint
+type ImpersonateRequest struct {
+	User string `json:"user"` // ID or handle
+}
+
+type OIDCAuthorizeResponse struct {
+	AuthorizationURL string `json:"authorizationUrl"` // Send the user here
+	FlowToken        string `json:"flowToken"`        // Keep until the provider redirects back
+}
+
+type OIDCCallbackRequest struct {
+	Code      string `json:"code"`
+	State     string `json:"state"`
+	FlowToken string `json:"flowToken"`
+}
+
+// OIDCSignupRequired is returned by the OIDC callback instead of an
+// AuthResponse when the identity has no account yet
+type OIDCSignupRequired struct {
+	SignupRequired  bool   `json:"signupRequired"`
+	SignupToken     string `json:"signupToken"`
+	ExpiresIn       int    `json:"expiresIn"` // Seconds until SignupToken expires
+	SuggestedHandle string `json:"suggestedHandle"`
+	Name            string `json:"name"`
+	Email           string `json:"email"`
+}
+
+type OIDCSignupRequest struct {
+	SignupToken string `json:"signupToken"`
+	Handle      string `json:"handle"`
+}
+
+type TwoFactorSetupResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauthUri"`
+}
+
+type TwoFactorStatus struct {
+	Enabled           bool `json:"enabled"`
+	RecoveryCodesLeft int  `json:"recoveryCodesLeft"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recoveryCodes"` // Shown once; only hashes are kept
 }
@@ -31,8 +31,10 @@ type Community struct {
 	MemberCount int               `json:"memberCount"`
 	PostCount   int               `json:"postCount"`
 
-	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+	Version   int        `json:"version"`             // Incremented on every write, used for optimistic locking
+	DeletedAt *time.Time `json:"deletedAt,omitempty"` // Set when soft-deleted, until purged
 }
 
 type CommunityMember struct {
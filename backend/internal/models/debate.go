@@ -3,24 +3,33 @@ package models
 import "time"
 
 type Debate struct {
-	ID               string     `json:"id"`
-	Title            string     `json:"title"`
-	Description      string     `json:"description"`
-	Category         string     `json:"category"`
-	HostID           string     `json:"hostId"`
-	Type             string     `json:"type"`   // "PUBLIC" or "PRIVATE"
-	Status           string     `json:"status"` // "SCHEDULED", "ACTIVE", "ENDED"
-	StartTime        time.Time  `json:"startTime"`
-	EndTime          *time.Time `json:"endTime,omitempty"`
-	DurationMinutes  int        `json:"durationMinutes"` // 30, 60, 360, 1440
-	ShowInPulse      bool       `json:"showInPulse"`     // Only applies to PUBLIC debates
-	AgreeCount       int        `json:"agreeCount"`
-	DisagreeCount    int        `json:"disagreeCount"`
-	CreatedAt        time.Time  `json:"createdAt"`
-	UpdatedAt        time.Time  `json:"updatedAt"`
-	IsLocked         bool       `json:"isLocked"`
-	UnlockPhase      int        `json:"unlockPhase"`
-	EarlyAccessRoles []string   `json:"earlyAccessRoles,omitempty"`
+	ID               string        `json:"id"`
+	Title            string        `json:"title"`
+	Description      string        `json:"description"`
+	Category         string        `json:"category"`
+	HostID           string        `json:"hostId"`
+	Type             string        `json:"type"`   // "PUBLIC" or "PRIVATE"
+	Status           string        `json:"status"` // "SCHEDULED", "ACTIVE", "ENDED"
+	StartTime        time.Time     `json:"startTime"`
+	EndTime          *time.Time    `json:"endTime,omitempty"`
+	DurationMinutes  int           `json:"durationMinutes"` // 30, 60, 360, 1440
+	ShowInPulse      bool          `json:"showInPulse"`     // Only applies to PUBLIC debates
+	AgreeCount       int           `json:"agreeCount"`
+	DisagreeCount    int           `json:"disagreeCount"`
+	CreatedAt        time.Time     `json:"createdAt"`
+	UpdatedAt        time.Time     `json:"updatedAt"`
+	Version          int           `json:"version"`                    // Incremented on every write, used for optimistic locking
+	IsLocked         bool          `json:"isLocked"`                   // Closed to users without early access
+	UnlockPhase      int           `json:"unlockPhase"`                // UnlockPhaseEarlyAccess or UnlockPhasePublic; 0 without early access
+	EarlyAccessRoles []string      `json:"earlyAccessRoles,omitempty"` // Who may join while locked
+	UnlockAt         *time.Time    `json:"unlockAt,omitempty"`         // When a locked debate opens to everyone
+	Format           string        `json:"format"`                     // One of DebateFormats; open floor when empty
+	CurrentRound     int           `json:"currentRound"`               // Index into the format's rounds
+	RoundStartedAt   *time.Time    `json:"roundStartedAt,omitempty"`   // Set once the first round starts
+	QueueOrder       string        `json:"queueOrder"`                 // QueueOrderFIFO (the default when empty) or QueueOrderAlternating
+	MaxTurnSeconds   int           `json:"maxTurnSeconds"`             // How long an approved speaker has; 0 for no limit
+	PollClosesAt     *time.Time    `json:"pollClosesAt,omitempty"`     // When the post poll closes; set once the debate ends
+	Result           *DebateResult `json:"result,omitempty"`           // Set when the post poll closes
 }
 
 type DebateParticipant struct {
@@ -36,9 +45,29 @@ type DebateParticipant struct {
 }
 
 type SpeakRequest struct {
-	ID        string    `json:"id"`
-	DebateID  string    `json:"debateId"`
-	UserID    string    `json:"userId"`
-	Status    string    `json:"status"` // "pending", "approved", "denied"
-	CreatedAt time.Time `json:"createdAt"`
+	ID            string     `json:"id"`
+	DebateID      string     `json:"debateId"`
+	UserID        string     `json:"userId"`
+	Side          string     `json:"side"`     // The requester's side when they asked
+	Status        string     `json:"status"`   // "pending", "approved" (speaking), "denied", "done"
+	Position      int        `json:"position"` // Set when the host reorders the queue; 0 until then
+	TurnStartedAt *time.Time `json:"turnStartedAt,omitempty"`
+	TurnEndsAt    *time.Time `json:"turnEndsAt,omitempty"` // Unset when turns have no limit
+	CreatedAt     time.Time  `json:"createdAt"`
+}
+
+// Speaker queue orders
+const (
+	QueueOrderFIFO        = "fifo"        // First come, first served
+	QueueOrderAlternating = "alternating" // Agree and disagree take turns, each first come, first served
+)
+
+// SpeakerQueue is a debate's live speak requests: who has the floor and who is
+// up next, in order
+type SpeakerQueue struct {
+	DebateID       string          `json:"debateId"`
+	Order          string          `json:"order"`
+	MaxTurnSeconds int             `json:"maxTurnSeconds"`
+	Speaking       []*SpeakRequest `json:"speaking"`
+	Waiting        []*SpeakRequest `json:"waiting"`
 }
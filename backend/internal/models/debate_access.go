@@ -0,0 +1,25 @@
+package models
+
+import "strings"
+
+// Debate access phases. A debate with early access starts locked in
+// UnlockPhaseEarlyAccess, open only to the host and users holding one of its
+// EarlyAccessRoles, and moves to UnlockPhasePublic at its UnlockAt or when the
+// host opens it. Debates without early access are public from the start.
+const (
+	UnlockPhaseEarlyAccess = 1
+	UnlockPhasePublic      = 2
+)
+
+// Early access roles
+const (
+	EarlyAccessFollowers       = "followers"  // The host's followers
+	EarlyAccessPlatinum        = "platinum"   // Platinum tier users
+	EarlyAccessCommunityPrefix = "community:" // Active members of a community, as "community:<id>"
+)
+
+// EarlyAccessCommunity returns the community a role admits the members of
+func EarlyAccessCommunity(role string) (string, bool) {
+	id, ok := strings.CutPrefix(role, EarlyAccessCommunityPrefix)
+	return id, ok && id != ""
+}
@@ -0,0 +1,81 @@
+package models
+
+import "time"
+
+// Debate formats
+const (
+	FormatOpenFloor      = "open_floor"
+	FormatOxford         = "oxford"
+	FormatLincolnDouglas = "lincoln_douglas"
+)
+
+// DebateRound is one stage of a format. While it runs only the side it
+// belongs to may speak; the rest are muted by the host.
+type DebateRound struct {
+	Name    string `json:"name"`
+	Side    string `json:"side"` // "agree", "disagree", or "" when both sides may speak
+	Seconds int    `json:"seconds"`
+}
+
+// DebateFormat is an ordered list of rounds. A format without rounds is an
+// open floor that lasts the debate's duration.
+type DebateFormat struct {
+	ID     string        `json:"id"`
+	Name   string        `json:"name"`
+	Rounds []DebateRound `json:"rounds"`
+}
+
+// Duration is how long all the rounds take
+func (f *DebateFormat) Duration() time.Duration {
+	var total time.Duration
+	for _, round := range f.Rounds {
+		total += time.Duration(round.Seconds) * time.Second
+	}
+	return total
+}
+
+// DebateFormats lists the formats debates can be held in
+var DebateFormats = []*DebateFormat{
+	{ID: FormatOpenFloor, Name: "Open floor"},
+	{ID: FormatOxford, Name: "Oxford", Rounds: []DebateRound{
+		{Name: "Opening (for)", Side: "agree", Seconds: 7 * 60},
+		{Name: "Opening (against)", Side: "disagree", Seconds: 7 * 60},
+		{Name: "Rebuttal (for)", Side: "agree", Seconds: 4 * 60},
+		{Name: "Rebuttal (against)", Side: "disagree", Seconds: 4 * 60},
+		{Name: "Closing (for)", Side: "agree", Seconds: 3 * 60},
+		{Name: "Closing (against)", Side: "disagree", Seconds: 3 * 60},
+	}},
+	{ID: FormatLincolnDouglas, Name: "Lincoln-Douglas", Rounds: []DebateRound{
+		{Name: "Affirmative constructive", Side: "agree", Seconds: 6 * 60},
+		{Name: "Cross-examination", Seconds: 3 * 60},
+		{Name: "Negative constructive", Side: "disagree", Seconds: 7 * 60},
+		{Name: "Cross-examination", Seconds: 3 * 60},
+		{Name: "First affirmative rebuttal", Side: "agree", Seconds: 4 * 60},
+		{Name: "Negative rebuttal", Side: "disagree", Seconds: 6 * 60},
+		{Name: "Second affirmative rebuttal", Side: "agree", Seconds: 3 * 60},
+	}},
+}
+
+// FindDebateFormat returns the format with id. An empty id is an open floor.
+func FindDebateFormat(id string) (*DebateFormat, bool) {
+	if id == "" {
+		id = FormatOpenFloor
+	}
+	for _, format := range DebateFormats {
+		if format.ID == id {
+			return format, true
+		}
+	}
+	return nil, false
+}
+
+// RoundStatus is the round a debate is in and how long it has left
+type RoundStatus struct {
+	DebateID         string      `json:"debateId"`
+	Index            int         `json:"index"`
+	TotalRounds      int         `json:"totalRounds"`
+	Round            DebateRound `json:"round"`
+	StartedAt        time.Time   `json:"startedAt"`
+	EndsAt           time.Time   `json:"endsAt"`
+	RemainingSeconds int         `json:"remainingSeconds"`
+}
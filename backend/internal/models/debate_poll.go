@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// Audience poll phases
+const (
+	PollPre  = "pre"  // Opens when the debate is created, closes shortly after it starts
+	PollPost = "post" // Opens when the debate ends, closes when the result is decided
+)
+
+// DebateVote is one audience member's answer to one of a debate's polls
+type DebateVote struct {
+	DebateID  string    `json:"debateId"`
+	UserID    string    `json:"userId"`
+	Phase     string    `json:"phase"` // PollPre or PollPost
+	Side      string    `json:"side"`  // "agree", "disagree" or "undecided"
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// VoteTally counts a poll's votes per side
+type VoteTally struct {
+	Agree     int `json:"agree"`
+	Disagree  int `json:"disagree"`
+	Undecided int `json:"undecided"`
+}
+
+// Total is how many votes the poll got
+func (t VoteTally) Total() int {
+	return t.Agree + t.Disagree + t.Undecided
+}
+
+// DebateResult is how the audience's votes moved over a debate and which side
+// won for it. A side's swing is how much its share of the vote grew from the
+// pre poll to the post poll, in percentage points; the larger swing wins. The
+// tallies only count users who voted in both polls.
+type DebateResult struct {
+	WinningSide   string    `json:"winningSide"` // "agree" or "disagree"; empty for a draw
+	Pre           VoteTally `json:"pre"`
+	Post          VoteTally `json:"post"`
+	AgreeSwing    float64   `json:"agreeSwing"`
+	DisagreeSwing float64   `json:"disagreeSwing"`
+	Winners       []string  `json:"winners"` // Speakers on the winning side, awarded points
+	DecidedAt     time.Time `json:"decidedAt"`
+}
+
+// DebatePoll is the running count of a debate's polls
+type DebatePoll struct {
+	DebateID string        `json:"debateId"`
+	Pre      VoteTally     `json:"pre"`
+	Post     VoteTally     `json:"post"`
+	PreOpen  bool          `json:"preOpen"`
+	PostOpen bool          `json:"postOpen"`
+	Voted    []string      `json:"voted,omitempty"` // The phases the signed-in user voted in
+	Result   *DebateResult `json:"result,omitempty"`
+}
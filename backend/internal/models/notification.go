@@ -30,5 +30,4 @@ type CreateNotificationRequest struct {
 	CommunityID   *string `json:"communityId,omitempty"`
 	CommunityName *string `json:"communityName,omitempty"`
 	PostID        *string `json:"postId,omitempty"`
-	ActorID       *string `json:"actorId,omitempty"`
 }
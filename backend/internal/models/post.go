@@ -14,6 +14,7 @@ const (
 type Post struct {
 	ID               string `json:"id"`
 	AuthorID         string `json:"authorId"`
+	AuthorIsBot      bool   `json:"authorIsBot"` // Posted by a bot account
 	Content          string `json:"content"`
 	MediaType        string `json:"mediaType,omitempty"` // "image" or "video"
 	MediaURL         string `json:"mediaUrl,omitempty"`
@@ -41,18 +42,21 @@ type Post struct {
 	OriginalLanguage string            `json:"originalLanguage,omitempty"` // Detected/specified language code (e.g., "en", "te")
 	Translations     map[string]string `json:"translations,omitempty"`     // Cached translations {"te": "...", "hi": "..."}
 
-	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+	Version   int        `json:"version"`             // Incremented on every write, used for optimistic locking
+	DeletedAt *time.Time `json:"deletedAt,omitempty"` // Set when soft-deleted, until purged
 }
 
 type Comment struct {
-	ID        string    `json:"id"`
-	PostID    string    `json:"postId"`
-	AuthorID  string    `json:"authorId"`
-	ParentID  *string   `json:"parentId,omitempty"`
-	Content   string    `json:"content"`
-	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
+	ID        string     `json:"id"`
+	PostID    string     `json:"postId"`
+	AuthorID  string     `json:"authorId"`
+	ParentID  *string    `json:"parentId,omitempty"`
+	Content   string     `json:"content"`
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+	DeletedAt *time.Time `json:"deletedAt,omitempty"` // Set when soft-deleted, until purged
 }
 
 type Reaction struct {
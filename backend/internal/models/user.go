@@ -9,11 +9,32 @@ const (
 	TierPlatinum UserTier = "PLATINUM"
 )
 
+// PlatformRole decides what a user may do beyond their own content. It is not
+// to be confused with CommunityRole, which only applies inside one community.
+type PlatformRole string
+
+const (
+	PlatformRoleUser      PlatformRole = "user"
+	PlatformRoleModerator PlatformRole = "moderator" // Reviews reported posts and removes any post
+	PlatformRoleAdmin     PlatformRole = "admin"     // Everything, including granting roles
+	PlatformRoleSupport   PlatformRole = "support"   // Lifts sign-in lockouts for users
+)
+
+// Valid reports whether r is one of the known roles
+func (r PlatformRole) Valid() bool {
+	switch r {
+	case PlatformRoleUser, PlatformRoleModerator, PlatformRoleAdmin, PlatformRoleSupport:
+		return true
+	}
+	return false
+}
+
 type User struct {
 	ID                    string   `json:"id"`
 	Name                  string   `json:"name"`
 	Handle                string   `json:"handle"`
 	Email                 string   `json:"email"`
+	EmailVerified         bool     `json:"emailVerified"`
 	Password              string   `json:"-"` // Internal use only, never exposed via JSON
 	PhoneNumber           string   `json:"phoneNumber"`
 	Languages             []string `json:"languages"`
@@ -27,6 +48,13 @@ type User struct {
 	FollowingCount        int      `json:"followingCount"`
 	PostsCount            int      `json:"postsCount"`
 
+	// Platform role
+	Role PlatformRole `json:"role"` // user, moderator, admin or support
+
+	// Bot accounts are run by integrations through API keys and cannot sign in
+	IsBot      bool   `json:"isBot"`
+	BotOwnerID string `json:"botOwnerId,omitempty"` // The user who created the bot and manages its keys
+
 	// Tier and Points System
 	Tier               UserTier   `json:"tier"`                 // SILVER or PLATINUM
 	Points             int        `json:"points"`               // User points
@@ -48,6 +76,7 @@ type User struct {
 
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
+	Version   int       `json:"version"` // Incremented on every write, used for optimistic locking
 }
 
 type Follow struct {
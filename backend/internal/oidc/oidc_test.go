@@ -0,0 +1,108 @@
+package oidc_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/yourusername/v-backend/internal/oidc"
+	"github.com/yourusername/v-backend/internal/oidc/oidctest"
+)
+
+func newProvider(t *testing.T) (*oidc.Provider, *oidctest.Server) {
+	t.Helper()
+	server := oidctest.NewServer("client", "secret")
+	t.Cleanup(server.Close)
+	server.SetUser(oidctest.User{Subject: "123", Email: "alice@example.com", EmailVerified: true, Name: "Alice"})
+
+	provider := oidc.NewProvider(oidc.Config{
+		Name:         "test",
+		Issuer:       server.URL,
+		ClientID:     "client",
+		ClientSecret: "secret",
+		RedirectURL:  "http://localhost:3000/auth/callback/test",
+	}, nil)
+	return provider, server
+}
+
+func TestCodeFlowWithPKCE(t *testing.T) {
+	provider, server := newProvider(t)
+	ctx := context.Background()
+
+	authURL, err := provider.AuthCodeURL(ctx, "state", "nonce", oidc.CodeChallenge("verifier"))
+	if err != nil {
+		t.Fatalf("auth URL: %v", err)
+	}
+	code, state, err := server.Authorize(authURL)
+	if err != nil || state != "state" {
+		t.Fatalf("authorize = %q, %q, %v", code, state, err)
+	}
+
+	claims, err := provider.Exchange(ctx, code, "verifier", "nonce")
+	if err != nil {
+		t.Fatalf("exchange: %v", err)
+	}
+	if claims.Subject != "123" || claims.Email != "alice@example.com" || !claims.EmailVerified || claims.Name != "Alice" {
+		t.Errorf("claims = %+v", claims)
+	}
+
+	// Codes work once
+	if _, err := provider.Exchange(ctx, code, "verifier", "nonce"); err == nil {
+		t.Error("code was accepted twice")
+	}
+}
+
+func TestExchangeRejectsWrongVerifier(t *testing.T) {
+	provider, server := newProvider(t)
+	ctx := context.Background()
+
+	authURL, _ := provider.AuthCodeURL(ctx, "state", "nonce", oidc.CodeChallenge("verifier"))
+	code, _, _ := server.Authorize(authURL)
+	if _, err := provider.Exchange(ctx, code, "stolen", "nonce"); err == nil {
+		t.Error("exchange with the wrong verifier succeeded")
+	}
+}
+
+func TestVerifyRejectsBadTokens(t *testing.T) {
+	provider, server := newProvider(t)
+	now := time.Now()
+	valid := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":   server.URL,
+			"aud":   "client",
+			"sub":   "123",
+			"iat":   now.Unix(),
+			"exp":   now.Add(time.Hour).Unix(),
+			"nonce": "nonce",
+		}
+	}
+
+	if _, err := provider.Verify(context.Background(), server.SignToken(valid()), "nonce"); err != nil {
+		t.Fatalf("valid token rejected: %v", err)
+	}
+
+	cases := map[string]func(jwt.MapClaims){
+		"wrong issuer":   func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" },
+		"wrong audience": func(c jwt.MapClaims) { c["aud"] = "another-client" },
+		"expired":        func(c jwt.MapClaims) { c["exp"] = now.Add(-time.Hour).Unix() },
+		"no expiry":      func(c jwt.MapClaims) { delete(c, "exp") },
+		"wrong nonce":    func(c jwt.MapClaims) { c["nonce"] = "replayed" },
+		"other azp":      func(c jwt.MapClaims) { c["aud"] = []string{"client", "another-client"}; c["azp"] = "another-client" },
+	}
+	for name, modify := range cases {
+		claims := valid()
+		modify(claims)
+		if _, err := provider.Verify(context.Background(), server.SignToken(claims), "nonce"); !errors.Is(err, oidc.ErrInvalidIDToken) {
+			t.Errorf("%s: verify = %v, want invalid", name, err)
+		}
+	}
+
+	// Signed with a key the provider does not publish
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, valid())
+	signed, _ := forged.SignedString([]byte("secret"))
+	if _, err := provider.Verify(context.Background(), signed, "nonce"); !errors.Is(err, oidc.ErrInvalidIDToken) {
+		t.Errorf("HS256 token: verify = %v, want invalid", err)
+	}
+}
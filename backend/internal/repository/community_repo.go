@@ -1,13 +1,22 @@
 package repository
 
-import "github.com/yourusername/v-backend/internal/models"
+import (
+	"time"
 
+	"github.com/yourusername/v-backend/internal/models"
+)
+
+// CommunityRepository defines the interface for community data access.
+// Deleting a community is soft, as for posts.
 type CommunityRepository interface {
 	Create(community *models.Community) error
 	GetByID(id string) (*models.Community, error)
+	GetDeletedByID(id string) (*models.Community, error)
 	Update(community *models.Community) error
 	List() ([]*models.Community, error)
 	Delete(id string) error
+	Restore(id string, deletedSince time.Time) error // ErrRestoreWindowExpired if deleted before deletedSince
+	Purge(cutoff time.Time) (int, error)
 
 	// Member management
 	AddMember(member *models.CommunityMember) error
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ConflictError is returned by Update when the stored entity changed after the
+// caller read it, so the caller's Version no longer matches. The caller should
+// re-read the entity and reapply its change.
+type ConflictError struct {
+	Entity  string
+	ID      string
+	Version int // the version currently stored
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%s %s was modified concurrently (now at version %d)", e.Entity, e.ID, e.Version)
+}
+
+// IsConflict reports whether err is or wraps a *ConflictError
+func IsConflict(err error) bool {
+	var conflict *ConflictError
+	return errors.As(err, &conflict)
+}
+
+// RetryOnConflict runs fn, which should read, modify and update an entity,
+// until it succeeds, fails with an error other than a conflict, or has been
+// tried attempts times
+func RetryOnConflict(attempts int, fn func() error) error {
+	var err error
+	for i := 0; i < attempts; i++ {
+		if err = fn(); !IsConflict(err) {
+			return err
+		}
+	}
+	return err
+}
@@ -0,0 +1,6 @@
+package repository
+
+import "errors"
+
+// ErrAlreadyVoted is returned when a user votes a second time in the same poll
+var ErrAlreadyVoted = errors.New("already voted in this poll")
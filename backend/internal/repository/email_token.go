@@ -0,0 +1,7 @@
+package repository
+
+import "errors"
+
+// ErrInvalidEmailToken is returned by Consume for tokens that are unknown,
+// used, expired or meant for something else
+var ErrInvalidEmailToken = errors.New("invalid or expired token")
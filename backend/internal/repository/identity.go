@@ -0,0 +1,6 @@
+package repository
+
+import "errors"
+
+// ErrIdentityLinked is returned when linking an identity that is already linked
+var ErrIdentityLinked = errors.New("identity is already linked")
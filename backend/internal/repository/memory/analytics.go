@@ -1,7 +1,6 @@
 package memory
 
 import (
-	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,7 +10,7 @@ import (
 type AnalyticsMemoryRepository struct {
 	impressions map[string][]*models.PostImpression // postID -> impressions
 	postRepo    *PostMemoryRepository
-	mu          sync.RWMutex
+	mu          repoMutex
 }
 
 func NewAnalyticsMemoryRepository(postRepo *PostMemoryRepository) *AnalyticsMemoryRepository {
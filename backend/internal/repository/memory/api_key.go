@@ -0,0 +1,105 @@
+package memory
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/yourusername/v-backend/internal/models"
+)
+
+type APIKeyMemoryRepository struct {
+	keys map[string]*models.APIKey
+	mu   repoMutex
+}
+
+func NewAPIKeyMemoryRepository() *APIKeyMemoryRepository {
+	return &APIKeyMemoryRepository{
+		keys: make(map[string]*models.APIKey),
+	}
+}
+
+func cloneAPIKey(key *models.APIKey) *models.APIKey {
+	clone := *key
+	clone.Scopes = append([]models.APIScope(nil), key.Scopes...)
+	if key.LastUsedAt != nil {
+		lastUsedAt := *key.LastUsedAt
+		clone.LastUsedAt = &lastUsedAt
+	}
+	if key.ExpiresAt != nil {
+		expiresAt := *key.ExpiresAt
+		clone.ExpiresAt = &expiresAt
+	}
+	if key.RevokedAt != nil {
+		revokedAt := *key.RevokedAt
+		clone.RevokedAt = &revokedAt
+	}
+	return &clone
+}
+
+func (r *APIKeyMemoryRepository) Create(key *models.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.keys[key.ID]; exists {
+		return errors.New("API key already exists")
+	}
+	key.CreatedAt = time.Now()
+	r.keys[key.ID] = cloneAPIKey(key)
+	return nil
+}
+
+func (r *APIKeyMemoryRepository) GetByID(id string) (*models.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	key, exists := r.keys[id]
+	if !exists {
+		return nil, errors.New("API key not found")
+	}
+	return cloneAPIKey(key), nil
+}
+
+func (r *APIKeyMemoryRepository) ListByUser(userID string) ([]*models.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := make([]*models.APIKey, 0)
+	for _, key := range r.keys {
+		if key.UserID == userID && key.RevokedAt == nil {
+			keys = append(keys, cloneAPIKey(key))
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if !keys[i].CreatedAt.Equal(keys[j].CreatedAt) {
+			return keys[i].CreatedAt.After(keys[j].CreatedAt)
+		}
+		return keys[i].ID > keys[j].ID
+	})
+	return keys, nil
+}
+
+func (r *APIKeyMemoryRepository) Touch(id string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key, exists := r.keys[id]
+	if !exists {
+		return errors.New("API key not found")
+	}
+	key.LastUsedAt = &at
+	return nil
+}
+
+func (r *APIKeyMemoryRepository) Revoke(userID, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key, exists := r.keys[id]
+	if !exists || key.UserID != userID || key.RevokedAt != nil {
+		return errors.New("API key not found")
+	}
+	now := time.Now()
+	key.RevokedAt = &now
+	return nil
+}
@@ -2,7 +2,6 @@ package memory
 
 import (
 	"errors"
-	"sync"
 	"time"
 
 	"github.com/yourusername/v-backend/internal/models"
@@ -10,7 +9,7 @@ import (
 
 type AuthMemoryRepository struct {
 	auths map[string]*models.Auth // userID -> auth
-	mu    sync.RWMutex
+	mu    repoMutex
 }
 
 func NewAuthMemoryRepository() *AuthMemoryRepository {
@@ -2,7 +2,7 @@ package memory
 
 import (
 	"errors"
-	"sync"
+	"time"
 
 	"github.com/yourusername/v-backend/internal/models"
 	"github.com/yourusername/v-backend/internal/repository"
@@ -12,10 +12,10 @@ type CommunityMemoryRepository struct {
 	communities map[string]*models.Community
 	members     map[string][]*models.CommunityMember // Key: CommunityID
 	history     map[string]map[string]bool           // Key: CommunityID -> UserID -> bool (Joined ever?)
-	mu          sync.RWMutex
+	mu          repoMutex
 }
 
-func NewCommunityMemoryRepository() repository.CommunityRepository {
+func NewCommunityMemoryRepository() *CommunityMemoryRepository {
 	repo := &CommunityMemoryRepository{
 		communities: make(map[string]*models.Community),
 		members:     make(map[string][]*models.CommunityMember),
@@ -36,7 +36,8 @@ func (r *CommunityMemoryRepository) Create(c *models.Community) error {
 		return errors.New("community already exists")
 	}
 
-	r.communities[c.ID] = c
+	c.Version = 1
+	r.communities[c.ID] = cloneCommunity(c)
 	r.history[c.ID] = make(map[string]bool) // Initialize history for this community
 	return nil
 }
@@ -46,21 +47,37 @@ func (r *CommunityMemoryRepository) GetByID(id string) (*models.Community, error
 	defer r.mu.RUnlock()
 
 	c, exists := r.communities[id]
-	if !exists {
+	if !exists || c.DeletedAt != nil {
 		return nil, errors.New("community not found")
 	}
-	return c, nil
+	return cloneCommunity(c), nil
+}
+
+func (r *CommunityMemoryRepository) GetDeletedByID(id string) (*models.Community, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	c, exists := r.communities[id]
+	if !exists || c.DeletedAt == nil {
+		return nil, errors.New("deleted community not found")
+	}
+	return cloneCommunity(c), nil
 }
 
 func (r *CommunityMemoryRepository) Update(c *models.Community) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, exists := r.communities[c.ID]; !exists {
+	stored, exists := r.communities[c.ID]
+	if !exists {
 		return errors.New("community not found")
 	}
+	if stored.Version != c.Version {
+		return &repository.ConflictError{Entity: "community", ID: c.ID, Version: stored.Version}
+	}
 
-	r.communities[c.ID] = c
+	c.Version++
+	r.communities[c.ID] = cloneCommunity(c)
 	return nil
 }
 
@@ -70,7 +87,9 @@ func (r *CommunityMemoryRepository) List() ([]*models.Community, error) {
 
 	list := make([]*models.Community, 0, len(r.communities))
 	for _, c := range r.communities {
-		list = append(list, c)
+		if c.DeletedAt == nil {
+			list = append(list, cloneCommunity(c))
+		}
 	}
 	return list, nil
 }
@@ -79,21 +98,64 @@ func (r *CommunityMemoryRepository) Delete(id string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	delete(r.communities, id)
-	delete(r.members, id)
-	delete(r.history, id)
+	// Members and history are kept so a restore brings them back
+	if c, exists := r.communities[id]; exists && c.DeletedAt == nil {
+		now := time.Now()
+		c.DeletedAt = &now
+		c.Version++
+	}
 	return nil
 }
 
+func (r *CommunityMemoryRepository) Restore(id string, deletedSince time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c, exists := r.communities[id]
+	if !exists || c.DeletedAt == nil {
+		return errors.New("deleted community not found")
+	}
+	if c.DeletedAt.Before(deletedSince) {
+		return repository.ErrRestoreWindowExpired
+	}
+
+	c.DeletedAt = nil
+	c.Version++
+	return nil
+}
+
+func (r *CommunityMemoryRepository) Purge(cutoff time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	purged := 0
+	for id, c := range r.communities {
+		if c.DeletedAt != nil && c.DeletedAt.Before(cutoff) {
+			delete(r.communities, id)
+			delete(r.members, id)
+			delete(r.history, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
 func (r *CommunityMemoryRepository) AddMember(m *models.CommunityMember) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	// Add to members list
-	if r.members[m.CommunityID] == nil {
-		r.members[m.CommunityID] = make([]*models.CommunityMember, 0)
+	// Add to members list, replacing any existing membership
+	replaced := false
+	for i, existing := range r.members[m.CommunityID] {
+		if existing.UserID == m.UserID {
+			r.members[m.CommunityID][i] = m
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		r.members[m.CommunityID] = append(r.members[m.CommunityID], m)
 	}
-	r.members[m.CommunityID] = append(r.members[m.CommunityID], m)
 
 	// Update history
 	if r.history[m.CommunityID] == nil {
@@ -174,8 +236,8 @@ func (r *CommunityMemoryRepository) GetJoinedCommunities(userID string) ([]*mode
 			// Check if user is a member and status is active (or pending? usually joined implies active member, let's include all for now or just active?)
 			// User request "joined communities" implies active membership.
 			if m.UserID == userID && m.Status == "active" {
-				if community, exists := r.communities[communityID]; exists {
-					joinedCommunities = append(joinedCommunities, community)
+				if community, exists := r.communities[communityID]; exists && community.DeletedAt == nil {
+					joinedCommunities = append(joinedCommunities, cloneCommunity(community))
 				}
 				break // Found membership in this community, move to next community
 			}
@@ -183,3 +245,9 @@ func (r *CommunityMemoryRepository) GetJoinedCommunities(userID string) ([]*mode
 	}
 	return joinedCommunities, nil
 }
+
+// cloneCommunity copies a stored community so callers cannot change it without Update
+func cloneCommunity(c *models.Community) *models.Community {
+	clone := *c
+	return &clone
+}
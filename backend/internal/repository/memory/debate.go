@@ -3,19 +3,21 @@ package memory
 import (
 	"errors"
 	"log"
+	"slices"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
 )
 
 type DebateMemoryRepository struct {
 	debates       map[string]*models.Debate
 	participants  map[string][]*models.DebateParticipant // debateID -> participants
 	speakRequests map[string]*models.SpeakRequest
-	mu            sync.RWMutex
+	votes         map[string][]*models.DebateVote // debateID -> votes
+	mu            repoMutex
 }
 
 func NewDebateMemoryRepository() *DebateMemoryRepository {
@@ -23,6 +25,7 @@ func NewDebateMemoryRepository() *DebateMemoryRepository {
 		debates:       make(map[string]*models.Debate),
 		participants:  make(map[string][]*models.DebateParticipant),
 		speakRequests: make(map[string]*models.SpeakRequest),
+		votes:         make(map[string][]*models.DebateVote),
 	}
 }
 
@@ -36,7 +39,8 @@ func (r *DebateMemoryRepository) Create(debate *models.Debate) error {
 
 	debate.CreatedAt = time.Now()
 	debate.UpdatedAt = time.Now()
-	r.debates[debate.ID] = debate
+	debate.Version = 1
+	r.debates[debate.ID] = cloneDebate(debate)
 	return nil
 }
 
@@ -48,19 +52,24 @@ func (r *DebateMemoryRepository) GetByID(id string) (*models.Debate, error) {
 	if !exists {
 		return nil, errors.New("debate not found")
 	}
-	return debate, nil
+	return cloneDebate(debate), nil
 }
 
 func (r *DebateMemoryRepository) Update(debate *models.Debate) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, exists := r.debates[debate.ID]; !exists {
+	stored, exists := r.debates[debate.ID]
+	if !exists {
 		return errors.New("debate not found")
 	}
+	if stored.Version != debate.Version {
+		return &repository.ConflictError{Entity: "debate", ID: debate.ID, Version: stored.Version}
+	}
 
+	debate.Version++
 	debate.UpdatedAt = time.Now()
-	r.debates[debate.ID] = debate
+	r.debates[debate.ID] = cloneDebate(debate)
 	return nil
 }
 
@@ -70,6 +79,7 @@ func (r *DebateMemoryRepository) Delete(id string) error {
 
 	delete(r.debates, id)
 	delete(r.participants, id)
+	delete(r.votes, id)
 
 	// Delete associated speak requests
 	for reqID, req := range r.speakRequests {
@@ -81,28 +91,41 @@ func (r *DebateMemoryRepository) Delete(id string) error {
 	return nil
 }
 
-func (r *DebateMemoryRepository) List(status string, limit, offset int) ([]*models.Debate, error) {
+func (r *DebateMemoryRepository) List(status string, page repository.Page) ([]*models.Debate, string, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	debates := make([]*models.Debate, 0)
 	for _, debate := range r.debates {
 		if status == "" || debate.Status == status {
-			debates = append(debates, debate)
+			debates = append(debates, cloneDebate(debate))
 		}
 	}
 
-	start := offset
-	if start > len(debates) {
-		return []*models.Debate{}, nil
-	}
+	// Newest first
+	return paginate(debates, page, true, func(d *models.Debate) (time.Time, string) {
+		return d.CreatedAt, d.ID
+	})
+}
 
-	end := start + limit
-	if end > len(debates) {
-		end = len(debates)
-	}
+func (r *DebateMemoryRepository) ListPending() ([]*models.Debate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 
-	return debates[start:end], nil
+	debates := make([]*models.Debate, 0)
+	for _, debate := range r.debates {
+		pollOpen := debate.Status == "ENDED" && debate.PollClosesAt != nil && debate.Result == nil
+		if debate.Status == "SCHEDULED" || debate.Status == "ACTIVE" || pollOpen {
+			debates = append(debates, cloneDebate(debate))
+		}
+	}
+	slices.SortFunc(debates, func(a, b *models.Debate) int {
+		if c := a.StartTime.Compare(b.StartTime); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return debates, nil
 }
 
 func (r *DebateMemoryRepository) AddParticipant(participant *models.DebateParticipant) error {
@@ -133,9 +156,11 @@ func (r *DebateMemoryRepository) AddParticipant(participant *models.DebatePartic
 				log.Printf("[AddParticipant] Rejoining participant: debateID=%s, userId=%s, side=%s (normalized: %s)", participant.DebateID, participant.UserID, participant.Side, side)
 				if side == "agree" {
 					debate.AgreeCount++
+					debate.Version++
 					log.Printf("[AddParticipant] Incremented AgreeCount (rejoin): debateID=%s, new count=%d", participant.DebateID, debate.AgreeCount)
 				} else if side == "disagree" {
 					debate.DisagreeCount++
+					debate.Version++
 					log.Printf("[AddParticipant] Incremented DisagreeCount (rejoin): debateID=%s, new count=%d", participant.DebateID, debate.DisagreeCount)
 				} else {
 					log.Printf("[AddParticipant] Side is not agree/disagree (rejoin): debateID=%s, side=%s (normalized: %s)", participant.DebateID, participant.Side, side)
@@ -166,18 +191,22 @@ func (r *DebateMemoryRepository) AddParticipant(participant *models.DebatePartic
 				// Decrement old side count
 				if oldSideNorm == "agree" && debate.AgreeCount > 0 {
 					debate.AgreeCount--
+					debate.Version++
 					log.Printf("[AddParticipant] Decremented AgreeCount (side switch): debateID=%s, new count=%d", participant.DebateID, debate.AgreeCount)
 				} else if oldSideNorm == "disagree" && debate.DisagreeCount > 0 {
 					debate.DisagreeCount--
+					debate.Version++
 					log.Printf("[AddParticipant] Decremented DisagreeCount (side switch): debateID=%s, new count=%d", participant.DebateID, debate.DisagreeCount)
 				}
 
 				// Increment new side count
 				if newSideNorm == "agree" {
 					debate.AgreeCount++
+					debate.Version++
 					log.Printf("[AddParticipant] Incremented AgreeCount (side switch): debateID=%s, new count=%d", participant.DebateID, debate.AgreeCount)
 				} else if newSideNorm == "disagree" {
 					debate.DisagreeCount++
+					debate.Version++
 					log.Printf("[AddParticipant] Incremented DisagreeCount (side switch): debateID=%s, new count=%d", participant.DebateID, debate.DisagreeCount)
 				} else {
 					log.Printf("[AddParticipant] New side is not agree/disagree (side switch): debateID=%s, newSide=%s (normalized: %s)", participant.DebateID, newSide, newSideNorm)
@@ -202,9 +231,11 @@ func (r *DebateMemoryRepository) AddParticipant(participant *models.DebatePartic
 	log.Printf("[AddParticipant] New participant added: debateID=%s, userId=%s, side=%s (normalized: %s)", participant.DebateID, participant.UserID, participant.Side, side)
 	if side == "agree" {
 		debate.AgreeCount++
+		debate.Version++
 		log.Printf("[AddParticipant] Incremented AgreeCount: debateID=%s, new count=%d", participant.DebateID, debate.AgreeCount)
 	} else if side == "disagree" {
 		debate.DisagreeCount++
+		debate.Version++
 		log.Printf("[AddParticipant] Incremented DisagreeCount: debateID=%s, new count=%d", participant.DebateID, debate.DisagreeCount)
 	} else {
 		log.Printf("[AddParticipant] Side is not agree/disagree: debateID=%s, side=%s (normalized: %s), not incrementing counts", participant.DebateID, participant.Side, side)
@@ -232,9 +263,11 @@ func (r *DebateMemoryRepository) RemoveParticipant(debateID, userID string) erro
 			side := strings.ToLower(strings.TrimSpace(p.Side))
 			if side == "agree" && debate.AgreeCount > 0 {
 				debate.AgreeCount--
+				debate.Version++
 				log.Printf("[RemoveParticipant] Decremented AgreeCount: debateID=%s, new count=%d", debateID, debate.AgreeCount)
 			} else if side == "disagree" && debate.DisagreeCount > 0 {
 				debate.DisagreeCount--
+				debate.Version++
 				log.Printf("[RemoveParticipant] Decremented DisagreeCount: debateID=%s, new count=%d", debateID, debate.DisagreeCount)
 			}
 
@@ -319,7 +352,8 @@ func (r *DebateMemoryRepository) CreateSpeakRequest(request *models.SpeakRequest
 	}
 
 	request.CreatedAt = time.Now()
-	r.speakRequests[request.ID] = request
+	clone := *request
+	r.speakRequests[request.ID] = &clone
 	return nil
 }
 
@@ -327,14 +361,31 @@ func (r *DebateMemoryRepository) UpdateSpeakRequest(request *models.SpeakRequest
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, exists := r.speakRequests[request.ID]; !exists {
+	stored, exists := r.speakRequests[request.ID]
+	if !exists {
 		return errors.New("speak request not found")
 	}
 
-	r.speakRequests[request.ID] = request
+	// Who asked, for which debate and from which side never changes
+	stored.Status = request.Status
+	stored.Position = request.Position
+	stored.TurnStartedAt = request.TurnStartedAt
+	stored.TurnEndsAt = request.TurnEndsAt
 	return nil
 }
 
+func (r *DebateMemoryRepository) GetSpeakRequest(id string) (*models.SpeakRequest, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	request, exists := r.speakRequests[id]
+	if !exists {
+		return nil, errors.New("speak request not found")
+	}
+	clone := *request
+	return &clone, nil
+}
+
 func (r *DebateMemoryRepository) GetSpeakRequests(debateID string) ([]*models.SpeakRequest, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -346,9 +397,16 @@ func (r *DebateMemoryRepository) GetSpeakRequests(debateID string) ([]*models.Sp
 	requests := make([]*models.SpeakRequest, 0)
 	for _, req := range r.speakRequests {
 		if req.DebateID == debateID {
-			requests = append(requests, req)
+			clone := *req
+			requests = append(requests, &clone)
 		}
 	}
+	slices.SortFunc(requests, func(a, b *models.SpeakRequest) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
 
 	return requests, nil
 }
@@ -361,6 +419,37 @@ func (r *DebateMemoryRepository) DeleteSpeakRequest(id string) error {
 	return nil
 }
 
+func (r *DebateMemoryRepository) CastVote(vote *models.DebateVote) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.debates[vote.DebateID]; !exists {
+		return errors.New("debate not found")
+	}
+	for _, v := range r.votes[vote.DebateID] {
+		if v.UserID == vote.UserID && v.Phase == vote.Phase {
+			return repository.ErrAlreadyVoted
+		}
+	}
+
+	vote.CreatedAt = time.Now()
+	clone := *vote
+	r.votes[vote.DebateID] = append(r.votes[vote.DebateID], &clone)
+	return nil
+}
+
+func (r *DebateMemoryRepository) GetVotes(debateID string) ([]*models.DebateVote, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	votes := make([]*models.DebateVote, 0, len(r.votes[debateID]))
+	for _, v := range r.votes[debateID] {
+		clone := *v
+		votes = append(votes, &clone)
+	}
+	return votes, nil
+}
+
 // GetUserDebatesToday returns the number of debates created by a user today
 func (r *DebateMemoryRepository) GetUserDebatesToday(userID string) int {
 	r.mu.RLock()
@@ -409,9 +498,22 @@ func (r *DebateMemoryRepository) ClearAll() error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	r.debates = make(map[string]*models.Debate)
-	r.participants = make(map[string][]*models.DebateParticipant)
-	r.speakRequests = make(map[string]*models.SpeakRequest)
+	clear(r.debates)
+	clear(r.participants)
+	clear(r.speakRequests)
+	clear(r.votes)
 
 	return nil
 }
+
+// cloneDebate copies a stored debate so callers cannot change it without Update
+func cloneDebate(debate *models.Debate) *models.Debate {
+	clone := *debate
+	clone.EarlyAccessRoles = slices.Clone(debate.EarlyAccessRoles)
+	if debate.Result != nil {
+		result := *debate.Result
+		result.Winners = slices.Clone(debate.Result.Winners)
+		clone.Result = &result
+	}
+	return &clone
+}
@@ -4,7 +4,6 @@ import (
 	"errors"
 	"log"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/yourusername/v-backend/internal/models"
@@ -13,7 +12,7 @@ import (
 type DebateStatsMemoryRepository struct {
 	stats           map[string]*models.DebateTopicStats // normalized topic -> stats
 	recordedDebates map[string]bool                     // debate ID -> true (track which debates have been recorded)
-	mu              sync.RWMutex
+	mu              repoMutex
 }
 
 func NewDebateStatsMemoryRepository() *DebateStatsMemoryRepository {
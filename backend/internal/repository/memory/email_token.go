@@ -0,0 +1,70 @@
+package memory
+
+import (
+	"errors"
+	"time"
+
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
+)
+
+type EmailTokenMemoryRepository struct {
+	tokens map[string]*models.EmailToken
+	mu     repoMutex
+}
+
+func NewEmailTokenMemoryRepository() *EmailTokenMemoryRepository {
+	return &EmailTokenMemoryRepository{
+		tokens: make(map[string]*models.EmailToken),
+	}
+}
+
+func cloneEmailToken(token *models.EmailToken) *models.EmailToken {
+	clone := *token
+	if token.UsedAt != nil {
+		usedAt := *token.UsedAt
+		clone.UsedAt = &usedAt
+	}
+	return &clone
+}
+
+func (r *EmailTokenMemoryRepository) Create(token *models.EmailToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.tokens[token.ID]; exists {
+		return errors.New("email token already exists")
+	}
+
+	token.CreatedAt = time.Now()
+	r.tokens[token.ID] = cloneEmailToken(token)
+	return nil
+}
+
+func (r *EmailTokenMemoryRepository) Consume(id string, purpose models.EmailTokenPurpose, hash string) (*models.EmailToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	token, exists := r.tokens[id]
+	now := time.Now()
+	if !exists || token.Purpose != purpose || token.TokenHash != hash || token.UsedAt != nil || !now.Before(token.ExpiresAt) {
+		return nil, repository.ErrInvalidEmailToken
+	}
+
+	token.UsedAt = &now
+	return cloneEmailToken(token), nil
+}
+
+func (r *EmailTokenMemoryRepository) InvalidateForUser(userID string, purpose models.EmailTokenPurpose) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for _, token := range r.tokens {
+		if token.UserID == userID && token.Purpose == purpose && token.UsedAt == nil {
+			usedAt := now
+			token.UsedAt = &usedAt
+		}
+	}
+	return nil
+}
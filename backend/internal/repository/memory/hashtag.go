@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
 )
 
 type HashtagMemoryRepository struct {
@@ -20,8 +21,8 @@ type HashtagMemoryRepository struct {
 	trendingCache         map[time.Duration][]*models.Hashtag
 	trendingCategoryCache map[time.Duration]map[string][]*models.Hashtag
 	popularCache          []*models.Hashtag
-	cacheMu               sync.RWMutex
-	mu                    sync.RWMutex
+	cacheMu               *sync.RWMutex // Shared with the views units of work get
+	mu                    repoMutex
 }
 
 func NewHashtagMemoryRepository(postRepo *PostMemoryRepository) *HashtagMemoryRepository {
@@ -33,6 +34,7 @@ func NewHashtagMemoryRepository(postRepo *PostMemoryRepository) *HashtagMemoryRe
 		trendingCache:         make(map[time.Duration][]*models.Hashtag),
 		trendingCategoryCache: make(map[time.Duration]map[string][]*models.Hashtag),
 		popularCache:          make([]*models.Hashtag, 0),
+		cacheMu:               new(sync.RWMutex),
 	}
 
 	// Start background cache refresher
@@ -77,7 +79,7 @@ func (r *HashtagMemoryRepository) GetBySlug(slug string) (*models.Hashtag, error
 	return nil, errors.New("hashtag not found")
 }
 
-func (r *HashtagMemoryRepository) List(limit, offset int) ([]*models.Hashtag, error) {
+func (r *HashtagMemoryRepository) List(page repository.Page) ([]*models.Hashtag, string, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -86,17 +88,10 @@ func (r *HashtagMemoryRepository) List(limit, offset int) ([]*models.Hashtag, er
 		hashtags = append(hashtags, hashtag)
 	}
 
-	start := offset
-	if start > len(hashtags) {
-		return []*models.Hashtag{}, nil
-	}
-
-	end := start + limit
-	if end > len(hashtags) {
-		end = len(hashtags)
-	}
-
-	return hashtags[start:end], nil
+	// Oldest first, so pages stay stable as hashtags are created
+	return paginate(hashtags, page, false, func(h *models.Hashtag) (time.Time, string) {
+		return h.CreatedAt, h.ID
+	})
 }
 
 func (r *HashtagMemoryRepository) Delete(id string) error {
@@ -337,53 +332,13 @@ func (r *HashtagMemoryRepository) refreshCaches() {
 	r.refreshPopularCache()
 }
 
-// getEnvInt reads an integer from environment variable or returns default
-func getEnvInt(key string, defaultVal int) int {
-	if valStr := os.Getenv(key); valStr != "" {
-		if val, err := strconv.Atoi(valStr); err == nil {
-			return val
-		}
-	}
-	return defaultVal
-}
-
-// getEnvFloat reads a float from environment variable or returns default
-func getEnvFloat(key string, defaultVal float64) float64 {
-	if valStr := os.Getenv(key); valStr != "" {
-		if val, err := strconv.ParseFloat(valStr, 64); err == nil {
-			return val
-		}
-	}
-	return defaultVal
-}
-
-// TrendingConfig holds thresholds for trending logic
-type TrendingConfig struct {
-	MinPosts1h  int
-	MinUsers1h  int
-	MinSpike1h  float64
-	MinPosts24h int
-	MinUsers24h int
-	UserPostCap int
-}
-
-// DefaultTrendingConfig with strict values, configurable via env
-var DefaultTrendingConfig = TrendingConfig{
-	MinPosts1h:  getEnvInt("TRENDING_MIN_POSTS_1H", 250),
-	MinUsers1h:  getEnvInt("TRENDING_MIN_USERS_1H", 100),
-	MinSpike1h:  getEnvFloat("TRENDING_MIN_SPIKE_1H", 3.0),
-	MinPosts24h: getEnvInt("TRENDING_MIN_POSTS_24H", 800),
-	MinUsers24h: getEnvInt("TRENDING_MIN_USERS_24H", 300),
-	UserPostCap: getEnvInt("TRENDING_USER_POST_CAP", 3),
-}
-
 func (r *HashtagMemoryRepository) refreshTrendingCache(window time.Duration) {
 	type scoredHashtag struct {
 		hashtag *models.Hashtag
 		score   float64
 	}
 
-	config := DefaultTrendingConfig // In real app, this could be dynamic
+	config := repository.DefaultTrendingConfig // In real app, this could be dynamic
 
 	r.mu.RLock()
 	fmt.Printf("DEBUG: Refreshing Trending Cache. Window: %v. Config - MinPosts: %d, MinUsers: %d\n", window, config.MinPosts1h, config.MinUsers1h)
@@ -0,0 +1,86 @@
+package memory
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
+)
+
+type IdentityMemoryRepository struct {
+	identities map[string]*models.Identity // provider + subject -> identity
+	mu         repoMutex
+}
+
+func NewIdentityMemoryRepository() *IdentityMemoryRepository {
+	return &IdentityMemoryRepository{
+		identities: make(map[string]*models.Identity),
+	}
+}
+
+func identityKey(provider, subject string) string {
+	return provider + "\x00" + subject
+}
+
+func (r *IdentityMemoryRepository) Create(identity *models.Identity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.identities[identityKey(identity.Provider, identity.Subject)]; exists {
+		return repository.ErrIdentityLinked
+	}
+	for _, existing := range r.identities {
+		if existing.UserID == identity.UserID && existing.Provider == identity.Provider {
+			return repository.ErrIdentityLinked
+		}
+	}
+
+	identity.CreatedAt = time.Now()
+	clone := *identity
+	r.identities[identityKey(identity.Provider, identity.Subject)] = &clone
+	return nil
+}
+
+func (r *IdentityMemoryRepository) Get(provider, subject string) (*models.Identity, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	identity, exists := r.identities[identityKey(provider, subject)]
+	if !exists {
+		return nil, errors.New("identity not found")
+	}
+	clone := *identity
+	return &clone, nil
+}
+
+func (r *IdentityMemoryRepository) ListByUser(userID string) ([]*models.Identity, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	identities := []*models.Identity{}
+	for _, identity := range r.identities {
+		if identity.UserID == userID {
+			clone := *identity
+			identities = append(identities, &clone)
+		}
+	}
+	sort.Slice(identities, func(i, j int) bool {
+		return identities[i].Provider < identities[j].Provider
+	})
+	return identities, nil
+}
+
+func (r *IdentityMemoryRepository) Delete(userID, provider string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key, identity := range r.identities {
+		if identity.UserID == userID && identity.Provider == provider {
+			delete(r.identities, key)
+			return nil
+		}
+	}
+	return errors.New("identity not found")
+}
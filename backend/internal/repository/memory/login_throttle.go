@@ -0,0 +1,87 @@
+package memory
+
+import (
+	"errors"
+	"time"
+
+	"github.com/yourusername/v-backend/internal/models"
+)
+
+type LoginThrottleMemoryRepository struct {
+	throttles map[string]*models.LoginThrottle
+	mu        repoMutex
+}
+
+func NewLoginThrottleMemoryRepository() *LoginThrottleMemoryRepository {
+	return &LoginThrottleMemoryRepository{
+		throttles: make(map[string]*models.LoginThrottle),
+	}
+}
+
+func cloneLoginThrottle(throttle *models.LoginThrottle) *models.LoginThrottle {
+	clone := *throttle
+	if throttle.BlockedUntil != nil {
+		blockedUntil := *throttle.BlockedUntil
+		clone.BlockedUntil = &blockedUntil
+	}
+	return &clone
+}
+
+func (r *LoginThrottleMemoryRepository) Get(key string) (*models.LoginThrottle, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	throttle, exists := r.throttles[key]
+	if !exists {
+		return nil, errors.New("login throttle not found")
+	}
+	return cloneLoginThrottle(throttle), nil
+}
+
+func (r *LoginThrottleMemoryRepository) RecordFailure(key string, at, since time.Time) (*models.LoginThrottle, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	throttle, exists := r.throttles[key]
+	if !exists || throttle.LastFailureAt.Before(since) {
+		throttle = &models.LoginThrottle{Key: key}
+		r.throttles[key] = throttle
+	}
+	throttle.Failures++
+	throttle.LastFailureAt = at
+	return cloneLoginThrottle(throttle), nil
+}
+
+func (r *LoginThrottleMemoryRepository) Block(key string, until time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	throttle, exists := r.throttles[key]
+	if !exists {
+		return errors.New("login throttle not found")
+	}
+	throttle.BlockedUntil = &until
+	return nil
+}
+
+func (r *LoginThrottleMemoryRepository) Reset(key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.throttles, key)
+	return nil
+}
+
+func (r *LoginThrottleMemoryRepository) Prune(cutoff time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pruned := 0
+	for key, throttle := range r.throttles {
+		if throttle.LastFailureAt.Before(cutoff) && (throttle.BlockedUntil == nil || throttle.BlockedUntil.Before(cutoff)) {
+			delete(r.throttles, key)
+			pruned++
+		}
+	}
+	return pruned, nil
+}
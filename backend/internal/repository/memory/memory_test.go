@@ -0,0 +1,14 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/yourusername/v-backend/internal/repository"
+	"github.com/yourusername/v-backend/internal/repository/repotest"
+)
+
+func TestConformance(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) *repository.Repositories {
+		return NewRepositories()
+	})
+}
@@ -2,16 +2,16 @@ package memory
 
 import (
 	"errors"
-	"sync"
 	"time"
 
 	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
 )
 
 type MessageMemoryRepository struct {
 	conversations map[string]*models.Conversation
 	messages      map[string]*models.Message
-	mu            sync.RWMutex
+	mu            repoMutex
 }
 
 func NewMessageMemoryRepository() *MessageMemoryRepository {
@@ -107,7 +107,7 @@ func (r *MessageMemoryRepository) GetMessage(id string) (*models.Message, error)
 	return message, nil
 }
 
-func (r *MessageMemoryRepository) ListMessages(conversationID string, limit, offset int) ([]*models.Message, error) {
+func (r *MessageMemoryRepository) ListMessages(conversationID string, page repository.Page) ([]*models.Message, string, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -118,20 +118,10 @@ func (r *MessageMemoryRepository) ListMessages(conversationID string, limit, off
 		}
 	}
 
-	// In production, sort by created time
-	// For now, return as-is
-
-	start := offset
-	if start > len(messages) {
-		return []*models.Message{}, nil
-	}
-
-	end := start + limit
-	if end > len(messages) {
-		end = len(messages)
-	}
-
-	return messages[start:end], nil
+	// Oldest first, in conversation order
+	return paginate(messages, page, false, func(m *models.Message) (time.Time, string) {
+		return m.CreatedAt, m.ID
+	})
 }
 
 func (r *MessageMemoryRepository) MarkAsRead(messageID string) error {
@@ -2,16 +2,16 @@ package memory
 
 import (
 	"errors"
-	"sort"
-	"sync"
+	"time"
 
 	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
 )
 
 type NotificationMemoryRepository struct {
 	notifications map[string]*models.Notification // id -> notification
 	userIndex     map[string][]string             // userID -> []notificationIDs
-	mu            sync.RWMutex
+	mu            repoMutex
 }
 
 func NewNotificationMemoryRepository() *NotificationMemoryRepository {
@@ -31,39 +31,22 @@ func (r *NotificationMemoryRepository) Create(notification *models.Notification)
 	return nil
 }
 
-func (r *NotificationMemoryRepository) GetByUserID(userID string, limit, offset int) ([]*models.Notification, error) {
+func (r *NotificationMemoryRepository) GetByUserID(userID string, page repository.Page) ([]*models.Notification, string, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	notifIDs := r.userIndex[userID]
-	if len(notifIDs) == 0 {
-		return []*models.Notification{}, nil
-	}
-
 	// Get all notifications for this user
-	var notifications []*models.Notification
-	for _, id := range notifIDs {
+	notifications := make([]*models.Notification, 0, len(r.userIndex[userID]))
+	for _, id := range r.userIndex[userID] {
 		if notif, exists := r.notifications[id]; exists {
 			notifications = append(notifications, notif)
 		}
 	}
 
-	// Sort by creation time (newest first)
-	sort.Slice(notifications, func(i, j int) bool {
-		return notifications[i].CreatedAt.After(notifications[j].CreatedAt)
+	// Newest first
+	return paginate(notifications, page, true, func(n *models.Notification) (time.Time, string) {
+		return n.CreatedAt, n.ID
 	})
-
-	// Apply pagination
-	if offset >= len(notifications) {
-		return []*models.Notification{}, nil
-	}
-
-	end := offset + limit
-	if end > len(notifications) {
-		end = len(notifications)
-	}
-
-	return notifications[offset:end], nil
 }
 
 func (r *NotificationMemoryRepository) GetByID(id string) (*models.Notification, error) {
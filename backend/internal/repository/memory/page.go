@@ -0,0 +1,46 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/yourusername/v-backend/internal/repository"
+)
+
+// paginate sorts items by the creation time and ID returned by key, newest
+// first when desc is set, and returns the page after page.Cursor together
+// with the cursor of the following page ("" on the last page)
+func paginate[T any](items []T, page repository.Page, desc bool, key func(T) (time.Time, string)) ([]T, string, error) {
+	cursor, err := repository.DecodeCursor(page.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		ti, idi := key(items[i])
+		tj, idj := key(items[j])
+		if !ti.Equal(tj) {
+			return ti.After(tj) == desc
+		}
+		return (idi > idj) == desc
+	})
+
+	if page.Limit <= 0 {
+		return []T{}, "", nil
+	}
+
+	start := 0
+	if cursor != nil {
+		start = sort.Search(len(items), func(i int) bool {
+			createdAt, id := key(items[i])
+			return cursor.Precedes(createdAt, id, desc)
+		})
+	}
+
+	end := start + page.Limit
+	if end >= len(items) {
+		return items[start:], "", nil
+	}
+	createdAt, id := key(items[end-1])
+	return items[start:end], repository.EncodeCursor(createdAt, id), nil
+}
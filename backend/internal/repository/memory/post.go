@@ -2,11 +2,11 @@ package memory
 
 import (
 	"errors"
-	"sort"
-	"sync"
+	"maps"
 	"time"
 
 	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
 )
 
 type PostMemoryRepository struct {
@@ -14,7 +14,7 @@ type PostMemoryRepository struct {
 	comments  map[string]*models.Comment
 	reactions map[string]*models.Reaction     // key: userID-postID or userID-postID-commentID
 	saves     map[string]map[string]time.Time // userID -> map[postID]timestamp
-	mu        sync.RWMutex
+	mu        repoMutex
 }
 
 func NewPostMemoryRepository() *PostMemoryRepository {
@@ -45,8 +45,9 @@ func (r *PostMemoryRepository) Create(post *models.Post) error {
 		post.ReportCount = 0
 	}
 	post.InModerationQueue = false
+	post.Version = 1
 
-	r.posts[post.ID] = post
+	r.posts[post.ID] = clonePost(post)
 	return nil
 }
 
@@ -55,22 +56,38 @@ func (r *PostMemoryRepository) GetByID(id string) (*models.Post, error) {
 	defer r.mu.RUnlock()
 
 	post, exists := r.posts[id]
-	if !exists {
+	if !exists || post.DeletedAt != nil {
 		return nil, errors.New("post not found")
 	}
-	return post, nil
+	return clonePost(post), nil
+}
+
+func (r *PostMemoryRepository) GetDeletedByID(id string) (*models.Post, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	post, exists := r.posts[id]
+	if !exists || post.DeletedAt == nil {
+		return nil, errors.New("deleted post not found")
+	}
+	return clonePost(post), nil
 }
 
 func (r *PostMemoryRepository) Update(post *models.Post) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, exists := r.posts[post.ID]; !exists {
+	stored, exists := r.posts[post.ID]
+	if !exists {
 		return errors.New("post not found")
 	}
+	if stored.Version != post.Version {
+		return &repository.ConflictError{Entity: "post", ID: post.ID, Version: stored.Version}
+	}
 
+	post.Version++
 	post.UpdatedAt = time.Now()
-	r.posts[post.ID] = post
+	r.posts[post.ID] = clonePost(post)
 	return nil
 }
 
@@ -78,6 +95,54 @@ func (r *PostMemoryRepository) Delete(id string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	// Comments, reactions and saves are kept so a restore brings them back
+	if post, exists := r.posts[id]; exists && post.DeletedAt == nil {
+		now := time.Now()
+		post.DeletedAt = &now
+		post.Version++
+	}
+	return nil
+}
+
+func (r *PostMemoryRepository) Restore(id string, deletedSince time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	post, exists := r.posts[id]
+	if !exists || post.DeletedAt == nil {
+		return errors.New("deleted post not found")
+	}
+	if post.DeletedAt.Before(deletedSince) {
+		return repository.ErrRestoreWindowExpired
+	}
+
+	post.DeletedAt = nil
+	post.Version++
+	return nil
+}
+
+func (r *PostMemoryRepository) Purge(cutoff time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	purged := 0
+	for id, post := range r.posts {
+		if post.DeletedAt != nil && post.DeletedAt.Before(cutoff) {
+			r.purgePost(id)
+			purged++
+		}
+	}
+	for id, comment := range r.comments {
+		if comment.DeletedAt != nil && comment.DeletedAt.Before(cutoff) {
+			r.purgeComment(id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// purgePost removes a post with everything attached to it
+func (r *PostMemoryRepository) purgePost(id string) {
 	delete(r.posts, id)
 
 	// Delete associated comments
@@ -98,11 +163,19 @@ func (r *PostMemoryRepository) Delete(id string) error {
 	for _, savedPosts := range r.saves {
 		delete(savedPosts, id)
 	}
+}
 
-	return nil
+// purgeComment removes a comment and the reactions on it
+func (r *PostMemoryRepository) purgeComment(id string) {
+	delete(r.comments, id)
+	for reactionKey, reaction := range r.reactions {
+		if reaction.CommentID != nil && *reaction.CommentID == id {
+			delete(r.reactions, reactionKey)
+		}
+	}
 }
 
-func (r *PostMemoryRepository) List(limit, offset int) ([]*models.Post, error) {
+func (r *PostMemoryRepository) List(page repository.Page) ([]*models.Post, string, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -110,91 +183,49 @@ func (r *PostMemoryRepository) List(limit, offset int) ([]*models.Post, error) {
 	for _, post := range r.posts {
 		// Filter out TEMP_HIDDEN and REMOVED posts from feed
 		// AND filter out Community posts from main feed
-		if post.Status != models.PostStatusTempHidden && post.Status != models.PostStatusRemoved && post.CommunityID == nil {
-			posts = append(posts, post)
+		if post.DeletedAt == nil && post.Status != models.PostStatusTempHidden && post.Status != models.PostStatusRemoved && post.CommunityID == nil {
+			posts = append(posts, clonePost(post))
 		}
 	}
 
-	// Sort by created date (newest first)
-	sort.Slice(posts, func(i, j int) bool {
-		return posts[i].CreatedAt.After(posts[j].CreatedAt)
-	})
-
-	start := offset
-	if start > len(posts) {
-		return []*models.Post{}, nil
-	}
-
-	end := start + limit
-	if end > len(posts) {
-		end = len(posts)
-	}
-
-	return posts[start:end], nil
+	// Newest first
+	return paginate(posts, page, true, postKey)
 }
 
-func (r *PostMemoryRepository) ListByAuthor(authorID string, limit, offset int) ([]*models.Post, error) {
+func (r *PostMemoryRepository) ListByAuthor(authorID string, page repository.Page) ([]*models.Post, string, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	posts := make([]*models.Post, 0)
 	for _, post := range r.posts {
-		if post.AuthorID == authorID {
+		if post.AuthorID == authorID && post.DeletedAt == nil {
 			// Filter out TEMP_HIDDEN and REMOVED posts from feed
 			if post.Status != models.PostStatusTempHidden && post.Status != models.PostStatusRemoved {
-				posts = append(posts, post)
+				posts = append(posts, clonePost(post))
 			}
 		}
 	}
 
-	// Sort by created date (newest first)
-	sort.Slice(posts, func(i, j int) bool {
-		return posts[i].CreatedAt.After(posts[j].CreatedAt)
-	})
-
-	start := offset
-	if start > len(posts) {
-		return []*models.Post{}, nil
-	}
-
-	end := start + limit
-	if end > len(posts) {
-		end = len(posts)
-	}
-
-	return posts[start:end], nil
+	// Newest first
+	return paginate(posts, page, true, postKey)
 }
 
-func (r *PostMemoryRepository) ListByCommunity(communityID string, limit, offset int) ([]*models.Post, error) {
+func (r *PostMemoryRepository) ListByCommunity(communityID string, page repository.Page) ([]*models.Post, string, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	posts := make([]*models.Post, 0)
 	for _, post := range r.posts {
-		if post.CommunityID != nil && *post.CommunityID == communityID {
+		if post.CommunityID != nil && *post.CommunityID == communityID && post.DeletedAt == nil {
 			// Filter out TEMP_HIDDEN and REMOVED posts from feed
 			if post.Status != models.PostStatusTempHidden && post.Status != models.PostStatusRemoved {
-				posts = append(posts, post)
+				posts = append(posts, clonePost(post))
 			}
 		}
 	}
 
-	// Sort by created date (newest first)
-	sort.Slice(posts, func(i, j int) bool {
-		return posts[i].CreatedAt.After(posts[j].CreatedAt)
-	})
-
-	start := offset
-	if start > len(posts) {
-		return []*models.Post{}, nil
-	}
-
-	end := start + limit
-	if end > len(posts) {
-		end = len(posts)
-	}
-
-	return posts[start:end], nil
+	// Newest first
+	return paginate(posts, page, true, postKey)
 }
 
 // Comment operations
@@ -207,7 +238,7 @@ func (r *PostMemoryRepository) CreateComment(comment *models.Comment) error {
 	}
 
 	// Verify post exists
-	if _, exists := r.posts[comment.PostID]; !exists {
+	if post, exists := r.posts[comment.PostID]; !exists || post.DeletedAt != nil {
 		return errors.New("post not found")
 	}
 
@@ -217,6 +248,7 @@ func (r *PostMemoryRepository) CreateComment(comment *models.Comment) error {
 
 	// Increment post comment count
 	r.posts[comment.PostID].CommentCount++
+	r.posts[comment.PostID].Version++
 
 	return nil
 }
@@ -226,8 +258,11 @@ func (r *PostMemoryRepository) GetCommentsByPost(postID string) ([]*models.Comme
 	defer r.mu.RUnlock()
 
 	comments := make([]*models.Comment, 0)
+	if post, exists := r.posts[postID]; !exists || post.DeletedAt != nil {
+		return comments, nil
+	}
 	for _, comment := range r.comments {
-		if comment.PostID == postID {
+		if comment.PostID == postID && comment.DeletedAt == nil {
 			comments = append(comments, comment)
 		}
 	}
@@ -235,21 +270,55 @@ func (r *PostMemoryRepository) GetCommentsByPost(postID string) ([]*models.Comme
 	return comments, nil
 }
 
+func (r *PostMemoryRepository) GetCommentByID(id string) (*models.Comment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	comment, exists := r.comments[id]
+	if !exists {
+		return nil, errors.New("comment not found")
+	}
+	clone := *comment
+	return &clone, nil
+}
+
 func (r *PostMemoryRepository) DeleteComment(id string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	comment, exists := r.comments[id]
-	if !exists {
+	if !exists || comment.DeletedAt != nil {
 		return errors.New("comment not found")
 	}
 
 	// Decrement post comment count
 	if post, exists := r.posts[comment.PostID]; exists && post.CommentCount > 0 {
 		post.CommentCount--
+		post.Version++
 	}
 
-	delete(r.comments, id)
+	now := time.Now()
+	comment.DeletedAt = &now
+	return nil
+}
+
+func (r *PostMemoryRepository) RestoreComment(id string, deletedSince time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	comment, exists := r.comments[id]
+	if !exists || comment.DeletedAt == nil {
+		return errors.New("deleted comment not found")
+	}
+	if comment.DeletedAt.Before(deletedSince) {
+		return repository.ErrRestoreWindowExpired
+	}
+
+	comment.DeletedAt = nil
+	if post, exists := r.posts[comment.PostID]; exists {
+		post.CommentCount++
+		post.Version++
+	}
 	return nil
 }
 
@@ -275,6 +344,7 @@ func (r *PostMemoryRepository) AddReaction(reaction *models.Reaction) error {
 	// Increment post reaction count
 	if post, exists := r.posts[reaction.PostID]; exists {
 		post.ReactionCount++
+		post.Version++
 	}
 
 	return nil
@@ -300,6 +370,7 @@ func (r *PostMemoryRepository) RemoveReaction(userID, postID string, commentID *
 	// Decrement post reaction count
 	if post, exists := r.posts[postID]; exists && post.ReactionCount > 0 {
 		post.ReactionCount--
+		post.Version++
 	}
 
 	return nil
@@ -325,7 +396,7 @@ func (r *PostMemoryRepository) SavePost(userID, postID string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, exists := r.posts[postID]; !exists {
+	if post, exists := r.posts[postID]; !exists || post.DeletedAt != nil {
 		return errors.New("post not found")
 	}
 
@@ -341,6 +412,7 @@ func (r *PostMemoryRepository) SavePost(userID, postID string) error {
 
 	// Increment post save count
 	r.posts[postID].SaveCount++
+	r.posts[postID].Version++
 
 	return nil
 }
@@ -349,42 +421,35 @@ func (r *PostMemoryRepository) UnsavePost(userID, postID string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if r.saves[userID] != nil {
+	if _, exists := r.saves[userID][postID]; exists {
 		delete(r.saves[userID], postID)
 
 		// Decrement post save count
 		if post, exists := r.posts[postID]; exists && post.SaveCount > 0 {
 			post.SaveCount--
+			post.Version++
 		}
 	}
 
 	return nil
 }
 
-func (r *PostMemoryRepository) GetSavedPosts(userID string, limit, offset int) ([]*models.Post, error) {
+func (r *PostMemoryRepository) GetSavedPosts(userID string, page repository.Page) ([]*models.Post, string, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
+	savedPostIDs := r.saves[userID]
 	savedPosts := make([]*models.Post, 0)
-	if savedPostIDs := r.saves[userID]; savedPostIDs != nil {
-		for postID := range savedPostIDs {
-			if post, exists := r.posts[postID]; exists {
-				savedPosts = append(savedPosts, post)
-			}
+	for postID := range savedPostIDs {
+		if post, exists := r.posts[postID]; exists && post.DeletedAt == nil {
+			savedPosts = append(savedPosts, clonePost(post))
 		}
 	}
 
-	start := offset
-	if start > len(savedPosts) {
-		return []*models.Post{}, nil
-	}
-
-	end := start + limit
-	if end > len(savedPosts) {
-		end = len(savedPosts)
-	}
-
-	return savedPosts[start:end], nil
+	// Most recently saved first
+	return paginate(savedPosts, page, true, func(p *models.Post) (time.Time, string) {
+		return savedPostIDs[p.ID], p.ID
+	})
 }
 
 func (r *PostMemoryRepository) IsSaved(userID, postID string) (bool, error) {
@@ -398,3 +463,15 @@ func (r *PostMemoryRepository) IsSaved(userID, postID string) (bool, error) {
 	_, exists := r.saves[userID][postID]
 	return exists, nil
 }
+
+// postKey orders posts by creation time for paginate
+func postKey(p *models.Post) (time.Time, string) {
+	return p.CreatedAt, p.ID
+}
+
+// clonePost copies a stored post so callers cannot change it without Update
+func clonePost(post *models.Post) *models.Post {
+	clone := *post
+	clone.Translations = maps.Clone(post.Translations)
+	return &clone
+}
@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"sync"
+
+	"github.com/yourusername/v-backend/internal/repository"
+)
+
+// Store holds one of each in-memory repository so they can be snapshotted
+// and restored together
+type Store struct {
+	Auth          *AuthMemoryRepository
+	Session       *SessionMemoryRepository
+	EmailToken    *EmailTokenMemoryRepository
+	TwoFactor     *TwoFactorMemoryRepository
+	LoginThrottle *LoginThrottleMemoryRepository
+	Identity      *IdentityMemoryRepository
+	SigningKey    *SigningKeyMemoryRepository
+	APIKey        *APIKeyMemoryRepository
+	User          *UserMemoryRepository
+	Post          *PostMemoryRepository
+	Message       *MessageMemoryRepository
+	Hashtag       *HashtagMemoryRepository
+	Debate        *DebateMemoryRepository
+	DebateStats   *DebateStatsMemoryRepository
+	Notification  *NotificationMemoryRepository
+	Analytics     *AnalyticsMemoryRepository
+	Community     *CommunityMemoryRepository
+
+	txMu sync.Mutex // Units of work run one at a time
+}
+
+// NewStore returns a Store of empty in-memory repositories
+func NewStore() *Store {
+	postRepo := NewPostMemoryRepository()
+
+	return &Store{
+		Auth:          NewAuthMemoryRepository(),
+		Session:       NewSessionMemoryRepository(),
+		EmailToken:    NewEmailTokenMemoryRepository(),
+		TwoFactor:     NewTwoFactorMemoryRepository(),
+		LoginThrottle: NewLoginThrottleMemoryRepository(),
+		Identity:      NewIdentityMemoryRepository(),
+		SigningKey:    NewSigningKeyMemoryRepository(),
+		APIKey:        NewAPIKeyMemoryRepository(),
+		User:          NewUserMemoryRepository(),
+		Post:          postRepo,
+		Message:       NewMessageMemoryRepository(),
+		Hashtag:       NewHashtagMemoryRepository(postRepo),
+		Debate:        NewDebateMemoryRepository(),
+		DebateStats:   NewDebateStatsMemoryRepository(),
+		Notification:  NewNotificationMemoryRepository(),
+		Analytics:     NewAnalyticsMemoryRepository(postRepo),
+		Community:     NewCommunityMemoryRepository(),
+	}
+}
+
+// Repositories exposes the store through the repository interfaces
+func (s *Store) Repositories() *repository.Repositories {
+	repos := s.repositories()
+	repos.UnitOfWork = &unitOfWork{store: s}
+	return repos
+}
+
+func (s *Store) repositories() *repository.Repositories {
+	return &repository.Repositories{
+		Auth:          s.Auth,
+		Session:       s.Session,
+		EmailToken:    s.EmailToken,
+		TwoFactor:     s.TwoFactor,
+		LoginThrottle: s.LoginThrottle,
+		Identity:      s.Identity,
+		SigningKey:    s.SigningKey,
+		APIKey:        s.APIKey,
+		User:          s.User,
+		Post:          s.Post,
+		Message:       s.Message,
+		Hashtag:       s.Hashtag,
+		Debate:        s.Debate,
+		DebateStats:   s.DebateStats,
+		Notification:  s.Notification,
+		Analytics:     s.Analytics,
+		Community:     s.Community,
+	}
+}
+
+// NewRepositories returns in-memory implementations of every repository
+func NewRepositories() *repository.Repositories {
+	return NewStore().Repositories()
+}
@@ -0,0 +1,123 @@
+package memory
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
+)
+
+type SessionMemoryRepository struct {
+	sessions map[string]*models.Session
+	mu       repoMutex
+}
+
+func NewSessionMemoryRepository() *SessionMemoryRepository {
+	return &SessionMemoryRepository{
+		sessions: make(map[string]*models.Session),
+	}
+}
+
+func cloneSession(session *models.Session) *models.Session {
+	clone := *session
+	if session.RevokedAt != nil {
+		revokedAt := *session.RevokedAt
+		clone.RevokedAt = &revokedAt
+	}
+	return &clone
+}
+
+func (r *SessionMemoryRepository) Create(session *models.Session) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.sessions[session.ID]; exists {
+		return errors.New("session already exists")
+	}
+
+	session.CreatedAt = time.Now()
+	session.LastUsedAt = session.CreatedAt
+	r.sessions[session.ID] = cloneSession(session)
+	return nil
+}
+
+func (r *SessionMemoryRepository) GetByID(id string) (*models.Session, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	session, exists := r.sessions[id]
+	if !exists {
+		return nil, errors.New("session not found")
+	}
+	return cloneSession(session), nil
+}
+
+func (r *SessionMemoryRepository) ListActive(userID string) ([]*models.Session, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := time.Now()
+	sessions := []*models.Session{}
+	for _, session := range r.sessions {
+		if session.UserID == userID && session.Active(now) {
+			sessions = append(sessions, cloneSession(session))
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastUsedAt.After(sessions[j].LastUsedAt)
+	})
+	return sessions, nil
+}
+
+func (r *SessionMemoryRepository) Rotate(id, oldHash, newHash string, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, exists := r.sessions[id]
+	if !exists {
+		return errors.New("session not found")
+	}
+	if session.TokenHash != oldHash || !session.Active(time.Now()) {
+		if session.PreviousTokenHash != "" && session.PreviousTokenHash == oldHash {
+			return repository.ErrRefreshTokenReused
+		}
+		return repository.ErrRefreshTokenInvalid
+	}
+
+	session.PreviousTokenHash = session.TokenHash
+	session.TokenHash = newHash
+	session.ExpiresAt = expiresAt
+	session.LastUsedAt = time.Now()
+	return nil
+}
+
+func (r *SessionMemoryRepository) Revoke(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, exists := r.sessions[id]
+	if !exists {
+		return errors.New("session not found")
+	}
+	if session.RevokedAt == nil {
+		now := time.Now()
+		session.RevokedAt = &now
+	}
+	return nil
+}
+
+func (r *SessionMemoryRepository) RevokeAllForUser(userID, keepID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for _, session := range r.sessions {
+		if session.UserID == userID && session.ID != keepID && session.RevokedAt == nil {
+			revokedAt := now
+			session.RevokedAt = &revokedAt
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package memory
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/yourusername/v-backend/internal/models"
+)
+
+type SigningKeyMemoryRepository struct {
+	keys map[string]*models.SigningKey
+	mu   repoMutex
+}
+
+func NewSigningKeyMemoryRepository() *SigningKeyMemoryRepository {
+	return &SigningKeyMemoryRepository{
+		keys: make(map[string]*models.SigningKey),
+	}
+}
+
+func cloneSigningKey(key *models.SigningKey) *models.SigningKey {
+	clone := *key
+	clone.PrivateKey = append([]byte(nil), key.PrivateKey...)
+	return &clone
+}
+
+func (r *SigningKeyMemoryRepository) Create(key *models.SigningKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.keys[key.ID]; exists {
+		return errors.New("signing key already exists")
+	}
+	key.CreatedAt = time.Now()
+	r.keys[key.ID] = cloneSigningKey(key)
+	return nil
+}
+
+func (r *SigningKeyMemoryRepository) List() ([]*models.SigningKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := make([]*models.SigningKey, 0, len(r.keys))
+	for _, key := range r.keys {
+		keys = append(keys, cloneSigningKey(key))
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if !keys[i].ActivatesAt.Equal(keys[j].ActivatesAt) {
+			return keys[i].ActivatesAt.Before(keys[j].ActivatesAt)
+		}
+		return keys[i].ID < keys[j].ID
+	})
+	return keys, nil
+}
+
+func (r *SigningKeyMemoryRepository) Prune(cutoff time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pruned := 0
+	for id, key := range r.keys {
+		if key.ExpiresAt.Before(cutoff) {
+			delete(r.keys, id)
+			pruned++
+		}
+	}
+	return pruned, nil
+}
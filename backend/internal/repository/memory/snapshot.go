@@ -0,0 +1,704 @@
+package memory
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/yourusername/v-backend/internal/models"
+)
+
+// SnapshotVersion is the on-disk format written by WriteSnapshot. Snapshots
+// are gob encoded, so adding or removing fields stays readable; bump the
+// version for changes gob cannot bridge and convert older versions in ReadSnapshot.
+const SnapshotVersion = 1
+
+const snapshotMagic = "v-backend memory snapshot"
+
+// snapshotHeader precedes the Snapshot body in every snapshot file
+type snapshotHeader struct {
+	Magic   string
+	Version int
+}
+
+// Snapshot is a copy of the data held by every in-memory repository
+type Snapshot struct {
+	CreatedAt time.Time
+
+	Users          []models.User
+	Follows        []models.Follow
+	Auth           []models.Auth
+	Sessions       []models.Session
+	EmailTokens    []models.EmailToken
+	TwoFactors     []models.TwoFactor
+	LoginThrottles []models.LoginThrottle
+	Identities     []models.Identity
+	SigningKeys    []models.SigningKey
+	APIKeys        []models.APIKey
+
+	Posts     []models.Post
+	Comments  []models.Comment
+	Reactions []models.Reaction
+	Saves     []models.SavedPost
+
+	Conversations []models.Conversation
+	Messages      []models.Message
+
+	Hashtags         []models.Hashtag
+	HashtagPosts     []models.HashtagPost
+	HashtagFollowers []HashtagFollower
+
+	Debates         []models.Debate
+	Participants    []models.DebateParticipant
+	SpeakRequests   []models.SpeakRequest
+	DebateVotes     []models.DebateVote
+	DebateStats     []models.DebateTopicStats
+	RecordedDebates []string
+
+	Notifications []models.Notification
+	Impressions   []models.PostImpression
+
+	Communities   []models.Community
+	Members       []models.CommunityMember
+	MemberHistory []CommunityJoin
+}
+
+// HashtagFollower records that a user follows a hashtag
+type HashtagFollower struct {
+	HashtagID string
+	UserID    string
+}
+
+// CommunityJoin records that a user has joined a community at some point
+type CommunityJoin struct {
+	CommunityID string
+	UserID      string
+}
+
+// Snapshot copies the contents of every repository. Each repository is
+// copied under its own lock, so under concurrent writes the copies may be a
+// few operations apart from each other.
+func (s *Store) Snapshot() *Snapshot {
+	snap := &Snapshot{CreatedAt: time.Now()}
+	s.User.snapshot(snap)
+	s.Auth.snapshot(snap)
+	s.Session.snapshot(snap)
+	s.EmailToken.snapshot(snap)
+	s.TwoFactor.snapshot(snap)
+	s.LoginThrottle.snapshot(snap)
+	s.Identity.snapshot(snap)
+	s.SigningKey.snapshot(snap)
+	s.APIKey.snapshot(snap)
+	s.Post.snapshot(snap)
+	s.Message.snapshot(snap)
+	s.Hashtag.snapshot(snap)
+	s.Debate.snapshot(snap)
+	s.DebateStats.snapshot(snap)
+	s.Notification.snapshot(snap)
+	s.Analytics.snapshot(snap)
+	s.Community.snapshot(snap)
+	return snap
+}
+
+// Restore replaces the contents of every repository with snap
+func (s *Store) Restore(snap *Snapshot) {
+	s.User.restore(snap)
+	s.Auth.restore(snap)
+	s.Session.restore(snap)
+	s.EmailToken.restore(snap)
+	s.TwoFactor.restore(snap)
+	s.LoginThrottle.restore(snap)
+	s.Identity.restore(snap)
+	s.SigningKey.restore(snap)
+	s.APIKey.restore(snap)
+	s.Post.restore(snap)
+	s.Message.restore(snap)
+	s.Hashtag.restore(snap)
+	s.Debate.restore(snap)
+	s.DebateStats.restore(snap)
+	s.Notification.restore(snap)
+	s.Analytics.restore(snap)
+	s.Community.restore(snap)
+
+	// Trending and popular caches are derived from the restored posts
+	s.Hashtag.refreshCaches()
+}
+
+// WriteSnapshot encodes snap in the current snapshot format
+func WriteSnapshot(w io.Writer, snap *Snapshot) error {
+	enc := gob.NewEncoder(w)
+	if err := enc.Encode(snapshotHeader{Magic: snapshotMagic, Version: SnapshotVersion}); err != nil {
+		return err
+	}
+	return enc.Encode(snap)
+}
+
+// ReadSnapshot decodes a snapshot written by WriteSnapshot
+func ReadSnapshot(r io.Reader) (*Snapshot, error) {
+	dec := gob.NewDecoder(r)
+
+	var header snapshotHeader
+	if err := dec.Decode(&header); err != nil {
+		return nil, fmt.Errorf("read snapshot header: %w", err)
+	}
+	if header.Magic != snapshotMagic {
+		return nil, errors.New("not a memory snapshot")
+	}
+	if header.Version > SnapshotVersion {
+		return nil, fmt.Errorf("snapshot version %d is newer than supported version %d", header.Version, SnapshotVersion)
+	}
+
+	snap := &Snapshot{}
+	if err := dec.Decode(snap); err != nil {
+		return nil, fmt.Errorf("read snapshot: %w", err)
+	}
+	return snap, nil
+}
+
+// SaveSnapshot writes a snapshot of the store to path. The file is replaced
+// atomically, so a crash mid-write leaves the previous snapshot intact.
+func (s *Store) SaveSnapshot(path string) (*Snapshot, error) {
+	snap := s.Snapshot()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := WriteSnapshot(tmp, snap); err != nil {
+		tmp.Close()
+		return nil, err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return nil, err
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return nil, err
+	}
+	return snap, nil
+}
+
+// LoadSnapshot restores the store from the snapshot at path. It returns
+// nil and no error if the file does not exist.
+func (s *Store) LoadSnapshot(path string) (*Snapshot, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	snap, err := ReadSnapshot(f)
+	if err != nil {
+		return nil, err
+	}
+	s.Restore(snap)
+	return snap, nil
+}
+
+// SnapshotEvery saves a snapshot to path on every tick of interval. It never
+// returns, so run it in its own goroutine.
+func (s *Store) SnapshotEvery(path string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	for range ticker.C {
+		if _, err := s.SaveSnapshot(path); err != nil {
+			log.Printf("[Snapshot] Failed to save snapshot to %s: %v", path, err)
+		}
+	}
+}
+
+func (r *UserMemoryRepository) snapshot(snap *Snapshot) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, user := range r.users {
+		snap.Users = append(snap.Users, *user)
+	}
+	for followerID, following := range r.follows {
+		for followingID, at := range following {
+			snap.Follows = append(snap.Follows, models.Follow{FollowerID: followerID, FollowingID: followingID, CreatedAt: at})
+		}
+	}
+}
+
+func (r *UserMemoryRepository) restore(snap *Snapshot) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	clear(r.users)
+	for i := range snap.Users {
+		user := snap.Users[i]
+		r.users[user.ID] = &user
+	}
+	clear(r.follows)
+	for _, f := range snap.Follows {
+		if r.follows[f.FollowerID] == nil {
+			r.follows[f.FollowerID] = make(map[string]time.Time)
+		}
+		r.follows[f.FollowerID][f.FollowingID] = f.CreatedAt
+	}
+}
+
+func (r *AuthMemoryRepository) snapshot(snap *Snapshot) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, auth := range r.auths {
+		snap.Auth = append(snap.Auth, *auth)
+	}
+}
+
+func (r *AuthMemoryRepository) restore(snap *Snapshot) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	clear(r.auths)
+	for i := range snap.Auth {
+		auth := snap.Auth[i]
+		r.auths[auth.UserID] = &auth
+	}
+}
+
+func (r *SessionMemoryRepository) snapshot(snap *Snapshot) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, session := range r.sessions {
+		snap.Sessions = append(snap.Sessions, *cloneSession(session))
+	}
+}
+
+func (r *SessionMemoryRepository) restore(snap *Snapshot) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	clear(r.sessions)
+	for i := range snap.Sessions {
+		r.sessions[snap.Sessions[i].ID] = cloneSession(&snap.Sessions[i])
+	}
+}
+
+func (r *EmailTokenMemoryRepository) snapshot(snap *Snapshot) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, token := range r.tokens {
+		snap.EmailTokens = append(snap.EmailTokens, *cloneEmailToken(token))
+	}
+}
+
+func (r *EmailTokenMemoryRepository) restore(snap *Snapshot) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	clear(r.tokens)
+	for i := range snap.EmailTokens {
+		r.tokens[snap.EmailTokens[i].ID] = cloneEmailToken(&snap.EmailTokens[i])
+	}
+}
+
+func (r *TwoFactorMemoryRepository) snapshot(snap *Snapshot) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, tf := range r.enrollments {
+		snap.TwoFactors = append(snap.TwoFactors, *cloneTwoFactor(tf))
+	}
+}
+
+func (r *TwoFactorMemoryRepository) restore(snap *Snapshot) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	clear(r.enrollments)
+	for i := range snap.TwoFactors {
+		r.enrollments[snap.TwoFactors[i].UserID] = cloneTwoFactor(&snap.TwoFactors[i])
+	}
+}
+
+func (r *LoginThrottleMemoryRepository) snapshot(snap *Snapshot) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, throttle := range r.throttles {
+		snap.LoginThrottles = append(snap.LoginThrottles, *cloneLoginThrottle(throttle))
+	}
+}
+
+func (r *LoginThrottleMemoryRepository) restore(snap *Snapshot) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	clear(r.throttles)
+	for i := range snap.LoginThrottles {
+		r.throttles[snap.LoginThrottles[i].Key] = cloneLoginThrottle(&snap.LoginThrottles[i])
+	}
+}
+
+func (r *IdentityMemoryRepository) snapshot(snap *Snapshot) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, identity := range r.identities {
+		snap.Identities = append(snap.Identities, *identity)
+	}
+}
+
+func (r *IdentityMemoryRepository) restore(snap *Snapshot) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	clear(r.identities)
+	for i := range snap.Identities {
+		identity := snap.Identities[i]
+		r.identities[identityKey(identity.Provider, identity.Subject)] = &identity
+	}
+}
+
+func (r *SigningKeyMemoryRepository) snapshot(snap *Snapshot) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, key := range r.keys {
+		snap.SigningKeys = append(snap.SigningKeys, *cloneSigningKey(key))
+	}
+}
+
+func (r *SigningKeyMemoryRepository) restore(snap *Snapshot) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	clear(r.keys)
+	for i := range snap.SigningKeys {
+		r.keys[snap.SigningKeys[i].ID] = cloneSigningKey(&snap.SigningKeys[i])
+	}
+}
+
+func (r *APIKeyMemoryRepository) snapshot(snap *Snapshot) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, key := range r.keys {
+		snap.APIKeys = append(snap.APIKeys, *cloneAPIKey(key))
+	}
+}
+
+func (r *APIKeyMemoryRepository) restore(snap *Snapshot) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	clear(r.keys)
+	for i := range snap.APIKeys {
+		r.keys[snap.APIKeys[i].ID] = cloneAPIKey(&snap.APIKeys[i])
+	}
+}
+
+func (r *PostMemoryRepository) snapshot(snap *Snapshot) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, post := range r.posts {
+		snap.Posts = append(snap.Posts, *post)
+	}
+	for _, comment := range r.comments {
+		snap.Comments = append(snap.Comments, *comment)
+	}
+	for _, reaction := range r.reactions {
+		snap.Reactions = append(snap.Reactions, *reaction)
+	}
+	for userID, saved := range r.saves {
+		for postID, at := range saved {
+			snap.Saves = append(snap.Saves, models.SavedPost{UserID: userID, PostID: postID, CreatedAt: at})
+		}
+	}
+}
+
+func (r *PostMemoryRepository) restore(snap *Snapshot) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	clear(r.posts)
+	for i := range snap.Posts {
+		post := snap.Posts[i]
+		r.posts[post.ID] = &post
+	}
+	clear(r.comments)
+	for i := range snap.Comments {
+		comment := snap.Comments[i]
+		r.comments[comment.ID] = &comment
+	}
+	clear(r.reactions)
+	for i := range snap.Reactions {
+		reaction := snap.Reactions[i]
+		key := reaction.UserID + "-" + reaction.PostID
+		if reaction.CommentID != nil {
+			key += "-" + *reaction.CommentID
+		}
+		r.reactions[key] = &reaction
+	}
+	clear(r.saves)
+	for _, s := range snap.Saves {
+		if r.saves[s.UserID] == nil {
+			r.saves[s.UserID] = make(map[string]time.Time)
+		}
+		r.saves[s.UserID][s.PostID] = s.CreatedAt
+	}
+}
+
+func (r *MessageMemoryRepository) snapshot(snap *Snapshot) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, conv := range r.conversations {
+		snap.Conversations = append(snap.Conversations, *conv)
+	}
+	for _, message := range r.messages {
+		snap.Messages = append(snap.Messages, *message)
+	}
+}
+
+func (r *MessageMemoryRepository) restore(snap *Snapshot) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	clear(r.conversations)
+	for i := range snap.Conversations {
+		conv := snap.Conversations[i]
+		r.conversations[conv.ID] = &conv
+	}
+	clear(r.messages)
+	for i := range snap.Messages {
+		message := snap.Messages[i]
+		r.messages[message.ID] = &message
+	}
+}
+
+func (r *HashtagMemoryRepository) snapshot(snap *Snapshot) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, hashtag := range r.hashtags {
+		snap.Hashtags = append(snap.Hashtags, *hashtag)
+	}
+	for _, posts := range r.hashtagPosts {
+		for _, hp := range posts {
+			snap.HashtagPosts = append(snap.HashtagPosts, *hp)
+		}
+	}
+	for hashtagID, followers := range r.hashtagFollowers {
+		for userID := range followers {
+			snap.HashtagFollowers = append(snap.HashtagFollowers, HashtagFollower{HashtagID: hashtagID, UserID: userID})
+		}
+	}
+}
+
+func (r *HashtagMemoryRepository) restore(snap *Snapshot) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	clear(r.hashtags)
+	for i := range snap.Hashtags {
+		hashtag := snap.Hashtags[i]
+		r.hashtags[hashtag.ID] = &hashtag
+	}
+	clear(r.hashtagPosts)
+	for i := range snap.HashtagPosts {
+		hp := snap.HashtagPosts[i]
+		r.hashtagPosts[hp.HashtagID] = append(r.hashtagPosts[hp.HashtagID], &hp)
+	}
+	clear(r.hashtagFollowers)
+	for _, f := range snap.HashtagFollowers {
+		if r.hashtagFollowers[f.HashtagID] == nil {
+			r.hashtagFollowers[f.HashtagID] = make(map[string]bool)
+		}
+		r.hashtagFollowers[f.HashtagID][f.UserID] = true
+	}
+}
+
+func (r *DebateMemoryRepository) snapshot(snap *Snapshot) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, debate := range r.debates {
+		snap.Debates = append(snap.Debates, *debate)
+	}
+	// Participants keep their join order within each debate
+	for _, participants := range r.participants {
+		for _, p := range participants {
+			snap.Participants = append(snap.Participants, *p)
+		}
+	}
+	for _, request := range r.speakRequests {
+		snap.SpeakRequests = append(snap.SpeakRequests, *request)
+	}
+	for _, votes := range r.votes {
+		for _, vote := range votes {
+			snap.DebateVotes = append(snap.DebateVotes, *vote)
+		}
+	}
+}
+
+func (r *DebateMemoryRepository) restore(snap *Snapshot) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	clear(r.debates)
+	for i := range snap.Debates {
+		debate := snap.Debates[i]
+		r.debates[debate.ID] = &debate
+	}
+	clear(r.participants)
+	for i := range snap.Participants {
+		p := snap.Participants[i]
+		r.participants[p.DebateID] = append(r.participants[p.DebateID], &p)
+	}
+	clear(r.speakRequests)
+	for i := range snap.SpeakRequests {
+		request := snap.SpeakRequests[i]
+		r.speakRequests[request.ID] = &request
+	}
+	clear(r.votes)
+	for i := range snap.DebateVotes {
+		vote := snap.DebateVotes[i]
+		r.votes[vote.DebateID] = append(r.votes[vote.DebateID], &vote)
+	}
+}
+
+func (r *DebateStatsMemoryRepository) snapshot(snap *Snapshot) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, stats := range r.stats {
+		snap.DebateStats = append(snap.DebateStats, *stats)
+	}
+	for debateID := range r.recordedDebates {
+		snap.RecordedDebates = append(snap.RecordedDebates, debateID)
+	}
+}
+
+func (r *DebateStatsMemoryRepository) restore(snap *Snapshot) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	clear(r.stats)
+	for i := range snap.DebateStats {
+		stats := snap.DebateStats[i]
+		r.stats[normalizeTopic(stats.Topic)] = &stats
+	}
+	clear(r.recordedDebates)
+	for _, debateID := range snap.RecordedDebates {
+		r.recordedDebates[debateID] = true
+	}
+}
+
+func (r *NotificationMemoryRepository) snapshot(snap *Snapshot) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, notification := range r.notifications {
+		snap.Notifications = append(snap.Notifications, *notification)
+	}
+}
+
+func (r *NotificationMemoryRepository) restore(snap *Snapshot) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Rebuild the user index in creation order
+	notifications := make([]models.Notification, len(snap.Notifications))
+	copy(notifications, snap.Notifications)
+	sort.SliceStable(notifications, func(i, j int) bool {
+		return notifications[i].CreatedAt.Before(notifications[j].CreatedAt)
+	})
+
+	clear(r.notifications)
+	clear(r.userIndex)
+	for i := range notifications {
+		notification := &notifications[i]
+		r.notifications[notification.ID] = notification
+		r.userIndex[notification.UserID] = append(r.userIndex[notification.UserID], notification.ID)
+	}
+}
+
+func (r *AnalyticsMemoryRepository) snapshot(snap *Snapshot) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, impressions := range r.impressions {
+		for _, imp := range impressions {
+			snap.Impressions = append(snap.Impressions, *imp)
+		}
+	}
+}
+
+func (r *AnalyticsMemoryRepository) restore(snap *Snapshot) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	clear(r.impressions)
+	for i := range snap.Impressions {
+		imp := snap.Impressions[i]
+		r.impressions[imp.PostID] = append(r.impressions[imp.PostID], &imp)
+	}
+}
+
+func (r *CommunityMemoryRepository) snapshot(snap *Snapshot) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, community := range r.communities {
+		snap.Communities = append(snap.Communities, *community)
+	}
+	for _, members := range r.members {
+		for _, m := range members {
+			snap.Members = append(snap.Members, *m)
+		}
+	}
+	for communityID, users := range r.history {
+		for userID, joined := range users {
+			if joined {
+				snap.MemberHistory = append(snap.MemberHistory, CommunityJoin{CommunityID: communityID, UserID: userID})
+			}
+		}
+	}
+}
+
+func (r *CommunityMemoryRepository) restore(snap *Snapshot) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	clear(r.communities)
+	clear(r.history)
+	for i := range snap.Communities {
+		community := snap.Communities[i]
+		r.communities[community.ID] = &community
+		r.history[community.ID] = make(map[string]bool)
+	}
+	clear(r.members)
+	for i := range snap.Members {
+		m := snap.Members[i]
+		r.members[m.CommunityID] = append(r.members[m.CommunityID], &m)
+	}
+	for _, j := range snap.MemberHistory {
+		if r.history[j.CommunityID] == nil {
+			r.history[j.CommunityID] = make(map[string]bool)
+		}
+		r.history[j.CommunityID][j.UserID] = true
+	}
+}
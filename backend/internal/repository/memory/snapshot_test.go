@@ -0,0 +1,127 @@
+package memory
+
+import (
+	"bytes"
+	"encoding/gob"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	store := NewStore()
+	repos := store.Repositories()
+	now := time.Now()
+
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	must(repos.User.Create(&models.User{ID: "alice", Handle: "alice", Password: "secret"}))
+	must(repos.User.Create(&models.User{ID: "bob", Handle: "bob"}))
+	must(repos.User.Follow("alice", "bob"))
+	must(repos.Auth.CreateAuth(&models.Auth{UserID: "alice", Email: "alice@example.com", PasswordHash: "hash"}))
+	must(repos.Post.Create(&models.Post{ID: "p1", AuthorID: "alice", Content: "hello"}))
+	must(repos.Post.CreateComment(&models.Comment{ID: "c1", PostID: "p1", AuthorID: "bob"}))
+	must(repos.Post.AddReaction(&models.Reaction{UserID: "bob", PostID: "p1"}))
+	must(repos.Post.SavePost("bob", "p1"))
+	must(repos.Hashtag.Create(&models.Hashtag{ID: "h1", Slug: "go"}))
+	must(repos.Hashtag.AddPostToHashtag("h1", "p1", true))
+	must(repos.Hashtag.FollowHashtag("bob", "h1"))
+	must(repos.Debate.Create(&models.Debate{ID: "d1", HostID: "alice", Status: "ACTIVE"}))
+	must(repos.Debate.AddParticipant(&models.DebateParticipant{DebateID: "d1", UserID: "alice", Side: "agree"}))
+	must(repos.Debate.AddParticipant(&models.DebateParticipant{DebateID: "d1", UserID: "bob", Side: "disagree"}))
+	_, err := repos.DebateStats.RecordStats("Climate", 1, 1, 2, "d1")
+	must(err)
+	must(repos.Notification.Create(&models.Notification{ID: "n1", UserID: "bob", CreatedAt: now.Add(-time.Minute)}))
+	must(repos.Notification.Create(&models.Notification{ID: "n2", UserID: "bob", CreatedAt: now}))
+	must(repos.Analytics.RecordImpression("p1", "bob"))
+	must(repos.Community.Create(&models.Community{ID: "c1", Name: "Go"}))
+	must(repos.Community.AddMember(&models.CommunityMember{CommunityID: "c1", UserID: "bob", Status: "active"}))
+	must(repos.Community.RemoveMember("c1", "bob"))
+
+	path := filepath.Join(t.TempDir(), "snapshots", "memory.snap")
+	if _, err := store.SaveSnapshot(path); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	restored := NewStore()
+	snap, err := restored.LoadSnapshot(path)
+	if err != nil || snap == nil {
+		t.Fatalf("load = %v, %v", snap, err)
+	}
+	repos = restored.Repositories()
+
+	if user, _ := repos.User.GetByID("alice"); user == nil || user.Password != "secret" || user.FollowingCount != 1 {
+		t.Errorf("alice = %+v", user)
+	}
+	if following, _ := repos.User.IsFollowing("alice", "bob"); !following {
+		t.Error("follow lost")
+	}
+	if auth, _ := repos.Auth.GetByEmail("alice@example.com"); auth == nil || auth.PasswordHash != "hash" {
+		t.Errorf("auth = %+v", auth)
+	}
+	if post, _ := repos.Post.GetByID("p1"); post == nil || post.CommentCount != 1 || post.ReactionCount != 1 || post.SaveCount != 1 {
+		t.Errorf("post = %+v", post)
+	}
+	if reacted, _ := repos.Post.HasReacted("bob", "p1", nil); !reacted {
+		t.Error("reaction lost")
+	}
+	if saved, _ := repos.Post.IsSaved("bob", "p1"); !saved {
+		t.Error("save lost")
+	}
+	if boosts, _, _ := repos.Hashtag.GetHashtagStats("h1"); boosts != 1 {
+		t.Errorf("boosts = %d, want 1", boosts)
+	}
+	if following, _ := repos.Hashtag.IsFollowing("bob", "h1"); !following {
+		t.Error("hashtag follow lost")
+	}
+	if participants, _ := repos.Debate.GetParticipants("d1"); len(participants) != 2 || participants[0].UserID != "alice" {
+		t.Errorf("participants = %v", participants)
+	}
+	// The debate was recorded before the snapshot and must not count twice
+	if stats, _ := repos.DebateStats.RecordStats("Climate", 1, 1, 2, "d1"); stats == nil || stats.SessionsCount != 1 {
+		t.Errorf("stats = %+v", stats)
+	}
+	if list, _, _ := repos.Notification.GetByUserID("bob", repository.Page{Limit: 10}); len(list) != 2 || list[0].ID != "n2" {
+		t.Errorf("notifications = %v", list)
+	}
+	if viewers, _ := repos.Analytics.GetUniqueViewersAll("p1"); viewers != 1 {
+		t.Errorf("viewers = %d, want 1", viewers)
+	}
+	if joined, _ := repos.Community.HasJoinedBefore("c1", "bob"); !joined {
+		t.Error("community join history lost")
+	}
+}
+
+func TestLoadSnapshotMissingFile(t *testing.T) {
+	snap, err := NewStore().LoadSnapshot(filepath.Join(t.TempDir(), "missing.snap"))
+	if snap != nil || err != nil {
+		t.Errorf("load missing = %v, %v; want nil, nil", snap, err)
+	}
+}
+
+func TestReadSnapshotRejectsUnknownFormats(t *testing.T) {
+	headers := map[string]snapshotHeader{
+		"wrong magic":    {Magic: "something else", Version: SnapshotVersion},
+		"future version": {Magic: snapshotMagic, Version: SnapshotVersion + 1},
+	}
+	for name, header := range headers {
+		var buf bytes.Buffer
+		enc := gob.NewEncoder(&buf)
+		if err := enc.Encode(header); err != nil {
+			t.Fatal(err)
+		}
+		if err := enc.Encode(&Snapshot{}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ReadSnapshot(&buf); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package memory
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
+)
+
+type TwoFactorMemoryRepository struct {
+	enrollments map[string]*models.TwoFactor // userID -> enrollment
+	mu          repoMutex
+}
+
+func NewTwoFactorMemoryRepository() *TwoFactorMemoryRepository {
+	return &TwoFactorMemoryRepository{
+		enrollments: make(map[string]*models.TwoFactor),
+	}
+}
+
+func cloneTwoFactor(tf *models.TwoFactor) *models.TwoFactor {
+	clone := *tf
+	clone.RecoveryCodes = slices.Clone(tf.RecoveryCodes)
+	if tf.EnabledAt != nil {
+		enabledAt := *tf.EnabledAt
+		clone.EnabledAt = &enabledAt
+	}
+	return &clone
+}
+
+func (r *TwoFactorMemoryRepository) Get(userID string) (*models.TwoFactor, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tf, exists := r.enrollments[userID]
+	if !exists {
+		return nil, errors.New("two-factor enrollment not found")
+	}
+	return cloneTwoFactor(tf), nil
+}
+
+func (r *TwoFactorMemoryRepository) Save(tf *models.TwoFactor) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, exists := r.enrollments[tf.UserID]; exists {
+		tf.CreatedAt = existing.CreatedAt
+	} else {
+		tf.CreatedAt = time.Now()
+	}
+	r.enrollments[tf.UserID] = cloneTwoFactor(tf)
+	return nil
+}
+
+func (r *TwoFactorMemoryRepository) Delete(userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.enrollments, userID)
+	return nil
+}
+
+func (r *TwoFactorMemoryRepository) UseStep(userID string, step int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tf, exists := r.enrollments[userID]
+	if !exists {
+		return errors.New("two-factor enrollment not found")
+	}
+	if step <= tf.LastUsedStep {
+		return repository.ErrTwoFactorCodeUsed
+	}
+	tf.LastUsedStep = step
+	return nil
+}
+
+func (r *TwoFactorMemoryRepository) UseRecoveryCode(userID, hash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tf, exists := r.enrollments[userID]
+	if !exists {
+		return errors.New("two-factor enrollment not found")
+	}
+	i := slices.Index(tf.RecoveryCodes, hash)
+	if i < 0 {
+		return repository.ErrTwoFactorCodeUsed
+	}
+	tf.RecoveryCodes = slices.Delete(tf.RecoveryCodes, i, i+1)
+	return nil
+}
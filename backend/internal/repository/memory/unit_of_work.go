@@ -0,0 +1,286 @@
+package memory
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/yourusername/v-backend/internal/repository"
+)
+
+// repoMutex is the lock of an in-memory repository. The view of a repository
+// handed to a unit of work has a repoMutex of its own, whose first use makes
+// the unit of work take over the repository.
+type repoMutex struct {
+	sync.RWMutex
+	claim func()
+}
+
+func (m *repoMutex) Lock() {
+	if m.claim != nil {
+		m.claim()
+	}
+	m.RWMutex.Lock()
+}
+
+func (m *repoMutex) RLock() {
+	if m.claim != nil {
+		m.claim()
+	}
+	m.RWMutex.RLock()
+}
+
+// unitOfWork makes a group of writes atomic. fn works on views of the
+// repositories, which share their data but not their locks. The first time fn
+// uses a repository, the unit of work locks the repository itself until fn
+// returns and copies its contents; when fn fails, the repositories it used
+// are restored from those copies. Other writers wait for the repositories fn
+// uses, so a rollback only undoes fn's own writes, and only the repositories
+// fn used are copied.
+type unitOfWork struct {
+	store *Store
+}
+
+func (u *unitOfWork) Do(fn func(tx *repository.Repositories) error) error {
+	u.store.txMu.Lock()
+	defer u.store.txMu.Unlock()
+
+	t := &transaction{}
+	repos := t.views(u.store)
+	repos.UnitOfWork = joinedUnitOfWork{repos: repos}
+	err := fn(repos)
+	t.end(u.store, err != nil)
+	return err
+}
+
+// joinedUnitOfWork is handed to code already inside a unit of work, so nested
+// calls share the outer rollback instead of waiting on the store lock
+type joinedUnitOfWork struct {
+	repos *repository.Repositories
+}
+
+func (j joinedUnitOfWork) Do(fn func(tx *repository.Repositories) error) error {
+	return fn(j.repos)
+}
+
+// claimed is a repository a unit of work takes over
+type claimed struct {
+	lock     *repoMutex // The repository's own lock, held until the unit of work ends
+	taken    atomic.Bool
+	before   *Snapshot
+	snapshot func(*Snapshot) // Through the view, whose lock is free
+	restore  func(*Snapshot)
+}
+
+// transaction tracks the repositories one unit of work has taken over
+type transaction struct {
+	mu      sync.Mutex
+	claimed []*claimed
+}
+
+// track returns the takeover of a repository, done when its view is first used
+func (t *transaction) track(view, lock *repoMutex, snapshot, restore func(*Snapshot)) *claimed {
+	c := &claimed{lock: lock, snapshot: snapshot, restore: restore}
+	t.takeOnUse(view, c)
+	return c
+}
+
+// takeOnUse makes the first use of view take over repos
+func (t *transaction) takeOnUse(view *repoMutex, repos ...*claimed) {
+	view.claim = func() {
+		for _, c := range repos {
+			if !c.taken.Load() {
+				t.take(repos)
+				return
+			}
+		}
+	}
+}
+
+// take locks the repositories not yet taken, in order, and copies them
+func (t *transaction) take(repos []*claimed) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var fresh []*claimed
+	for _, c := range repos {
+		if !c.taken.Load() {
+			c.lock.RWMutex.Lock()
+			fresh = append(fresh, c)
+		}
+	}
+	// Copying goes through the views, so mark them taken first
+	for _, c := range fresh {
+		c.taken.Store(true)
+	}
+	for _, c := range fresh {
+		c.before = &Snapshot{}
+		c.snapshot(c.before)
+		t.claimed = append(t.claimed, c)
+	}
+}
+
+// end restores the repositories taken when rolling back, and lets them go
+func (t *transaction) end(s *Store, rollback bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, c := range t.claimed {
+		if rollback {
+			c.restore(c.before)
+		}
+		c.lock.RWMutex.Unlock()
+	}
+	if rollback && len(t.claimed) > 0 {
+		// Trending and popular caches are derived from the restored posts
+		s.Hashtag.refreshCaches()
+	}
+}
+
+// views returns the repositories of a unit of work
+func (t *transaction) views(s *Store) *repository.Repositories {
+	user := s.User.view()
+	t.track(&user.mu, &s.User.mu, user.snapshot, user.restore)
+	auth := s.Auth.view()
+	t.track(&auth.mu, &s.Auth.mu, auth.snapshot, auth.restore)
+	session := s.Session.view()
+	t.track(&session.mu, &s.Session.mu, session.snapshot, session.restore)
+	emailToken := s.EmailToken.view()
+	t.track(&emailToken.mu, &s.EmailToken.mu, emailToken.snapshot, emailToken.restore)
+	twoFactor := s.TwoFactor.view()
+	t.track(&twoFactor.mu, &s.TwoFactor.mu, twoFactor.snapshot, twoFactor.restore)
+	loginThrottle := s.LoginThrottle.view()
+	t.track(&loginThrottle.mu, &s.LoginThrottle.mu, loginThrottle.snapshot, loginThrottle.restore)
+	identity := s.Identity.view()
+	t.track(&identity.mu, &s.Identity.mu, identity.snapshot, identity.restore)
+	signingKey := s.SigningKey.view()
+	t.track(&signingKey.mu, &s.SigningKey.mu, signingKey.snapshot, signingKey.restore)
+	apiKey := s.APIKey.view()
+	t.track(&apiKey.mu, &s.APIKey.mu, apiKey.snapshot, apiKey.restore)
+	message := s.Message.view()
+	t.track(&message.mu, &s.Message.mu, message.snapshot, message.restore)
+	debate := s.Debate.view()
+	t.track(&debate.mu, &s.Debate.mu, debate.snapshot, debate.restore)
+	debateStats := s.DebateStats.view()
+	t.track(&debateStats.mu, &s.DebateStats.mu, debateStats.snapshot, debateStats.restore)
+	notification := s.Notification.view()
+	t.track(&notification.mu, &s.Notification.mu, notification.snapshot, notification.restore)
+	community := s.Community.view()
+	t.track(&community.mu, &s.Community.mu, community.snapshot, community.restore)
+
+	// Hashtags and analytics read posts while holding their own lock, so the
+	// three are taken together, posts last, so a unit of work never holds
+	// posts while waiting on a writer that holds one of the others
+	post := s.Post.view()
+	hashtag := s.Hashtag.view(post)
+	analytics := s.Analytics.view(post)
+	group := []*claimed{
+		t.track(&hashtag.mu, &s.Hashtag.mu, hashtag.snapshot, hashtag.restore),
+		t.track(&analytics.mu, &s.Analytics.mu, analytics.snapshot, analytics.restore),
+		t.track(&post.mu, &s.Post.mu, post.snapshot, post.restore),
+	}
+	for _, view := range []*repoMutex{&hashtag.mu, &analytics.mu, &post.mu} {
+		t.takeOnUse(view, group...)
+	}
+
+	return &repository.Repositories{
+		Auth:          auth,
+		Session:       session,
+		EmailToken:    emailToken,
+		TwoFactor:     twoFactor,
+		LoginThrottle: loginThrottle,
+		Identity:      identity,
+		SigningKey:    signingKey,
+		APIKey:        apiKey,
+		User:          user,
+		Post:          post,
+		Message:       message,
+		Hashtag:       hashtag,
+		Debate:        debate,
+		DebateStats:   debateStats,
+		Notification:  notification,
+		Analytics:     analytics,
+		Community:     community,
+	}
+}
+
+// The views below share a repository's data, but not its lock
+
+func (r *UserMemoryRepository) view() *UserMemoryRepository {
+	return &UserMemoryRepository{users: r.users, follows: r.follows}
+}
+
+func (r *AuthMemoryRepository) view() *AuthMemoryRepository {
+	return &AuthMemoryRepository{auths: r.auths}
+}
+
+func (r *SessionMemoryRepository) view() *SessionMemoryRepository {
+	return &SessionMemoryRepository{sessions: r.sessions}
+}
+
+func (r *EmailTokenMemoryRepository) view() *EmailTokenMemoryRepository {
+	return &EmailTokenMemoryRepository{tokens: r.tokens}
+}
+
+func (r *TwoFactorMemoryRepository) view() *TwoFactorMemoryRepository {
+	return &TwoFactorMemoryRepository{enrollments: r.enrollments}
+}
+
+func (r *LoginThrottleMemoryRepository) view() *LoginThrottleMemoryRepository {
+	return &LoginThrottleMemoryRepository{throttles: r.throttles}
+}
+
+func (r *IdentityMemoryRepository) view() *IdentityMemoryRepository {
+	return &IdentityMemoryRepository{identities: r.identities}
+}
+
+func (r *SigningKeyMemoryRepository) view() *SigningKeyMemoryRepository {
+	return &SigningKeyMemoryRepository{keys: r.keys}
+}
+
+func (r *APIKeyMemoryRepository) view() *APIKeyMemoryRepository {
+	return &APIKeyMemoryRepository{keys: r.keys}
+}
+
+func (r *PostMemoryRepository) view() *PostMemoryRepository {
+	return &PostMemoryRepository{posts: r.posts, comments: r.comments, reactions: r.reactions, saves: r.saves}
+}
+
+func (r *MessageMemoryRepository) view() *MessageMemoryRepository {
+	return &MessageMemoryRepository{conversations: r.conversations, messages: r.messages}
+}
+
+// view reads posts through post, the view of the same unit of work
+func (r *HashtagMemoryRepository) view(post *PostMemoryRepository) *HashtagMemoryRepository {
+	r.cacheMu.RLock()
+	defer r.cacheMu.RUnlock()
+	return &HashtagMemoryRepository{
+		hashtags:              r.hashtags,
+		hashtagPosts:          r.hashtagPosts,
+		hashtagFollowers:      r.hashtagFollowers,
+		postRepo:              post,
+		trendingCache:         r.trendingCache,
+		trendingCategoryCache: r.trendingCategoryCache,
+		popularCache:          r.popularCache,
+		cacheMu:               r.cacheMu,
+	}
+}
+
+func (r *DebateMemoryRepository) view() *DebateMemoryRepository {
+	return &DebateMemoryRepository{debates: r.debates, participants: r.participants, speakRequests: r.speakRequests, votes: r.votes}
+}
+
+func (r *DebateStatsMemoryRepository) view() *DebateStatsMemoryRepository {
+	return &DebateStatsMemoryRepository{stats: r.stats, recordedDebates: r.recordedDebates}
+}
+
+func (r *NotificationMemoryRepository) view() *NotificationMemoryRepository {
+	return &NotificationMemoryRepository{notifications: r.notifications, userIndex: r.userIndex}
+}
+
+// view reads posts through post, the view of the same unit of work
+func (r *AnalyticsMemoryRepository) view(post *PostMemoryRepository) *AnalyticsMemoryRepository {
+	return &AnalyticsMemoryRepository{impressions: r.impressions, postRepo: post}
+}
+
+func (r *CommunityMemoryRepository) view() *CommunityMemoryRepository {
+	return &CommunityMemoryRepository{communities: r.communities, members: r.members, history: r.history}
+}
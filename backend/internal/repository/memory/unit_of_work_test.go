@@ -0,0 +1,61 @@
+package memory
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
+)
+
+func TestUnitOfWorkRollbackKeepsOtherWrites(t *testing.T) {
+	repos := NewRepositories()
+	if err := repos.User.Create(&models.User{ID: "alice", Handle: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+
+	errFailed := errors.New("failed after writing")
+	bobCreated := make(chan error, 1)
+	err := repos.UnitOfWork.Do(func(tx *repository.Repositories) error {
+		user, err := tx.User.GetByID("alice")
+		if err != nil {
+			return err
+		}
+		user.Points = 50
+		if err := tx.User.Update(user); err != nil {
+			return err
+		}
+
+		// Posts are not used here, so other writers go straight through
+		if err := repos.Post.Create(&models.Post{ID: "p1", AuthorID: "alice", Content: "p1"}); err != nil {
+			return err
+		}
+		// Users are, so this waits for the unit of work to end
+		go func() {
+			bobCreated <- repos.User.Create(&models.User{ID: "bob", Handle: "bob"})
+		}()
+		select {
+		case err := <-bobCreated:
+			t.Errorf("bob created during the unit of work: %v", err)
+		case <-time.After(50 * time.Millisecond):
+		}
+		return errFailed
+	})
+	if err != errFailed {
+		t.Fatalf("unit of work = %v, want %v", err, errFailed)
+	}
+
+	if err := <-bobCreated; err != nil {
+		t.Fatalf("create bob: %v", err)
+	}
+	if user, _ := repos.User.GetByID("alice"); user == nil || user.Points != 0 {
+		t.Errorf("alice = %+v, want points rolled back", user)
+	}
+	if _, err := repos.User.GetByID("bob"); err != nil {
+		t.Errorf("bob lost in rollback: %v", err)
+	}
+	if _, err := repos.Post.GetByID("p1"); err != nil {
+		t.Errorf("post written outside the unit of work lost in rollback: %v", err)
+	}
+}
@@ -2,16 +2,18 @@ package memory
 
 import (
 	"errors"
-	"sync"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
 )
 
 type UserMemoryRepository struct {
 	users   map[string]*models.User
 	follows map[string]map[string]time.Time // followerID -> map[followingID]timestamp
-	mu      sync.RWMutex
+	mu      repoMutex
 }
 
 func NewUserMemoryRepository() *UserMemoryRepository {
@@ -27,8 +29,10 @@ func NewUserMemoryRepository() *UserMemoryRepository {
 		Handle:    "demo_user",
 		Email:     "demo@example.com",
 		AvatarURL: "https://api.dicebear.com/9.x/bottts/svg?seed=demo",
+		Role:      models.PlatformRoleUser,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
+		Version:   1,
 	}
 	repo.users[demoUser.ID] = demoUser
 
@@ -43,9 +47,13 @@ func (r *UserMemoryRepository) Create(user *models.User) error {
 		return errors.New("user already exists")
 	}
 
+	if user.Role == "" {
+		user.Role = models.PlatformRoleUser
+	}
 	user.CreatedAt = time.Now()
 	user.UpdatedAt = time.Now()
-	r.users[user.ID] = user
+	user.Version = 1
+	r.users[user.ID] = cloneUser(user)
 	return nil
 }
 
@@ -57,7 +65,7 @@ func (r *UserMemoryRepository) GetByID(id string) (*models.User, error) {
 	if !exists {
 		return nil, errors.New("user not found")
 	}
-	return user, nil
+	return cloneUser(user), nil
 }
 
 func (r *UserMemoryRepository) GetByHandle(handle string) (*models.User, error) {
@@ -66,7 +74,7 @@ func (r *UserMemoryRepository) GetByHandle(handle string) (*models.User, error)
 
 	for _, user := range r.users {
 		if user.Handle == handle {
-			return user, nil
+			return cloneUser(user), nil
 		}
 	}
 	return nil, errors.New("user not found")
@@ -78,7 +86,7 @@ func (r *UserMemoryRepository) GetByEmail(email string) (*models.User, error) {
 
 	for _, user := range r.users {
 		if user.Email == email {
-			return user, nil
+			return cloneUser(user), nil
 		}
 	}
 	return nil, errors.New("user not found")
@@ -88,12 +96,17 @@ func (r *UserMemoryRepository) Update(user *models.User) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, exists := r.users[user.ID]; !exists {
+	stored, exists := r.users[user.ID]
+	if !exists {
 		return errors.New("user not found")
 	}
+	if stored.Version != user.Version {
+		return &repository.ConflictError{Entity: "user", ID: user.ID, Version: stored.Version}
+	}
 
+	user.Version++
 	user.UpdatedAt = time.Now()
-	r.users[user.ID] = user
+	r.users[user.ID] = cloneUser(user)
 	return nil
 }
 
@@ -112,27 +125,38 @@ func (r *UserMemoryRepository) Delete(id string) error {
 	return nil
 }
 
-func (r *UserMemoryRepository) List(limit, offset int) ([]*models.User, error) {
+func (r *UserMemoryRepository) List(page repository.Page) ([]*models.User, string, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	users := make([]*models.User, 0, len(r.users))
 	for _, user := range r.users {
-		users = append(users, user)
+		users = append(users, cloneUser(user))
 	}
 
-	// Apply pagination
-	start := offset
-	if start > len(users) {
-		return []*models.User{}, nil
-	}
+	// Oldest accounts first
+	return paginate(users, page, false, func(u *models.User) (time.Time, string) {
+		return u.CreatedAt, u.ID
+	})
+}
 
-	end := start + limit
-	if end > len(users) {
-		end = len(users)
-	}
+func (r *UserMemoryRepository) ListBots(ownerID string) ([]*models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 
-	return users[start:end], nil
+	bots := make([]*models.User, 0)
+	for _, user := range r.users {
+		if user.IsBot && user.BotOwnerID == ownerID {
+			bots = append(bots, cloneUser(user))
+		}
+	}
+	slices.SortFunc(bots, func(a, b *models.User) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return bots, nil
 }
 
 func (r *UserMemoryRepository) Follow(followerID, followingID string) error {
@@ -150,11 +174,18 @@ func (r *UserMemoryRepository) Follow(followerID, followingID string) error {
 		r.follows[followerID] = make(map[string]time.Time)
 	}
 
+	// Already following, counts are unchanged
+	if _, exists := r.follows[followerID][followingID]; exists {
+		return nil
+	}
+
 	r.follows[followerID][followingID] = time.Now()
 
 	// Update counts
 	r.users[followerID].FollowingCount++
+	r.users[followerID].Version++
 	r.users[followingID].FollowersCount++
+	r.users[followingID].Version++
 
 	return nil
 }
@@ -163,15 +194,17 @@ func (r *UserMemoryRepository) Unfollow(followerID, followingID string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if r.follows[followerID] != nil {
+	if _, exists := r.follows[followerID][followingID]; exists {
 		delete(r.follows[followerID], followingID)
 
 		// Update counts
 		if user := r.users[followerID]; user != nil && user.FollowingCount > 0 {
 			user.FollowingCount--
+			user.Version++
 		}
 		if user := r.users[followingID]; user != nil && user.FollowersCount > 0 {
 			user.FollowersCount--
+			user.Version++
 		}
 	}
 
@@ -190,7 +223,7 @@ func (r *UserMemoryRepository) IsFollowing(followerID, followingID string) (bool
 	return exists, nil
 }
 
-func (r *UserMemoryRepository) GetFollowers(userID string, limit, offset int) ([]*models.User, error) {
+func (r *UserMemoryRepository) GetFollowers(userID string, page repository.Page) ([]*models.User, string, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -198,48 +231,38 @@ func (r *UserMemoryRepository) GetFollowers(userID string, limit, offset int) ([
 	for followerID, followingMap := range r.follows {
 		if _, follows := followingMap[userID]; follows {
 			if user, exists := r.users[followerID]; exists {
-				followers = append(followers, user)
+				followers = append(followers, cloneUser(user))
 			}
 		}
 	}
 
-	// Apply pagination
-	start := offset
-	if start > len(followers) {
-		return []*models.User{}, nil
-	}
-
-	end := start + limit
-	if end > len(followers) {
-		end = len(followers)
-	}
-
-	return followers[start:end], nil
+	// In the order they followed
+	return paginate(followers, page, false, func(u *models.User) (time.Time, string) {
+		return r.follows[u.ID][userID], u.ID
+	})
 }
 
-func (r *UserMemoryRepository) GetFollowing(userID string, limit, offset int) ([]*models.User, error) {
+func (r *UserMemoryRepository) GetFollowing(userID string, page repository.Page) ([]*models.User, string, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
+	followingMap := r.follows[userID]
 	following := make([]*models.User, 0)
-	if followingMap := r.follows[userID]; followingMap != nil {
-		for followingID := range followingMap {
-			if user, exists := r.users[followingID]; exists {
-				following = append(following, user)
-			}
+	for followingID := range followingMap {
+		if user, exists := r.users[followingID]; exists {
+			following = append(following, cloneUser(user))
 		}
 	}
 
-	// Apply pagination
-	start := offset
-	if start > len(following) {
-		return []*models.User{}, nil
-	}
-
-	end := start + limit
-	if end > len(following) {
-		end = len(following)
-	}
+	// In the order they were followed
+	return paginate(following, page, false, func(u *models.User) (time.Time, string) {
+		return followingMap[u.ID], u.ID
+	})
+}
 
-	return following[start:end], nil
+// cloneUser copies a stored user so callers cannot change it without Update
+func cloneUser(user *models.User) *models.User {
+	clone := *user
+	clone.Languages = slices.Clone(user.Languages)
+	return &clone
 }
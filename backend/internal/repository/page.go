@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCursor is returned when a cursor was not issued by EncodeCursor
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Page selects one page of a list. Lists are ordered by creation time and ID,
+// so a cursor stays valid while new items are added. Cursor is the NextCursor
+// returned with the previous page, or empty for the first page.
+type Page struct {
+	Limit  int
+	Cursor string
+}
+
+// Cursor is the decoded position of the last item of a page
+type Cursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// EncodeCursor returns the opaque cursor pointing after the item at createdAt/id
+func EncodeCursor(createdAt time.Time, id string) string {
+	raw := createdAt.UTC().Format(time.RFC3339Nano) + "|" + id
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor parses a cursor from EncodeCursor. The empty cursor decodes to
+// nil, meaning the list starts from the beginning.
+func DecodeCursor(cursor string) (*Cursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	ts, id, ok := strings.Cut(string(raw), "|")
+	if !ok || id == "" {
+		return nil, ErrInvalidCursor
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &Cursor{CreatedAt: createdAt, ID: id}, nil
+}
+
+// Precedes reports whether the cursor position comes before the item at
+// createdAt/id in a list sorted ascending, or descending when desc is set
+func (c *Cursor) Precedes(createdAt time.Time, id string, desc bool) bool {
+	if !createdAt.Equal(c.CreatedAt) {
+		return createdAt.After(c.CreatedAt) != desc
+	}
+	if id == c.ID {
+		return false
+	}
+	return (id > c.ID) != desc
+}
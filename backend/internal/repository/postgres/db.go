@@ -0,0 +1,45 @@
+// Package postgres opens PostgreSQL databases for the repositories in sqlstore.
+package postgres
+
+import (
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/yourusername/v-backend/internal/repository"
+	"github.com/yourusername/v-backend/internal/repository/sqlstore"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Migrations holds the versioned schema for this backend, for use with the migrate package
+var Migrations, _ = fs.Sub(migrationFiles, "migrations")
+
+// Open connects to the database at databaseURL. The schema is managed
+// separately through Migrations.
+func Open(databaseURL string) (*sql.DB, error) {
+	db, err := sql.Open("pgx", databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+
+	db.SetMaxOpenConns(25)
+	db.SetMaxIdleConns(5)
+	db.SetConnMaxLifetime(30 * time.Minute)
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("ping database: %w", err)
+	}
+
+	return db, nil
+}
+
+// NewRepositories returns Postgres-backed implementations of every repository
+func NewRepositories(db *sql.DB) *repository.Repositories {
+	return sqlstore.NewRepositories(db)
+}
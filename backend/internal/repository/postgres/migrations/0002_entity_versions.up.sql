@@ -0,0 +1,5 @@
+-- Optimistic concurrency: every write bumps the version and updates must match it
+ALTER TABLE users ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE posts ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE debates ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE communities ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_communities_deleted;
+DROP INDEX IF EXISTS idx_comments_deleted;
+DROP INDEX IF EXISTS idx_posts_deleted;
+ALTER TABLE communities DROP COLUMN deleted_at;
+ALTER TABLE comments DROP COLUMN deleted_at;
+ALTER TABLE posts DROP COLUMN deleted_at;
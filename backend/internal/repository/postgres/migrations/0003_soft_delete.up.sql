@@ -0,0 +1,7 @@
+-- Soft deletion: rows stay until the purger removes them after the retention period
+ALTER TABLE posts ADD COLUMN deleted_at TIMESTAMPTZ;
+ALTER TABLE comments ADD COLUMN deleted_at TIMESTAMPTZ;
+ALTER TABLE communities ADD COLUMN deleted_at TIMESTAMPTZ;
+CREATE INDEX IF NOT EXISTS idx_posts_deleted ON posts (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_comments_deleted ON comments (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_communities_deleted ON communities (deleted_at);
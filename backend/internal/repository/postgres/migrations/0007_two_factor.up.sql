@@ -0,0 +1,16 @@
+-- TOTP enrollments and the bcrypt hashes of their unused recovery codes
+CREATE TABLE IF NOT EXISTS two_factor (
+    user_id        TEXT PRIMARY KEY,
+    secret         TEXT NOT NULL,
+    enabled        BOOLEAN NOT NULL DEFAULT FALSE,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    created_at     TIMESTAMPTZ NOT NULL,
+    enabled_at     TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS two_factor_recovery_codes (
+    user_id   TEXT NOT NULL,
+    code_hash TEXT NOT NULL,
+    position  INTEGER NOT NULL,
+    PRIMARY KEY (user_id, code_hash)
+);
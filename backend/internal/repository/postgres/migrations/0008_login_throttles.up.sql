@@ -0,0 +1,8 @@
+-- Failed sign-ins per account and per IP address, for backoff and lockouts
+CREATE TABLE IF NOT EXISTS login_throttles (
+    throttle_key    TEXT PRIMARY KEY,
+    failures        INTEGER NOT NULL,
+    last_failure_at TIMESTAMPTZ NOT NULL,
+    blocked_until   TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_login_throttles_last_failure ON login_throttles (last_failure_at);
@@ -0,0 +1,10 @@
+-- Keys access tokens are signed with, sealed with the JWT secret
+CREATE TABLE IF NOT EXISTS signing_keys (
+    id           TEXT PRIMARY KEY,
+    algorithm    TEXT NOT NULL,
+    private_key  BYTEA NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL,
+    activates_at TIMESTAMPTZ NOT NULL,
+    retires_at   TIMESTAMPTZ NOT NULL,
+    expires_at   TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_api_keys_user;
+DROP TABLE IF EXISTS api_keys;
+ALTER TABLE posts DROP COLUMN author_is_bot;
+DROP INDEX IF EXISTS idx_users_bot_owner;
+ALTER TABLE users DROP COLUMN bot_owner_id;
+ALTER TABLE users DROP COLUMN is_bot;
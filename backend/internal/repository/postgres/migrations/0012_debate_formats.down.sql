@@ -0,0 +1,3 @@
+ALTER TABLE debates DROP COLUMN round_started_at;
+ALTER TABLE debates DROP COLUMN current_round;
+ALTER TABLE debates DROP COLUMN format;
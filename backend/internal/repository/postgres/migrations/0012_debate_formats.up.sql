@@ -0,0 +1,4 @@
+-- Debate formats and the round clock
+ALTER TABLE debates ADD COLUMN format TEXT NOT NULL DEFAULT '';
+ALTER TABLE debates ADD COLUMN current_round INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE debates ADD COLUMN round_started_at TIMESTAMPTZ;
@@ -0,0 +1,6 @@
+ALTER TABLE speak_requests DROP COLUMN turn_ends_at;
+ALTER TABLE speak_requests DROP COLUMN turn_started_at;
+ALTER TABLE speak_requests DROP COLUMN position;
+ALTER TABLE speak_requests DROP COLUMN side;
+ALTER TABLE debates DROP COLUMN max_turn_seconds;
+ALTER TABLE debates DROP COLUMN queue_order;
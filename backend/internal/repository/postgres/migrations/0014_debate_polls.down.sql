@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS debate_votes;
+ALTER TABLE debates DROP COLUMN result;
+ALTER TABLE debates DROP COLUMN poll_closes_at;
@@ -0,0 +1,12 @@
+-- Audience polls before and after a debate, and the result they decide
+ALTER TABLE debates ADD COLUMN poll_closes_at TIMESTAMPTZ;
+ALTER TABLE debates ADD COLUMN result TEXT;
+
+CREATE TABLE IF NOT EXISTS debate_votes (
+    debate_id  TEXT NOT NULL REFERENCES debates (id) ON DELETE CASCADE,
+    user_id    TEXT NOT NULL,
+    phase      TEXT NOT NULL,
+    side       TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (debate_id, user_id, phase)
+);
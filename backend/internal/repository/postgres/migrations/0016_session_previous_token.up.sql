@@ -0,0 +1,3 @@
+-- The refresh token a session rotated away from last. Presenting it again
+-- means it was stolen, while other stale tokens are just rejected
+ALTER TABLE sessions ADD COLUMN previous_token_hash TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,26 @@
+package repository
+
+// Repositories bundles one implementation of every repository interface so the
+// storage backend can be chosen in a single place at startup
+type Repositories struct {
+	Auth          AuthRepository
+	Session       SessionRepository
+	EmailToken    EmailTokenRepository
+	TwoFactor     TwoFactorRepository
+	LoginThrottle LoginThrottleRepository
+	Identity      IdentityRepository
+	SigningKey    SigningKeyRepository
+	APIKey        APIKeyRepository
+	User          UserRepository
+	Post          PostRepository
+	Message       MessageRepository
+	Hashtag       HashtagRepository
+	Debate        DebateRepository
+	DebateStats   DebateStatsRepository
+	Notification  NotificationRepository
+	Analytics     AnalyticsRepository
+	Community     CommunityRepository
+
+	// UnitOfWork runs multi-repository writes in one transaction
+	UnitOfWork UnitOfWork
+}
@@ -15,6 +15,94 @@ type AuthRepository interface {
 	DeleteAuth(userID string) error
 }
 
+// TwoFactorRepository stores TOTP enrollments, at most one per user
+type TwoFactorRepository interface {
+	Get(userID string) (*models.TwoFactor, error)
+	// Save creates the user's enrollment or replaces it
+	Save(tf *models.TwoFactor) error
+	Delete(userID string) error
+	// UseStep records that the code of a time step was accepted, failing with
+	// ErrTwoFactorCodeUsed unless step is later than the last one recorded
+	UseStep(userID string, step int64) error
+	// UseRecoveryCode removes a recovery code hash, failing with
+	// ErrTwoFactorCodeUsed when it is already gone
+	UseRecoveryCode(userID, hash string) error
+}
+
+// IdentityRepository links accounts to users at OpenID Connect providers
+type IdentityRepository interface {
+	// Create links an identity, failing with ErrIdentityLinked when the
+	// provider's user is linked already or the account already has an
+	// identity at the provider
+	Create(identity *models.Identity) error
+	Get(provider, subject string) (*models.Identity, error)
+	ListByUser(userID string) ([]*models.Identity, error)
+	Delete(userID, provider string) error
+}
+
+// LoginThrottleRepository tracks failed sign-ins per account and per IP address
+type LoginThrottleRepository interface {
+	Get(key string) (*models.LoginThrottle, error)
+	// RecordFailure counts a failed sign-in at at and returns the throttle. The
+	// count starts over when the previous failure was before since.
+	RecordFailure(key string, at, since time.Time) (*models.LoginThrottle, error)
+	Block(key string, until time.Time) error
+	Reset(key string) error
+	// Prune deletes throttles whose last failure was before cutoff and returns
+	// how many went
+	Prune(cutoff time.Time) (int, error)
+}
+
+// APIKeyRepository stores API keys by the hash of the key
+type APIKeyRepository interface {
+	Create(key *models.APIKey) error
+	GetByID(id string) (*models.APIKey, error)
+	// ListByUser returns the user's keys that are not revoked, newest first
+	ListByUser(userID string) ([]*models.APIKey, error)
+	// Touch records that the key was used at at
+	Touch(id string, at time.Time) error
+	// Revoke revokes one of the user's keys. It fails if there is no such key
+	// or it was already revoked.
+	Revoke(userID, id string) error
+}
+
+// SigningKeyRepository stores the keys access tokens are signed with
+type SigningKeyRepository interface {
+	Create(key *models.SigningKey) error
+	// List returns every key, oldest activation first
+	List() ([]*models.SigningKey, error)
+	// Prune deletes keys that expired before cutoff and returns how many went
+	Prune(cutoff time.Time) (int, error)
+}
+
+// EmailTokenRepository stores the hashes of tokens mailed to users for email
+// verification and password resets
+type EmailTokenRepository interface {
+	Create(token *models.EmailToken) error
+	// Consume marks the token used and returns it, unless it was already used,
+	// has expired or does not match purpose and hash
+	Consume(id string, purpose models.EmailTokenPurpose, hash string) (*models.EmailToken, error)
+	// InvalidateForUser uses up every outstanding token of the user for purpose
+	InvalidateForUser(userID string, purpose models.EmailTokenPurpose) error
+}
+
+// SessionRepository stores sign-in sessions. Revoked sessions are kept until
+// they expire so a replayed refresh token can still be traced to its session.
+type SessionRepository interface {
+	Create(session *models.Session) error
+	GetByID(id string) (*models.Session, error)
+	// ListActive returns the user's unrevoked, unexpired sessions, most recently used first
+	ListActive(userID string) ([]*models.Session, error)
+	// Rotate replaces the refresh token hash of a live session, keeping the
+	// old one as the previous hash. It fails with ErrRefreshTokenReused when
+	// oldHash is the previous hash, and ErrRefreshTokenInvalid when it is
+	// neither or the session is no longer live.
+	Rotate(id, oldHash, newHash string, expiresAt time.Time) error
+	Revoke(id string) error
+	// RevokeAllForUser revokes every session of the user except keepID
+	RevokeAllForUser(userID, keepID string) error
+}
+
 // UserRepository defines the interface for user data access
 type UserRepository interface {
 	Create(user *models.User) error
@@ -23,30 +111,39 @@ type UserRepository interface {
 	GetByEmail(email string) (*models.User, error)
 	Update(user *models.User) error
 	Delete(id string) error
-	List(limit, offset int) ([]*models.User, error)
+	List(page Page) ([]*models.User, string, error)
+	// ListBots returns the bot accounts a user owns, oldest first
+	ListBots(ownerID string) ([]*models.User, error)
 
 	// Follow operations
 	Follow(followerID, followingID string) error
 	Unfollow(followerID, followingID string) error
 	IsFollowing(followerID, followingID string) (bool, error)
-	GetFollowers(userID string, limit, offset int) ([]*models.User, error)
-	GetFollowing(userID string, limit, offset int) ([]*models.User, error)
+	GetFollowers(userID string, page Page) ([]*models.User, string, error)
+	GetFollowing(userID string, page Page) ([]*models.User, string, error)
 }
 
-// PostRepository defines the interface for post data access
+// PostRepository defines the interface for post data access.
+// Deleting posts and comments is soft: they disappear from every query except
+// GetDeletedByID, can be restored for a while and are removed by Purge.
 type PostRepository interface {
 	Create(post *models.Post) error
 	GetByID(id string) (*models.Post, error)
+	GetDeletedByID(id string) (*models.Post, error)
 	Update(post *models.Post) error
 	Delete(id string) error
-	List(limit, offset int) ([]*models.Post, error)
-	ListByAuthor(authorID string, limit, offset int) ([]*models.Post, error)
-	ListByCommunity(communityID string, limit, offset int) ([]*models.Post, error)
+	Restore(id string, deletedSince time.Time) error // ErrRestoreWindowExpired if deleted before deletedSince
+	List(page Page) ([]*models.Post, string, error)
+	ListByAuthor(authorID string, page Page) ([]*models.Post, string, error)
+	ListByCommunity(communityID string, page Page) ([]*models.Post, string, error)
 
 	// Comment operations
 	CreateComment(comment *models.Comment) error
 	GetCommentsByPost(postID string) ([]*models.Comment, error)
+	// GetCommentByID also returns deleted comments, with DeletedAt set
+	GetCommentByID(id string) (*models.Comment, error)
 	DeleteComment(id string) error
+	RestoreComment(id string, deletedSince time.Time) error
 
 	// Reaction operations
 	AddReaction(reaction *models.Reaction) error
@@ -56,8 +153,11 @@ type PostRepository interface {
 	// Save operations
 	SavePost(userID, postID string) error
 	UnsavePost(userID, postID string) error
-	GetSavedPosts(userID string, limit, offset int) ([]*models.Post, error)
+	GetSavedPosts(userID string, page Page) ([]*models.Post, string, error)
 	IsSaved(userID, postID string) (bool, error)
+
+	// Purge hard-deletes posts and comments deleted before cutoff and returns how many
+	Purge(cutoff time.Time) (int, error)
 }
 
 // MessageRepository defines the interface for message data access
@@ -69,7 +169,7 @@ type MessageRepository interface {
 
 	CreateMessage(message *models.Message) error
 	GetMessage(id string) (*models.Message, error)
-	ListMessages(conversationID string, limit, offset int) ([]*models.Message, error)
+	ListMessages(conversationID string, page Page) ([]*models.Message, string, error)
 	MarkAsRead(messageID string) error
 	GetUnreadCount(conversationID, userID string) (int, error)
 }
@@ -79,7 +179,7 @@ type HashtagRepository interface {
 	Create(hashtag *models.Hashtag) error
 	GetByID(id string) (*models.Hashtag, error)
 	GetBySlug(slug string) (*models.Hashtag, error)
-	List(limit, offset int) ([]*models.Hashtag, error)
+	List(page Page) ([]*models.Hashtag, string, error)
 	Delete(id string) error
 
 	AddPostToHashtag(hashtagID, postID string, isBoost bool) error
@@ -100,7 +200,11 @@ type DebateRepository interface {
 	GetByID(id string) (*models.Debate, error)
 	Update(debate *models.Debate) error
 	Delete(id string) error
-	List(status string, limit, offset int) ([]*models.Debate, error)
+	List(status string, page Page) ([]*models.Debate, string, error)
+	// ListPending returns the debates the scheduler still has work for, earliest
+	// start first: those scheduled or active, and those ended whose post poll
+	// is still open
+	ListPending() ([]*models.Debate, error)
 	ClearAll() error // Clear all debates, participants, and speak requests
 
 	AddParticipant(participant *models.DebateParticipant) error
@@ -111,14 +215,19 @@ type DebateRepository interface {
 
 	CreateSpeakRequest(request *models.SpeakRequest) error
 	UpdateSpeakRequest(request *models.SpeakRequest) error
+	GetSpeakRequest(id string) (*models.SpeakRequest, error)
 	GetSpeakRequests(debateID string) ([]*models.SpeakRequest, error)
 	DeleteSpeakRequest(id string) error
+
+	// CastVote records a vote in a debate's poll, or returns ErrAlreadyVoted
+	CastVote(vote *models.DebateVote) error
+	GetVotes(debateID string) ([]*models.DebateVote, error)
 }
 
 // NotificationRepository defines the interface for notification data access
 type NotificationRepository interface {
 	Create(notification *models.Notification) error
-	GetByUserID(userID string, limit, offset int) ([]*models.Notification, error)
+	GetByUserID(userID string, page Page) ([]*models.Notification, string, error)
 	GetByID(id string) (*models.Notification, error)
 	MarkAsRead(id string) error
 	MarkAllAsRead(userID string) error
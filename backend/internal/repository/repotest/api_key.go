@@ -0,0 +1,87 @@
+package repotest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
+)
+
+func testAPIKeys(t *testing.T, repos *repository.Repositories) {
+	expires := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	for _, key := range []*models.APIKey{
+		{ID: "k1", UserID: "alice", Name: "digest", KeyHash: "h1", Scopes: []models.APIScope{models.ScopeReadPosts, models.ScopeWritePosts}},
+		{ID: "k2", UserID: "alice", Name: "results", KeyHash: "h2", Scopes: []models.APIScope{models.ScopeWriteDebates}, ExpiresAt: &expires},
+		{ID: "k3", UserID: "bob", Name: "other", KeyHash: "h3"},
+	} {
+		if err := repos.APIKey.Create(key); err != nil {
+			t.Fatalf("create %s: %v", key.ID, err)
+		}
+		time.Sleep(5 * time.Millisecond) // Distinct creation times to order by
+	}
+
+	key, err := repos.APIKey.GetByID("k2")
+	if err != nil || key.KeyHash != "h2" || len(key.Scopes) != 1 || key.Scopes[0] != models.ScopeWriteDebates ||
+		key.ExpiresAt == nil || !key.ExpiresAt.Equal(expires) || key.LastUsedAt != nil {
+		t.Fatalf("get = %+v, %v", key, err)
+	}
+
+	used := time.Now().Truncate(time.Second)
+	if err := repos.APIKey.Touch("k1", used); err != nil {
+		t.Fatalf("touch: %v", err)
+	}
+	if key, _ := repos.APIKey.GetByID("k1"); key.LastUsedAt == nil || !key.LastUsedAt.Equal(used) {
+		t.Errorf("last used = %v, want %v", key.LastUsedAt, used)
+	}
+
+	keys, err := repos.APIKey.ListByUser("alice")
+	if err != nil || len(keys) != 2 || keys[0].ID != "k2" || keys[1].ID != "k1" {
+		t.Fatalf("list = %d keys, %v", len(keys), err)
+	}
+
+	if err := repos.APIKey.Revoke("bob", "k1"); err == nil {
+		t.Error("revoked someone else's key")
+	}
+	if err := repos.APIKey.Revoke("alice", "k1"); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+	if err := repos.APIKey.Revoke("alice", "k1"); err == nil {
+		t.Error("revoked a key twice")
+	}
+	if key, _ := repos.APIKey.GetByID("k1"); key.RevokedAt == nil {
+		t.Error("revoked key has no revocation time")
+	}
+	if keys, _ := repos.APIKey.ListByUser("alice"); len(keys) != 1 || keys[0].ID != "k2" {
+		t.Errorf("list after revoke = %d keys", len(keys))
+	}
+}
+
+func testBots(t *testing.T, repos *repository.Repositories) {
+	createUsers(t, repos.User, "alice", "bob")
+	for _, bot := range []*models.User{
+		{ID: "digest-bot", Handle: "digest_bot", IsBot: true, BotOwnerID: "alice"},
+		{ID: "results-bot", Handle: "results_bot", IsBot: true, BotOwnerID: "alice"},
+		{ID: "bob-bot", Handle: "bob_bot", IsBot: true, BotOwnerID: "bob"},
+	} {
+		if err := repos.User.Create(bot); err != nil {
+			t.Fatalf("create %s: %v", bot.ID, err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	bots, err := repos.User.ListBots("alice")
+	if err != nil || len(bots) != 2 || bots[0].ID != "digest-bot" || bots[1].ID != "results-bot" {
+		t.Fatalf("list bots = %d, %v", len(bots), err)
+	}
+	if !bots[0].IsBot || bots[0].BotOwnerID != "alice" {
+		t.Errorf("bot = %+v", bots[0])
+	}
+
+	if err := repos.Post.Create(&models.Post{ID: "digest", AuthorID: "digest-bot", AuthorIsBot: true}); err != nil {
+		t.Fatalf("create bot post: %v", err)
+	}
+	if post, err := repos.Post.GetByID("digest"); err != nil || !post.AuthorIsBot {
+		t.Errorf("bot post = %+v, %v", post, err)
+	}
+}
@@ -0,0 +1,149 @@
+package repotest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
+)
+
+func testCommunityMembership(t *testing.T, repos *repository.Repositories) {
+	now := time.Now()
+	for _, id := range []string{"c1", "c2"} {
+		c := &models.Community{ID: id, Name: id, Category: models.CategoryTechnology, CreatedAt: now, UpdatedAt: now}
+		if err := repos.Community.Create(c); err != nil {
+			t.Fatalf("create %s: %v", id, err)
+		}
+	}
+	if err := repos.Community.Create(&models.Community{ID: "c1", CreatedAt: now, UpdatedAt: now}); err == nil {
+		t.Fatal("expected duplicate create to fail")
+	}
+
+	members := []*models.CommunityMember{
+		{CommunityID: "c1", UserID: "alice", Role: models.RoleMember, Status: "active", JoinedAt: now},
+		{CommunityID: "c2", UserID: "alice", Role: models.RoleMember, Status: "pending", JoinedAt: now},
+		{CommunityID: "c1", UserID: "bob", Role: models.RoleAdmin, Status: "active", JoinedAt: now},
+	}
+	for _, m := range members {
+		if err := repos.Community.AddMember(m); err != nil {
+			t.Fatalf("add %s to %s: %v", m.UserID, m.CommunityID, err)
+		}
+	}
+
+	// Only active memberships count as joined
+	joined, err := repos.Community.GetJoinedCommunities("alice")
+	if err != nil || len(joined) != 1 || joined[0].ID != "c1" {
+		t.Fatalf("joined = %v, %v", joined, err)
+	}
+
+	// Adding an existing member again replaces the membership
+	if err := repos.Community.AddMember(&models.CommunityMember{CommunityID: "c1", UserID: "alice", Role: models.RoleAdmin, Status: "active", JoinedAt: now}); err != nil {
+		t.Fatalf("re-add: %v", err)
+	}
+	if list, _ := repos.Community.GetMembers("c1"); len(list) != 2 {
+		t.Errorf("members = %d, want 2", len(list))
+	}
+	if m, err := repos.Community.GetMember("c1", "alice"); err != nil || m.Role != models.RoleAdmin {
+		t.Errorf("member = %+v, %v; want admin", m, err)
+	}
+
+	member, _ := repos.Community.GetMember("c2", "alice")
+	member.Status = "active"
+	member.PointsAwarded = true
+	if err := repos.Community.UpdateMember(member); err != nil {
+		t.Fatalf("update member: %v", err)
+	}
+	if m, _ := repos.Community.GetMember("c2", "alice"); m.Status != "active" || !m.PointsAwarded {
+		t.Errorf("member after update = %+v", m)
+	}
+	if err := repos.Community.UpdateMember(&models.CommunityMember{CommunityID: "c2", UserID: "nobody"}); err == nil {
+		t.Error("expected updating a missing member to fail")
+	}
+
+	// Join history outlives the membership
+	if err := repos.Community.RemoveMember("c1", "alice"); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if err := repos.Community.RemoveMember("c1", "alice"); err == nil {
+		t.Error("expected removing a missing member to fail")
+	}
+	if _, err := repos.Community.GetMember("c1", "alice"); err == nil {
+		t.Error("member survived removal")
+	}
+
+	history := []struct {
+		community, user string
+		want            bool
+	}{
+		{"c1", "alice", true},
+		{"c1", "bob", true},
+		{"c1", "carol", false},
+		{"missing", "alice", false},
+	}
+	for _, h := range history {
+		got, err := repos.Community.HasJoinedBefore(h.community, h.user)
+		if err != nil || got != h.want {
+			t.Errorf("HasJoinedBefore(%s, %s) = %v, %v; want %v", h.community, h.user, got, err, h.want)
+		}
+	}
+
+	if err := repos.Community.Delete("c1"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := repos.Community.GetByID("c1"); err == nil {
+		t.Error("community survived delete")
+	}
+	if list, _ := repos.Community.List(); len(list) != 1 || list[0].ID != "c2" {
+		t.Errorf("communities = %v, want [c2]", list)
+	}
+}
+
+func testCommunitySoftDelete(t *testing.T, repos *repository.Repositories) {
+	now := time.Now()
+	if err := repos.Community.Create(&models.Community{ID: "c1", Name: "c1", CreatedAt: now, UpdatedAt: now}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	member := &models.CommunityMember{CommunityID: "c1", UserID: "alice", Role: models.RoleMember, Status: "active", JoinedAt: now}
+	if err := repos.Community.AddMember(member); err != nil {
+		t.Fatalf("add member: %v", err)
+	}
+
+	if err := repos.Community.Delete("c1"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := repos.Community.GetByID("c1"); err == nil {
+		t.Error("deleted community still readable")
+	}
+	if list, _ := repos.Community.List(); len(list) != 0 {
+		t.Errorf("list has %d communities, want 0", len(list))
+	}
+	if joined, _ := repos.Community.GetJoinedCommunities("alice"); len(joined) != 0 {
+		t.Errorf("alice still in %d communities", len(joined))
+	}
+
+	// Memberships come back with the community
+	if err := repos.Community.Restore("c1", time.Now().Add(time.Hour)); !errors.Is(err, repository.ErrRestoreWindowExpired) {
+		t.Errorf("restore after window = %v, want %v", err, repository.ErrRestoreWindowExpired)
+	}
+	if err := repos.Community.Restore("c1", time.Now().Add(-time.Hour)); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if joined, _ := repos.Community.GetJoinedCommunities("alice"); len(joined) != 1 {
+		t.Errorf("alice in %d communities after restore, want 1", len(joined))
+	}
+
+	if err := repos.Community.Delete("c1"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if n, err := repos.Community.Purge(time.Now().Add(time.Second)); err != nil || n != 1 {
+		t.Errorf("purge = %d, %v; want 1", n, err)
+	}
+	if _, err := repos.Community.GetDeletedByID("c1"); err == nil {
+		t.Error("community survived purge")
+	}
+	if _, err := repos.Community.GetMember("c1", "alice"); err == nil {
+		t.Error("membership survived purge")
+	}
+}
@@ -0,0 +1,401 @@
+package repotest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
+)
+
+func createDebate(t *testing.T, repo repository.DebateRepository, id string) {
+	t.Helper()
+	debate := &models.Debate{ID: id, Title: id, HostID: "host", Status: "ACTIVE", StartTime: time.Now()}
+	if err := repo.Create(debate); err != nil {
+		t.Fatalf("create debate %s: %v", id, err)
+	}
+}
+
+func assertSideCounts(t *testing.T, repo repository.DebateRepository, step, debateID string, agree, disagree int) {
+	t.Helper()
+	debate, err := repo.GetByID(debateID)
+	if err != nil {
+		t.Fatalf("%s: %v", step, err)
+	}
+	if debate.AgreeCount != agree || debate.DisagreeCount != disagree {
+		t.Errorf("%s: agree/disagree = %d/%d, want %d/%d", step, debate.AgreeCount, debate.DisagreeCount, agree, disagree)
+	}
+}
+
+func testDebateParticipants(t *testing.T, repos *repository.Repositories) {
+	createDebate(t, repos.Debate, "d1")
+	if err := repos.Debate.AddParticipant(&models.DebateParticipant{DebateID: "missing", UserID: "alice"}); err == nil {
+		t.Error("expected joining a missing debate to fail")
+	}
+
+	for _, p := range []*models.DebateParticipant{
+		{DebateID: "d1", UserID: "alice", Side: "agree"},
+		{DebateID: "d1", UserID: "bob", Side: " Disagree "},
+		{DebateID: "d1", UserID: "carol", Side: "spectator"},
+	} {
+		if err := repos.Debate.AddParticipant(p); err != nil {
+			t.Fatalf("join %s: %v", p.UserID, err)
+		}
+	}
+	assertSideCounts(t, repos.Debate, "after joining", "d1", 1, 1)
+
+	// Leaving keeps the participant in the full list
+	participants, err := repos.Debate.GetAllParticipants("d1")
+	if err != nil {
+		t.Fatalf("all participants: %v", err)
+	}
+	var alice *models.DebateParticipant
+	for _, p := range participants {
+		if p.UserID == "alice" {
+			alice = p
+		}
+	}
+	if alice == nil || alice.ID == "" || alice.JoinedAt.IsZero() {
+		t.Fatalf("alice = %+v, want an ID and join time", alice)
+	}
+	left := time.Now()
+	alice.LeftAt = &left
+	if err := repos.Debate.UpdateParticipant(alice); err != nil {
+		t.Fatalf("leave: %v", err)
+	}
+
+	lists := []struct {
+		name string
+		list func(string) ([]*models.DebateParticipant, error)
+		want int
+	}{
+		{"active", repos.Debate.GetParticipants, 2},
+		{"all including left", repos.Debate.GetAllParticipants, 3},
+	}
+	for _, l := range lists {
+		got, err := l.list("d1")
+		if err != nil || len(got) != l.want {
+			t.Errorf("%s participants = %d, %v; want %d", l.name, len(got), err, l.want)
+		}
+	}
+	if _, err := repos.Debate.GetAllParticipants("missing"); err == nil {
+		t.Error("expected participants of a missing debate to fail")
+	}
+
+	// Rejoining reuses the same participant record on the new side
+	if err := repos.Debate.AddParticipant(&models.DebateParticipant{DebateID: "d1", UserID: "alice", Side: "disagree"}); err != nil {
+		t.Fatalf("rejoin: %v", err)
+	}
+	all, _ := repos.Debate.GetAllParticipants("d1")
+	active, _ := repos.Debate.GetParticipants("d1")
+	if len(all) != 3 || len(active) != 3 {
+		t.Errorf("after rejoin all/active = %d/%d, want 3/3", len(all), len(active))
+	}
+	assertSideCounts(t, repos.Debate, "after rejoin", "d1", 1, 2)
+
+	if err := repos.Debate.RemoveParticipant("d1", "bob"); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if err := repos.Debate.RemoveParticipant("d1", "bob"); err == nil {
+		t.Error("expected removing a missing participant to fail")
+	}
+	assertSideCounts(t, repos.Debate, "after remove", "d1", 1, 1)
+}
+
+func testDebateSideSwitch(t *testing.T, repos *repository.Repositories) {
+	createDebate(t, repos.Debate, "d1")
+
+	steps := []struct {
+		side            string
+		agree, disagree int
+	}{
+		{"agree", 1, 0},
+		{"agree", 1, 0}, // same side is a no-op
+		{"disagree", 0, 1},
+		{"agree", 1, 0},
+	}
+	for _, s := range steps {
+		if err := repos.Debate.AddParticipant(&models.DebateParticipant{DebateID: "d1", UserID: "alice", Side: s.side}); err != nil {
+			t.Fatalf("join %s: %v", s.side, err)
+		}
+		assertSideCounts(t, repos.Debate, "switch to "+s.side, "d1", s.agree, s.disagree)
+	}
+
+	all, _ := repos.Debate.GetAllParticipants("d1")
+	if len(all) != 1 || all[0].Side != "agree" {
+		t.Errorf("participants = %+v, want alice on agree", all)
+	}
+}
+
+func testPendingDebates(t *testing.T, repos *repository.Repositories) {
+	start := time.Now().Truncate(time.Second)
+	for _, d := range []*models.Debate{
+		{ID: "later", Status: "SCHEDULED", StartTime: start.Add(time.Hour)},
+		{ID: "ended", Status: "ENDED", StartTime: start.Add(-2 * time.Hour)},
+		{ID: "live", Status: "ACTIVE", StartTime: start.Add(-time.Hour)},
+	} {
+		if err := repos.Debate.Create(d); err != nil {
+			t.Fatalf("create %s: %v", d.ID, err)
+		}
+	}
+
+	pending, err := repos.Debate.ListPending()
+	if err != nil {
+		t.Fatalf("list pending: %v", err)
+	}
+	var ids []string
+	for _, d := range pending {
+		ids = append(ids, d.ID)
+	}
+	if len(ids) != 2 || ids[0] != "live" || ids[1] != "later" {
+		t.Errorf("pending = %v, want [live later]", ids)
+	}
+}
+
+func testDebateRounds(t *testing.T, repos *repository.Repositories) {
+	debate := &models.Debate{ID: "d1", Status: "ACTIVE", StartTime: time.Now(), Format: models.FormatOxford}
+	if err := repos.Debate.Create(debate); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	started := time.Now().Truncate(time.Second)
+	debate.CurrentRound = 2
+	debate.RoundStartedAt = &started
+	if err := repos.Debate.Update(debate); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	got, err := repos.Debate.GetByID("d1")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.Format != models.FormatOxford || got.CurrentRound != 2 || got.RoundStartedAt == nil || !got.RoundStartedAt.Equal(started) {
+		t.Errorf("round = %s/%d/%v, want oxford/2/%v", got.Format, got.CurrentRound, got.RoundStartedAt, started)
+	}
+}
+
+func testDebateUnlock(t *testing.T, repos *repository.Repositories) {
+	unlockAt := time.Now().Add(time.Hour).Truncate(time.Second)
+	debate := &models.Debate{
+		ID: "d1", Status: "SCHEDULED", StartTime: time.Now(), IsLocked: true, UnlockPhase: models.UnlockPhaseEarlyAccess,
+		EarlyAccessRoles: []string{models.EarlyAccessFollowers, "community:c1"}, UnlockAt: &unlockAt,
+	}
+	if err := repos.Debate.Create(debate); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	got, err := repos.Debate.GetByID("d1")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if !got.IsLocked || got.UnlockPhase != models.UnlockPhaseEarlyAccess || len(got.EarlyAccessRoles) != 2 ||
+		got.EarlyAccessRoles[1] != "community:c1" || got.UnlockAt == nil || !got.UnlockAt.Equal(unlockAt) {
+		t.Errorf("access = %v/%d/%v/%v, want locked early access until %v", got.IsLocked, got.UnlockPhase, got.EarlyAccessRoles, got.UnlockAt, unlockAt)
+	}
+
+	got.IsLocked = false
+	got.UnlockPhase = models.UnlockPhasePublic
+	if err := repos.Debate.Update(got); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if got, _ := repos.Debate.GetByID("d1"); got.IsLocked || got.UnlockPhase != models.UnlockPhasePublic {
+		t.Errorf("access = %v/%d, want unlocked public", got.IsLocked, got.UnlockPhase)
+	}
+}
+
+func testSpeakRequests(t *testing.T, repos *repository.Repositories) {
+	createDebate(t, repos.Debate, "d1")
+	createDebate(t, repos.Debate, "d2")
+
+	for _, r := range []*models.SpeakRequest{
+		{ID: "r1", DebateID: "d1", UserID: "alice", Status: "pending"},
+		{ID: "r2", DebateID: "d1", UserID: "bob", Status: "pending"},
+		{ID: "r3", DebateID: "d2", UserID: "alice", Status: "pending"},
+	} {
+		if err := repos.Debate.CreateSpeakRequest(r); err != nil {
+			t.Fatalf("create %s: %v", r.ID, err)
+		}
+	}
+	if err := repos.Debate.CreateSpeakRequest(&models.SpeakRequest{ID: "r9", DebateID: "missing", UserID: "alice"}); err == nil {
+		t.Error("expected a request for a missing debate to fail")
+	}
+
+	if err := repos.Debate.UpdateSpeakRequest(&models.SpeakRequest{ID: "r1", DebateID: "d1", UserID: "alice", Status: "approved"}); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if err := repos.Debate.UpdateSpeakRequest(&models.SpeakRequest{ID: "missing", Status: "approved"}); err == nil {
+		t.Error("expected updating a missing request to fail")
+	}
+	if err := repos.Debate.DeleteSpeakRequest("r2"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if req, err := repos.Debate.GetSpeakRequest("r3"); err != nil || req.DebateID != "d2" || req.UserID != "alice" {
+		t.Errorf("r3 = %+v, %v", req, err)
+	}
+	if _, err := repos.Debate.GetSpeakRequest("r2"); err == nil {
+		t.Error("expected a deleted request to be gone")
+	}
+
+	requests, err := repos.Debate.GetSpeakRequests("d1")
+	if err != nil || len(requests) != 1 || requests[0].ID != "r1" || requests[0].Status != "approved" {
+		t.Fatalf("requests = %v, %v", requests, err)
+	}
+
+	// Deleting a debate takes its participants and requests with it
+	if err := repos.Debate.AddParticipant(&models.DebateParticipant{DebateID: "d2", UserID: "alice", Side: "agree"}); err != nil {
+		t.Fatalf("join: %v", err)
+	}
+	if err := repos.Debate.Delete("d2"); err != nil {
+		t.Fatalf("delete debate: %v", err)
+	}
+	if _, err := repos.Debate.GetByID("d2"); err == nil {
+		t.Error("debate survived delete")
+	}
+	createDebate(t, repos.Debate, "d2")
+	if requests, _ := repos.Debate.GetSpeakRequests("d2"); len(requests) != 0 {
+		t.Errorf("%d speak requests survived debate delete", len(requests))
+	}
+	if all, _ := repos.Debate.GetAllParticipants("d2"); len(all) != 0 {
+		t.Errorf("%d participants survived debate delete", len(all))
+	}
+
+	if err := repos.Debate.ClearAll(); err != nil {
+		t.Fatalf("clear: %v", err)
+	}
+	if debates, _, _ := repos.Debate.List("", firstPage); len(debates) != 0 {
+		t.Errorf("%d debates survived clear", len(debates))
+	}
+}
+
+func testSpeakerQueue(t *testing.T, repos *repository.Repositories) {
+	debate := &models.Debate{ID: "d1", Status: "ACTIVE", StartTime: time.Now(), QueueOrder: models.QueueOrderAlternating, MaxTurnSeconds: 90}
+	if err := repos.Debate.Create(debate); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if got, err := repos.Debate.GetByID("d1"); err != nil || got.QueueOrder != models.QueueOrderAlternating || got.MaxTurnSeconds != 90 {
+		t.Fatalf("debate = %+v, %v", got, err)
+	}
+
+	request := &models.SpeakRequest{ID: "r1", DebateID: "d1", UserID: "alice", Side: "agree", Status: "pending"}
+	if err := repos.Debate.CreateSpeakRequest(request); err != nil {
+		t.Fatalf("create request: %v", err)
+	}
+
+	started := time.Now().Truncate(time.Second)
+	ends := started.Add(90 * time.Second)
+	request.Status = "approved"
+	request.Position = 3
+	request.TurnStartedAt = &started
+	request.TurnEndsAt = &ends
+	request.Side = "disagree" // Ignored: the side is the one they asked from
+	if err := repos.Debate.UpdateSpeakRequest(request); err != nil {
+		t.Fatalf("update request: %v", err)
+	}
+
+	got, err := repos.Debate.GetSpeakRequest("r1")
+	if err != nil {
+		t.Fatalf("get request: %v", err)
+	}
+	if got.Side != "agree" || got.Status != "approved" || got.Position != 3 ||
+		got.TurnStartedAt == nil || !got.TurnStartedAt.Equal(started) || got.TurnEndsAt == nil || !got.TurnEndsAt.Equal(ends) {
+		t.Errorf("request = %+v", got)
+	}
+}
+
+func testDebatePolls(t *testing.T, repos *repository.Repositories) {
+	closes := time.Now().Add(time.Hour).Truncate(time.Second)
+	debate := &models.Debate{ID: "d1", Status: "ENDED", StartTime: time.Now(), PollClosesAt: &closes}
+	if err := repos.Debate.Create(debate); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	for _, v := range []*models.DebateVote{
+		{DebateID: "d1", UserID: "alice", Phase: models.PollPre, Side: "agree"},
+		{DebateID: "d1", UserID: "alice", Phase: models.PollPost, Side: "disagree"},
+		{DebateID: "d1", UserID: "bob", Phase: models.PollPre, Side: "undecided"},
+	} {
+		if err := repos.Debate.CastVote(v); err != nil {
+			t.Fatalf("vote %s/%s: %v", v.UserID, v.Phase, err)
+		}
+	}
+	if err := repos.Debate.CastVote(&models.DebateVote{DebateID: "d1", UserID: "alice", Phase: models.PollPre, Side: "disagree"}); !errors.Is(err, repository.ErrAlreadyVoted) {
+		t.Errorf("second vote = %v, want ErrAlreadyVoted", err)
+	}
+	if err := repos.Debate.CastVote(&models.DebateVote{DebateID: "missing", UserID: "alice", Phase: models.PollPre, Side: "agree"}); err == nil {
+		t.Error("expected a vote in a missing debate to fail")
+	}
+	votes, err := repos.Debate.GetVotes("d1")
+	if err != nil || len(votes) != 3 {
+		t.Fatalf("votes = %v, %v", votes, err)
+	}
+
+	// An ended debate stays pending until its post poll decides the result
+	if pending, _ := repos.Debate.ListPending(); len(pending) != 1 || pending[0].ID != "d1" {
+		t.Errorf("pending = %v, want [d1]", pending)
+	}
+	debate.Result = &models.DebateResult{
+		WinningSide: "disagree",
+		Pre:         models.VoteTally{Agree: 1, Undecided: 1},
+		Post:        models.VoteTally{Disagree: 1},
+		AgreeSwing:  -50,
+		Winners:     []string{"bob"},
+		DecidedAt:   closes,
+	}
+	if err := repos.Debate.Update(debate); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if pending, _ := repos.Debate.ListPending(); len(pending) != 0 {
+		t.Errorf("%d debate(s) still pending after the result", len(pending))
+	}
+
+	got, err := repos.Debate.GetByID("d1")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.PollClosesAt == nil || !got.PollClosesAt.Equal(closes) || got.Result == nil ||
+		got.Result.WinningSide != "disagree" || got.Result.Pre.Undecided != 1 || len(got.Result.Winners) != 1 {
+		t.Errorf("debate = %+v, result %+v", got, got.Result)
+	}
+
+	if err := repos.Debate.Delete("d1"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if votes, _ := repos.Debate.GetVotes("d1"); len(votes) != 0 {
+		t.Errorf("%d votes survived debate delete", len(votes))
+	}
+}
+
+func testDebateStats(t *testing.T, repos *repository.Repositories) {
+	// The same debate is only counted once; topics match case-insensitively
+	records := []struct {
+		topic                         string
+		agree, disagree, participants int
+		debateID                      string
+	}{
+		{" Climate ", 3, 2, 5, "d1"},
+		{"climate", 3, 2, 5, "d1"},
+		{"CLIMATE", 1, 1, 2, "d2"},
+		{"Tax", 1, 0, 1, "d3"},
+	}
+	for _, r := range records {
+		if _, err := repos.DebateStats.RecordStats(r.topic, r.agree, r.disagree, r.participants, r.debateID); err != nil {
+			t.Fatalf("record %s/%s: %v", r.topic, r.debateID, err)
+		}
+	}
+
+	stats, err := repos.DebateStats.GetByTopic("Climate")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if stats.Topic != "Climate" || stats.SessionsCount != 2 || stats.TotalAgree != 4 ||
+		stats.TotalDisagree != 3 || stats.TotalParticipants != 7 {
+		t.Errorf("climate stats = %+v", stats)
+	}
+	if _, err := repos.DebateStats.GetByTopic("unknown"); err == nil {
+		t.Error("expected missing topic lookup to fail")
+	}
+	if all, _ := repos.DebateStats.GetAllStats(); len(all) != 2 {
+		t.Errorf("topics = %d, want 2", len(all))
+	}
+}
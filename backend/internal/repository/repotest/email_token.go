@@ -0,0 +1,61 @@
+package repotest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
+)
+
+func testEmailTokens(t *testing.T, repos *repository.Repositories) {
+	expiresAt := time.Now().Add(time.Hour)
+	for _, token := range []*models.EmailToken{
+		{ID: "t1", UserID: "alice", Purpose: models.EmailTokenVerify, Email: "alice@example.com", TokenHash: "h1", ExpiresAt: expiresAt},
+		{ID: "t2", UserID: "alice", Purpose: models.EmailTokenReset, TokenHash: "h2", ExpiresAt: expiresAt},
+		{ID: "t3", UserID: "alice", Purpose: models.EmailTokenReset, TokenHash: "h3", ExpiresAt: expiresAt},
+		{ID: "t4", UserID: "alice", Purpose: models.EmailTokenReset, TokenHash: "h4", ExpiresAt: time.Now().Add(-time.Minute)},
+		{ID: "t5", UserID: "bob", Purpose: models.EmailTokenReset, TokenHash: "h5", ExpiresAt: expiresAt},
+	} {
+		if err := repos.EmailToken.Create(token); err != nil {
+			t.Fatalf("create %s: %v", token.ID, err)
+		}
+	}
+
+	invalid := []struct {
+		name    string
+		id      string
+		purpose models.EmailTokenPurpose
+		hash    string
+	}{
+		{"wrong purpose", "t1", models.EmailTokenReset, "h1"},
+		{"wrong hash", "t1", models.EmailTokenVerify, "h2"},
+		{"expired", "t4", models.EmailTokenReset, "h4"},
+		{"missing", "missing", models.EmailTokenVerify, "h"},
+	}
+	for _, c := range invalid {
+		if _, err := repos.EmailToken.Consume(c.id, c.purpose, c.hash); !errors.Is(err, repository.ErrInvalidEmailToken) {
+			t.Errorf("%s: consume = %v, want invalid", c.name, err)
+		}
+	}
+
+	// Tokens work exactly once
+	token, err := repos.EmailToken.Consume("t1", models.EmailTokenVerify, "h1")
+	if err != nil || token.UserID != "alice" || token.Email != "alice@example.com" || token.UsedAt == nil {
+		t.Fatalf("consume = %+v, %v", token, err)
+	}
+	if _, err := repos.EmailToken.Consume("t1", models.EmailTokenVerify, "h1"); !errors.Is(err, repository.ErrInvalidEmailToken) {
+		t.Errorf("second consume = %v, want invalid", err)
+	}
+
+	if err := repos.EmailToken.InvalidateForUser("alice", models.EmailTokenReset); err != nil {
+		t.Fatalf("invalidate: %v", err)
+	}
+	if _, err := repos.EmailToken.Consume("t3", models.EmailTokenReset, "h3"); !errors.Is(err, repository.ErrInvalidEmailToken) {
+		t.Errorf("consume after invalidate = %v, want invalid", err)
+	}
+	if _, err := repos.EmailToken.Consume("t5", models.EmailTokenReset, "h5"); err != nil {
+		t.Errorf("bob's token was invalidated with alice's: %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package repotest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
+)
+
+func testIdentities(t *testing.T, repos *repository.Repositories) {
+	for _, identity := range []*models.Identity{
+		{Provider: "google", Subject: "g-alice", UserID: "alice", Email: "alice@example.com"},
+		{Provider: "github", Subject: "gh-alice", UserID: "alice"},
+		{Provider: "google", Subject: "g-bob", UserID: "bob"},
+	} {
+		if err := repos.Identity.Create(identity); err != nil {
+			t.Fatalf("create %s/%s: %v", identity.Provider, identity.Subject, err)
+		}
+	}
+
+	// A provider's user links to one account, and an account to one user per provider
+	for _, identity := range []*models.Identity{
+		{Provider: "google", Subject: "g-alice", UserID: "bob"},
+		{Provider: "google", Subject: "g-alice-2", UserID: "alice"},
+	} {
+		if err := repos.Identity.Create(identity); !errors.Is(err, repository.ErrIdentityLinked) {
+			t.Errorf("create %s for %s = %v, want linked", identity.Subject, identity.UserID, err)
+		}
+	}
+
+	identity, err := repos.Identity.Get("google", "g-alice")
+	if err != nil || identity.UserID != "alice" || identity.Email != "alice@example.com" || identity.CreatedAt.IsZero() {
+		t.Fatalf("get = %+v, %v", identity, err)
+	}
+	if _, err := repos.Identity.Get("github", "g-alice"); err == nil {
+		t.Error("subject matched across providers")
+	}
+
+	identities, err := repos.Identity.ListByUser("alice")
+	if err != nil || len(identities) != 2 || identities[0].Provider != "github" || identities[1].Provider != "google" {
+		t.Fatalf("list = %+v, %v", identities, err)
+	}
+
+	if err := repos.Identity.Delete("alice", "google"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := repos.Identity.Delete("alice", "google"); err == nil {
+		t.Error("second delete succeeded")
+	}
+	if _, err := repos.Identity.Get("google", "g-alice"); err == nil {
+		t.Error("get after delete succeeded")
+	}
+	if _, err := repos.Identity.Get("google", "g-bob"); err != nil {
+		t.Errorf("bob's identity went with alice's: %v", err)
+	}
+}
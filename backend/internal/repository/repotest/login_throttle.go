@@ -0,0 +1,61 @@
+package repotest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yourusername/v-backend/internal/repository"
+)
+
+func testLoginThrottles(t *testing.T, repos *repository.Repositories) {
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	if _, err := repos.LoginThrottle.Get("account:alice@example.com"); err == nil {
+		t.Fatal("get before any failure succeeded")
+	}
+	for i := 1; i <= 3; i++ {
+		throttle, err := repos.LoginThrottle.RecordFailure("account:alice@example.com", base.Add(time.Duration(i)*time.Minute), base)
+		if err != nil {
+			t.Fatalf("record failure %d: %v", i, err)
+		}
+		if throttle.Failures != i || throttle.BlockedUntil != nil {
+			t.Errorf("after failure %d got %+v", i, throttle)
+		}
+	}
+
+	until := base.Add(10 * time.Minute)
+	if err := repos.LoginThrottle.Block("account:alice@example.com", until); err != nil {
+		t.Fatalf("block: %v", err)
+	}
+	throttle, err := repos.LoginThrottle.Get("account:alice@example.com")
+	if err != nil || throttle.Failures != 3 || throttle.BlockedUntil == nil || !throttle.BlockedUntil.Equal(until) {
+		t.Fatalf("get = %+v, %v", throttle, err)
+	}
+	if err := repos.LoginThrottle.Block("ip:10.0.0.1", until); err == nil {
+		t.Error("block without a throttle succeeded")
+	}
+
+	// A failure after a quiet spell starts the count over and lifts the block
+	throttle, err = repos.LoginThrottle.RecordFailure("account:alice@example.com", base.Add(time.Hour), base.Add(30*time.Minute))
+	if err != nil || throttle.Failures != 1 || throttle.BlockedUntil != nil {
+		t.Errorf("failure after window = %+v, %v", throttle, err)
+	}
+
+	if _, err := repos.LoginThrottle.RecordFailure("ip:10.0.0.1", base, base); err != nil {
+		t.Fatalf("record ip failure: %v", err)
+	}
+	pruned, err := repos.LoginThrottle.Prune(base.Add(time.Minute))
+	if err != nil || pruned != 1 {
+		t.Errorf("prune = %d, %v, want 1", pruned, err)
+	}
+	if _, err := repos.LoginThrottle.Get("account:alice@example.com"); err != nil {
+		t.Errorf("recent throttle was pruned: %v", err)
+	}
+
+	if err := repos.LoginThrottle.Reset("account:alice@example.com"); err != nil {
+		t.Fatalf("reset: %v", err)
+	}
+	if _, err := repos.LoginThrottle.Get("account:alice@example.com"); err == nil {
+		t.Error("get after reset succeeded")
+	}
+}
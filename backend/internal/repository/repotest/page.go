@@ -0,0 +1,105 @@
+package repotest
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
+)
+
+// walk follows next cursors from the first page until the last one and returns
+// the IDs seen. afterFirst runs once the first page has been read.
+func walk[T any](t *testing.T, limit int, list func(repository.Page) ([]T, string, error), id func(T) string, afterFirst func()) []string {
+	t.Helper()
+
+	var ids []string
+	page := repository.Page{Limit: limit}
+	for i := 0; ; i++ {
+		items, next, err := list(page)
+		if err != nil {
+			t.Fatalf("page %d: %v", i, err)
+		}
+		if len(items) > limit {
+			t.Fatalf("page %d has %d items, limit %d", i, len(items), limit)
+		}
+		for _, item := range items {
+			ids = append(ids, id(item))
+		}
+		if i == 0 && afterFirst != nil {
+			afterFirst()
+		}
+		if next == "" {
+			return ids
+		}
+		if i > 10 {
+			t.Fatal("cursor never reached the last page")
+		}
+		page.Cursor = next
+	}
+}
+
+func testCursorPagination(t *testing.T, repos *repository.Repositories) {
+	createUsers(t, repos.User, "alice", "b1", "b2", "b3", "b4")
+
+	// Notifications n01-n03 share a timestamp, so their order comes from the ID
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	for i := 1; i <= 7; i++ {
+		at := base
+		if i > 3 {
+			at = base.Add(time.Duration(i) * time.Minute)
+		}
+		id := fmt.Sprintf("n%02d", i)
+		if err := repos.Notification.Create(&models.Notification{ID: id, UserID: "alice", Type: "follow", CreatedAt: at}); err != nil {
+			t.Fatalf("create %s: %v", id, err)
+		}
+	}
+	notifIDs := walk(t, 3,
+		func(page repository.Page) ([]*models.Notification, string, error) {
+			return repos.Notification.GetByUserID("alice", page)
+		},
+		func(n *models.Notification) string { return n.ID },
+		func() {
+			// A notification arriving mid-scroll must not shift later pages
+			n := &models.Notification{ID: "n08", UserID: "alice", Type: "follow", CreatedAt: base.Add(time.Hour)}
+			if err := repos.Notification.Create(n); err != nil {
+				t.Fatalf("create n08: %v", err)
+			}
+		},
+	)
+	if want := []string{"n07", "n06", "n05", "n04", "n03", "n02", "n01"}; !equalIDs(notifIDs, want) {
+		t.Errorf("notifications = %v, want %v", notifIDs, want)
+	}
+
+	for _, id := range []string{"p1", "p2", "p3", "p4", "p5"} {
+		createPost(t, repos.Post, id, "alice")
+	}
+	feed := walk(t, 2, repos.Post.List, func(p *models.Post) string { return p.ID }, func() {
+		createPost(t, repos.Post, "p6", "alice")
+	})
+	if want := []string{"p5", "p4", "p3", "p2", "p1"}; !equalIDs(feed, want) {
+		t.Errorf("feed = %v, want %v", feed, want)
+	}
+
+	for _, id := range []string{"b1", "b2", "b3", "b4"} {
+		if err := repos.User.Follow(id, "alice"); err != nil {
+			t.Fatalf("follow: %v", err)
+		}
+	}
+	followers := walk(t, 3,
+		func(page repository.Page) ([]*models.User, string, error) {
+			return repos.User.GetFollowers("alice", page)
+		},
+		func(u *models.User) string { return u.ID },
+		nil,
+	)
+	if want := []string{"b1", "b2", "b3", "b4"}; !equalIDs(followers, want) {
+		t.Errorf("followers = %v, want %v", followers, want)
+	}
+
+	if _, _, err := repos.Post.List(repository.Page{Limit: 2, Cursor: "not a cursor"}); !errors.Is(err, repository.ErrInvalidCursor) {
+		t.Errorf("invalid cursor error = %v, want %v", err, repository.ErrInvalidCursor)
+	}
+}
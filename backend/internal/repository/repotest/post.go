@@ -0,0 +1,426 @@
+package repotest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
+)
+
+func testPostFeeds(t *testing.T, repos *repository.Repositories) {
+	community := "c1"
+	posts := []*models.Post{
+		{ID: "p1", AuthorID: "alice", Content: "first", Translations: map[string]string{"te": "modati"}},
+		{ID: "p2", AuthorID: "alice", Content: "hidden", Status: models.PostStatusTempHidden},
+		{ID: "p3", AuthorID: "alice", Content: "community", CommunityID: &community},
+		{ID: "p4", AuthorID: "bob", Content: "removed", Status: models.PostStatusRemoved},
+		{ID: "p5", AuthorID: "bob", Content: "latest"},
+	}
+	for _, p := range posts {
+		if err := repos.Post.Create(p); err != nil {
+			t.Fatalf("create %s: %v", p.ID, err)
+		}
+	}
+	if err := repos.Post.Create(&models.Post{ID: "p1"}); err == nil {
+		t.Fatal("expected duplicate create to fail")
+	}
+
+	p1, err := repos.Post.GetByID("p1")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if p1.Status != models.PostStatusVisible {
+		t.Errorf("default status = %q, want %q", p1.Status, models.PostStatusVisible)
+	}
+	if p1.Translations["te"] != "modati" {
+		t.Errorf("translations = %v", p1.Translations)
+	}
+
+	// Feeds hide moderated posts and are newest first
+	feeds := []struct {
+		name string
+		list func() ([]*models.Post, string, error)
+		want []string
+	}{
+		{"main feed skips community posts", func() ([]*models.Post, string, error) { return repos.Post.List(firstPage) }, []string{"p5", "p1"}},
+		{"by author", func() ([]*models.Post, string, error) { return repos.Post.ListByAuthor("alice", firstPage) }, []string{"p3", "p1"}},
+		{"by community", func() ([]*models.Post, string, error) { return repos.Post.ListByCommunity("c1", firstPage) }, []string{"p3"}},
+	}
+	for _, f := range feeds {
+		got, next, err := f.list()
+		if err != nil {
+			t.Errorf("%s: %v", f.name, err)
+			continue
+		}
+		if next != "" {
+			t.Errorf("%s: next cursor %q on the last page", f.name, next)
+		}
+		if ids := postIDs(got); !equalIDs(ids, f.want) {
+			t.Errorf("%s = %v, want %v", f.name, ids, f.want)
+		}
+	}
+}
+
+func testPostCounters(t *testing.T, repos *repository.Repositories) {
+	createPost(t, repos.Post, "p1", "alice")
+
+	if err := repos.Post.CreateComment(&models.Comment{ID: "c1", PostID: "p1", AuthorID: "bob", Content: "hi"}); err != nil {
+		t.Fatalf("comment: %v", err)
+	}
+	if err := repos.Post.CreateComment(&models.Comment{ID: "c2", PostID: "missing", AuthorID: "bob"}); err == nil {
+		t.Error("expected comment on missing post to fail")
+	}
+
+	commentID := "c1"
+	reactions := []*models.Reaction{
+		{UserID: "bob", PostID: "p1"},
+		{UserID: "carol", PostID: "p1"},
+		{UserID: "bob", PostID: "p1", CommentID: &commentID},
+	}
+	for _, r := range reactions {
+		if err := repos.Post.AddReaction(r); err != nil {
+			t.Fatalf("react: %v", err)
+		}
+	}
+	if err := repos.Post.AddReaction(&models.Reaction{UserID: "bob", PostID: "p1"}); err == nil {
+		t.Error("expected duplicate reaction to fail")
+	}
+	if reacted, _ := repos.Post.HasReacted("bob", "p1", &commentID); !reacted {
+		t.Error("comment reaction not recorded")
+	}
+
+	for _, user := range []string{"bob", "carol"} {
+		if err := repos.Post.SavePost(user, "p1"); err != nil {
+			t.Fatalf("save: %v", err)
+		}
+	}
+	if err := repos.Post.SavePost("bob", "p1"); err == nil {
+		t.Error("expected duplicate save to fail")
+	}
+
+	assertCounts := func(step string, comments, reactions, saves int) {
+		t.Helper()
+		p, err := repos.Post.GetByID("p1")
+		if err != nil {
+			t.Fatalf("%s: %v", step, err)
+		}
+		if p.CommentCount != comments || p.ReactionCount != reactions || p.SaveCount != saves {
+			t.Errorf("%s: comments/reactions/saves = %d/%d/%d, want %d/%d/%d",
+				step, p.CommentCount, p.ReactionCount, p.SaveCount, comments, reactions, saves)
+		}
+	}
+	assertCounts("after adding", 1, 3, 2)
+
+	saved, _, err := repos.Post.GetSavedPosts("bob", firstPage)
+	if err != nil || !equalIDs(postIDs(saved), []string{"p1"}) {
+		t.Fatalf("saved = %v, %v", saved, err)
+	}
+
+	if err := repos.Post.RemoveReaction("carol", "p1", nil); err != nil {
+		t.Fatalf("unreact: %v", err)
+	}
+	if err := repos.Post.RemoveReaction("carol", "p1", nil); err == nil {
+		t.Error("expected removing a missing reaction to fail")
+	}
+	// Unsaving twice must not double count
+	for i := 0; i < 2; i++ {
+		if err := repos.Post.UnsavePost("bob", "p1"); err != nil {
+			t.Fatalf("unsave: %v", err)
+		}
+	}
+	if err := repos.Post.UnsavePost("dave", "p1"); err != nil {
+		t.Fatalf("unsave never-saved: %v", err)
+	}
+	if err := repos.Post.DeleteComment("c1"); err != nil {
+		t.Fatalf("delete comment: %v", err)
+	}
+	assertCounts("after removing", 0, 2, 1)
+}
+
+func testPostSoftDelete(t *testing.T, repos *repository.Repositories) {
+	createPost(t, repos.Post, "p1", "alice")
+	if err := repos.Post.CreateComment(&models.Comment{ID: "c1", PostID: "p1", AuthorID: "bob"}); err != nil {
+		t.Fatalf("comment: %v", err)
+	}
+	if err := repos.Post.AddReaction(&models.Reaction{UserID: "bob", PostID: "p1"}); err != nil {
+		t.Fatalf("react: %v", err)
+	}
+	if err := repos.Post.SavePost("bob", "p1"); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	// A deleted post disappears from every normal query
+	if err := repos.Post.Delete("p1"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := repos.Post.GetByID("p1"); err == nil {
+		t.Error("deleted post still readable")
+	}
+	if feed, _, _ := repos.Post.List(firstPage); len(feed) != 0 {
+		t.Errorf("feed = %v, want no posts", postIDs(feed))
+	}
+	if saved, _, _ := repos.Post.GetSavedPosts("bob", firstPage); len(saved) != 0 {
+		t.Errorf("saved = %v, want no posts", postIDs(saved))
+	}
+	if comments, _ := repos.Post.GetCommentsByPost("p1"); len(comments) != 0 {
+		t.Errorf("%d comments visible on deleted post", len(comments))
+	}
+	if deleted, err := repos.Post.GetDeletedByID("p1"); err != nil || deleted.DeletedAt == nil {
+		t.Fatalf("deleted post = %+v, %v", deleted, err)
+	}
+
+	// Restoring brings back everything attached to the post
+	if err := repos.Post.Restore("p1", time.Now().Add(time.Hour)); !errors.Is(err, repository.ErrRestoreWindowExpired) {
+		t.Errorf("restore after window = %v, want %v", err, repository.ErrRestoreWindowExpired)
+	}
+	if err := repos.Post.Restore("p1", time.Now().Add(-time.Hour)); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if err := repos.Post.Restore("p1", time.Now().Add(-time.Hour)); err == nil {
+		t.Error("restored a post that is not deleted")
+	}
+	if comments, _ := repos.Post.GetCommentsByPost("p1"); len(comments) != 1 {
+		t.Errorf("%d comments after restore, want 1", len(comments))
+	}
+
+	if err := repos.Post.DeleteComment("c1"); err != nil {
+		t.Fatalf("delete comment: %v", err)
+	}
+	if post, _ := repos.Post.GetByID("p1"); post.CommentCount != 0 {
+		t.Errorf("comments = %d after comment delete, want 0", post.CommentCount)
+	}
+	if comment, err := repos.Post.GetCommentByID("c1"); err != nil || comment.AuthorID != "bob" || comment.DeletedAt == nil {
+		t.Errorf("deleted comment = %+v, %v; want it with DeletedAt set", comment, err)
+	}
+	if err := repos.Post.RestoreComment("c1", time.Now().Add(-time.Hour)); err != nil {
+		t.Fatalf("restore comment: %v", err)
+	}
+	if post, _ := repos.Post.GetByID("p1"); post.CommentCount != 1 {
+		t.Errorf("comments = %d after comment restore, want 1", post.CommentCount)
+	}
+
+	// Purging removes the post for good, with its comments, reactions and saves
+	if err := repos.Post.Delete("p1"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if n, err := repos.Post.Purge(time.Now().Add(-time.Hour)); err != nil || n != 0 {
+		t.Errorf("purge before deletion = %d, %v; want 0", n, err)
+	}
+	if n, err := repos.Post.Purge(time.Now().Add(time.Second)); err != nil || n != 1 {
+		t.Errorf("purge = %d, %v; want 1", n, err)
+	}
+	if _, err := repos.Post.GetDeletedByID("p1"); err == nil {
+		t.Error("post survived purge")
+	}
+	if reacted, _ := repos.Post.HasReacted("bob", "p1", nil); reacted {
+		t.Error("reaction survived purge")
+	}
+	if saved, _ := repos.Post.IsSaved("bob", "p1"); saved {
+		t.Error("save survived purge")
+	}
+}
+
+func testHashtags(t *testing.T, repos *repository.Repositories) {
+	if err := repos.Hashtag.Create(&models.Hashtag{ID: "h1", Name: "Go", Slug: "go", Category: "Technology"}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := repos.Hashtag.Create(&models.Hashtag{ID: "h1", Name: "Go", Slug: "go"}); err == nil {
+		t.Fatal("expected duplicate create to fail")
+	}
+	if h, err := repos.Hashtag.GetBySlug("go"); err != nil || h.ID != "h1" {
+		t.Fatalf("get by slug = %v, %v", h, err)
+	}
+
+	createPost(t, repos.Post, "p1", "alice")
+	createPost(t, repos.Post, "p2", "alice")
+	links := []struct {
+		postID  string
+		isBoost bool
+	}{
+		{"p1", false},
+		{"p2", false},
+		{"p2", true}, // relinking as a boost upgrades the existing link
+		{"p1", false},
+	}
+	for _, l := range links {
+		if err := repos.Hashtag.AddPostToHashtag("h1", l.postID, l.isBoost); err != nil {
+			t.Fatalf("link %s: %v", l.postID, err)
+		}
+	}
+	if err := repos.Hashtag.AddPostToHashtag("missing", "p1", false); err == nil {
+		t.Error("expected linking to a missing hashtag to fail")
+	}
+
+	boosts, shouts, err := repos.Hashtag.GetHashtagStats("h1")
+	if err != nil || boosts != 1 || shouts != 1 {
+		t.Errorf("stats = %d boosts, %d shouts, %v; want 1, 1", boosts, shouts, err)
+	}
+	if posts, _ := repos.Hashtag.GetPostsByHashtag("h1"); len(posts) != 2 {
+		t.Errorf("posts by hashtag = %d, want 2", len(posts))
+	}
+
+	// Deleted posts stop counting
+	if err := repos.Post.Delete("p2"); err != nil {
+		t.Fatalf("delete post: %v", err)
+	}
+	if boosts, shouts, _ := repos.Hashtag.GetHashtagStats("h1"); boosts != 0 || shouts != 1 {
+		t.Errorf("stats after delete = %d boosts, %d shouts; want 0, 1", boosts, shouts)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := repos.Hashtag.FollowHashtag("bob", "h1"); err != nil {
+			t.Fatalf("follow: %v", err)
+		}
+	}
+	if h, _ := repos.Hashtag.GetByID("h1"); h.Followers != 1 {
+		t.Errorf("followers = %d, want 1", h.Followers)
+	}
+	if err := repos.Hashtag.UnfollowHashtag("bob", "h1"); err != nil {
+		t.Fatalf("unfollow: %v", err)
+	}
+	if following, _ := repos.Hashtag.IsFollowing("bob", "h1"); following {
+		t.Error("still following after unfollow")
+	}
+	if h, _ := repos.Hashtag.GetByID("h1"); h.Followers != 0 {
+		t.Errorf("followers after unfollow = %d, want 0", h.Followers)
+	}
+}
+
+func testAnalytics(t *testing.T, repos *repository.Repositories) {
+	createPost(t, repos.Post, "p1", "alice")
+	if err := repos.Post.AddReaction(&models.Reaction{UserID: "bob", PostID: "p1"}); err != nil {
+		t.Fatalf("react: %v", err)
+	}
+
+	// Repeat views count once and the author's own views never count as reach
+	for _, viewer := range []string{"bob", "bob", "carol", "alice"} {
+		if err := repos.Analytics.RecordImpression("p1", viewer); err != nil {
+			t.Fatalf("impression: %v", err)
+		}
+	}
+
+	analytics, err := repos.Analytics.GetPostAnalytics("p1")
+	if err != nil {
+		t.Fatalf("analytics: %v", err)
+	}
+	if analytics.Impressions != 3 || analytics.ReachAll != 2 || analytics.Reach24h != 2 {
+		t.Errorf("impressions/reach/reach24h = %d/%d/%d, want 3/2/2",
+			analytics.Impressions, analytics.ReachAll, analytics.Reach24h)
+	}
+	if analytics.Reactions != 1 || analytics.Engagement != 50 {
+		t.Errorf("reactions = %d, engagement = %v; want 1, 50", analytics.Reactions, analytics.Engagement)
+	}
+
+	metrics, err := repos.Analytics.GetPostMetrics("p1")
+	if err != nil || metrics.ReachAll != 2 || metrics.Engagement != 50 {
+		t.Errorf("metrics = %+v, %v", metrics, err)
+	}
+	if viewers, _ := repos.Analytics.GetUniqueViewersAll("p1"); viewers != 2 {
+		t.Errorf("unique viewers = %d, want 2", viewers)
+	}
+
+	// Metrics for a post that no longer exists still report reach
+	if err := repos.Analytics.RecordImpression("gone", "bob"); err != nil {
+		t.Fatalf("impression: %v", err)
+	}
+	if gone, err := repos.Analytics.GetPostAnalytics("gone"); err != nil || gone.ReachAll != 1 || gone.Engagement != 0 {
+		t.Errorf("missing post analytics = %+v, %v", gone, err)
+	}
+}
+
+func testMessages(t *testing.T, repos *repository.Repositories) {
+	if err := repos.Message.CreateConversation(&models.Conversation{ID: "conv1", Participant1ID: "alice", Participant2ID: "bob"}); err != nil {
+		t.Fatalf("create conversation: %v", err)
+	}
+	if conv, err := repos.Message.GetConversationByParticipants("bob", "alice"); err != nil || conv.ID != "conv1" {
+		t.Fatalf("lookup by participants in either order = %v, %v", conv, err)
+	}
+	if _, err := repos.Message.GetConversationByParticipants("alice", "carol"); err == nil {
+		t.Error("expected missing conversation lookup to fail")
+	}
+
+	for i, sender := range []string{"alice", "bob", "bob"} {
+		msg := &models.Message{ID: "m" + string(rune('1'+i)), ConversationID: "conv1", SenderID: sender, Content: "hi"}
+		if err := repos.Message.CreateMessage(msg); err != nil {
+			t.Fatalf("message: %v", err)
+		}
+	}
+	if err := repos.Message.CreateMessage(&models.Message{ID: "m9", ConversationID: "missing", SenderID: "alice"}); err == nil {
+		t.Error("expected message to a missing conversation to fail")
+	}
+
+	// Unread counts only include messages from the other participant
+	if n, _ := repos.Message.GetUnreadCount("conv1", "alice"); n != 2 {
+		t.Errorf("alice unread = %d, want 2", n)
+	}
+	if err := repos.Message.MarkAsRead("m2"); err != nil {
+		t.Fatalf("mark read: %v", err)
+	}
+	if n, _ := repos.Message.GetUnreadCount("conv1", "alice"); n != 1 {
+		t.Errorf("alice unread after read = %d, want 1", n)
+	}
+	if msg, _ := repos.Message.GetMessage("m2"); !msg.Read {
+		t.Error("message not marked read")
+	}
+
+	if msgs, _, _ := repos.Message.ListMessages("conv1", firstPage); len(msgs) != 3 {
+		t.Errorf("messages = %d, want 3", len(msgs))
+	}
+	for _, user := range []string{"alice", "bob"} {
+		if convs, _ := repos.Message.ListConversations(user); len(convs) != 1 {
+			t.Errorf("%s conversations = %d, want 1", user, len(convs))
+		}
+	}
+}
+
+func testNotifications(t *testing.T, repos *repository.Repositories) {
+	base := time.Now().Add(-time.Hour)
+	for i, id := range []string{"n1", "n2", "n3"} {
+		n := &models.Notification{ID: id, UserID: "alice", Type: "follow", Title: id, CreatedAt: base.Add(time.Duration(i) * time.Minute)}
+		if err := repos.Notification.Create(n); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+	}
+	if err := repos.Notification.Create(&models.Notification{ID: "n4", UserID: "bob", Type: "follow", CreatedAt: base}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	list, _, err := repos.Notification.GetByUserID("alice", repository.Page{Limit: 2})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(list) != 2 || list[0].ID != "n3" || list[1].ID != "n2" {
+		t.Errorf("first page = %v, want n3, n2", list)
+	}
+
+	if n, _ := repos.Notification.GetUnreadCount("alice"); n != 3 {
+		t.Errorf("unread = %d, want 3", n)
+	}
+	if err := repos.Notification.MarkAsRead("n1"); err != nil {
+		t.Fatalf("mark read: %v", err)
+	}
+	if n, _ := repos.Notification.GetUnreadCount("alice"); n != 2 {
+		t.Errorf("unread after one read = %d, want 2", n)
+	}
+	if err := repos.Notification.MarkAllAsRead("alice"); err != nil {
+		t.Fatalf("mark all read: %v", err)
+	}
+	if n, _ := repos.Notification.GetUnreadCount("alice"); n != 0 {
+		t.Errorf("unread after all read = %d, want 0", n)
+	}
+	if n, _ := repos.Notification.GetUnreadCount("bob"); n != 1 {
+		t.Errorf("marking alice's notifications read touched bob's: unread = %d", n)
+	}
+
+	if err := repos.Notification.Delete("n2"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := repos.Notification.Delete("n2"); err == nil {
+		t.Error("expected deleting a missing notification to fail")
+	}
+	if list, _, _ := repos.Notification.GetByUserID("alice", firstPage); len(list) != 2 {
+		t.Errorf("notifications after delete = %d, want 2", len(list))
+	}
+}
@@ -0,0 +1,114 @@
+// Package repotest is a conformance suite for implementations of the
+// repository interfaces.
+//
+// Every storage backend runs the same tests from its own package:
+//
+//	func TestConformance(t *testing.T) {
+//		repotest.Run(t, func(t *testing.T) *repository.Repositories {
+//			return memory.NewRepositories()
+//		})
+//	}
+//
+// A new backend is only considered equivalent once it passes the whole suite.
+package repotest
+
+import (
+	"testing"
+
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
+)
+
+// firstPage is large enough to hold everything a single test creates
+var firstPage = repository.Page{Limit: 50}
+
+// Factory returns an empty set of repositories for a single test. It is
+// called once per test, so state never leaks between them.
+type Factory func(t *testing.T) *repository.Repositories
+
+var tests = []struct {
+	name string
+	fn   func(t *testing.T, repos *repository.Repositories)
+}{
+	{"Auth", testAuth},
+	{"Sessions", testSessions},
+	{"EmailTokens", testEmailTokens},
+	{"TwoFactor", testTwoFactor},
+	{"LoginThrottles", testLoginThrottles},
+	{"Identities", testIdentities},
+	{"SigningKeys", testSigningKeys},
+	{"APIKeys", testAPIKeys},
+	{"Bots", testBots},
+	{"UserLookup", testUserLookup},
+	{"FollowCounters", testFollowCounters},
+	{"PostFeeds", testPostFeeds},
+	{"PostCounters", testPostCounters},
+	{"PostSoftDelete", testPostSoftDelete},
+	{"Hashtags", testHashtags},
+	{"Analytics", testAnalytics},
+	{"Messages", testMessages},
+	{"Notifications", testNotifications},
+	{"DebateParticipants", testDebateParticipants},
+	{"DebateSideSwitch", testDebateSideSwitch},
+	{"PendingDebates", testPendingDebates},
+	{"DebateRounds", testDebateRounds},
+	{"DebateUnlock", testDebateUnlock},
+	{"SpeakRequests", testSpeakRequests},
+	{"SpeakerQueue", testSpeakerQueue},
+	{"DebatePolls", testDebatePolls},
+	{"DebateStats", testDebateStats},
+	{"CommunityMembership", testCommunityMembership},
+	{"CommunitySoftDelete", testCommunitySoftDelete},
+	{"CursorPagination", testCursorPagination},
+	{"UnitOfWorkCommits", testUnitOfWorkCommits},
+	{"UnitOfWorkRollsBack", testUnitOfWorkRollsBack},
+	{"VersionConflicts", testVersionConflicts},
+}
+
+// Run runs the conformance suite against the repositories built by newRepos
+func Run(t *testing.T, newRepos Factory) {
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, newRepos(t))
+		})
+	}
+}
+
+// createUsers creates a user with a matching handle for every id
+func createUsers(t *testing.T, repo repository.UserRepository, ids ...string) {
+	t.Helper()
+	for _, id := range ids {
+		if err := repo.Create(&models.User{ID: id, Handle: id, Email: id + "@example.com"}); err != nil {
+			t.Fatalf("create user %s: %v", id, err)
+		}
+	}
+}
+
+// createPost creates a visible post by authorID
+func createPost(t *testing.T, repo repository.PostRepository, id, authorID string) {
+	t.Helper()
+	if err := repo.Create(&models.Post{ID: id, AuthorID: authorID, Content: id}); err != nil {
+		t.Fatalf("create post %s: %v", id, err)
+	}
+}
+
+// ids returns the IDs of posts in order
+func postIDs(posts []*models.Post) []string {
+	ids := make([]string, len(posts))
+	for i, p := range posts {
+		ids[i] = p.ID
+	}
+	return ids
+}
+
+func equalIDs(got, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
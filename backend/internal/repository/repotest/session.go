@@ -0,0 +1,88 @@
+package repotest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
+)
+
+func testSessions(t *testing.T, repos *repository.Repositories) {
+	expiresAt := time.Now().Add(time.Hour)
+	for _, s := range []*models.Session{
+		{ID: "s1", UserID: "alice", TokenHash: "h1", ExpiresAt: expiresAt},
+		{ID: "s2", UserID: "alice", TokenHash: "h2", ExpiresAt: expiresAt},
+		{ID: "s3", UserID: "alice", TokenHash: "h3", ExpiresAt: time.Now().Add(-time.Minute)},
+		{ID: "s4", UserID: "bob", TokenHash: "h4", ExpiresAt: expiresAt},
+	} {
+		if err := repos.Session.Create(s); err != nil {
+			t.Fatalf("create %s: %v", s.ID, err)
+		}
+	}
+
+	// Rotation only succeeds with the current hash. The previous one is caught
+	// as a replay; any other hash is just wrong.
+	if err := repos.Session.Rotate("s1", "h1", "h1b", expiresAt); err != nil {
+		t.Fatalf("rotate: %v", err)
+	}
+	if err := repos.Session.Rotate("s1", "h1", "h1c", expiresAt); !errors.Is(err, repository.ErrRefreshTokenReused) {
+		t.Errorf("replayed rotate = %v, want reuse", err)
+	}
+	if err := repos.Session.Rotate("s1", "forged", "h1c", expiresAt); !errors.Is(err, repository.ErrRefreshTokenInvalid) {
+		t.Errorf("rotate with unknown hash = %v, want invalid", err)
+	}
+	if err := repos.Session.Rotate("s3", "h3", "h3b", expiresAt); !errors.Is(err, repository.ErrRefreshTokenInvalid) {
+		t.Errorf("rotate of expired session = %v, want invalid", err)
+	}
+	if err := repos.Session.Rotate("missing", "h", "h", expiresAt); err == nil || errors.Is(err, repository.ErrRefreshTokenReused) {
+		t.Errorf("rotate of missing session = %v, want not found", err)
+	}
+	session, err := repos.Session.GetByID("s1")
+	if err != nil || session.TokenHash != "h1b" || session.PreviousTokenHash != "h1" || session.RevokedAt != nil {
+		t.Fatalf("s1 = %+v, %v", session, err)
+	}
+
+	active, err := repos.Session.ListActive("alice")
+	if err != nil || !equalIDs(sessionIDs(active), []string{"s1", "s2"}) {
+		t.Errorf("active = %v, %v; want s1 (just used) then s2", sessionIDs(active), err)
+	}
+
+	if err := repos.Session.Revoke("s2"); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+	if err := repos.Session.Revoke("s2"); err != nil {
+		t.Errorf("revoking twice = %v", err)
+	}
+	if err := repos.Session.Revoke("missing"); err == nil {
+		t.Error("expected revoking a missing session to fail")
+	}
+	if session, _ := repos.Session.GetByID("s2"); session == nil || session.RevokedAt == nil {
+		t.Errorf("s2 = %+v, want revoked and still stored", session)
+	}
+	if err := repos.Session.Rotate("s2", "h2", "h2b", expiresAt); !errors.Is(err, repository.ErrRefreshTokenInvalid) {
+		t.Errorf("rotate of revoked session = %v, want invalid", err)
+	}
+
+	if err := repos.Session.Create(&models.Session{ID: "s5", UserID: "alice", TokenHash: "h5", ExpiresAt: expiresAt}); err != nil {
+		t.Fatalf("create s5: %v", err)
+	}
+	if err := repos.Session.RevokeAllForUser("alice", "s5"); err != nil {
+		t.Fatalf("revoke all: %v", err)
+	}
+	if active, _ := repos.Session.ListActive("alice"); !equalIDs(sessionIDs(active), []string{"s5"}) {
+		t.Errorf("alice active after revoke all = %v, want s5", sessionIDs(active))
+	}
+	if active, _ := repos.Session.ListActive("bob"); len(active) != 1 {
+		t.Errorf("bob lost %d session(s) to alice's revoke all", 1-len(active))
+	}
+}
+
+func sessionIDs(sessions []*models.Session) []string {
+	ids := make([]string, len(sessions))
+	for i, s := range sessions {
+		ids[i] = s.ID
+	}
+	return ids
+}
@@ -0,0 +1,51 @@
+package repotest
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
+)
+
+func testSigningKeys(t *testing.T, repos *repository.Repositories) {
+	base := time.Now().Truncate(time.Second)
+
+	for i, id := range []string{"next", "current", "old"} {
+		activates := base.Add(-time.Duration(i) * 24 * time.Hour)
+		err := repos.SigningKey.Create(&models.SigningKey{
+			ID:          id,
+			Algorithm:   "RS256",
+			PrivateKey:  []byte{0, byte(i), 0xff},
+			ActivatesAt: activates,
+			RetiresAt:   activates.Add(24 * time.Hour),
+			ExpiresAt:   activates.Add(25 * time.Hour),
+		})
+		if err != nil {
+			t.Fatalf("create %s: %v", id, err)
+		}
+	}
+	if err := repos.SigningKey.Create(&models.SigningKey{ID: "old", Algorithm: "RS256", PrivateKey: []byte{1}}); err == nil {
+		t.Error("duplicate key ID accepted")
+	}
+
+	keys, err := repos.SigningKey.List()
+	if err != nil || len(keys) != 3 {
+		t.Fatalf("list = %d keys, %v", len(keys), err)
+	}
+	if keys[0].ID != "old" || keys[1].ID != "current" || keys[2].ID != "next" {
+		t.Errorf("list order = %s, %s, %s", keys[0].ID, keys[1].ID, keys[2].ID)
+	}
+	if !bytes.Equal(keys[1].PrivateKey, []byte{0, 1, 0xff}) || !keys[1].ActivatesAt.Equal(base.Add(-24*time.Hour)) || keys[1].CreatedAt.IsZero() {
+		t.Errorf("current = %+v", keys[1])
+	}
+
+	pruned, err := repos.SigningKey.Prune(base)
+	if err != nil || pruned != 1 {
+		t.Errorf("prune = %d, %v, want 1", pruned, err)
+	}
+	if keys, _ := repos.SigningKey.List(); len(keys) != 2 || keys[0].ID != "current" {
+		t.Errorf("after prune %d keys", len(keys))
+	}
+}
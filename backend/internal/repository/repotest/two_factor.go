@@ -0,0 +1,78 @@
+package repotest
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
+)
+
+func testTwoFactor(t *testing.T, repos *repository.Repositories) {
+	if _, err := repos.TwoFactor.Get("alice"); err == nil {
+		t.Fatal("get before save succeeded")
+	}
+
+	tf := &models.TwoFactor{UserID: "alice", Secret: "SECRET1"}
+	if err := repos.TwoFactor.Save(tf); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	createdAt := tf.CreatedAt
+	if createdAt.IsZero() {
+		t.Error("created at not set")
+	}
+
+	// Saving again replaces the enrollment but keeps when it was created
+	enabledAt := time.Now()
+	tf = &models.TwoFactor{UserID: "alice", Secret: "SECRET2", Enabled: true, RecoveryCodes: []string{"c1", "c2", "c3"}, EnabledAt: &enabledAt}
+	if err := repos.TwoFactor.Save(tf); err != nil {
+		t.Fatalf("save again: %v", err)
+	}
+	got, err := repos.TwoFactor.Get("alice")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.Secret != "SECRET2" || !got.Enabled || got.EnabledAt == nil || !slices.Equal(got.RecoveryCodes, []string{"c1", "c2", "c3"}) {
+		t.Errorf("got %+v", got)
+	}
+	if !got.CreatedAt.Equal(createdAt) {
+		t.Errorf("created at = %v, want %v", got.CreatedAt, createdAt)
+	}
+
+	// Steps only move forward, so a code cannot be replayed
+	if err := repos.TwoFactor.UseStep("alice", 10); err != nil {
+		t.Fatalf("use step: %v", err)
+	}
+	for _, step := range []int64{10, 9} {
+		if err := repos.TwoFactor.UseStep("alice", step); !errors.Is(err, repository.ErrTwoFactorCodeUsed) {
+			t.Errorf("use step %d = %v, want used", step, err)
+		}
+	}
+	if err := repos.TwoFactor.UseStep("bob", 1); err == nil || errors.Is(err, repository.ErrTwoFactorCodeUsed) {
+		t.Errorf("use step without enrollment = %v, want not found", err)
+	}
+
+	// Recovery codes work once
+	if err := repos.TwoFactor.UseRecoveryCode("alice", "c2"); err != nil {
+		t.Fatalf("use recovery code: %v", err)
+	}
+	if err := repos.TwoFactor.UseRecoveryCode("alice", "c2"); !errors.Is(err, repository.ErrTwoFactorCodeUsed) {
+		t.Errorf("reuse recovery code = %v, want used", err)
+	}
+	got, _ = repos.TwoFactor.Get("alice")
+	if got.LastUsedStep != 10 || !slices.Equal(got.RecoveryCodes, []string{"c1", "c3"}) {
+		t.Errorf("after use got %+v", got)
+	}
+
+	if err := repos.TwoFactor.Delete("alice"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := repos.TwoFactor.Get("alice"); err == nil {
+		t.Error("get after delete succeeded")
+	}
+	if err := repos.TwoFactor.UseRecoveryCode("alice", "c1"); err == nil {
+		t.Errorf("recovery code survived delete: %v", err)
+	}
+}
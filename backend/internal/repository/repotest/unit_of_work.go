@@ -0,0 +1,72 @@
+package repotest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
+)
+
+// writeInUnitOfWork awards alice points and creates a commented post through
+// tx, with the comment written by a nested unit of work
+func writeInUnitOfWork(tx *repository.Repositories) error {
+	user, err := tx.User.GetByID("alice")
+	if err != nil {
+		return err
+	}
+	user.Points = 50
+	if err := tx.User.Update(user); err != nil {
+		return err
+	}
+	if err := tx.Post.Create(&models.Post{ID: "p1", AuthorID: "alice", Content: "p1"}); err != nil {
+		return err
+	}
+	return tx.UnitOfWork.Do(func(tx *repository.Repositories) error {
+		return tx.Post.CreateComment(&models.Comment{ID: "c1", PostID: "p1", AuthorID: "alice"})
+	})
+}
+
+func testUnitOfWorkCommits(t *testing.T, repos *repository.Repositories) {
+	createUsers(t, repos.User, "alice")
+
+	if err := repos.UnitOfWork.Do(writeInUnitOfWork); err != nil {
+		t.Fatalf("unit of work: %v", err)
+	}
+
+	if user, _ := repos.User.GetByID("alice"); user == nil || user.Points != 50 {
+		t.Errorf("alice = %+v, want 50 points", user)
+	}
+	post, err := repos.Post.GetByID("p1")
+	if err != nil {
+		t.Fatalf("post not committed: %v", err)
+	}
+	if post.CommentCount != 1 {
+		t.Errorf("comments = %d, want 1", post.CommentCount)
+	}
+}
+
+func testUnitOfWorkRollsBack(t *testing.T, repos *repository.Repositories) {
+	createUsers(t, repos.User, "alice")
+
+	errFailed := errors.New("failed after writing")
+	err := repos.UnitOfWork.Do(func(tx *repository.Repositories) error {
+		if err := writeInUnitOfWork(tx); err != nil {
+			return err
+		}
+		return errFailed
+	})
+	if err != errFailed {
+		t.Fatalf("unit of work = %v, want %v", err, errFailed)
+	}
+
+	if user, _ := repos.User.GetByID("alice"); user == nil || user.Points != 0 {
+		t.Errorf("alice = %+v, want points rolled back", user)
+	}
+	if _, err := repos.Post.GetByID("p1"); err == nil {
+		t.Error("post survived rollback")
+	}
+	if comments, _ := repos.Post.GetCommentsByPost("p1"); len(comments) != 0 {
+		t.Errorf("comments = %d after rollback, want 0", len(comments))
+	}
+}
@@ -0,0 +1,156 @@
+package repotest
+
+import (
+	"testing"
+
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
+)
+
+func testAuth(t *testing.T, repos *repository.Repositories) {
+	if err := repos.Auth.CreateAuth(&models.Auth{UserID: "alice", Email: "alice@example.com", PasswordHash: "h1"}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	dupes := []struct {
+		name string
+		auth *models.Auth
+	}{
+		{"same user", &models.Auth{UserID: "alice", Email: "other@example.com"}},
+		{"same email", &models.Auth{UserID: "bob", Email: "alice@example.com"}},
+	}
+	for _, d := range dupes {
+		if err := repos.Auth.CreateAuth(d.auth); err == nil {
+			t.Errorf("%s: expected duplicate create to fail", d.name)
+		}
+	}
+
+	if err := repos.Auth.UpdatePassword("alice", "h2"); err != nil {
+		t.Fatalf("update password: %v", err)
+	}
+	auth, err := repos.Auth.GetByEmail("alice@example.com")
+	if err != nil || auth.UserID != "alice" || auth.PasswordHash != "h2" {
+		t.Fatalf("get by email = %+v, %v", auth, err)
+	}
+	if err := repos.Auth.UpdatePassword("nobody", "h"); err == nil {
+		t.Error("expected update of missing auth to fail")
+	}
+
+	if err := repos.Auth.DeleteAuth("alice"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := repos.Auth.GetByUserID("alice"); err == nil {
+		t.Error("auth survived delete")
+	}
+}
+
+func testUserLookup(t *testing.T, repos *repository.Repositories) {
+	if err := repos.User.Create(&models.User{ID: "alice", Handle: "alice", Email: "alice@example.com", Languages: []string{"en", "te"}}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := repos.User.Create(&models.User{ID: "alice"}); err == nil {
+		t.Fatal("expected duplicate create to fail")
+	}
+
+	lookups := []struct {
+		name string
+		get  func() (*models.User, error)
+	}{
+		{"by id", func() (*models.User, error) { return repos.User.GetByID("alice") }},
+		{"by handle", func() (*models.User, error) { return repos.User.GetByHandle("alice") }},
+		{"by email", func() (*models.User, error) { return repos.User.GetByEmail("alice@example.com") }},
+	}
+	for _, l := range lookups {
+		user, err := l.get()
+		if err != nil || user.ID != "alice" {
+			t.Errorf("%s = %v, %v", l.name, user, err)
+			continue
+		}
+		if len(user.Languages) != 2 || user.Languages[1] != "te" {
+			t.Errorf("%s: languages = %v", l.name, user.Languages)
+		}
+	}
+	if _, err := repos.User.GetByID("nobody"); err == nil {
+		t.Error("expected missing user lookup to fail")
+	}
+
+	user, _ := repos.User.GetByID("alice")
+	if user.Role != models.PlatformRoleUser {
+		t.Errorf("role = %q, want user by default", user.Role)
+	}
+	user.Bio = "updated"
+	user.Role = models.PlatformRoleModerator
+	user.EmailVerified = true
+	if err := repos.User.Update(user); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if user, _ := repos.User.GetByID("alice"); user.Bio != "updated" || user.Role != models.PlatformRoleModerator || !user.EmailVerified {
+		t.Errorf("bio, role, verified = %q, %q, %v after update", user.Bio, user.Role, user.EmailVerified)
+	}
+	if err := repos.User.Update(&models.User{ID: "nobody"}); err == nil {
+		t.Error("expected update of missing user to fail")
+	}
+
+	if err := repos.User.Delete("alice"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := repos.User.GetByID("alice"); err == nil {
+		t.Error("user survived delete")
+	}
+}
+
+func testFollowCounters(t *testing.T, repos *repository.Repositories) {
+	createUsers(t, repos.User, "alice", "bob", "carol")
+
+	// Following twice must not double count
+	for i := 0; i < 2; i++ {
+		if err := repos.User.Follow("alice", "bob"); err != nil {
+			t.Fatalf("follow: %v", err)
+		}
+	}
+	if err := repos.User.Follow("carol", "bob"); err != nil {
+		t.Fatalf("follow: %v", err)
+	}
+	if err := repos.User.Follow("alice", "nobody"); err == nil {
+		t.Error("expected following a missing user to fail")
+	}
+
+	counts := func(step string, want map[string][2]int) {
+		t.Helper()
+		for id, w := range want {
+			user, err := repos.User.GetByID(id)
+			if err != nil {
+				t.Fatalf("%s: get %s: %v", step, id, err)
+			}
+			if user.FollowersCount != w[0] || user.FollowingCount != w[1] {
+				t.Errorf("%s: %s followers/following = %d/%d, want %d/%d",
+					step, id, user.FollowersCount, user.FollowingCount, w[0], w[1])
+			}
+		}
+	}
+	counts("after follow", map[string][2]int{"alice": {0, 1}, "bob": {2, 0}, "carol": {0, 1}})
+
+	if following, _ := repos.User.IsFollowing("alice", "bob"); !following {
+		t.Error("alice should follow bob")
+	}
+	if following, _ := repos.User.IsFollowing("bob", "alice"); following {
+		t.Error("follows must be directional")
+	}
+
+	followers, _, err := repos.User.GetFollowers("bob", firstPage)
+	if err != nil || len(followers) != 2 {
+		t.Fatalf("followers = %v, %v", followers, err)
+	}
+	following, _, err := repos.User.GetFollowing("alice", firstPage)
+	if err != nil || len(following) != 1 || following[0].ID != "bob" {
+		t.Fatalf("following = %v, %v", following, err)
+	}
+
+	// Unfollowing twice must not double count either
+	for i := 0; i < 2; i++ {
+		if err := repos.User.Unfollow("alice", "bob"); err != nil {
+			t.Fatalf("unfollow: %v", err)
+		}
+	}
+	counts("after unfollow", map[string][2]int{"alice": {0, 0}, "bob": {1, 0}, "carol": {0, 1}})
+}
@@ -0,0 +1,86 @@
+package repotest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
+)
+
+// assertConflict checks that err reports a stale write of an entity now at version
+func assertConflict(t *testing.T, step string, err error, version int) {
+	t.Helper()
+	var conflict *repository.ConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("%s: err = %v, want a conflict", step, err)
+	}
+	if conflict.Version != version {
+		t.Errorf("%s: conflict at version %d, want %d", step, conflict.Version, version)
+	}
+}
+
+func testVersionConflicts(t *testing.T, repos *repository.Repositories) {
+	createUsers(t, repos.User, "alice", "bob")
+
+	// Two writers read the same version; the second one loses
+	first, _ := repos.User.GetByID("alice")
+	second, _ := repos.User.GetByID("alice")
+	if first.Version != 1 {
+		t.Fatalf("new user at version %d, want 1", first.Version)
+	}
+	first.Bio = "first"
+	if err := repos.User.Update(first); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if first.Version != 2 {
+		t.Errorf("updated user at version %d, want 2", first.Version)
+	}
+	second.Bio = "second"
+	assertConflict(t, "stale user update", repos.User.Update(second), 2)
+	if user, _ := repos.User.GetByID("alice"); user.Bio != "first" {
+		t.Errorf("bio = %q, stale update was applied", user.Bio)
+	}
+
+	// Counter writes bump the version too, so they cannot be clobbered
+	if err := repos.User.Follow("bob", "alice"); err != nil {
+		t.Fatalf("follow: %v", err)
+	}
+	first.Bio = "again"
+	assertConflict(t, "update after follow", repos.User.Update(first), 3)
+
+	if err := repos.User.Update(&models.User{ID: "nobody", Version: 1}); err == nil || repository.IsConflict(err) {
+		t.Errorf("update of missing user = %v, want not found", err)
+	}
+
+	createPost(t, repos.Post, "p1", "alice")
+	post, _ := repos.Post.GetByID("p1")
+	if err := repos.Post.AddReaction(&models.Reaction{UserID: "bob", PostID: "p1"}); err != nil {
+		t.Fatalf("react: %v", err)
+	}
+	post.Content = "edited"
+	assertConflict(t, "post update after reaction", repos.Post.Update(post), 2)
+
+	createDebate(t, repos.Debate, "d1")
+	debate, _ := repos.Debate.GetByID("d1")
+	stale := *debate
+	debate.Status = "ENDED"
+	if err := repos.Debate.Update(debate); err != nil {
+		t.Fatalf("update debate: %v", err)
+	}
+	assertConflict(t, "stale debate update", repos.Debate.Update(&stale), 2)
+
+	now := time.Now()
+	if err := repos.Community.Create(&models.Community{ID: "c1", Name: "c1", CreatedAt: now, UpdatedAt: now}); err != nil {
+		t.Fatalf("create community: %v", err)
+	}
+	community, _ := repos.Community.GetByID("c1")
+	other, _ := repos.Community.GetByID("c1")
+	community.MemberCount = 1
+	if err := repos.Community.Update(community); err != nil {
+		t.Fatalf("update community: %v", err)
+	}
+	other.MemberCount = 5
+	assertConflict(t, "stale community update", repos.Community.Update(other), 2)
+}
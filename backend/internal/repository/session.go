@@ -0,0 +1,13 @@
+package repository
+
+import "errors"
+
+var (
+	// ErrRefreshTokenReused is returned by Rotate when the presented refresh
+	// token is the one the session rotated away from last, which means it was
+	// used before
+	ErrRefreshTokenReused = errors.New("refresh token has already been used")
+	// ErrRefreshTokenInvalid is returned by Rotate for any other token that is
+	// not the session's current one, or when the session is no longer live
+	ErrRefreshTokenInvalid = errors.New("refresh token is not valid for this session")
+)
@@ -0,0 +1,7 @@
+package repository
+
+import "errors"
+
+// ErrRestoreWindowExpired is returned by Restore when the entity was deleted
+// before the start of the restore window. It stays hidden until it is purged.
+var ErrRestoreWindowExpired = errors.New("restore window has expired")
@@ -0,0 +1,59 @@
+// Package sqlite opens embedded SQLite databases for the repositories in sqlstore.
+// It uses a pure-Go driver, so no cgo toolchain is needed.
+package sqlite
+
+import (
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/yourusername/v-backend/internal/repository"
+	"github.com/yourusername/v-backend/internal/repository/sqlstore"
+	_ "modernc.org/sqlite"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Migrations holds the versioned schema for this backend, for use with the migrate package
+var Migrations, _ = fs.Sub(migrationFiles, "migrations")
+
+// Open opens (creating if needed) the database file at path. The schema is
+// managed separately through Migrations.
+func Open(path string) (*sql.DB, error) {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("create database directory: %w", err)
+		}
+	}
+
+	params := url.Values{}
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Add("_pragma", "busy_timeout(5000)")
+	params.Add("_pragma", "journal_mode(WAL)")
+
+	db, err := sql.Open("sqlite", "file:"+path+"?"+params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+
+	// SQLite allows a single writer; one connection keeps transactions from
+	// failing with SQLITE_BUSY under concurrent requests
+	db.SetMaxOpenConns(1)
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("ping database: %w", err)
+	}
+
+	return db, nil
+}
+
+// NewRepositories returns SQLite-backed implementations of every repository
+func NewRepositories(db *sql.DB) *repository.Repositories {
+	return sqlstore.NewRepositories(db)
+}
@@ -0,0 +1,22 @@
+DROP TABLE IF EXISTS community_member_history;
+DROP TABLE IF EXISTS community_members;
+DROP TABLE IF EXISTS communities;
+DROP TABLE IF EXISTS post_impressions;
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS debate_stats_recorded;
+DROP TABLE IF EXISTS debate_topic_stats;
+DROP TABLE IF EXISTS speak_requests;
+DROP TABLE IF EXISTS debate_participants;
+DROP TABLE IF EXISTS debates;
+DROP TABLE IF EXISTS hashtag_followers;
+DROP TABLE IF EXISTS hashtag_posts;
+DROP TABLE IF EXISTS hashtags;
+DROP TABLE IF EXISTS messages;
+DROP TABLE IF EXISTS conversations;
+DROP TABLE IF EXISTS saves;
+DROP TABLE IF EXISTS reactions;
+DROP TABLE IF EXISTS comments;
+DROP TABLE IF EXISTS posts;
+DROP TABLE IF EXISTS auth;
+DROP TABLE IF EXISTS follows;
+DROP TABLE IF EXISTS users;
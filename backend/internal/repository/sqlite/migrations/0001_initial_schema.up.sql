@@ -0,0 +1,272 @@
+CREATE TABLE IF NOT EXISTS users (
+    id                       TEXT PRIMARY KEY,
+    name                     TEXT NOT NULL DEFAULT '',
+    handle                   TEXT NOT NULL DEFAULT '',
+    email                    TEXT NOT NULL DEFAULT '',
+    password                 TEXT NOT NULL DEFAULT '',
+    phone_number             TEXT NOT NULL DEFAULT '',
+    languages                TEXT NOT NULL DEFAULT '[]',
+    bio                      TEXT NOT NULL DEFAULT '',
+    gender                   TEXT NOT NULL DEFAULT '',
+    date_of_birth            TIMESTAMP NOT NULL,
+    avatar_url               TEXT NOT NULL DEFAULT '',
+    cover_photo_url          TEXT NOT NULL DEFAULT '',
+    followers_only_comments  BOOLEAN NOT NULL DEFAULT FALSE,
+    followers_count          INTEGER NOT NULL DEFAULT 0,
+    following_count          INTEGER NOT NULL DEFAULT 0,
+    posts_count              INTEGER NOT NULL DEFAULT 0,
+    tier                     TEXT NOT NULL DEFAULT '',
+    points                   INTEGER NOT NULL DEFAULT 0,
+    subscription_active      BOOLEAN NOT NULL DEFAULT FALSE,
+    temporarily_muted        BOOLEAN NOT NULL DEFAULT FALSE,
+    muted_until              TIMESTAMP,
+    last_abusive_post_date   TIMESTAMP,
+    abusive_post_count_today INTEGER NOT NULL DEFAULT 0,
+    last_debate_host_date    TIMESTAMP,
+    debates_hosted_today     INTEGER NOT NULL DEFAULT 0,
+    last_login_date          TIMESTAMP,
+    login_streak             INTEGER NOT NULL DEFAULT 0,
+    created_at               TIMESTAMP NOT NULL,
+    updated_at               TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_users_handle ON users (handle);
+CREATE INDEX IF NOT EXISTS idx_users_email ON users (email);
+
+CREATE TABLE IF NOT EXISTS follows (
+    follower_id  TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    following_id TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created_at   TIMESTAMP NOT NULL,
+    PRIMARY KEY (follower_id, following_id)
+);
+CREATE INDEX IF NOT EXISTS idx_follows_following ON follows (following_id);
+
+CREATE TABLE IF NOT EXISTS auth (
+    user_id       TEXT PRIMARY KEY,
+    email         TEXT NOT NULL UNIQUE,
+    password_hash TEXT NOT NULL,
+    created_at    TIMESTAMP NOT NULL,
+    updated_at    TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS posts (
+    id                  TEXT PRIMARY KEY,
+    author_id           TEXT NOT NULL,
+    content             TEXT NOT NULL DEFAULT '',
+    media_type          TEXT NOT NULL DEFAULT '',
+    media_url           TEXT NOT NULL DEFAULT '',
+    comments_disabled   BOOLEAN NOT NULL DEFAULT FALSE,
+    comment_limit       INTEGER,
+    reaction_count      INTEGER NOT NULL DEFAULT 0,
+    comment_count       INTEGER NOT NULL DEFAULT 0,
+    save_count          INTEGER NOT NULL DEFAULT 0,
+    reach_24h           INTEGER NOT NULL DEFAULT 0,
+    reach_all           INTEGER NOT NULL DEFAULT 0,
+    community_id        TEXT,
+    post_type           TEXT NOT NULL DEFAULT '',
+    topic_tag           TEXT NOT NULL DEFAULT '',
+    response_to_post_id TEXT NOT NULL DEFAULT '',
+    status              TEXT NOT NULL DEFAULT 'VISIBLE',
+    report_count        INTEGER NOT NULL DEFAULT 0,
+    in_moderation_queue BOOLEAN NOT NULL DEFAULT FALSE,
+    original_language   TEXT NOT NULL DEFAULT '',
+    translations        TEXT NOT NULL DEFAULT '{}',
+    created_at          TIMESTAMP NOT NULL,
+    updated_at          TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_posts_created ON posts (created_at);
+CREATE INDEX IF NOT EXISTS idx_posts_author ON posts (author_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_posts_community ON posts (community_id, created_at);
+
+CREATE TABLE IF NOT EXISTS comments (
+    id         TEXT PRIMARY KEY,
+    post_id    TEXT NOT NULL REFERENCES posts (id) ON DELETE CASCADE,
+    author_id  TEXT NOT NULL,
+    parent_id  TEXT,
+    content    TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_comments_post ON comments (post_id, created_at);
+
+-- comment_id is '' for reactions on the post itself so it can be part of the key
+CREATE TABLE IF NOT EXISTS reactions (
+    user_id    TEXT NOT NULL,
+    post_id    TEXT NOT NULL,
+    comment_id TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, post_id, comment_id)
+);
+CREATE INDEX IF NOT EXISTS idx_reactions_post ON reactions (post_id);
+
+CREATE TABLE IF NOT EXISTS saves (
+    user_id    TEXT NOT NULL,
+    post_id    TEXT NOT NULL REFERENCES posts (id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, post_id)
+);
+
+CREATE TABLE IF NOT EXISTS conversations (
+    id              TEXT PRIMARY KEY,
+    participant1_id TEXT NOT NULL,
+    participant2_id TEXT NOT NULL,
+    created_at      TIMESTAMP NOT NULL,
+    updated_at      TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_conversations_p1 ON conversations (participant1_id);
+CREATE INDEX IF NOT EXISTS idx_conversations_p2 ON conversations (participant2_id);
+
+CREATE TABLE IF NOT EXISTS messages (
+    id              TEXT PRIMARY KEY,
+    conversation_id TEXT NOT NULL REFERENCES conversations (id) ON DELETE CASCADE,
+    sender_id       TEXT NOT NULL,
+    content         TEXT NOT NULL DEFAULT '',
+    is_read         BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at      TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_messages_conversation ON messages (conversation_id, created_at);
+
+CREATE TABLE IF NOT EXISTS hashtags (
+    id         TEXT PRIMARY KEY,
+    name       TEXT NOT NULL DEFAULT '',
+    slug       TEXT NOT NULL,
+    category   TEXT NOT NULL DEFAULT '',
+    created_by TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    followers  INTEGER NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS idx_hashtags_slug ON hashtags (slug);
+
+CREATE TABLE IF NOT EXISTS hashtag_posts (
+    hashtag_id TEXT NOT NULL REFERENCES hashtags (id) ON DELETE CASCADE,
+    post_id    TEXT NOT NULL,
+    is_boost   BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (hashtag_id, post_id)
+);
+CREATE INDEX IF NOT EXISTS idx_hashtag_posts_post ON hashtag_posts (post_id);
+CREATE INDEX IF NOT EXISTS idx_hashtag_posts_created ON hashtag_posts (created_at);
+
+CREATE TABLE IF NOT EXISTS hashtag_followers (
+    hashtag_id TEXT NOT NULL REFERENCES hashtags (id) ON DELETE CASCADE,
+    user_id    TEXT NOT NULL,
+    PRIMARY KEY (hashtag_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS debates (
+    id                 TEXT PRIMARY KEY,
+    title              TEXT NOT NULL DEFAULT '',
+    description        TEXT NOT NULL DEFAULT '',
+    category           TEXT NOT NULL DEFAULT '',
+    host_id            TEXT NOT NULL,
+    type               TEXT NOT NULL DEFAULT '',
+    status             TEXT NOT NULL DEFAULT '',
+    start_time         TIMESTAMP NOT NULL,
+    end_time           TIMESTAMP,
+    duration_minutes   INTEGER NOT NULL DEFAULT 0,
+    show_in_pulse      BOOLEAN NOT NULL DEFAULT FALSE,
+    agree_count        INTEGER NOT NULL DEFAULT 0,
+    disagree_count     INTEGER NOT NULL DEFAULT 0,
+    is_locked          BOOLEAN NOT NULL DEFAULT FALSE,
+    unlock_phase       INTEGER NOT NULL DEFAULT 0,
+    early_access_roles TEXT NOT NULL DEFAULT '[]',
+    created_at         TIMESTAMP NOT NULL,
+    updated_at         TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_debates_status ON debates (status, created_at);
+
+CREATE TABLE IF NOT EXISTS debate_participants (
+    id               TEXT PRIMARY KEY,
+    debate_id        TEXT NOT NULL REFERENCES debates (id) ON DELETE CASCADE,
+    user_id          TEXT NOT NULL,
+    role             TEXT NOT NULL DEFAULT '',
+    side             TEXT NOT NULL DEFAULT '',
+    is_self_muted    BOOLEAN NOT NULL DEFAULT FALSE,
+    is_muted_by_host BOOLEAN NOT NULL DEFAULT FALSE,
+    joined_at        TIMESTAMP NOT NULL,
+    left_at          TIMESTAMP,
+    UNIQUE (debate_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS speak_requests (
+    id         TEXT PRIMARY KEY,
+    debate_id  TEXT NOT NULL REFERENCES debates (id) ON DELETE CASCADE,
+    user_id    TEXT NOT NULL,
+    status     TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_speak_requests_debate ON speak_requests (debate_id, created_at);
+
+-- topic_key is the trimmed, lower-cased topic used for lookups
+CREATE TABLE IF NOT EXISTS debate_topic_stats (
+    topic_key          TEXT PRIMARY KEY,
+    topic              TEXT NOT NULL,
+    total_participants INTEGER NOT NULL DEFAULT 0,
+    total_agree        INTEGER NOT NULL DEFAULT 0,
+    total_disagree     INTEGER NOT NULL DEFAULT 0,
+    sessions_count     INTEGER NOT NULL DEFAULT 0,
+    last_updated       TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS debate_stats_recorded (
+    debate_id   TEXT PRIMARY KEY,
+    recorded_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id             TEXT PRIMARY KEY,
+    user_id        TEXT NOT NULL,
+    type           TEXT NOT NULL DEFAULT '',
+    title          TEXT NOT NULL DEFAULT '',
+    message        TEXT NOT NULL DEFAULT '',
+    debate_id      TEXT,
+    debate_title   TEXT,
+    community_id   TEXT,
+    community_name TEXT,
+    post_id        TEXT,
+    actor_id       TEXT,
+    actor_name     TEXT,
+    actor_handle   TEXT,
+    is_read        BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at     TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_notifications_user ON notifications (user_id, created_at);
+
+CREATE TABLE IF NOT EXISTS post_impressions (
+    id        TEXT PRIMARY KEY,
+    post_id   TEXT NOT NULL,
+    user_id   TEXT NOT NULL,
+    viewed_at TIMESTAMP NOT NULL,
+    UNIQUE (post_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS communities (
+    id           TEXT PRIMARY KEY,
+    name         TEXT NOT NULL DEFAULT '',
+    description  TEXT NOT NULL DEFAULT '',
+    category     TEXT NOT NULL DEFAULT '',
+    image_url    TEXT NOT NULL DEFAULT '',
+    banner_url   TEXT NOT NULL DEFAULT '',
+    creator_id   TEXT NOT NULL DEFAULT '',
+    member_count INTEGER NOT NULL DEFAULT 0,
+    post_count   INTEGER NOT NULL DEFAULT 0,
+    created_at   TIMESTAMP NOT NULL,
+    updated_at   TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS community_members (
+    community_id   TEXT NOT NULL REFERENCES communities (id) ON DELETE CASCADE,
+    user_id        TEXT NOT NULL,
+    role           TEXT NOT NULL DEFAULT '',
+    status         TEXT NOT NULL DEFAULT '',
+    points_awarded BOOLEAN NOT NULL DEFAULT FALSE,
+    joined_at      TIMESTAMP NOT NULL,
+    PRIMARY KEY (community_id, user_id)
+);
+CREATE INDEX IF NOT EXISTS idx_community_members_user ON community_members (user_id);
+
+-- Survives leaving so join points are only ever awarded once per community
+CREATE TABLE IF NOT EXISTS community_member_history (
+    community_id TEXT NOT NULL REFERENCES communities (id) ON DELETE CASCADE,
+    user_id      TEXT NOT NULL,
+    PRIMARY KEY (community_id, user_id)
+);
@@ -0,0 +1,4 @@
+ALTER TABLE communities DROP COLUMN version;
+ALTER TABLE debates DROP COLUMN version;
+ALTER TABLE posts DROP COLUMN version;
+ALTER TABLE users DROP COLUMN version;
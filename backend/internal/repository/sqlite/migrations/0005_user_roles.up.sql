@@ -0,0 +1,2 @@
+-- Platform roles: user, moderator, admin or support
+ALTER TABLE users ADD COLUMN role TEXT NOT NULL DEFAULT 'user';
@@ -0,0 +1,10 @@
+-- Keys access tokens are signed with, sealed with the JWT secret
+CREATE TABLE IF NOT EXISTS signing_keys (
+    id           TEXT PRIMARY KEY,
+    algorithm    TEXT NOT NULL,
+    private_key  BLOB NOT NULL,
+    created_at   TIMESTAMP NOT NULL,
+    activates_at TIMESTAMP NOT NULL,
+    retires_at   TIMESTAMP NOT NULL,
+    expires_at   TIMESTAMP NOT NULL
+);
@@ -0,0 +1,18 @@
+-- Bot accounts, and scoped API keys for users and bots
+ALTER TABLE users ADD COLUMN is_bot BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ADD COLUMN bot_owner_id TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_users_bot_owner ON users (bot_owner_id) WHERE is_bot;
+ALTER TABLE posts ADD COLUMN author_is_bot BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id           TEXT PRIMARY KEY,
+    user_id      TEXT NOT NULL,
+    name         TEXT NOT NULL,
+    key_hash     TEXT NOT NULL,
+    scopes       TEXT NOT NULL DEFAULT '[]',
+    created_at   TIMESTAMP NOT NULL,
+    last_used_at TIMESTAMP,
+    expires_at   TIMESTAMP,
+    revoked_at   TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_api_keys_user ON api_keys (user_id);
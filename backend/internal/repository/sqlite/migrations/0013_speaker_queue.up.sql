@@ -0,0 +1,7 @@
+-- Speaker queue: how a debate orders its speak requests and how long a turn lasts
+ALTER TABLE debates ADD COLUMN queue_order TEXT NOT NULL DEFAULT '';
+ALTER TABLE debates ADD COLUMN max_turn_seconds INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE speak_requests ADD COLUMN side TEXT NOT NULL DEFAULT '';
+ALTER TABLE speak_requests ADD COLUMN position INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE speak_requests ADD COLUMN turn_started_at TIMESTAMP;
+ALTER TABLE speak_requests ADD COLUMN turn_ends_at TIMESTAMP;
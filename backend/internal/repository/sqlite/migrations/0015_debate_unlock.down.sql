@@ -0,0 +1 @@
+ALTER TABLE debates DROP COLUMN unlock_at;
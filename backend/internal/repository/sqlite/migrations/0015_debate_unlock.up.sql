@@ -0,0 +1,2 @@
+-- When a debate locked to early access opens to everyone
+ALTER TABLE debates ADD COLUMN unlock_at TIMESTAMP;
@@ -0,0 +1,103 @@
+package sqlite
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/yourusername/v-backend/internal/migrate"
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
+	"github.com/yourusername/v-backend/internal/repository/repotest"
+)
+
+func openMigrated(t *testing.T, path string) *sql.DB {
+	t.Helper()
+
+	db, err := Open(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	migrator, err := migrate.New(db, Migrations)
+	if err != nil {
+		t.Fatalf("load migrations: %v", err)
+	}
+	if _, err := migrator.Up(); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+func TestConformance(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) *repository.Repositories {
+		db := openMigrated(t, filepath.Join(t.TempDir(), "v.db"))
+		t.Cleanup(func() { db.Close() })
+		return NewRepositories(db)
+	})
+}
+
+func TestDataSurvivesReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "v.db")
+
+	db := openMigrated(t, path)
+	repos := NewRepositories(db)
+	for _, id := range []string{"alice", "bob"} {
+		if err := repos.User.Create(&models.User{ID: id, Handle: id}); err != nil {
+			t.Fatalf("create %s: %v", id, err)
+		}
+	}
+	if err := repos.User.Follow("alice", "bob"); err != nil {
+		t.Fatalf("follow: %v", err)
+	}
+	if err := repos.Post.Create(&models.Post{ID: "p1", AuthorID: "alice", Content: "hello"}); err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	db.Close()
+
+	db = openMigrated(t, path)
+	defer db.Close()
+	repos = NewRepositories(db)
+
+	bob, err := repos.User.GetByHandle("bob")
+	if err != nil {
+		t.Fatalf("get bob: %v", err)
+	}
+	if bob.FollowersCount != 1 {
+		t.Errorf("bob followers = %d, want 1", bob.FollowersCount)
+	}
+	if following, _ := repos.User.IsFollowing("alice", "bob"); !following {
+		t.Error("follow lost after reopen")
+	}
+	if _, err := repos.Post.GetByID("p1"); err != nil {
+		t.Errorf("post lost after reopen: %v", err)
+	}
+}
+
+func TestForeignKeysEnforced(t *testing.T) {
+	db := openMigrated(t, filepath.Join(t.TempDir(), "v.db"))
+	defer db.Close()
+	repos := NewRepositories(db)
+
+	if err := repos.Post.Create(&models.Post{ID: "p1", AuthorID: "alice"}); err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	if err := repos.Post.CreateComment(&models.Comment{ID: "c1", PostID: "p1", AuthorID: "bob"}); err != nil {
+		t.Fatalf("comment: %v", err)
+	}
+
+	// Comments cascade with their post once it is purged
+	if err := repos.Post.Delete("p1"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := repos.Post.Purge(time.Now().Add(time.Second)); err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM comments`).Scan(&count); err != nil {
+		t.Fatalf("count: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("comments = %d after post delete, want 0", count)
+	}
+}
@@ -0,0 +1,109 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/v-backend/internal/models"
+)
+
+type AnalyticsSQLRepository struct {
+	db dbtx
+}
+
+func NewAnalyticsSQLRepository(db *sql.DB) *AnalyticsSQLRepository {
+	return &AnalyticsSQLRepository{db: db}
+}
+
+func (r *AnalyticsSQLRepository) RecordImpression(postID, userID string) error {
+	// A user is only counted once per post
+	_, err := r.db.Exec(
+		`INSERT INTO post_impressions (id, post_id, user_id, viewed_at) VALUES ($1, $2, $3, $4) ON CONFLICT DO NOTHING`,
+		uuid.New().String(), postID, userID, now(),
+	)
+	return err
+}
+
+// reach counts impressions and unique non-author viewers for a post, all-time
+// and over the last 24 hours
+func (r *AnalyticsSQLRepository) reach(postID string) (reach24h, reachAll, impressions int, err error) {
+	err = r.db.QueryRow(`
+		SELECT
+			COUNT(DISTINCT CASE WHEN i.user_id <> COALESCE(p.author_id, '') AND i.viewed_at > $2 THEN i.user_id END),
+			COUNT(DISTINCT CASE WHEN i.user_id <> COALESCE(p.author_id, '') THEN i.user_id END),
+			COUNT(*)
+		FROM post_impressions i LEFT JOIN posts p ON p.id = i.post_id
+		WHERE i.post_id = $1`,
+		postID, now().Add(-24*time.Hour),
+	).Scan(&reach24h, &reachAll, &impressions)
+	return reach24h, reachAll, impressions, err
+}
+
+// interactions returns the post's engagement counters, or ok=false if it no longer exists
+func (r *AnalyticsSQLRepository) interactions(postID string) (reactions, comments, saves int, ok bool, err error) {
+	err = r.db.QueryRow(
+		`SELECT reaction_count, comment_count, save_count FROM posts WHERE id = $1`, postID,
+	).Scan(&reactions, &comments, &saves)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, 0, 0, false, nil
+	}
+	return reactions, comments, saves, err == nil, err
+}
+
+func (r *AnalyticsSQLRepository) GetPostMetrics(postID string) (*models.PostMetrics, error) {
+	analytics, err := r.GetPostAnalytics(postID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.PostMetrics{
+		Reach24h:    analytics.Reach24h,
+		ReachAll:    analytics.ReachAll,
+		Impressions: analytics.Impressions,
+		Engagement:  analytics.Engagement,
+	}, nil
+}
+
+func (r *AnalyticsSQLRepository) GetPostAnalytics(postID string) (*models.PostAnalytics, error) {
+	reach24h, reachAll, impressions, err := r.reach(postID)
+	if err != nil {
+		return nil, err
+	}
+
+	reactions, comments, saves, ok, err := r.interactions(postID)
+	if err != nil {
+		return nil, err
+	}
+
+	analytics := &models.PostAnalytics{
+		PostID:      postID,
+		Reach24h:    reach24h,
+		ReachAll:    reachAll,
+		Impressions: impressions,
+	}
+	if !ok {
+		return analytics, nil
+	}
+
+	analytics.Reactions = reactions
+	analytics.Comments = comments
+	analytics.Saves = saves
+
+	// Calculate engagement rate
+	if reachAll > 0 {
+		analytics.Engagement = float64(reactions+comments+saves) / float64(reachAll) * 100
+	}
+	return analytics, nil
+}
+
+func (r *AnalyticsSQLRepository) GetUniqueViewers24h(postID string) (int, error) {
+	reach24h, _, _, err := r.reach(postID)
+	return reach24h, err
+}
+
+func (r *AnalyticsSQLRepository) GetUniqueViewersAll(postID string) (int, error) {
+	_, reachAll, _, err := r.reach(postID)
+	return reachAll, err
+}
@@ -0,0 +1,99 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/yourusername/v-backend/internal/models"
+)
+
+type APIKeySQLRepository struct {
+	db dbtx
+}
+
+func NewAPIKeySQLRepository(db *sql.DB) *APIKeySQLRepository {
+	return &APIKeySQLRepository{db: db}
+}
+
+const apiKeyColumns = `id, user_id, name, key_hash, scopes, created_at, last_used_at, expires_at, revoked_at`
+
+func scanAPIKey(row scanner) (*models.APIKey, error) {
+	key := &models.APIKey{}
+	var scopes []byte
+	err := row.Scan(
+		&key.ID, &key.UserID, &key.Name, &key.KeyHash, &scopes, &key.CreatedAt,
+		&key.LastUsedAt, &key.ExpiresAt, &key.RevokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := fromJSON(scopes, &key.Scopes); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (r *APIKeySQLRepository) Create(key *models.APIKey) error {
+	scopes, err := toJSON(key.Scopes)
+	if err != nil {
+		return err
+	}
+
+	key.CreatedAt = now()
+	_, err = r.db.Exec(
+		`INSERT INTO api_keys (id, user_id, name, key_hash, scopes, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		key.ID, key.UserID, key.Name, key.KeyHash, scopes, key.CreatedAt, utcPtr(key.ExpiresAt),
+	)
+	return err
+}
+
+func (r *APIKeySQLRepository) GetByID(id string) (*models.APIKey, error) {
+	key, err := scanAPIKey(r.db.QueryRow(`SELECT `+apiKeyColumns+` FROM api_keys WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("API key not found")
+	}
+	return key, err
+}
+
+func (r *APIKeySQLRepository) ListByUser(userID string) ([]*models.APIKey, error) {
+	rows, err := r.db.Query(
+		`SELECT `+apiKeyColumns+` FROM api_keys
+		WHERE user_id = $1 AND revoked_at IS NULL ORDER BY created_at DESC, id DESC`,
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := make([]*models.APIKey, 0)
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+func (r *APIKeySQLRepository) Touch(id string, at time.Time) error {
+	_, err := r.db.Exec(`UPDATE api_keys SET last_used_at = $1 WHERE id = $2`, at.UTC(), id)
+	return err
+}
+
+func (r *APIKeySQLRepository) Revoke(userID, id string) error {
+	res, err := r.db.Exec(
+		`UPDATE api_keys SET revoked_at = $1 WHERE id = $2 AND user_id = $3 AND revoked_at IS NULL`,
+		now(), id, userID,
+	)
+	if err != nil {
+		return err
+	}
+	if ok, err := rowsAffected(res); err != nil || ok {
+		return err
+	}
+	return errors.New("API key not found")
+}
@@ -0,0 +1,93 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/yourusername/v-backend/internal/models"
+)
+
+type AuthSQLRepository struct {
+	db dbtx
+}
+
+func NewAuthSQLRepository(db *sql.DB) *AuthSQLRepository {
+	return &AuthSQLRepository{db: db}
+}
+
+const authColumns = `user_id, email, password_hash, created_at, updated_at`
+
+func scanAuth(row scanner) (*models.Auth, error) {
+	auth := &models.Auth{}
+	if err := row.Scan(&auth.UserID, &auth.Email, &auth.PasswordHash, &auth.CreatedAt, &auth.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return auth, nil
+}
+
+func (r *AuthSQLRepository) CreateAuth(auth *models.Auth) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		var exists bool
+		if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM auth WHERE user_id = $1)`, auth.UserID).Scan(&exists); err != nil {
+			return err
+		}
+		if exists {
+			return errors.New("auth already exists for this user")
+		}
+
+		createdAt := now()
+		res, err := tx.Exec(
+			`INSERT INTO auth (`+authColumns+`) VALUES ($1, $2, $3, $4, $5) ON CONFLICT DO NOTHING`,
+			auth.UserID, auth.Email, auth.PasswordHash, createdAt, createdAt,
+		)
+		if err != nil {
+			return err
+		}
+		if ok, err := rowsAffected(res); err != nil {
+			return err
+		} else if !ok {
+			return errors.New("email already exists")
+		}
+
+		auth.CreatedAt = createdAt
+		auth.UpdatedAt = createdAt
+		return nil
+	})
+}
+
+func (r *AuthSQLRepository) GetByEmail(email string) (*models.Auth, error) {
+	auth, err := scanAuth(r.db.QueryRow(`SELECT `+authColumns+` FROM auth WHERE email = $1`, email))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("auth not found")
+	}
+	return auth, err
+}
+
+func (r *AuthSQLRepository) GetByUserID(userID string) (*models.Auth, error) {
+	auth, err := scanAuth(r.db.QueryRow(`SELECT `+authColumns+` FROM auth WHERE user_id = $1`, userID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("auth not found")
+	}
+	return auth, err
+}
+
+func (r *AuthSQLRepository) UpdatePassword(userID, newPasswordHash string) error {
+	res, err := r.db.Exec(
+		`UPDATE auth SET password_hash = $1, updated_at = $2 WHERE user_id = $3`,
+		newPasswordHash, now(), userID,
+	)
+	if err != nil {
+		return err
+	}
+	if ok, err := rowsAffected(res); err != nil {
+		return err
+	} else if !ok {
+		return errors.New("auth not found")
+	}
+	return nil
+}
+
+func (r *AuthSQLRepository) DeleteAuth(userID string) error {
+	_, err := r.db.Exec(`DELETE FROM auth WHERE user_id = $1`, userID)
+	return err
+}
@@ -0,0 +1,256 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/yourusername/v-backend/internal/models"
+)
+
+type CommunitySQLRepository struct {
+	db dbtx
+}
+
+func NewCommunitySQLRepository(db *sql.DB) *CommunitySQLRepository {
+	return &CommunitySQLRepository{db: db}
+}
+
+const communityColumns = `c.id, c.name, c.description, c.category, c.image_url, c.banner_url, c.creator_id,
+	c.member_count, c.post_count, c.created_at, c.updated_at, c.version, c.deleted_at`
+
+const memberColumns = `community_id, user_id, role, status, points_awarded, joined_at`
+
+func scanCommunity(row scanner) (*models.Community, error) {
+	c := &models.Community{}
+	if err := row.Scan(
+		&c.ID, &c.Name, &c.Description, &c.Category, &c.ImageURL, &c.BannerURL, &c.CreatorID,
+		&c.MemberCount, &c.PostCount, &c.CreatedAt, &c.UpdatedAt, &c.Version, &c.DeletedAt,
+	); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func scanCommunities(rows *sql.Rows) ([]*models.Community, error) {
+	defer rows.Close()
+
+	communities := make([]*models.Community, 0)
+	for rows.Next() {
+		c, err := scanCommunity(rows)
+		if err != nil {
+			return nil, err
+		}
+		communities = append(communities, c)
+	}
+	return communities, rows.Err()
+}
+
+func scanMember(row scanner) (*models.CommunityMember, error) {
+	m := &models.CommunityMember{}
+	if err := row.Scan(&m.CommunityID, &m.UserID, &m.Role, &m.Status, &m.PointsAwarded, &m.JoinedAt); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (r *CommunitySQLRepository) Create(c *models.Community) error {
+	res, err := r.db.Exec(`
+		INSERT INTO communities (id, name, description, category, image_url, banner_url, creator_id,
+			member_count, post_count, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) ON CONFLICT DO NOTHING`,
+		c.ID, c.Name, c.Description, string(c.Category), c.ImageURL, c.BannerURL, c.CreatorID,
+		c.MemberCount, c.PostCount, c.CreatedAt.UTC(), c.UpdatedAt.UTC(),
+	)
+	if err != nil {
+		return err
+	}
+	if ok, err := rowsAffected(res); err != nil {
+		return err
+	} else if !ok {
+		return errors.New("community already exists")
+	}
+
+	c.Version = 1
+	return nil
+}
+
+func (r *CommunitySQLRepository) GetByID(id string) (*models.Community, error) {
+	c, err := scanCommunity(r.db.QueryRow(`SELECT `+communityColumns+` FROM communities c WHERE c.id = $1 AND c.deleted_at IS NULL`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("community not found")
+	}
+	return c, err
+}
+
+func (r *CommunitySQLRepository) GetDeletedByID(id string) (*models.Community, error) {
+	c, err := scanCommunity(r.db.QueryRow(`SELECT `+communityColumns+` FROM communities c WHERE c.id = $1 AND c.deleted_at IS NOT NULL`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("deleted community not found")
+	}
+	return c, err
+}
+
+func (r *CommunitySQLRepository) Update(c *models.Community) error {
+	res, err := r.db.Exec(`
+		UPDATE communities SET name = $2, description = $3, category = $4, image_url = $5, banner_url = $6,
+			creator_id = $7, member_count = $8, post_count = $9, created_at = $10, updated_at = $11,
+			version = version + 1
+		WHERE id = $1 AND version = $12`,
+		c.ID, c.Name, c.Description, string(c.Category), c.ImageURL, c.BannerURL, c.CreatorID,
+		c.MemberCount, c.PostCount, c.CreatedAt.UTC(), c.UpdatedAt.UTC(), c.Version,
+	)
+	if err != nil {
+		return err
+	}
+	if ok, err := rowsAffected(res); err != nil {
+		return err
+	} else if !ok {
+		return updateConflict(r.db, "communities", "community", c.ID)
+	}
+
+	c.Version++
+	return nil
+}
+
+func (r *CommunitySQLRepository) List() ([]*models.Community, error) {
+	rows, err := r.db.Query(`SELECT ` + communityColumns + ` FROM communities c WHERE c.deleted_at IS NULL ORDER BY c.created_at, c.id`)
+	if err != nil {
+		return nil, err
+	}
+	return scanCommunities(rows)
+}
+
+func (r *CommunitySQLRepository) Delete(id string) error {
+	// Members and join history are kept so a restore brings them back
+	_, err := r.db.Exec(
+		`UPDATE communities SET deleted_at = $2, version = version + 1 WHERE id = $1 AND deleted_at IS NULL`,
+		id, now(),
+	)
+	return err
+}
+
+func (r *CommunitySQLRepository) Restore(id string, deletedSince time.Time) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		if err := checkRestorable(tx, "communities", "community", id, deletedSince); err != nil {
+			return err
+		}
+		_, err := tx.Exec(`UPDATE communities SET deleted_at = NULL, version = version + 1 WHERE id = $1`, id)
+		return err
+	})
+}
+
+func (r *CommunitySQLRepository) Purge(cutoff time.Time) (int, error) {
+	// Members and join history cascade
+	res, err := r.db.Exec(`DELETE FROM communities WHERE deleted_at < $1`, cutoff.UTC())
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+func (r *CommunitySQLRepository) AddMember(m *models.CommunityMember) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`
+			INSERT INTO community_members (`+memberColumns+`) VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (community_id, user_id) DO UPDATE SET role = excluded.role, status = excluded.status,
+				points_awarded = excluded.points_awarded, joined_at = excluded.joined_at`,
+			m.CommunityID, m.UserID, string(m.Role), m.Status, m.PointsAwarded, m.JoinedAt.UTC(),
+		); err != nil {
+			return err
+		}
+
+		// Update history
+		_, err := tx.Exec(
+			`INSERT INTO community_member_history (community_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+			m.CommunityID, m.UserID,
+		)
+		return err
+	})
+}
+
+func (r *CommunitySQLRepository) RemoveMember(communityID, userID string) error {
+	res, err := r.db.Exec(`DELETE FROM community_members WHERE community_id = $1 AND user_id = $2`, communityID, userID)
+	if err != nil {
+		return err
+	}
+	if ok, err := rowsAffected(res); err != nil {
+		return err
+	} else if !ok {
+		return errors.New("member not found")
+	}
+	return nil
+}
+
+func (r *CommunitySQLRepository) GetMember(communityID, userID string) (*models.CommunityMember, error) {
+	m, err := scanMember(r.db.QueryRow(
+		`SELECT `+memberColumns+` FROM community_members WHERE community_id = $1 AND user_id = $2`,
+		communityID, userID,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("member not found")
+	}
+	return m, err
+}
+
+func (r *CommunitySQLRepository) GetMembers(communityID string) ([]*models.CommunityMember, error) {
+	rows, err := r.db.Query(
+		`SELECT `+memberColumns+` FROM community_members WHERE community_id = $1 ORDER BY joined_at, user_id`,
+		communityID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := make([]*models.CommunityMember, 0)
+	for rows.Next() {
+		m, err := scanMember(rows)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, m)
+	}
+	return members, rows.Err()
+}
+
+func (r *CommunitySQLRepository) HasJoinedBefore(communityID, userID string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(
+		`SELECT EXISTS (SELECT 1 FROM community_member_history WHERE community_id = $1 AND user_id = $2)`,
+		communityID, userID,
+	).Scan(&exists)
+	return exists, err
+}
+
+func (r *CommunitySQLRepository) UpdateMember(member *models.CommunityMember) error {
+	res, err := r.db.Exec(`
+		UPDATE community_members SET role = $1, status = $2, points_awarded = $3, joined_at = $4
+		WHERE community_id = $5 AND user_id = $6`,
+		string(member.Role), member.Status, member.PointsAwarded, member.JoinedAt.UTC(),
+		member.CommunityID, member.UserID,
+	)
+	if err != nil {
+		return err
+	}
+	if ok, err := rowsAffected(res); err != nil {
+		return err
+	} else if !ok {
+		return errors.New("member not found")
+	}
+	return nil
+}
+
+func (r *CommunitySQLRepository) GetJoinedCommunities(userID string) ([]*models.Community, error) {
+	rows, err := r.db.Query(`
+		SELECT `+communityColumns+` FROM community_members m JOIN communities c ON c.id = m.community_id
+		WHERE m.user_id = $1 AND m.status = 'active' AND c.deleted_at IS NULL
+		ORDER BY m.joined_at, c.id`,
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return scanCommunities(rows)
+}
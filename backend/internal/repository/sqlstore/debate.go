@@ -0,0 +1,561 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
+)
+
+type DebateSQLRepository struct {
+	db dbtx
+}
+
+func NewDebateSQLRepository(db *sql.DB) *DebateSQLRepository {
+	return &DebateSQLRepository{db: db}
+}
+
+const debateColumns = `id, title, description, category, host_id, type, status, start_time, end_time,
+	duration_minutes, show_in_pulse, agree_count, disagree_count, is_locked, unlock_phase,
+	early_access_roles, format, current_round, round_started_at, queue_order, max_turn_seconds,
+	poll_closes_at, result, unlock_at, created_at, updated_at, version`
+
+const participantColumns = `id, debate_id, user_id, role, side, is_self_muted, is_muted_by_host, joined_at, left_at`
+
+const voteColumns = `debate_id, user_id, phase, side, created_at`
+
+const speakRequestColumns = `id, debate_id, user_id, side, status, position, turn_started_at, turn_ends_at, created_at`
+
+func scanDebate(row scanner) (*models.Debate, error) {
+	debate := &models.Debate{}
+	var roles, result []byte
+	err := row.Scan(
+		&debate.ID, &debate.Title, &debate.Description, &debate.Category, &debate.HostID, &debate.Type,
+		&debate.Status, &debate.StartTime, &debate.EndTime, &debate.DurationMinutes, &debate.ShowInPulse,
+		&debate.AgreeCount, &debate.DisagreeCount, &debate.IsLocked, &debate.UnlockPhase, &roles,
+		&debate.Format, &debate.CurrentRound, &debate.RoundStartedAt, &debate.QueueOrder, &debate.MaxTurnSeconds,
+		&debate.PollClosesAt, &result, &debate.UnlockAt, &debate.CreatedAt, &debate.UpdatedAt, &debate.Version,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := fromJSON(roles, &debate.EarlyAccessRoles); err != nil {
+		return nil, err
+	}
+	if err := fromJSON(result, &debate.Result); err != nil {
+		return nil, err
+	}
+	return debate, nil
+}
+
+// debateResult encodes a debate's result, leaving the column NULL until it is
+// decided
+func debateResult(debate *models.Debate) (any, error) {
+	if debate.Result == nil {
+		return nil, nil
+	}
+	return toJSON(debate.Result)
+}
+
+func scanParticipant(row scanner) (*models.DebateParticipant, error) {
+	p := &models.DebateParticipant{}
+	if err := row.Scan(
+		&p.ID, &p.DebateID, &p.UserID, &p.Role, &p.Side, &p.IsSelfMuted, &p.IsMutedByHost, &p.JoinedAt, &p.LeftAt,
+	); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func scanSpeakRequest(row scanner) (*models.SpeakRequest, error) {
+	req := &models.SpeakRequest{}
+	if err := row.Scan(
+		&req.ID, &req.DebateID, &req.UserID, &req.Side, &req.Status, &req.Position, &req.TurnStartedAt, &req.TurnEndsAt, &req.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func (r *DebateSQLRepository) Create(debate *models.Debate) error {
+	roles, err := toJSON(debate.EarlyAccessRoles)
+	if err != nil {
+		return err
+	}
+	result, err := debateResult(debate)
+	if err != nil {
+		return err
+	}
+
+	createdAt := now()
+	res, err := r.db.Exec(`
+		INSERT INTO debates (`+debateColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, 1)
+		ON CONFLICT DO NOTHING`,
+		debate.ID, debate.Title, debate.Description, debate.Category, debate.HostID, debate.Type,
+		debate.Status, debate.StartTime.UTC(), utcPtr(debate.EndTime), debate.DurationMinutes, debate.ShowInPulse,
+		debate.AgreeCount, debate.DisagreeCount, debate.IsLocked, debate.UnlockPhase, roles,
+		debate.Format, debate.CurrentRound, utcPtr(debate.RoundStartedAt), debate.QueueOrder, debate.MaxTurnSeconds,
+		utcPtr(debate.PollClosesAt), result, utcPtr(debate.UnlockAt), createdAt, createdAt,
+	)
+	if err != nil {
+		return err
+	}
+	if ok, err := rowsAffected(res); err != nil {
+		return err
+	} else if !ok {
+		return errors.New("debate already exists")
+	}
+
+	debate.CreatedAt = createdAt
+	debate.UpdatedAt = createdAt
+	debate.Version = 1
+	return nil
+}
+
+func (r *DebateSQLRepository) GetByID(id string) (*models.Debate, error) {
+	debate, err := scanDebate(r.db.QueryRow(`SELECT `+debateColumns+` FROM debates WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("debate not found")
+	}
+	return debate, err
+}
+
+func (r *DebateSQLRepository) Update(debate *models.Debate) error {
+	roles, err := toJSON(debate.EarlyAccessRoles)
+	if err != nil {
+		return err
+	}
+	result, err := debateResult(debate)
+	if err != nil {
+		return err
+	}
+
+	updatedAt := now()
+	res, err := r.db.Exec(`
+		UPDATE debates SET title = $2, description = $3, category = $4, host_id = $5, type = $6,
+			status = $7, start_time = $8, end_time = $9, duration_minutes = $10, show_in_pulse = $11,
+			agree_count = $12, disagree_count = $13, is_locked = $14, unlock_phase = $15,
+			early_access_roles = $16, format = $17, current_round = $18, round_started_at = $19,
+			queue_order = $20, max_turn_seconds = $21, poll_closes_at = $22, result = $23,
+			unlock_at = $24, updated_at = $25, version = version + 1
+		WHERE id = $1 AND version = $26`,
+		debate.ID, debate.Title, debate.Description, debate.Category, debate.HostID, debate.Type,
+		debate.Status, debate.StartTime.UTC(), utcPtr(debate.EndTime), debate.DurationMinutes, debate.ShowInPulse,
+		debate.AgreeCount, debate.DisagreeCount, debate.IsLocked, debate.UnlockPhase, roles,
+		debate.Format, debate.CurrentRound, utcPtr(debate.RoundStartedAt), debate.QueueOrder, debate.MaxTurnSeconds,
+		utcPtr(debate.PollClosesAt), result, utcPtr(debate.UnlockAt), updatedAt, debate.Version,
+	)
+	if err != nil {
+		return err
+	}
+	if ok, err := rowsAffected(res); err != nil {
+		return err
+	} else if !ok {
+		return updateConflict(r.db, "debates", "debate", debate.ID)
+	}
+
+	debate.Version++
+	debate.UpdatedAt = updatedAt
+	return nil
+}
+
+func (r *DebateSQLRepository) Delete(id string) error {
+	// Participants, speak requests and votes cascade
+	_, err := r.db.Exec(`DELETE FROM debates WHERE id = $1`, id)
+	return err
+}
+
+func (r *DebateSQLRepository) List(status string, page repository.Page) ([]*models.Debate, string, error) {
+	query, args, err := keyset(`
+		SELECT `+debateColumns+` FROM debates
+		WHERE ($1 = '' OR status = $1)`,
+		[]any{status}, page, "created_at", "id", true,
+	)
+	if err != nil {
+		return nil, "", err
+	}
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	debates := make([]*models.Debate, 0)
+	for rows.Next() {
+		debate, err := scanDebate(rows)
+		if err != nil {
+			return nil, "", err
+		}
+		debates = append(debates, debate)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	debates, next := nextPage(debates, page, func(d *models.Debate) (time.Time, string) {
+		return d.CreatedAt, d.ID
+	})
+	return debates, next, nil
+}
+
+func (r *DebateSQLRepository) ListPending() ([]*models.Debate, error) {
+	rows, err := r.db.Query(`
+		SELECT ` + debateColumns + ` FROM debates
+		WHERE status IN ('SCHEDULED', 'ACTIVE')
+			OR (status = 'ENDED' AND poll_closes_at IS NOT NULL AND result IS NULL)
+		ORDER BY start_time, id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	debates := make([]*models.Debate, 0)
+	for rows.Next() {
+		debate, err := scanDebate(rows)
+		if err != nil {
+			return nil, err
+		}
+		debates = append(debates, debate)
+	}
+	return debates, rows.Err()
+}
+
+// ClearAll removes all debates, participants, and speak requests
+func (r *DebateSQLRepository) ClearAll() error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		for _, table := range []string{"debate_votes", "speak_requests", "debate_participants", "debates"} {
+			if _, err := tx.Exec(`DELETE FROM ` + table); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// adjustSideCount moves a debate's agree/disagree tally by delta for the given side
+func adjustSideCount(tx *sql.Tx, debateID, side string, delta int) error {
+	var column string
+	switch strings.ToLower(strings.TrimSpace(side)) {
+	case "agree":
+		column = "agree_count"
+	case "disagree":
+		column = "disagree_count"
+	default:
+		return nil
+	}
+
+	if delta < 0 {
+		_, err := tx.Exec(`UPDATE debates SET `+column+` = `+column+` - 1, version = version + 1 WHERE id = $1 AND `+column+` > 0`, debateID)
+		return err
+	}
+	_, err := tx.Exec(`UPDATE debates SET `+column+` = `+column+` + 1, version = version + 1 WHERE id = $1`, debateID)
+	return err
+}
+
+func (r *DebateSQLRepository) AddParticipant(participant *models.DebateParticipant) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		var exists bool
+		if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM debates WHERE id = $1)`, participant.DebateID).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			return errors.New("debate not found")
+		}
+
+		existing, err := scanParticipant(tx.QueryRow(
+			`SELECT `+participantColumns+` FROM debate_participants WHERE debate_id = $1 AND user_id = $2`,
+			participant.DebateID, participant.UserID,
+		))
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		if existing != nil {
+			if existing.LeftAt != nil {
+				// User is rejoining - clear LeftAt, update JoinedAt and reset host mute
+				if _, err := tx.Exec(`
+					UPDATE debate_participants SET left_at = NULL, joined_at = $1, side = $2,
+						is_self_muted = $3, is_muted_by_host = FALSE
+					WHERE id = $4`,
+					now(), participant.Side, participant.IsSelfMuted, existing.ID,
+				); err != nil {
+					return err
+				}
+				log.Printf("[AddParticipant] Rejoining participant: debateID=%s, userId=%s, side=%s", participant.DebateID, participant.UserID, participant.Side)
+				return adjustSideCount(tx, participant.DebateID, participant.Side, 1)
+			}
+
+			// User exists and hasn't left. Update side if changed.
+			if existing.Side == participant.Side {
+				return nil
+			}
+			if _, err := tx.Exec(`UPDATE debate_participants SET side = $1 WHERE id = $2`, participant.Side, existing.ID); err != nil {
+				return err
+			}
+			log.Printf("[AddParticipant] Side switch: debateID=%s, userId=%s, %s -> %s", participant.DebateID, participant.UserID, existing.Side, participant.Side)
+			if err := adjustSideCount(tx, participant.DebateID, existing.Side, -1); err != nil {
+				return err
+			}
+			return adjustSideCount(tx, participant.DebateID, participant.Side, 1)
+		}
+
+		if participant.JoinedAt.IsZero() {
+			participant.JoinedAt = time.Now()
+		}
+		if participant.ID == "" {
+			participant.ID = uuid.New().String()
+		}
+		if _, err := tx.Exec(`
+			INSERT INTO debate_participants (`+participantColumns+`)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+			participant.ID, participant.DebateID, participant.UserID, participant.Role, participant.Side,
+			participant.IsSelfMuted, participant.IsMutedByHost, participant.JoinedAt.UTC(), utcPtr(participant.LeftAt),
+		); err != nil {
+			return err
+		}
+		log.Printf("[AddParticipant] New participant added: debateID=%s, userId=%s, side=%s", participant.DebateID, participant.UserID, participant.Side)
+		return adjustSideCount(tx, participant.DebateID, participant.Side, 1)
+	})
+}
+
+func (r *DebateSQLRepository) RemoveParticipant(debateID, userID string) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		var exists bool
+		if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM debates WHERE id = $1)`, debateID).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			return errors.New("debate not found")
+		}
+
+		var side string
+		err := tx.QueryRow(
+			`DELETE FROM debate_participants WHERE debate_id = $1 AND user_id = $2 RETURNING side`,
+			debateID, userID,
+		).Scan(&side)
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.New("participant not found")
+		}
+		if err != nil {
+			return err
+		}
+
+		return adjustSideCount(tx, debateID, side, -1)
+	})
+}
+
+func (r *DebateSQLRepository) listParticipants(debateID string, activeOnly bool) ([]*models.DebateParticipant, error) {
+	var exists bool
+	if err := r.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM debates WHERE id = $1)`, debateID).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.New("debate not found")
+	}
+
+	query := `SELECT ` + participantColumns + ` FROM debate_participants WHERE debate_id = $1`
+	if activeOnly {
+		query += ` AND left_at IS NULL`
+	}
+	rows, err := r.db.Query(query+` ORDER BY joined_at, id`, debateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	participants := make([]*models.DebateParticipant, 0)
+	for rows.Next() {
+		p, err := scanParticipant(rows)
+		if err != nil {
+			return nil, err
+		}
+		participants = append(participants, p)
+	}
+	return participants, rows.Err()
+}
+
+func (r *DebateSQLRepository) GetParticipants(debateID string) ([]*models.DebateParticipant, error) {
+	return r.listParticipants(debateID, true)
+}
+
+// GetAllParticipants returns all participants including those who left (for internal use)
+func (r *DebateSQLRepository) GetAllParticipants(debateID string) ([]*models.DebateParticipant, error) {
+	return r.listParticipants(debateID, false)
+}
+
+func (r *DebateSQLRepository) UpdateParticipant(participant *models.DebateParticipant) error {
+	var exists bool
+	if err := r.db.QueryRow(
+		`SELECT EXISTS (SELECT 1 FROM debate_participants WHERE debate_id = $1)`, participant.DebateID,
+	).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return errors.New("debate not found")
+	}
+
+	res, err := r.db.Exec(`
+		UPDATE debate_participants SET role = $1, side = $2, is_self_muted = $3, is_muted_by_host = $4,
+			joined_at = $5, left_at = $6
+		WHERE debate_id = $7 AND user_id = $8`,
+		participant.Role, participant.Side, participant.IsSelfMuted, participant.IsMutedByHost,
+		participant.JoinedAt.UTC(), utcPtr(participant.LeftAt), participant.DebateID, participant.UserID,
+	)
+	if err != nil {
+		return err
+	}
+	if ok, err := rowsAffected(res); err != nil {
+		return err
+	} else if !ok {
+		return errors.New("participant not found")
+	}
+	return nil
+}
+
+func (r *DebateSQLRepository) CreateSpeakRequest(request *models.SpeakRequest) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		var exists bool
+		if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM debates WHERE id = $1)`, request.DebateID).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			return errors.New("debate not found")
+		}
+
+		createdAt := now()
+		res, err := tx.Exec(
+			`INSERT INTO speak_requests (`+speakRequestColumns+`) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) ON CONFLICT DO NOTHING`,
+			request.ID, request.DebateID, request.UserID, request.Side, request.Status, request.Position,
+			utcPtr(request.TurnStartedAt), utcPtr(request.TurnEndsAt), createdAt,
+		)
+		if err != nil {
+			return err
+		}
+		if ok, err := rowsAffected(res); err != nil {
+			return err
+		} else if !ok {
+			return errors.New("speak request already exists")
+		}
+
+		request.CreatedAt = createdAt
+		return nil
+	})
+}
+
+func (r *DebateSQLRepository) UpdateSpeakRequest(request *models.SpeakRequest) error {
+	// Who asked, for which debate and from which side never changes
+	res, err := r.db.Exec(
+		`UPDATE speak_requests SET status = $2, position = $3, turn_started_at = $4, turn_ends_at = $5 WHERE id = $1`,
+		request.ID, request.Status, request.Position, utcPtr(request.TurnStartedAt), utcPtr(request.TurnEndsAt),
+	)
+	if err != nil {
+		return err
+	}
+	if ok, err := rowsAffected(res); err != nil {
+		return err
+	} else if !ok {
+		return errors.New("speak request not found")
+	}
+	return nil
+}
+
+func (r *DebateSQLRepository) GetSpeakRequest(id string) (*models.SpeakRequest, error) {
+	req, err := scanSpeakRequest(r.db.QueryRow(`SELECT `+speakRequestColumns+` FROM speak_requests WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("speak request not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func (r *DebateSQLRepository) GetSpeakRequests(debateID string) ([]*models.SpeakRequest, error) {
+	var exists bool
+	if err := r.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM debates WHERE id = $1)`, debateID).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.New("debate not found")
+	}
+
+	rows, err := r.db.Query(
+		`SELECT `+speakRequestColumns+` FROM speak_requests WHERE debate_id = $1 ORDER BY created_at, id`,
+		debateID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	requests := make([]*models.SpeakRequest, 0)
+	for rows.Next() {
+		req, err := scanSpeakRequest(rows)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, req)
+	}
+	return requests, rows.Err()
+}
+
+func (r *DebateSQLRepository) DeleteSpeakRequest(id string) error {
+	_, err := r.db.Exec(`DELETE FROM speak_requests WHERE id = $1`, id)
+	return err
+}
+
+func (r *DebateSQLRepository) CastVote(vote *models.DebateVote) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		var exists bool
+		if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM debates WHERE id = $1)`, vote.DebateID).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			return errors.New("debate not found")
+		}
+
+		createdAt := now()
+		res, err := tx.Exec(
+			`INSERT INTO debate_votes (`+voteColumns+`) VALUES ($1, $2, $3, $4, $5) ON CONFLICT DO NOTHING`,
+			vote.DebateID, vote.UserID, vote.Phase, vote.Side, createdAt,
+		)
+		if err != nil {
+			return err
+		}
+		if ok, err := rowsAffected(res); err != nil {
+			return err
+		} else if !ok {
+			return repository.ErrAlreadyVoted
+		}
+
+		vote.CreatedAt = createdAt
+		return nil
+	})
+}
+
+func (r *DebateSQLRepository) GetVotes(debateID string) ([]*models.DebateVote, error) {
+	rows, err := r.db.Query(
+		`SELECT `+voteColumns+` FROM debate_votes WHERE debate_id = $1 ORDER BY created_at, user_id`,
+		debateID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	votes := make([]*models.DebateVote, 0)
+	for rows.Next() {
+		vote := &models.DebateVote{}
+		if err := rows.Scan(&vote.DebateID, &vote.UserID, &vote.Phase, &vote.Side, &vote.CreatedAt); err != nil {
+			return nil, err
+		}
+		votes = append(votes, vote)
+	}
+	return votes, rows.Err()
+}
@@ -0,0 +1,120 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/yourusername/v-backend/internal/models"
+)
+
+type DebateStatsSQLRepository struct {
+	db dbtx
+}
+
+func NewDebateStatsSQLRepository(db *sql.DB) *DebateStatsSQLRepository {
+	return &DebateStatsSQLRepository{db: db}
+}
+
+const debateStatsColumns = `topic, total_participants, total_agree, total_disagree, sessions_count, last_updated`
+
+// normalizeTopic normalizes a topic string to use as a key
+func normalizeTopic(topic string) string {
+	return strings.TrimSpace(strings.ToLower(topic))
+}
+
+func scanDebateStats(row scanner) (*models.DebateTopicStats, error) {
+	stats := &models.DebateTopicStats{}
+	if err := row.Scan(
+		&stats.Topic, &stats.TotalParticipants, &stats.TotalAgree, &stats.TotalDisagree,
+		&stats.SessionsCount, &stats.LastUpdated,
+	); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+func (r *DebateStatsSQLRepository) RecordStats(topic string, agreeCount, disagreeCount, participants int, debateID string) (*models.DebateTopicStats, error) {
+	key := normalizeTopic(topic)
+	var result *models.DebateTopicStats
+
+	err := withTx(r.db, func(tx *sql.Tx) error {
+		// Each debate contributes to the totals only once
+		if debateID != "" {
+			res, err := tx.Exec(
+				`INSERT INTO debate_stats_recorded (debate_id, recorded_at) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+				debateID, now(),
+			)
+			if err != nil {
+				return err
+			}
+			if ok, err := rowsAffected(res); err != nil {
+				return err
+			} else if !ok {
+				existing, err := scanDebateStats(tx.QueryRow(
+					`SELECT `+debateStatsColumns+` FROM debate_topic_stats WHERE topic_key = $1`, key,
+				))
+				if err == nil {
+					log.Printf("[DebateStats] Debate %s already recorded, returning existing stats", debateID)
+					result = existing
+					return nil
+				}
+				if !errors.Is(err, sql.ErrNoRows) {
+					return err
+				}
+			}
+		}
+
+		var err error
+		result, err = scanDebateStats(tx.QueryRow(`
+			INSERT INTO debate_topic_stats (topic_key, topic, total_participants, total_agree, total_disagree,
+				sessions_count, last_updated)
+			VALUES ($1, $2, $3, $4, $5, 1, $6)
+			ON CONFLICT (topic_key) DO UPDATE SET
+				total_participants = debate_topic_stats.total_participants + excluded.total_participants,
+				total_agree = debate_topic_stats.total_agree + excluded.total_agree,
+				total_disagree = debate_topic_stats.total_disagree + excluded.total_disagree,
+				sessions_count = debate_topic_stats.sessions_count + 1,
+				last_updated = excluded.last_updated
+			RETURNING `+debateStatsColumns,
+			key, strings.TrimSpace(topic), participants, agreeCount, disagreeCount, now(),
+		))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[DebateStats] Recorded stats: topic=%s, agree=%d, disagree=%d, sessions=%d",
+		result.Topic, result.TotalAgree, result.TotalDisagree, result.SessionsCount)
+	return result, nil
+}
+
+func (r *DebateStatsSQLRepository) GetAllStats() ([]*models.DebateTopicStats, error) {
+	rows, err := r.db.Query(`SELECT ` + debateStatsColumns + ` FROM debate_topic_stats ORDER BY topic_key`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make([]*models.DebateTopicStats, 0)
+	for rows.Next() {
+		stat, err := scanDebateStats(rows)
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, stat)
+	}
+	return stats, rows.Err()
+}
+
+func (r *DebateStatsSQLRepository) GetByTopic(topic string) (*models.DebateTopicStats, error) {
+	stats, err := scanDebateStats(r.db.QueryRow(
+		`SELECT `+debateStatsColumns+` FROM debate_topic_stats WHERE topic_key = $1`, normalizeTopic(topic),
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("stats not found for topic")
+	}
+	return stats, err
+}
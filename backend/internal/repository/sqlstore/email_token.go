@@ -0,0 +1,61 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
+)
+
+type EmailTokenSQLRepository struct {
+	db dbtx
+}
+
+func NewEmailTokenSQLRepository(db *sql.DB) *EmailTokenSQLRepository {
+	return &EmailTokenSQLRepository{db: db}
+}
+
+func (r *EmailTokenSQLRepository) Create(token *models.EmailToken) error {
+	createdAt := now()
+	_, err := r.db.Exec(
+		`INSERT INTO email_tokens (id, user_id, purpose, email, token_hash, expires_at, created_at, used_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NULL)`,
+		token.ID, token.UserID, string(token.Purpose), token.Email, token.TokenHash, token.ExpiresAt.UTC(), createdAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	token.CreatedAt = createdAt
+	return nil
+}
+
+func (r *EmailTokenSQLRepository) Consume(id string, purpose models.EmailTokenPurpose, hash string) (*models.EmailToken, error) {
+	usedAt := now()
+	token := &models.EmailToken{}
+	err := r.db.QueryRow(
+		`UPDATE email_tokens SET used_at = $1
+		WHERE id = $2 AND purpose = $3 AND token_hash = $4 AND used_at IS NULL AND expires_at > $1
+		RETURNING id, user_id, purpose, email, token_hash, expires_at, created_at, used_at`,
+		usedAt, id, string(purpose), hash,
+	).Scan(
+		&token.ID, &token.UserID, &token.Purpose, &token.Email, &token.TokenHash,
+		&token.ExpiresAt, &token.CreatedAt, &token.UsedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrInvalidEmailToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+func (r *EmailTokenSQLRepository) InvalidateForUser(userID string, purpose models.EmailTokenPurpose) error {
+	_, err := r.db.Exec(
+		`UPDATE email_tokens SET used_at = $1 WHERE user_id = $2 AND purpose = $3 AND used_at IS NULL`,
+		now(), userID, string(purpose),
+	)
+	return err
+}
@@ -0,0 +1,358 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
+)
+
+// HashtagSQLRepository computes trending and popular lists at query time
+// instead of keeping the background caches the memory implementation uses
+type HashtagSQLRepository struct {
+	db dbtx
+}
+
+func NewHashtagSQLRepository(db *sql.DB) *HashtagSQLRepository {
+	return &HashtagSQLRepository{db: db}
+}
+
+const hashtagColumns = `h.id, h.name, h.slug, h.category, h.created_by, h.created_at, h.followers`
+
+func scanHashtag(row scanner) (*models.Hashtag, error) {
+	hashtag := &models.Hashtag{}
+	if err := row.Scan(
+		&hashtag.ID, &hashtag.Name, &hashtag.Slug, &hashtag.Category, &hashtag.CreatedBy,
+		&hashtag.CreatedAt, &hashtag.Followers,
+	); err != nil {
+		return nil, err
+	}
+	return hashtag, nil
+}
+
+func scanHashtags(rows *sql.Rows) ([]*models.Hashtag, error) {
+	defer rows.Close()
+
+	hashtags := make([]*models.Hashtag, 0)
+	for rows.Next() {
+		hashtag, err := scanHashtag(rows)
+		if err != nil {
+			return nil, err
+		}
+		hashtags = append(hashtags, hashtag)
+	}
+	return hashtags, rows.Err()
+}
+
+func (r *HashtagSQLRepository) Create(hashtag *models.Hashtag) error {
+	createdAt := now()
+	res, err := r.db.Exec(`
+		INSERT INTO hashtags (id, name, slug, category, created_by, created_at, followers)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) ON CONFLICT DO NOTHING`,
+		hashtag.ID, hashtag.Name, hashtag.Slug, hashtag.Category, hashtag.CreatedBy, createdAt, hashtag.Followers,
+	)
+	if err != nil {
+		return err
+	}
+	if ok, err := rowsAffected(res); err != nil {
+		return err
+	} else if !ok {
+		return errors.New("hashtag already exists")
+	}
+
+	hashtag.CreatedAt = createdAt
+	return nil
+}
+
+func (r *HashtagSQLRepository) GetByID(id string) (*models.Hashtag, error) {
+	hashtag, err := scanHashtag(r.db.QueryRow(`SELECT `+hashtagColumns+` FROM hashtags h WHERE h.id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("hashtag not found")
+	}
+	return hashtag, err
+}
+
+func (r *HashtagSQLRepository) GetBySlug(slug string) (*models.Hashtag, error) {
+	hashtag, err := scanHashtag(r.db.QueryRow(
+		`SELECT `+hashtagColumns+` FROM hashtags h WHERE h.slug = $1 ORDER BY h.created_at LIMIT 1`, slug,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("hashtag not found")
+	}
+	return hashtag, err
+}
+
+func (r *HashtagSQLRepository) List(page repository.Page) ([]*models.Hashtag, string, error) {
+	query, args, err := keyset(`SELECT `+hashtagColumns+` FROM hashtags h WHERE TRUE`, nil, page, "h.created_at", "h.id", false)
+	if err != nil {
+		return nil, "", err
+	}
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	hashtags, err := scanHashtags(rows)
+	if err != nil {
+		return nil, "", err
+	}
+	hashtags, next := nextPage(hashtags, page, func(h *models.Hashtag) (time.Time, string) {
+		return h.CreatedAt, h.ID
+	})
+	return hashtags, next, nil
+}
+
+func (r *HashtagSQLRepository) Delete(id string) error {
+	// hashtag_posts and hashtag_followers cascade
+	_, err := r.db.Exec(`DELETE FROM hashtags WHERE id = $1`, id)
+	return err
+}
+
+func hashtagExists(q rowQuerier, hashtagID string) (bool, error) {
+	var exists bool
+	err := q.QueryRow(`SELECT EXISTS (SELECT 1 FROM hashtags WHERE id = $1)`, hashtagID).Scan(&exists)
+	return exists, err
+}
+
+func (r *HashtagSQLRepository) AddPostToHashtag(hashtagID, postID string, isBoost bool) error {
+	exists, err := hashtagExists(r.db, hashtagID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return errors.New("hashtag not found")
+	}
+
+	// A post that was first linked as a shout is upgraded when boosted later
+	_, err = r.db.Exec(`
+		INSERT INTO hashtag_posts (hashtag_id, post_id, is_boost, created_at) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (hashtag_id, post_id) DO UPDATE SET is_boost = hashtag_posts.is_boost OR excluded.is_boost`,
+		hashtagID, postID, isBoost, now(),
+	)
+	return err
+}
+
+func (r *HashtagSQLRepository) GetPostsByHashtag(hashtagID string) ([]*models.Post, error) {
+	rows, err := r.db.Query(`
+		SELECT `+postColumns+` FROM hashtag_posts hp JOIN posts p ON p.id = hp.post_id
+		WHERE hp.hashtag_id = $1 AND p.deleted_at IS NULL
+		ORDER BY hp.created_at, p.id`,
+		hashtagID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return scanPosts(rows)
+}
+
+func (r *HashtagSQLRepository) GetHashtagStats(hashtagID string) (boosts, shouts int, err error) {
+	exists, err := hashtagExists(r.db, hashtagID)
+	if err != nil {
+		return 0, 0, err
+	}
+	if !exists {
+		return 0, 0, errors.New("hashtag not found")
+	}
+
+	// Links to deleted posts are skipped
+	err = r.db.QueryRow(`
+		SELECT
+			COALESCE(SUM(CASE WHEN hp.is_boost THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN hp.is_boost THEN 0 ELSE 1 END), 0)
+		FROM hashtag_posts hp JOIN posts p ON p.id = hp.post_id
+		WHERE hp.hashtag_id = $1 AND p.deleted_at IS NULL`,
+		hashtagID,
+	).Scan(&boosts, &shouts)
+	return boosts, shouts, err
+}
+
+func (r *HashtagSQLRepository) RemovePostFromHashtag(postID string) error {
+	_, err := r.db.Exec(`DELETE FROM hashtag_posts WHERE post_id = $1`, postID)
+	return err
+}
+
+func (r *HashtagSQLRepository) FollowHashtag(userID, hashtagID string) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		exists, err := hashtagExists(tx, hashtagID)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return errors.New("hashtag not found")
+		}
+
+		if _, err := tx.Exec(
+			`INSERT INTO hashtag_followers (hashtag_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+			hashtagID, userID,
+		); err != nil {
+			return err
+		}
+		return r.syncFollowers(tx, hashtagID)
+	})
+}
+
+func (r *HashtagSQLRepository) UnfollowHashtag(userID, hashtagID string) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		if _, err := tx.Exec(
+			`DELETE FROM hashtag_followers WHERE hashtag_id = $1 AND user_id = $2`, hashtagID, userID,
+		); err != nil {
+			return err
+		}
+		return r.syncFollowers(tx, hashtagID)
+	})
+}
+
+// syncFollowers updates the denormalized follower count on the hashtag row
+func (r *HashtagSQLRepository) syncFollowers(tx *sql.Tx, hashtagID string) error {
+	_, err := tx.Exec(`
+		UPDATE hashtags SET followers = (SELECT COUNT(*) FROM hashtag_followers WHERE hashtag_id = $1)
+		WHERE id = $1`,
+		hashtagID,
+	)
+	return err
+}
+
+func (r *HashtagSQLRepository) IsFollowing(userID, hashtagID string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(
+		`SELECT EXISTS (SELECT 1 FROM hashtag_followers WHERE hashtag_id = $1 AND user_id = $2)`,
+		hashtagID, userID,
+	).Scan(&exists)
+	return exists, err
+}
+
+type scoredHashtag struct {
+	hashtag *models.Hashtag
+	score   float64
+}
+
+// scoreTrending ranks hashtags active within window using the same thresholds
+// and scoring formula as the memory repository
+func (r *HashtagSQLRepository) scoreTrending(window time.Duration) ([]scoredHashtag, error) {
+	config := repository.DefaultTrendingConfig
+
+	rows, err := r.db.Query(`
+		SELECT hp.hashtag_id, p.author_id, p.reaction_count, p.comment_count
+		FROM hashtag_posts hp JOIN posts p ON p.id = hp.post_id
+		WHERE hp.created_at > $1 AND p.deleted_at IS NULL
+		ORDER BY hp.created_at`,
+		now().Add(-window),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type windowStats struct {
+		posts       int
+		uniqueUsers map[string]int
+		engagement  int
+	}
+	stats := make(map[string]*windowStats)
+
+	for rows.Next() {
+		var hashtagID, authorID string
+		var reactions, comments int
+		if err := rows.Scan(&hashtagID, &authorID, &reactions, &comments); err != nil {
+			return nil, err
+		}
+
+		s := stats[hashtagID]
+		if s == nil {
+			s = &windowStats{uniqueUsers: make(map[string]int)}
+			stats[hashtagID] = s
+		}
+
+		// Spam check: cap per user contribution to post count
+		if s.uniqueUsers[authorID] < config.UserPostCap {
+			s.posts++
+			s.uniqueUsers[authorID]++
+		}
+		s.engagement += reactions + comments
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	minPosts, minUsers := config.MinPosts24h, config.MinUsers24h
+	if window == 1*time.Hour {
+		minPosts, minUsers = config.MinPosts1h, config.MinUsers1h
+	}
+
+	scores := make([]scoredHashtag, 0)
+	for hashtagID, s := range stats {
+		if (window == 1*time.Hour || window == 24*time.Hour) && (s.posts < minPosts || len(s.uniqueUsers) < minUsers) {
+			continue
+		}
+
+		hashtag, err := r.GetByID(hashtagID)
+		if err != nil {
+			continue
+		}
+
+		score := float64(s.posts*1) + float64(len(s.uniqueUsers)*10) + float64(s.engagement*2)
+		scores = append(scores, scoredHashtag{hashtag: hashtag, score: score})
+	}
+
+	sort.SliceStable(scores, func(i, j int) bool {
+		if scores[i].score != scores[j].score {
+			return scores[i].score > scores[j].score
+		}
+		return scores[i].hashtag.ID < scores[j].hashtag.ID
+	})
+	return scores, nil
+}
+
+// GetTrending returns trending hashtags for a specific time window
+func (r *HashtagSQLRepository) GetTrending(window time.Duration, limit int) ([]*models.Hashtag, error) {
+	scores, err := r.scoreTrending(window)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(scores) > limit {
+		scores = scores[:limit]
+	}
+	result := make([]*models.Hashtag, len(scores))
+	for i, s := range scores {
+		result[i] = s.hashtag
+	}
+	return result, nil
+}
+
+func (r *HashtagSQLRepository) GetTrendingByCategory(window time.Duration, limit int) (map[string][]*models.Hashtag, error) {
+	scores, err := r.scoreTrending(window)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string][]*models.Hashtag)
+	for _, s := range scores {
+		cat := s.hashtag.Category
+		if cat == "" {
+			cat = "General"
+		}
+		if len(result[cat]) < limit {
+			result[cat] = append(result[cat], s.hashtag)
+		}
+	}
+	return result, nil
+}
+
+// GetPopular returns all-time popular hashtags ranked by posts + followers*10
+func (r *HashtagSQLRepository) GetPopular(limit int) ([]*models.Hashtag, error) {
+	rows, err := r.db.Query(`
+		SELECT `+hashtagColumns+` FROM hashtags h
+		LEFT JOIN (SELECT hashtag_id, COUNT(*) AS posts FROM hashtag_posts GROUP BY hashtag_id) hp
+			ON hp.hashtag_id = h.id
+		ORDER BY COALESCE(hp.posts, 0) + h.followers * 10 DESC, h.id
+		LIMIT $1`,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return scanHashtags(rows)
+}
@@ -0,0 +1,85 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
+)
+
+type IdentitySQLRepository struct {
+	db dbtx
+}
+
+func NewIdentitySQLRepository(db *sql.DB) *IdentitySQLRepository {
+	return &IdentitySQLRepository{db: db}
+}
+
+const identityColumns = `provider, subject, user_id, email, created_at`
+
+func scanIdentity(row scanner) (*models.Identity, error) {
+	identity := &models.Identity{}
+	if err := row.Scan(&identity.Provider, &identity.Subject, &identity.UserID, &identity.Email, &identity.CreatedAt); err != nil {
+		return nil, err
+	}
+	return identity, nil
+}
+
+func (r *IdentitySQLRepository) Create(identity *models.Identity) error {
+	identity.CreatedAt = now()
+	res, err := r.db.Exec(
+		`INSERT INTO identities (`+identityColumns+`) VALUES ($1, $2, $3, $4, $5) ON CONFLICT DO NOTHING`,
+		identity.Provider, identity.Subject, identity.UserID, identity.Email, identity.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	if ok, err := rowsAffected(res); err != nil || ok {
+		return err
+	}
+	return repository.ErrIdentityLinked
+}
+
+func (r *IdentitySQLRepository) Get(provider, subject string) (*models.Identity, error) {
+	identity, err := scanIdentity(r.db.QueryRow(
+		`SELECT `+identityColumns+` FROM identities WHERE provider = $1 AND subject = $2`,
+		provider, subject,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("identity not found")
+	}
+	return identity, err
+}
+
+func (r *IdentitySQLRepository) ListByUser(userID string) ([]*models.Identity, error) {
+	rows, err := r.db.Query(
+		`SELECT `+identityColumns+` FROM identities WHERE user_id = $1 ORDER BY provider`,
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	identities := []*models.Identity{}
+	for rows.Next() {
+		identity, err := scanIdentity(rows)
+		if err != nil {
+			return nil, err
+		}
+		identities = append(identities, identity)
+	}
+	return identities, rows.Err()
+}
+
+func (r *IdentitySQLRepository) Delete(userID, provider string) error {
+	res, err := r.db.Exec(`DELETE FROM identities WHERE user_id = $1 AND provider = $2`, userID, provider)
+	if err != nil {
+		return err
+	}
+	if ok, err := rowsAffected(res); err != nil || ok {
+		return err
+	}
+	return errors.New("identity not found")
+}
@@ -0,0 +1,77 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/yourusername/v-backend/internal/models"
+)
+
+type LoginThrottleSQLRepository struct {
+	db dbtx
+}
+
+func NewLoginThrottleSQLRepository(db *sql.DB) *LoginThrottleSQLRepository {
+	return &LoginThrottleSQLRepository{db: db}
+}
+
+const loginThrottleColumns = `throttle_key, failures, last_failure_at, blocked_until`
+
+func scanLoginThrottle(row scanner) (*models.LoginThrottle, error) {
+	throttle := &models.LoginThrottle{}
+	if err := row.Scan(&throttle.Key, &throttle.Failures, &throttle.LastFailureAt, &throttle.BlockedUntil); err != nil {
+		return nil, err
+	}
+	return throttle, nil
+}
+
+func (r *LoginThrottleSQLRepository) Get(key string) (*models.LoginThrottle, error) {
+	throttle, err := scanLoginThrottle(r.db.QueryRow(
+		`SELECT `+loginThrottleColumns+` FROM login_throttles WHERE throttle_key = $1`, key,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("login throttle not found")
+	}
+	return throttle, err
+}
+
+func (r *LoginThrottleSQLRepository) RecordFailure(key string, at, since time.Time) (*models.LoginThrottle, error) {
+	return scanLoginThrottle(r.db.QueryRow(
+		`INSERT INTO login_throttles (throttle_key, failures, last_failure_at) VALUES ($1, 1, $2)
+		ON CONFLICT (throttle_key) DO UPDATE SET
+			failures = CASE WHEN login_throttles.last_failure_at < $3 THEN 1 ELSE login_throttles.failures + 1 END,
+			blocked_until = CASE WHEN login_throttles.last_failure_at < $3 THEN NULL ELSE login_throttles.blocked_until END,
+			last_failure_at = excluded.last_failure_at
+		RETURNING `+loginThrottleColumns,
+		key, at.UTC(), since.UTC(),
+	))
+}
+
+func (r *LoginThrottleSQLRepository) Block(key string, until time.Time) error {
+	res, err := r.db.Exec(`UPDATE login_throttles SET blocked_until = $1 WHERE throttle_key = $2`, until.UTC(), key)
+	if err != nil {
+		return err
+	}
+	if ok, err := rowsAffected(res); err != nil || ok {
+		return err
+	}
+	return errors.New("login throttle not found")
+}
+
+func (r *LoginThrottleSQLRepository) Reset(key string) error {
+	_, err := r.db.Exec(`DELETE FROM login_throttles WHERE throttle_key = $1`, key)
+	return err
+}
+
+func (r *LoginThrottleSQLRepository) Prune(cutoff time.Time) (int, error) {
+	res, err := r.db.Exec(
+		`DELETE FROM login_throttles WHERE last_failure_at < $1 AND (blocked_until IS NULL OR blocked_until < $1)`,
+		cutoff.UTC(),
+	)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
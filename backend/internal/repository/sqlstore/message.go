@@ -0,0 +1,201 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
+)
+
+type MessageSQLRepository struct {
+	db dbtx
+}
+
+func NewMessageSQLRepository(db *sql.DB) *MessageSQLRepository {
+	return &MessageSQLRepository{db: db}
+}
+
+const conversationColumns = `id, participant1_id, participant2_id, created_at, updated_at`
+
+const messageColumns = `id, conversation_id, sender_id, content, is_read, created_at`
+
+func scanConversation(row scanner) (*models.Conversation, error) {
+	conv := &models.Conversation{}
+	if err := row.Scan(&conv.ID, &conv.Participant1ID, &conv.Participant2ID, &conv.CreatedAt, &conv.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return conv, nil
+}
+
+func scanMessage(row scanner) (*models.Message, error) {
+	message := &models.Message{}
+	if err := row.Scan(
+		&message.ID, &message.ConversationID, &message.SenderID, &message.Content, &message.Read, &message.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+func (r *MessageSQLRepository) CreateConversation(conv *models.Conversation) error {
+	createdAt := now()
+	res, err := r.db.Exec(
+		`INSERT INTO conversations (`+conversationColumns+`) VALUES ($1, $2, $3, $4, $5) ON CONFLICT DO NOTHING`,
+		conv.ID, conv.Participant1ID, conv.Participant2ID, createdAt, createdAt,
+	)
+	if err != nil {
+		return err
+	}
+	if ok, err := rowsAffected(res); err != nil {
+		return err
+	} else if !ok {
+		return errors.New("conversation already exists")
+	}
+
+	conv.CreatedAt = createdAt
+	conv.UpdatedAt = createdAt
+	return nil
+}
+
+func (r *MessageSQLRepository) GetConversation(id string) (*models.Conversation, error) {
+	conv, err := scanConversation(r.db.QueryRow(`SELECT `+conversationColumns+` FROM conversations WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("conversation not found")
+	}
+	return conv, err
+}
+
+func (r *MessageSQLRepository) GetConversationByParticipants(user1ID, user2ID string) (*models.Conversation, error) {
+	conv, err := scanConversation(r.db.QueryRow(`
+		SELECT `+conversationColumns+` FROM conversations
+		WHERE (participant1_id = $1 AND participant2_id = $2) OR (participant1_id = $2 AND participant2_id = $1)
+		ORDER BY created_at LIMIT 1`,
+		user1ID, user2ID,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("conversation not found")
+	}
+	return conv, err
+}
+
+func (r *MessageSQLRepository) ListConversations(userID string) ([]*models.Conversation, error) {
+	rows, err := r.db.Query(`
+		SELECT `+conversationColumns+` FROM conversations
+		WHERE participant1_id = $1 OR participant2_id = $1
+		ORDER BY updated_at DESC, id`,
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	conversations := make([]*models.Conversation, 0)
+	for rows.Next() {
+		conv, err := scanConversation(rows)
+		if err != nil {
+			return nil, err
+		}
+		conversations = append(conversations, conv)
+	}
+	return conversations, rows.Err()
+}
+
+func (r *MessageSQLRepository) CreateMessage(message *models.Message) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		var exists bool
+		if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM messages WHERE id = $1)`, message.ID).Scan(&exists); err != nil {
+			return err
+		}
+		if exists {
+			return errors.New("message already exists")
+		}
+
+		// Verify conversation exists and bump its timestamp
+		createdAt := now()
+		res, err := tx.Exec(`UPDATE conversations SET updated_at = $1 WHERE id = $2`, createdAt, message.ConversationID)
+		if err != nil {
+			return err
+		}
+		if ok, err := rowsAffected(res); err != nil {
+			return err
+		} else if !ok {
+			return errors.New("conversation not found")
+		}
+
+		if _, err := tx.Exec(
+			`INSERT INTO messages (`+messageColumns+`) VALUES ($1, $2, $3, $4, $5, $6)`,
+			message.ID, message.ConversationID, message.SenderID, message.Content, message.Read, createdAt,
+		); err != nil {
+			return err
+		}
+
+		message.CreatedAt = createdAt
+		return nil
+	})
+}
+
+func (r *MessageSQLRepository) GetMessage(id string) (*models.Message, error) {
+	message, err := scanMessage(r.db.QueryRow(`SELECT `+messageColumns+` FROM messages WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("message not found")
+	}
+	return message, err
+}
+
+func (r *MessageSQLRepository) ListMessages(conversationID string, page repository.Page) ([]*models.Message, string, error) {
+	query, args, err := keyset(`
+		SELECT `+messageColumns+` FROM messages
+		WHERE conversation_id = $1`,
+		[]any{conversationID}, page, "created_at", "id", false,
+	)
+	if err != nil {
+		return nil, "", err
+	}
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	messages := make([]*models.Message, 0)
+	for rows.Next() {
+		message, err := scanMessage(rows)
+		if err != nil {
+			return nil, "", err
+		}
+		messages = append(messages, message)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	messages, next := nextPage(messages, page, func(m *models.Message) (time.Time, string) {
+		return m.CreatedAt, m.ID
+	})
+	return messages, next, nil
+}
+
+func (r *MessageSQLRepository) MarkAsRead(messageID string) error {
+	res, err := r.db.Exec(`UPDATE messages SET is_read = TRUE WHERE id = $1`, messageID)
+	if err != nil {
+		return err
+	}
+	if ok, err := rowsAffected(res); err != nil {
+		return err
+	} else if !ok {
+		return errors.New("message not found")
+	}
+	return nil
+}
+
+func (r *MessageSQLRepository) GetUnreadCount(conversationID, userID string) (int, error) {
+	var count int
+	err := r.db.QueryRow(
+		`SELECT COUNT(*) FROM messages WHERE conversation_id = $1 AND sender_id <> $2 AND NOT is_read`,
+		conversationID, userID,
+	).Scan(&count)
+	return count, err
+}
@@ -0,0 +1,132 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
+)
+
+type NotificationSQLRepository struct {
+	db dbtx
+}
+
+func NewNotificationSQLRepository(db *sql.DB) *NotificationSQLRepository {
+	return &NotificationSQLRepository{db: db}
+}
+
+const notificationColumns = `id, user_id, type, title, message, debate_id, debate_title, community_id,
+	community_name, post_id, actor_id, actor_name, actor_handle, is_read, created_at`
+
+func scanNotification(row scanner) (*models.Notification, error) {
+	n := &models.Notification{}
+	if err := row.Scan(
+		&n.ID, &n.UserID, &n.Type, &n.Title, &n.Message, &n.DebateID, &n.DebateTitle, &n.CommunityID,
+		&n.CommunityName, &n.PostID, &n.ActorID, &n.ActorName, &n.ActorHandle, &n.Read, &n.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+func (r *NotificationSQLRepository) Create(notification *models.Notification) error {
+	if notification.CreatedAt.IsZero() {
+		notification.CreatedAt = now()
+	}
+
+	_, err := r.db.Exec(`
+		INSERT INTO notifications (`+notificationColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		ON CONFLICT (id) DO UPDATE SET user_id = excluded.user_id, type = excluded.type,
+			title = excluded.title, message = excluded.message, debate_id = excluded.debate_id,
+			debate_title = excluded.debate_title, community_id = excluded.community_id,
+			community_name = excluded.community_name, post_id = excluded.post_id,
+			actor_id = excluded.actor_id, actor_name = excluded.actor_name,
+			actor_handle = excluded.actor_handle, is_read = excluded.is_read, created_at = excluded.created_at`,
+		notification.ID, notification.UserID, notification.Type, notification.Title, notification.Message,
+		notification.DebateID, notification.DebateTitle, notification.CommunityID, notification.CommunityName,
+		notification.PostID, notification.ActorID, notification.ActorName, notification.ActorHandle,
+		notification.Read, notification.CreatedAt.UTC(),
+	)
+	return err
+}
+
+func (r *NotificationSQLRepository) GetByUserID(userID string, page repository.Page) ([]*models.Notification, string, error) {
+	query, args, err := keyset(`
+		SELECT `+notificationColumns+` FROM notifications
+		WHERE user_id = $1`,
+		[]any{userID}, page, "created_at", "id", true,
+	)
+	if err != nil {
+		return nil, "", err
+	}
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	notifications := make([]*models.Notification, 0)
+	for rows.Next() {
+		n, err := scanNotification(rows)
+		if err != nil {
+			return nil, "", err
+		}
+		notifications = append(notifications, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	notifications, next := nextPage(notifications, page, func(n *models.Notification) (time.Time, string) {
+		return n.CreatedAt, n.ID
+	})
+	return notifications, next, nil
+}
+
+func (r *NotificationSQLRepository) GetByID(id string) (*models.Notification, error) {
+	n, err := scanNotification(r.db.QueryRow(`SELECT `+notificationColumns+` FROM notifications WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("notification not found")
+	}
+	return n, err
+}
+
+func (r *NotificationSQLRepository) MarkAsRead(id string) error {
+	res, err := r.db.Exec(`UPDATE notifications SET is_read = TRUE WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if ok, err := rowsAffected(res); err != nil {
+		return err
+	} else if !ok {
+		return errors.New("notification not found")
+	}
+	return nil
+}
+
+func (r *NotificationSQLRepository) MarkAllAsRead(userID string) error {
+	_, err := r.db.Exec(`UPDATE notifications SET is_read = TRUE WHERE user_id = $1 AND NOT is_read`, userID)
+	return err
+}
+
+func (r *NotificationSQLRepository) Delete(id string) error {
+	res, err := r.db.Exec(`DELETE FROM notifications WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if ok, err := rowsAffected(res); err != nil {
+		return err
+	} else if !ok {
+		return errors.New("notification not found")
+	}
+	return nil
+}
+
+func (r *NotificationSQLRepository) GetUnreadCount(userID string) (int, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND NOT is_read`, userID).Scan(&count)
+	return count, err
+}
@@ -0,0 +1,55 @@
+package sqlstore
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yourusername/v-backend/internal/repository"
+)
+
+// keyset appends the cursor condition, ordering and limit for page to query,
+// which must end in a WHERE clause using len(args) placeholders. One row more
+// than the limit is fetched so nextPage can tell whether another page follows.
+func keyset(query string, args []any, page repository.Page, timeCol, idCol string, desc bool) (string, []any, error) {
+	cursor, err := repository.DecodeCursor(page.Cursor)
+	if err != nil {
+		return "", nil, err
+	}
+
+	dir, cmp := "", ">"
+	if desc {
+		dir, cmp = " DESC", "<"
+	}
+	if cursor != nil {
+		query += fmt.Sprintf(` AND (%s, %s) %s ($%d, $%d)`, timeCol, idCol, cmp, len(args)+1, len(args)+2)
+		args = append(args, cursor.CreatedAt.UTC(), cursor.ID)
+	}
+	query += fmt.Sprintf(` ORDER BY %s%s, %s%s LIMIT $%d`, timeCol, dir, idCol, dir, len(args)+1)
+	args = append(args, max(page.Limit, 0)+1)
+	return query, args, nil
+}
+
+// nextPage drops the extra row fetched by keyset and returns the cursor of the
+// following page, or "" when items was the last page
+func nextPage[T any](items []T, page repository.Page, key func(T) (time.Time, string)) ([]T, string) {
+	if page.Limit <= 0 {
+		return items[:0], ""
+	}
+	if len(items) <= page.Limit {
+		return items, ""
+	}
+	items = items[:page.Limit]
+	createdAt, id := key(items[len(items)-1])
+	return items, repository.EncodeCursor(createdAt, id)
+}
+
+// keyedScanner scans one extra trailing column, the sort key of a list that is
+// not ordered by a column of the scanned model
+type keyedScanner struct {
+	scanner
+	key *time.Time
+}
+
+func (s keyedScanner) Scan(dest ...any) error {
+	return s.scanner.Scan(append(dest, s.key)...)
+}
@@ -0,0 +1,535 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
+)
+
+type PostSQLRepository struct {
+	db dbtx
+}
+
+func NewPostSQLRepository(db *sql.DB) *PostSQLRepository {
+	return &PostSQLRepository{db: db}
+}
+
+const postColumns = `p.id, p.author_id, p.author_is_bot, p.content, p.media_type, p.media_url, p.comments_disabled, p.comment_limit,
+	p.reaction_count, p.comment_count, p.save_count, p.reach_24h, p.reach_all, p.community_id, p.post_type,
+	p.topic_tag, p.response_to_post_id, p.status, p.report_count, p.in_moderation_queue,
+	p.original_language, p.translations, p.created_at, p.updated_at, p.version, p.deleted_at`
+
+// feedFilter hides deleted posts and those that moderation has pulled from feeds
+const feedFilter = `p.deleted_at IS NULL AND p.status NOT IN ('TEMP_HIDDEN', 'REMOVED')`
+
+func scanPost(row scanner) (*models.Post, error) {
+	post := &models.Post{}
+	var translations []byte
+	err := row.Scan(
+		&post.ID, &post.AuthorID, &post.AuthorIsBot, &post.Content, &post.MediaType, &post.MediaURL, &post.CommentsDisabled,
+		&post.CommentLimit, &post.ReactionCount, &post.CommentCount, &post.SaveCount, &post.Reach24h,
+		&post.ReachAll, &post.CommunityID, &post.PostType, &post.TopicTag, &post.ResponseToPostID,
+		&post.Status, &post.ReportCount, &post.InModerationQueue, &post.OriginalLanguage, &translations,
+		&post.CreatedAt, &post.UpdatedAt, &post.Version, &post.DeletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := fromJSON(translations, &post.Translations); err != nil {
+		return nil, err
+	}
+	return post, nil
+}
+
+func scanPosts(rows *sql.Rows) ([]*models.Post, error) {
+	defer rows.Close()
+
+	posts := make([]*models.Post, 0)
+	for rows.Next() {
+		post, err := scanPost(rows)
+		if err != nil {
+			return nil, err
+		}
+		posts = append(posts, post)
+	}
+	return posts, rows.Err()
+}
+
+func (r *PostSQLRepository) Create(post *models.Post) error {
+	// Initialize moderation fields if not set
+	if post.Status == "" {
+		post.Status = models.PostStatusVisible
+	}
+	post.InModerationQueue = false
+
+	translations, err := toJSON(post.Translations)
+	if err != nil {
+		return err
+	}
+
+	createdAt := now()
+	res, err := r.db.Exec(`
+		INSERT INTO posts (id, author_id, content, media_type, media_url, comments_disabled, comment_limit,
+			reaction_count, comment_count, save_count, reach_24h, reach_all, community_id, post_type,
+			topic_tag, response_to_post_id, status, report_count, in_moderation_queue,
+			original_language, translations, created_at, updated_at, author_is_bot)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20,
+			$21, $22, $23, $24)
+		ON CONFLICT DO NOTHING`,
+		post.ID, post.AuthorID, post.Content, post.MediaType, post.MediaURL, post.CommentsDisabled,
+		post.CommentLimit, post.ReactionCount, post.CommentCount, post.SaveCount, post.Reach24h,
+		post.ReachAll, post.CommunityID, post.PostType, post.TopicTag, post.ResponseToPostID,
+		string(post.Status), post.ReportCount, post.InModerationQueue, post.OriginalLanguage, translations,
+		createdAt, createdAt, post.AuthorIsBot,
+	)
+	if err != nil {
+		return err
+	}
+	if ok, err := rowsAffected(res); err != nil {
+		return err
+	} else if !ok {
+		return errors.New("post already exists")
+	}
+
+	post.CreatedAt = createdAt
+	post.UpdatedAt = createdAt
+	post.Version = 1
+	return nil
+}
+
+func (r *PostSQLRepository) GetByID(id string) (*models.Post, error) {
+	post, err := scanPost(r.db.QueryRow(`SELECT `+postColumns+` FROM posts p WHERE p.id = $1 AND p.deleted_at IS NULL`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("post not found")
+	}
+	return post, err
+}
+
+func (r *PostSQLRepository) GetDeletedByID(id string) (*models.Post, error) {
+	post, err := scanPost(r.db.QueryRow(`SELECT `+postColumns+` FROM posts p WHERE p.id = $1 AND p.deleted_at IS NOT NULL`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("deleted post not found")
+	}
+	return post, err
+}
+
+func (r *PostSQLRepository) Update(post *models.Post) error {
+	translations, err := toJSON(post.Translations)
+	if err != nil {
+		return err
+	}
+
+	updatedAt := now()
+	res, err := r.db.Exec(`
+		UPDATE posts SET author_id = $2, content = $3, media_type = $4, media_url = $5,
+			comments_disabled = $6, comment_limit = $7, reaction_count = $8, comment_count = $9,
+			save_count = $10, reach_24h = $11, reach_all = $12, community_id = $13, post_type = $14,
+			topic_tag = $15, response_to_post_id = $16, status = $17, report_count = $18,
+			in_moderation_queue = $19, original_language = $20, translations = $21, updated_at = $22,
+			version = version + 1
+		WHERE id = $1 AND version = $23`,
+		post.ID, post.AuthorID, post.Content, post.MediaType, post.MediaURL, post.CommentsDisabled,
+		post.CommentLimit, post.ReactionCount, post.CommentCount, post.SaveCount, post.Reach24h,
+		post.ReachAll, post.CommunityID, post.PostType, post.TopicTag, post.ResponseToPostID,
+		string(post.Status), post.ReportCount, post.InModerationQueue, post.OriginalLanguage, translations,
+		updatedAt, post.Version,
+	)
+	if err != nil {
+		return err
+	}
+	if ok, err := rowsAffected(res); err != nil {
+		return err
+	} else if !ok {
+		return updateConflict(r.db, "posts", "post", post.ID)
+	}
+
+	post.Version++
+	post.UpdatedAt = updatedAt
+	return nil
+}
+
+func (r *PostSQLRepository) Delete(id string) error {
+	// Comments, reactions and saves are kept so a restore brings them back
+	_, err := r.db.Exec(
+		`UPDATE posts SET deleted_at = $2, version = version + 1 WHERE id = $1 AND deleted_at IS NULL`,
+		id, now(),
+	)
+	return err
+}
+
+func (r *PostSQLRepository) Restore(id string, deletedSince time.Time) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		if err := checkRestorable(tx, "posts", "post", id, deletedSince); err != nil {
+			return err
+		}
+		_, err := tx.Exec(`UPDATE posts SET deleted_at = NULL, version = version + 1 WHERE id = $1`, id)
+		return err
+	})
+}
+
+func (r *PostSQLRepository) Purge(cutoff time.Time) (int, error) {
+	var purged int64
+	err := withTx(r.db, func(tx *sql.Tx) error {
+		// Comments and saves cascade; reactions are keyed loosely and removed here
+		if _, err := tx.Exec(`
+			DELETE FROM reactions WHERE post_id IN (SELECT id FROM posts WHERE deleted_at < $1)
+				OR comment_id IN (SELECT id FROM comments WHERE deleted_at < $1)`,
+			cutoff.UTC(),
+		); err != nil {
+			return err
+		}
+		for _, table := range []string{"posts", "comments"} {
+			res, err := tx.Exec(`DELETE FROM `+table+` WHERE deleted_at < $1`, cutoff.UTC())
+			if err != nil {
+				return err
+			}
+			n, err := res.RowsAffected()
+			if err != nil {
+				return err
+			}
+			purged += n
+		}
+		return nil
+	})
+	return int(purged), err
+}
+
+func (r *PostSQLRepository) List(page repository.Page) ([]*models.Post, string, error) {
+	// Community posts stay out of the main feed
+	query, args, err := keyset(`
+		SELECT `+postColumns+` FROM posts p
+		WHERE `+feedFilter+` AND p.community_id IS NULL`,
+		nil, page, "p.created_at", "p.id", true,
+	)
+	if err != nil {
+		return nil, "", err
+	}
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	posts, err := scanPosts(rows)
+	if err != nil {
+		return nil, "", err
+	}
+	posts, next := nextPage(posts, page, postKey)
+	return posts, next, nil
+}
+
+func (r *PostSQLRepository) ListByAuthor(authorID string, page repository.Page) ([]*models.Post, string, error) {
+	query, args, err := keyset(`
+		SELECT `+postColumns+` FROM posts p
+		WHERE p.author_id = $1 AND `+feedFilter+``,
+		[]any{authorID}, page, "p.created_at", "p.id", true,
+	)
+	if err != nil {
+		return nil, "", err
+	}
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	posts, err := scanPosts(rows)
+	if err != nil {
+		return nil, "", err
+	}
+	posts, next := nextPage(posts, page, postKey)
+	return posts, next, nil
+}
+
+func (r *PostSQLRepository) ListByCommunity(communityID string, page repository.Page) ([]*models.Post, string, error) {
+	query, args, err := keyset(`
+		SELECT `+postColumns+` FROM posts p
+		WHERE p.community_id = $1 AND `+feedFilter+``,
+		[]any{communityID}, page, "p.created_at", "p.id", true,
+	)
+	if err != nil {
+		return nil, "", err
+	}
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	posts, err := scanPosts(rows)
+	if err != nil {
+		return nil, "", err
+	}
+	posts, next := nextPage(posts, page, postKey)
+	return posts, next, nil
+}
+
+// Comment operations
+func (r *PostSQLRepository) CreateComment(comment *models.Comment) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		var exists bool
+		if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM comments WHERE id = $1)`, comment.ID).Scan(&exists); err != nil {
+			return err
+		}
+		if exists {
+			return errors.New("comment already exists")
+		}
+
+		// Verify post exists and bump its comment count in one step
+		res, err := tx.Exec(`UPDATE posts SET comment_count = comment_count + 1, version = version + 1 WHERE id = $1 AND deleted_at IS NULL`, comment.PostID)
+		if err != nil {
+			return err
+		}
+		if ok, err := rowsAffected(res); err != nil {
+			return err
+		} else if !ok {
+			return errors.New("post not found")
+		}
+
+		createdAt := now()
+		if _, err := tx.Exec(`
+			INSERT INTO comments (id, post_id, author_id, parent_id, content, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+			comment.ID, comment.PostID, comment.AuthorID, comment.ParentID, comment.Content, createdAt, createdAt,
+		); err != nil {
+			return err
+		}
+
+		comment.CreatedAt = createdAt
+		comment.UpdatedAt = createdAt
+		return nil
+	})
+}
+
+func (r *PostSQLRepository) GetCommentsByPost(postID string) ([]*models.Comment, error) {
+	rows, err := r.db.Query(`
+		SELECT id, post_id, author_id, parent_id, content, created_at, updated_at
+		FROM comments WHERE post_id = $1 AND deleted_at IS NULL
+			AND EXISTS (SELECT 1 FROM posts WHERE posts.id = comments.post_id AND posts.deleted_at IS NULL)
+		ORDER BY created_at, id`,
+		postID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	comments := make([]*models.Comment, 0)
+	for rows.Next() {
+		comment := &models.Comment{}
+		if err := rows.Scan(
+			&comment.ID, &comment.PostID, &comment.AuthorID, &comment.ParentID, &comment.Content,
+			&comment.CreatedAt, &comment.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		comments = append(comments, comment)
+	}
+	return comments, rows.Err()
+}
+
+func (r *PostSQLRepository) GetCommentByID(id string) (*models.Comment, error) {
+	comment := &models.Comment{}
+	err := r.db.QueryRow(
+		`SELECT id, post_id, author_id, parent_id, content, created_at, updated_at, deleted_at FROM comments WHERE id = $1`, id,
+	).Scan(
+		&comment.ID, &comment.PostID, &comment.AuthorID, &comment.ParentID, &comment.Content,
+		&comment.CreatedAt, &comment.UpdatedAt, &comment.DeletedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("comment not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+func (r *PostSQLRepository) DeleteComment(id string) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		var postID string
+		err := tx.QueryRow(
+			`UPDATE comments SET deleted_at = $2 WHERE id = $1 AND deleted_at IS NULL RETURNING post_id`,
+			id, now(),
+		).Scan(&postID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.New("comment not found")
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(`UPDATE posts SET comment_count = comment_count - 1, version = version + 1 WHERE id = $1 AND comment_count > 0`, postID)
+		return err
+	})
+}
+
+func (r *PostSQLRepository) RestoreComment(id string, deletedSince time.Time) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		if err := checkRestorable(tx, "comments", "comment", id, deletedSince); err != nil {
+			return err
+		}
+
+		var postID string
+		if err := tx.QueryRow(`UPDATE comments SET deleted_at = NULL WHERE id = $1 RETURNING post_id`, id).Scan(&postID); err != nil {
+			return err
+		}
+		_, err := tx.Exec(`UPDATE posts SET comment_count = comment_count + 1, version = version + 1 WHERE id = $1`, postID)
+		return err
+	})
+}
+
+// reactionCommentKey maps an optional comment ID onto the reactions key column
+func reactionCommentKey(commentID *string) string {
+	if commentID == nil {
+		return ""
+	}
+	return *commentID
+}
+
+// Reaction operations
+func (r *PostSQLRepository) AddReaction(reaction *models.Reaction) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		createdAt := now()
+		res, err := tx.Exec(
+			`INSERT INTO reactions (user_id, post_id, comment_id, created_at) VALUES ($1, $2, $3, $4) ON CONFLICT DO NOTHING`,
+			reaction.UserID, reaction.PostID, reactionCommentKey(reaction.CommentID), createdAt,
+		)
+		if err != nil {
+			return err
+		}
+		if ok, err := rowsAffected(res); err != nil {
+			return err
+		} else if !ok {
+			return errors.New("already reacted")
+		}
+
+		if _, err := tx.Exec(`UPDATE posts SET reaction_count = reaction_count + 1, version = version + 1 WHERE id = $1`, reaction.PostID); err != nil {
+			return err
+		}
+
+		reaction.CreatedAt = createdAt
+		return nil
+	})
+}
+
+func (r *PostSQLRepository) RemoveReaction(userID, postID string, commentID *string) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		res, err := tx.Exec(
+			`DELETE FROM reactions WHERE user_id = $1 AND post_id = $2 AND comment_id = $3`,
+			userID, postID, reactionCommentKey(commentID),
+		)
+		if err != nil {
+			return err
+		}
+		if ok, err := rowsAffected(res); err != nil {
+			return err
+		} else if !ok {
+			return errors.New("reaction not found")
+		}
+
+		_, err = tx.Exec(`UPDATE posts SET reaction_count = reaction_count - 1, version = version + 1 WHERE id = $1 AND reaction_count > 0`, postID)
+		return err
+	})
+}
+
+func (r *PostSQLRepository) HasReacted(userID, postID string, commentID *string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(
+		`SELECT EXISTS (SELECT 1 FROM reactions WHERE user_id = $1 AND post_id = $2 AND comment_id = $3)`,
+		userID, postID, reactionCommentKey(commentID),
+	).Scan(&exists)
+	return exists, err
+}
+
+// Save operations
+func (r *PostSQLRepository) SavePost(userID, postID string) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		var exists bool
+		if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM posts WHERE id = $1 AND deleted_at IS NULL)`, postID).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			return errors.New("post not found")
+		}
+
+		res, err := tx.Exec(
+			`INSERT INTO saves (user_id, post_id, created_at) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`,
+			userID, postID, now(),
+		)
+		if err != nil {
+			return err
+		}
+		if ok, err := rowsAffected(res); err != nil {
+			return err
+		} else if !ok {
+			return errors.New("post already saved")
+		}
+
+		_, err = tx.Exec(`UPDATE posts SET save_count = save_count + 1, version = version + 1 WHERE id = $1`, postID)
+		return err
+	})
+}
+
+func (r *PostSQLRepository) UnsavePost(userID, postID string) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		res, err := tx.Exec(`DELETE FROM saves WHERE user_id = $1 AND post_id = $2`, userID, postID)
+		if err != nil {
+			return err
+		}
+		if ok, err := rowsAffected(res); err != nil || !ok {
+			return err
+		}
+
+		_, err = tx.Exec(`UPDATE posts SET save_count = save_count - 1, version = version + 1 WHERE id = $1 AND save_count > 0`, postID)
+		return err
+	})
+}
+
+func (r *PostSQLRepository) GetSavedPosts(userID string, page repository.Page) ([]*models.Post, string, error) {
+	// Most recently saved first
+	query, args, err := keyset(`
+		SELECT `+postColumns+`, s.created_at FROM saves s JOIN posts p ON p.id = s.post_id
+		WHERE s.user_id = $1 AND p.deleted_at IS NULL`,
+		[]any{userID}, page, "s.created_at", "p.id", true,
+	)
+	if err != nil {
+		return nil, "", err
+	}
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	posts := make([]*models.Post, 0)
+	savedAt := make(map[string]time.Time)
+	for rows.Next() {
+		var at time.Time
+		post, err := scanPost(keyedScanner{rows, &at})
+		if err != nil {
+			return nil, "", err
+		}
+		posts = append(posts, post)
+		savedAt[post.ID] = at
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	posts, next := nextPage(posts, page, func(p *models.Post) (time.Time, string) {
+		return savedAt[p.ID], p.ID
+	})
+	return posts, next, nil
+}
+
+func (r *PostSQLRepository) IsSaved(userID, postID string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(
+		`SELECT EXISTS (SELECT 1 FROM saves WHERE user_id = $1 AND post_id = $2)`,
+		userID, postID,
+	).Scan(&exists)
+	return exists, err
+}
+
+// postKey is the feed ordering of posts
+func postKey(p *models.Post) (time.Time, string) {
+	return p.CreatedAt, p.ID
+}
@@ -0,0 +1,131 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
+)
+
+type SessionSQLRepository struct {
+	db dbtx
+}
+
+func NewSessionSQLRepository(db *sql.DB) *SessionSQLRepository {
+	return &SessionSQLRepository{db: db}
+}
+
+const sessionColumns = `id, user_id, token_hash, user_agent, ip_address, expires_at, created_at, last_used_at, revoked_at, previous_token_hash`
+
+func scanSession(row scanner) (*models.Session, error) {
+	session := &models.Session{}
+	if err := row.Scan(
+		&session.ID, &session.UserID, &session.TokenHash, &session.UserAgent, &session.IPAddress,
+		&session.ExpiresAt, &session.CreatedAt, &session.LastUsedAt, &session.RevokedAt, &session.PreviousTokenHash,
+	); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+func (r *SessionSQLRepository) Create(session *models.Session) error {
+	createdAt := now()
+	_, err := r.db.Exec(
+		`INSERT INTO sessions (`+sessionColumns+`) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULL, '')`,
+		session.ID, session.UserID, session.TokenHash, session.UserAgent, session.IPAddress,
+		session.ExpiresAt.UTC(), createdAt, createdAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	session.CreatedAt = createdAt
+	session.LastUsedAt = createdAt
+	return nil
+}
+
+func (r *SessionSQLRepository) GetByID(id string) (*models.Session, error) {
+	session, err := scanSession(r.db.QueryRow(`SELECT `+sessionColumns+` FROM sessions WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("session not found")
+	}
+	return session, err
+}
+
+func (r *SessionSQLRepository) ListActive(userID string) ([]*models.Session, error) {
+	rows, err := r.db.Query(
+		`SELECT `+sessionColumns+` FROM sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > $2
+		ORDER BY last_used_at DESC`,
+		userID, now(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []*models.Session{}
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, rows.Err()
+}
+
+func (r *SessionSQLRepository) Rotate(id, oldHash, newHash string, expiresAt time.Time) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		usedAt := now()
+		res, err := tx.Exec(
+			`UPDATE sessions SET previous_token_hash = token_hash, token_hash = $1, expires_at = $2, last_used_at = $3
+			WHERE id = $4 AND token_hash = $5 AND revoked_at IS NULL AND expires_at > $3`,
+			newHash, expiresAt.UTC(), usedAt, id, oldHash,
+		)
+		if err != nil {
+			return err
+		}
+		if ok, err := rowsAffected(res); err != nil || ok {
+			return err
+		}
+
+		var previous string
+		err = tx.QueryRow(`SELECT previous_token_hash FROM sessions WHERE id = $1`, id).Scan(&previous)
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.New("session not found")
+		}
+		if err != nil {
+			return err
+		}
+		if previous != "" && previous == oldHash {
+			return repository.ErrRefreshTokenReused
+		}
+		return repository.ErrRefreshTokenInvalid
+	})
+}
+
+func (r *SessionSQLRepository) Revoke(id string) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		var exists bool
+		if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM sessions WHERE id = $1)`, id).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			return errors.New("session not found")
+		}
+
+		_, err := tx.Exec(`UPDATE sessions SET revoked_at = $1 WHERE id = $2 AND revoked_at IS NULL`, now(), id)
+		return err
+	})
+}
+
+func (r *SessionSQLRepository) RevokeAllForUser(userID, keepID string) error {
+	_, err := r.db.Exec(
+		`UPDATE sessions SET revoked_at = $1 WHERE user_id = $2 AND id <> $3 AND revoked_at IS NULL`,
+		now(), userID, keepID,
+	)
+	return err
+}
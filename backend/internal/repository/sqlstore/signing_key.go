@@ -0,0 +1,57 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/yourusername/v-backend/internal/models"
+)
+
+type SigningKeySQLRepository struct {
+	db dbtx
+}
+
+func NewSigningKeySQLRepository(db *sql.DB) *SigningKeySQLRepository {
+	return &SigningKeySQLRepository{db: db}
+}
+
+func (r *SigningKeySQLRepository) Create(key *models.SigningKey) error {
+	key.CreatedAt = now()
+	_, err := r.db.Exec(
+		`INSERT INTO signing_keys (id, algorithm, private_key, created_at, activates_at, retires_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		key.ID, key.Algorithm, key.PrivateKey, key.CreatedAt,
+		key.ActivatesAt.UTC(), key.RetiresAt.UTC(), key.ExpiresAt.UTC(),
+	)
+	return err
+}
+
+func (r *SigningKeySQLRepository) List() ([]*models.SigningKey, error) {
+	rows, err := r.db.Query(
+		`SELECT id, algorithm, private_key, created_at, activates_at, retires_at, expires_at
+		FROM signing_keys ORDER BY activates_at, id`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []*models.SigningKey{}
+	for rows.Next() {
+		key := &models.SigningKey{}
+		if err := rows.Scan(&key.ID, &key.Algorithm, &key.PrivateKey, &key.CreatedAt, &key.ActivatesAt, &key.RetiresAt, &key.ExpiresAt); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+func (r *SigningKeySQLRepository) Prune(cutoff time.Time) (int, error) {
+	res, err := r.db.Exec(`DELETE FROM signing_keys WHERE expires_at < $1`, cutoff.UTC())
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
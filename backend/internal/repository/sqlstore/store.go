@@ -0,0 +1,166 @@
+// Package sqlstore implements the repository interfaces on top of database/sql.
+//
+// Queries stick to SQL understood by both PostgreSQL and SQLite ($n placeholders,
+// ON CONFLICT, RETURNING), so the postgres and sqlite packages only differ in
+// how they open the database and in their migrations.
+package sqlstore
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/yourusername/v-backend/internal/repository"
+)
+
+// NewRepositories returns SQL-backed implementations of every repository
+func NewRepositories(db *sql.DB) *repository.Repositories {
+	repos := newRepositories(db)
+	repos.UnitOfWork = &unitOfWork{db: db}
+	return repos
+}
+
+// newRepositories binds every repository to db, which is either the pool or a
+// transaction opened by a unit of work
+func newRepositories(db dbtx) *repository.Repositories {
+	return &repository.Repositories{
+		Auth:          &AuthSQLRepository{db: db},
+		Session:       &SessionSQLRepository{db: db},
+		EmailToken:    &EmailTokenSQLRepository{db: db},
+		TwoFactor:     &TwoFactorSQLRepository{db: db},
+		LoginThrottle: &LoginThrottleSQLRepository{db: db},
+		Identity:      &IdentitySQLRepository{db: db},
+		SigningKey:    &SigningKeySQLRepository{db: db},
+		APIKey:        &APIKeySQLRepository{db: db},
+		User:          &UserSQLRepository{db: db},
+		Post:          &PostSQLRepository{db: db},
+		Message:       &MessageSQLRepository{db: db},
+		Hashtag:       &HashtagSQLRepository{db: db},
+		Debate:        &DebateSQLRepository{db: db},
+		DebateStats:   &DebateStatsSQLRepository{db: db},
+		Notification:  &NotificationSQLRepository{db: db},
+		Analytics:     &AnalyticsSQLRepository{db: db},
+		Community:     &CommunitySQLRepository{db: db},
+	}
+}
+
+// unitOfWork runs a group of repository calls in one database transaction
+type unitOfWork struct {
+	db dbtx
+}
+
+func (u *unitOfWork) Do(fn func(tx *repository.Repositories) error) error {
+	return withTx(u.db, func(tx *sql.Tx) error {
+		repos := newRepositories(tx)
+		repos.UnitOfWork = &unitOfWork{db: tx}
+		return fn(repos)
+	})
+}
+
+// dbtx is satisfied by both *sql.DB and *sql.Tx, so a repository can run on its
+// own or inside a unit of work
+type dbtx interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// scanner is satisfied by both *sql.Row and *sql.Rows
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// rowQuerier is satisfied by both *sql.DB and *sql.Tx
+type rowQuerier interface {
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// withTx runs fn inside a transaction, committing on success and rolling back on
+// error. When db is already a transaction fn joins it, and the outermost caller
+// decides whether it commits.
+func withTx(db dbtx, fn func(tx *sql.Tx) error) error {
+	if tx, ok := db.(*sql.Tx); ok {
+		return fn(tx)
+	}
+	tx, err := db.(*sql.DB).Begin()
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// now returns the current time in UTC so stored timestamps sort consistently
+func now() time.Time {
+	return time.Now().UTC()
+}
+
+// utcPtr converts an optional timestamp to UTC
+func utcPtr(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
+
+// toJSON encodes v for storage in a JSON column
+func toJSON(v any) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// fromJSON decodes a JSON column into v, treating empty values as absent
+func fromJSON(data []byte, v any) error {
+	if len(data) == 0 || string(data) == "null" {
+		return nil
+	}
+	return json.Unmarshal(data, v)
+}
+
+// rowsAffected reports whether res changed at least one row
+// updateConflict explains why an update guarded by "WHERE id = $1 AND version = $n"
+// matched no row: the entity is gone, or another write bumped its version
+func updateConflict(db dbtx, table, entity, id string) error {
+	var version int
+	err := db.QueryRow(`SELECT version FROM `+table+` WHERE id = $1`, id).Scan(&version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errors.New(entity + " not found")
+	}
+	if err != nil {
+		return err
+	}
+	return &repository.ConflictError{Entity: entity, ID: id, Version: version}
+}
+
+// checkRestorable fails unless the row of table with id was soft-deleted no
+// earlier than deletedSince
+func checkRestorable(tx *sql.Tx, table, entity, id string, deletedSince time.Time) error {
+	var deletedAt *time.Time
+	err := tx.QueryRow(`SELECT deleted_at FROM `+table+` WHERE id = $1`, id).Scan(&deletedAt)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && deletedAt == nil) {
+		return errors.New("deleted " + entity + " not found")
+	}
+	if err != nil {
+		return err
+	}
+	if deletedAt.Before(deletedSince) {
+		return repository.ErrRestoreWindowExpired
+	}
+	return nil
+}
+
+func rowsAffected(res sql.Result) (bool, error) {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
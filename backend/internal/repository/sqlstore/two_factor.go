@@ -0,0 +1,118 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
+)
+
+type TwoFactorSQLRepository struct {
+	db dbtx
+}
+
+func NewTwoFactorSQLRepository(db *sql.DB) *TwoFactorSQLRepository {
+	return &TwoFactorSQLRepository{db: db}
+}
+
+func (r *TwoFactorSQLRepository) Get(userID string) (*models.TwoFactor, error) {
+	tf := &models.TwoFactor{}
+	err := r.db.QueryRow(
+		`SELECT user_id, secret, enabled, last_used_step, created_at, enabled_at FROM two_factor WHERE user_id = $1`,
+		userID,
+	).Scan(&tf.UserID, &tf.Secret, &tf.Enabled, &tf.LastUsedStep, &tf.CreatedAt, &tf.EnabledAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("two-factor enrollment not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(
+		`SELECT code_hash FROM two_factor_recovery_codes WHERE user_id = $1 ORDER BY position`,
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			return nil, err
+		}
+		tf.RecoveryCodes = append(tf.RecoveryCodes, hash)
+	}
+	return tf, rows.Err()
+}
+
+func (r *TwoFactorSQLRepository) Save(tf *models.TwoFactor) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		err := tx.QueryRow(
+			`INSERT INTO two_factor (user_id, secret, enabled, last_used_step, created_at, enabled_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (user_id) DO UPDATE SET secret = excluded.secret, enabled = excluded.enabled,
+				last_used_step = excluded.last_used_step, enabled_at = excluded.enabled_at
+			RETURNING created_at`,
+			tf.UserID, tf.Secret, tf.Enabled, tf.LastUsedStep, now(), utcPtr(tf.EnabledAt),
+		).Scan(&tf.CreatedAt)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(`DELETE FROM two_factor_recovery_codes WHERE user_id = $1`, tf.UserID); err != nil {
+			return err
+		}
+		for i, hash := range tf.RecoveryCodes {
+			if _, err := tx.Exec(
+				`INSERT INTO two_factor_recovery_codes (user_id, code_hash, position) VALUES ($1, $2, $3)`,
+				tf.UserID, hash, i,
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *TwoFactorSQLRepository) Delete(userID string) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`DELETE FROM two_factor_recovery_codes WHERE user_id = $1`, userID); err != nil {
+			return err
+		}
+		_, err := tx.Exec(`DELETE FROM two_factor WHERE user_id = $1`, userID)
+		return err
+	})
+}
+
+func (r *TwoFactorSQLRepository) UseStep(userID string, step int64) error {
+	res, err := r.db.Exec(
+		`UPDATE two_factor SET last_used_step = $1 WHERE user_id = $2 AND last_used_step < $1`,
+		step, userID,
+	)
+	if err != nil {
+		return err
+	}
+	if ok, err := rowsAffected(res); err != nil || ok {
+		return err
+	}
+	if _, err := r.Get(userID); err != nil {
+		return err
+	}
+	return repository.ErrTwoFactorCodeUsed
+}
+
+func (r *TwoFactorSQLRepository) UseRecoveryCode(userID, hash string) error {
+	res, err := r.db.Exec(
+		`DELETE FROM two_factor_recovery_codes WHERE user_id = $1 AND code_hash = $2`,
+		userID, hash,
+	)
+	if err != nil {
+		return err
+	}
+	if ok, err := rowsAffected(res); err != nil || ok {
+		return err
+	}
+	return repository.ErrTwoFactorCodeUsed
+}
@@ -0,0 +1,312 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
+)
+
+type UserSQLRepository struct {
+	db dbtx
+}
+
+func NewUserSQLRepository(db *sql.DB) *UserSQLRepository {
+	return &UserSQLRepository{db: db}
+}
+
+const userColumns = `u.id, u.name, u.handle, u.email, u.password, u.phone_number, u.languages, u.bio, u.gender,
+	u.date_of_birth, u.avatar_url, u.cover_photo_url, u.followers_only_comments, u.followers_count,
+	u.following_count, u.posts_count, u.tier, u.points, u.subscription_active, u.temporarily_muted,
+	u.muted_until, u.last_abusive_post_date, u.abusive_post_count_today, u.last_debate_host_date,
+	u.debates_hosted_today, u.last_login_date, u.login_streak, u.role, u.email_verified, u.is_bot,
+	u.bot_owner_id, u.created_at, u.updated_at, u.version`
+
+func scanUser(row scanner) (*models.User, error) {
+	user := &models.User{}
+	var languages []byte
+	err := row.Scan(
+		&user.ID, &user.Name, &user.Handle, &user.Email, &user.Password, &user.PhoneNumber, &languages,
+		&user.Bio, &user.Gender, &user.DateOfBirth, &user.AvatarURL, &user.CoverPhotoURL,
+		&user.FollowersOnlyComments, &user.FollowersCount, &user.FollowingCount, &user.PostsCount,
+		&user.Tier, &user.Points, &user.SubscriptionActive, &user.TemporarilyMuted, &user.MutedUntil,
+		&user.LastAbusivePostDate, &user.AbusivePostCountToday, &user.LastDebateHostDate,
+		&user.DebatesHostedToday, &user.LastLoginDate, &user.LoginStreak, &user.Role, &user.EmailVerified,
+		&user.IsBot, &user.BotOwnerID, &user.CreatedAt, &user.UpdatedAt, &user.Version,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := fromJSON(languages, &user.Languages); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func scanUsers(rows *sql.Rows) ([]*models.User, error) {
+	defer rows.Close()
+
+	users := make([]*models.User, 0)
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
+
+func (r *UserSQLRepository) getOne(where string, arg any) (*models.User, error) {
+	user, err := scanUser(r.db.QueryRow(`SELECT `+userColumns+` FROM users u WHERE `+where, arg))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("user not found")
+	}
+	return user, err
+}
+
+func (r *UserSQLRepository) Create(user *models.User) error {
+	languages, err := toJSON(user.Languages)
+	if err != nil {
+		return err
+	}
+
+	if user.Role == "" {
+		user.Role = models.PlatformRoleUser
+	}
+
+	createdAt := now()
+	res, err := r.db.Exec(`
+		INSERT INTO users (id, name, handle, email, password, phone_number, languages, bio, gender,
+			date_of_birth, avatar_url, cover_photo_url, followers_only_comments, followers_count,
+			following_count, posts_count, tier, points, subscription_active, temporarily_muted,
+			muted_until, last_abusive_post_date, abusive_post_count_today, last_debate_host_date,
+			debates_hosted_today, last_login_date, login_streak, role, email_verified, is_bot, bot_owner_id,
+			created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20,
+			$21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32, $33)
+		ON CONFLICT DO NOTHING`,
+		user.ID, user.Name, user.Handle, user.Email, user.Password, user.PhoneNumber, languages,
+		user.Bio, user.Gender, user.DateOfBirth.UTC(), user.AvatarURL, user.CoverPhotoURL,
+		user.FollowersOnlyComments, user.FollowersCount, user.FollowingCount, user.PostsCount,
+		string(user.Tier), user.Points, user.SubscriptionActive, user.TemporarilyMuted, utcPtr(user.MutedUntil),
+		utcPtr(user.LastAbusivePostDate), user.AbusivePostCountToday, utcPtr(user.LastDebateHostDate),
+		user.DebatesHostedToday, utcPtr(user.LastLoginDate), user.LoginStreak, string(user.Role), user.EmailVerified,
+		user.IsBot, user.BotOwnerID, createdAt, createdAt,
+	)
+	if err != nil {
+		return err
+	}
+	if ok, err := rowsAffected(res); err != nil {
+		return err
+	} else if !ok {
+		return errors.New("user already exists")
+	}
+
+	user.CreatedAt = createdAt
+	user.UpdatedAt = createdAt
+	user.Version = 1
+	return nil
+}
+
+func (r *UserSQLRepository) GetByID(id string) (*models.User, error) {
+	return r.getOne(`u.id = $1`, id)
+}
+
+func (r *UserSQLRepository) GetByHandle(handle string) (*models.User, error) {
+	return r.getOne(`u.handle = $1 ORDER BY u.created_at LIMIT 1`, handle)
+}
+
+func (r *UserSQLRepository) GetByEmail(email string) (*models.User, error) {
+	return r.getOne(`u.email = $1 ORDER BY u.created_at LIMIT 1`, email)
+}
+
+func (r *UserSQLRepository) ListBots(ownerID string) ([]*models.User, error) {
+	rows, err := r.db.Query(
+		`SELECT `+userColumns+` FROM users u WHERE u.is_bot AND u.bot_owner_id = $1 ORDER BY u.created_at, u.id`,
+		ownerID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return scanUsers(rows)
+}
+
+func (r *UserSQLRepository) Update(user *models.User) error {
+	languages, err := toJSON(user.Languages)
+	if err != nil {
+		return err
+	}
+
+	updatedAt := now()
+	res, err := r.db.Exec(`
+		UPDATE users SET name = $2, handle = $3, email = $4, password = $5, phone_number = $6,
+			languages = $7, bio = $8, gender = $9, date_of_birth = $10, avatar_url = $11,
+			cover_photo_url = $12, followers_only_comments = $13, followers_count = $14,
+			following_count = $15, posts_count = $16, tier = $17, points = $18,
+			subscription_active = $19, temporarily_muted = $20, muted_until = $21,
+			last_abusive_post_date = $22, abusive_post_count_today = $23, last_debate_host_date = $24,
+			debates_hosted_today = $25, last_login_date = $26, login_streak = $27, role = $28,
+			email_verified = $29, is_bot = $30, bot_owner_id = $31, updated_at = $32, version = version + 1
+		WHERE id = $1 AND version = $33`,
+		user.ID, user.Name, user.Handle, user.Email, user.Password, user.PhoneNumber, languages,
+		user.Bio, user.Gender, user.DateOfBirth.UTC(), user.AvatarURL, user.CoverPhotoURL,
+		user.FollowersOnlyComments, user.FollowersCount, user.FollowingCount, user.PostsCount,
+		string(user.Tier), user.Points, user.SubscriptionActive, user.TemporarilyMuted, utcPtr(user.MutedUntil),
+		utcPtr(user.LastAbusivePostDate), user.AbusivePostCountToday, utcPtr(user.LastDebateHostDate),
+		user.DebatesHostedToday, utcPtr(user.LastLoginDate), user.LoginStreak, string(user.Role),
+		user.EmailVerified, user.IsBot, user.BotOwnerID, updatedAt, user.Version,
+	)
+	if err != nil {
+		return err
+	}
+	if ok, err := rowsAffected(res); err != nil {
+		return err
+	} else if !ok {
+		return updateConflict(r.db, "users", "user", user.ID)
+	}
+
+	user.Version++
+	user.UpdatedAt = updatedAt
+	return nil
+}
+
+func (r *UserSQLRepository) Delete(id string) error {
+	// follows rows go with the user via ON DELETE CASCADE
+	_, err := r.db.Exec(`DELETE FROM users WHERE id = $1`, id)
+	return err
+}
+
+func (r *UserSQLRepository) List(page repository.Page) ([]*models.User, string, error) {
+	query, args, err := keyset(`SELECT `+userColumns+` FROM users u WHERE TRUE`, nil, page, "u.created_at", "u.id", false)
+	if err != nil {
+		return nil, "", err
+	}
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	users, err := scanUsers(rows)
+	if err != nil {
+		return nil, "", err
+	}
+	users, next := nextPage(users, page, func(u *models.User) (time.Time, string) {
+		return u.CreatedAt, u.ID
+	})
+	return users, next, nil
+}
+
+func (r *UserSQLRepository) Follow(followerID, followingID string) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		var exists bool
+		if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM users WHERE id = $1)`, followerID).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			return errors.New("follower not found")
+		}
+		if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM users WHERE id = $1)`, followingID).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			return errors.New("following user not found")
+		}
+
+		res, err := tx.Exec(
+			`INSERT INTO follows (follower_id, following_id, created_at) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`,
+			followerID, followingID, now(),
+		)
+		if err != nil {
+			return err
+		}
+		// Only touch the counters when a new edge was actually created
+		if ok, err := rowsAffected(res); err != nil || !ok {
+			return err
+		}
+
+		if _, err := tx.Exec(`UPDATE users SET following_count = following_count + 1, version = version + 1 WHERE id = $1`, followerID); err != nil {
+			return err
+		}
+		_, err = tx.Exec(`UPDATE users SET followers_count = followers_count + 1, version = version + 1 WHERE id = $1`, followingID)
+		return err
+	})
+}
+
+func (r *UserSQLRepository) Unfollow(followerID, followingID string) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		res, err := tx.Exec(`DELETE FROM follows WHERE follower_id = $1 AND following_id = $2`, followerID, followingID)
+		if err != nil {
+			return err
+		}
+		if ok, err := rowsAffected(res); err != nil || !ok {
+			return err
+		}
+
+		if _, err := tx.Exec(`UPDATE users SET following_count = following_count - 1, version = version + 1 WHERE id = $1 AND following_count > 0`, followerID); err != nil {
+			return err
+		}
+		_, err = tx.Exec(`UPDATE users SET followers_count = followers_count - 1, version = version + 1 WHERE id = $1 AND followers_count > 0`, followingID)
+		return err
+	})
+}
+
+func (r *UserSQLRepository) IsFollowing(followerID, followingID string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(
+		`SELECT EXISTS (SELECT 1 FROM follows WHERE follower_id = $1 AND following_id = $2)`,
+		followerID, followingID,
+	).Scan(&exists)
+	return exists, err
+}
+
+func (r *UserSQLRepository) GetFollowers(userID string, page repository.Page) ([]*models.User, string, error) {
+	return r.listFollows(`
+		SELECT `+userColumns+`, f.created_at FROM follows f JOIN users u ON u.id = f.follower_id
+		WHERE f.following_id = $1`,
+		userID, page,
+	)
+}
+
+func (r *UserSQLRepository) GetFollowing(userID string, page repository.Page) ([]*models.User, string, error) {
+	return r.listFollows(`
+		SELECT `+userColumns+`, f.created_at FROM follows f JOIN users u ON u.id = f.following_id
+		WHERE f.follower_id = $1`,
+		userID, page,
+	)
+}
+
+// listFollows pages through follow relations in the order they were created.
+// query selects the user columns followed by f.created_at.
+func (r *UserSQLRepository) listFollows(query, userID string, page repository.Page) ([]*models.User, string, error) {
+	query, args, err := keyset(query, []any{userID}, page, "f.created_at", "u.id", false)
+	if err != nil {
+		return nil, "", err
+	}
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	users := make([]*models.User, 0)
+	followedAt := make(map[string]time.Time)
+	for rows.Next() {
+		var at time.Time
+		user, err := scanUser(keyedScanner{rows, &at})
+		if err != nil {
+			return nil, "", err
+		}
+		users = append(users, user)
+		followedAt[user.ID] = at
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	users, next := nextPage(users, page, func(u *models.User) (time.Time, string) {
+		return followedAt[u.ID], u.ID
+	})
+	return users, next, nil
+}
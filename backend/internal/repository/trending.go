@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"os"
+	"strconv"
+)
+
+// TrendingConfig holds thresholds for trending logic
+type TrendingConfig struct {
+	MinPosts1h  int
+	MinUsers1h  int
+	MinSpike1h  float64
+	MinPosts24h int
+	MinUsers24h int
+	UserPostCap int
+}
+
+// DefaultTrendingConfig with strict values, configurable via env
+var DefaultTrendingConfig = TrendingConfig{
+	MinPosts1h:  getEnvInt("TRENDING_MIN_POSTS_1H", 250),
+	MinUsers1h:  getEnvInt("TRENDING_MIN_USERS_1H", 100),
+	MinSpike1h:  getEnvFloat("TRENDING_MIN_SPIKE_1H", 3.0),
+	MinPosts24h: getEnvInt("TRENDING_MIN_POSTS_24H", 800),
+	MinUsers24h: getEnvInt("TRENDING_MIN_USERS_24H", 300),
+	UserPostCap: getEnvInt("TRENDING_USER_POST_CAP", 3),
+}
+
+// getEnvInt reads an integer from environment variable or returns default
+func getEnvInt(key string, defaultVal int) int {
+	if valStr := os.Getenv(key); valStr != "" {
+		if val, err := strconv.Atoi(valStr); err == nil {
+			return val
+		}
+	}
+	return defaultVal
+}
+
+// getEnvFloat reads a float from environment variable or returns default
+func getEnvFloat(key string, defaultVal float64) float64 {
+	if valStr := os.Getenv(key); valStr != "" {
+		if val, err := strconv.ParseFloat(valStr, 64); err == nil {
+			return val
+		}
+	}
+	return defaultVal
+}
@@ -0,0 +1,6 @@
+package repository
+
+import "errors"
+
+// ErrTwoFactorCodeUsed is returned when a TOTP or recovery code was already used
+var ErrTwoFactorCodeUsed = errors.New("two-factor code has already been used")
@@ -0,0 +1,11 @@
+package repository
+
+// UnitOfWork groups writes across several repositories so that they are
+// applied together or not at all
+type UnitOfWork interface {
+	// Do calls fn with repositories bound to a single transaction. The
+	// transaction commits when fn returns nil and rolls back when it returns an
+	// error, which Do then returns. Calling Do on the repositories passed to fn
+	// joins the surrounding transaction.
+	Do(fn func(tx *Repositories) error) error
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/v-backend/internal/auth"
+	"github.com/yourusername/v-backend/internal/mail"
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
+)
+
+var ErrEmailAlreadyVerified = errors.New("email is already verified")
+
+const (
+	verifyEmailTTL   = 48 * time.Hour
+	passwordResetTTL = time.Hour
+)
+
+// AccountService mails email verification and password reset links and
+// redeems the tokens in them. Tokens are single use and only their hashes are
+// stored.
+type AccountService struct {
+	authRepo    repository.AuthRepository
+	userRepo    repository.UserRepository
+	tokenRepo   repository.EmailTokenRepository
+	sessionRepo repository.SessionRepository
+	mailer      mail.Mailer
+	appURL      string // Frontend the links in emails point to
+}
+
+func NewAccountService(authRepo repository.AuthRepository, userRepo repository.UserRepository, tokenRepo repository.EmailTokenRepository, sessionRepo repository.SessionRepository, mailer mail.Mailer, appURL string) *AccountService {
+	return &AccountService{
+		authRepo:    authRepo,
+		userRepo:    userRepo,
+		tokenRepo:   tokenRepo,
+		sessionRepo: sessionRepo,
+		mailer:      mailer,
+		appURL:      appURL,
+	}
+}
+
+// SendVerification mails the user a link to verify their email address.
+// Links sent before stop working.
+func (s *AccountService) SendVerification(user *models.User) error {
+	if user.EmailVerified {
+		return ErrEmailAlreadyVerified
+	}
+
+	token, err := s.issue(user.ID, user.Email, models.EmailTokenVerify, verifyEmailTTL)
+	if err != nil {
+		return err
+	}
+	return s.mailer.Send(mail.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nConfirm your email address by opening this link:\n\n%s/verify-email?token=%s\n\nThe link expires in 48 hours.\n",
+			user.Name, s.appURL, token,
+		),
+	})
+}
+
+// VerifyEmail marks the email address the token was sent to as verified
+func (s *AccountService) VerifyEmail(token string) (*models.User, error) {
+	emailToken, err := s.consume(models.EmailTokenVerify, token)
+	if err != nil {
+		return nil, err
+	}
+
+	var user *models.User
+	err = repository.RetryOnConflict(maxConflictRetries, func() error {
+		if user, err = s.userRepo.GetByID(emailToken.UserID); err != nil {
+			return err
+		}
+		// The address changed since the link was sent
+		if user.Email != emailToken.Email {
+			return repository.ErrInvalidEmailToken
+		}
+		if user.EmailVerified {
+			return nil
+		}
+		user.EmailVerified = true
+		return s.userRepo.Update(user)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// RequestPasswordReset mails a reset link if email belongs to an account.
+// Unknown addresses are not an error, so callers cannot tell who has an account.
+func (s *AccountService) RequestPasswordReset(email string) error {
+	authModel, err := s.authRepo.GetByEmail(email)
+	if err != nil {
+		log.Printf("[Account] Password reset requested for unknown email %s", email)
+		return nil
+	}
+	user, err := s.userRepo.GetByID(authModel.UserID)
+	if err != nil {
+		return err
+	}
+
+	token, err := s.issue(user.ID, authModel.Email, models.EmailTokenReset, passwordResetTTL)
+	if err != nil {
+		return err
+	}
+	return s.mailer.Send(mail.Message{
+		To:      authModel.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nSomeone asked to reset the password of your account. If it was you, choose a new one here:\n\n%s/reset-password?token=%s\n\nThe link expires in an hour. If you did not ask for this, ignore this email.\n",
+			user.Name, s.appURL, token,
+		),
+	})
+}
+
+// ResetPassword sets a new password with a token from RequestPasswordReset and
+// signs the user out everywhere
+func (s *AccountService) ResetPassword(token, newPassword string) error {
+	emailToken, err := s.consume(models.EmailTokenReset, token)
+	if err != nil {
+		return err
+	}
+
+	hash, err := auth.HashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+	if err := s.authRepo.UpdatePassword(emailToken.UserID, hash); err != nil {
+		return err
+	}
+
+	// Other reset links and every session opened with the old password are done for
+	if err := s.tokenRepo.InvalidateForUser(emailToken.UserID, models.EmailTokenReset); err != nil {
+		log.Printf("[Account] Failed to invalidate reset tokens of %s: %v", emailToken.UserID, err)
+	}
+	if err := s.sessionRepo.RevokeAllForUser(emailToken.UserID, ""); err != nil {
+		log.Printf("[Account] Failed to revoke sessions of %s after password reset: %v", emailToken.UserID, err)
+	}
+	log.Printf("[Account] Password reset for user %s", emailToken.UserID)
+	return nil
+}
+
+// issue replaces the user's outstanding tokens for purpose with a new one
+func (s *AccountService) issue(userID, email string, purpose models.EmailTokenPurpose, ttl time.Duration) (string, error) {
+	if err := s.tokenRepo.InvalidateForUser(userID, purpose); err != nil {
+		return "", err
+	}
+
+	id := uuid.New().String()
+	token, hash, err := auth.NewEmailToken(string(purpose), id)
+	if err != nil {
+		return "", err
+	}
+	err = s.tokenRepo.Create(&models.EmailToken{
+		ID:        id,
+		UserID:    userID,
+		Purpose:   purpose,
+		Email:     email,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(ttl),
+	})
+	return token, err
+}
+
+func (s *AccountService) consume(purpose models.EmailTokenPurpose, token string) (*models.EmailToken, error) {
+	id, hash, err := auth.ParseEmailToken(string(purpose), token)
+	if err != nil {
+		return nil, repository.ErrInvalidEmailToken
+	}
+	return s.tokenRepo.Consume(id, purpose, hash)
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/yourusername/v-backend/internal/auth"
+	"github.com/yourusername/v-backend/internal/mail"
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
+	"github.com/yourusername/v-backend/internal/repository/memory"
+)
+
+// outbox keeps sent mail so tests can follow the links in it
+type outbox []mail.Message
+
+func (o *outbox) Send(msg mail.Message) error {
+	*o = append(*o, msg)
+	return nil
+}
+
+var tokenInLink = regexp.MustCompile(`token=(\S+)`)
+
+func (o *outbox) lastToken(t *testing.T) string {
+	t.Helper()
+	if len(*o) == 0 {
+		t.Fatal("no mail sent")
+	}
+	match := tokenInLink.FindStringSubmatch((*o)[len(*o)-1].Body)
+	if match == nil {
+		t.Fatal("no token in mail")
+	}
+	return match[1]
+}
+
+func newTestAccounts(t *testing.T) (*AccountService, *repository.Repositories, *outbox) {
+	t.Helper()
+	repos := memory.NewRepositories()
+	if err := repos.User.Create(&models.User{ID: "alice", Name: "Alice", Email: "alice@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	hash, _ := auth.HashPassword("old-password")
+	if err := repos.Auth.CreateAuth(&models.Auth{UserID: "alice", Email: "alice@example.com", PasswordHash: hash}); err != nil {
+		t.Fatal(err)
+	}
+	sent := &outbox{}
+	return NewAccountService(repos.Auth, repos.User, repos.EmailToken, repos.Session, sent, "http://app.test"), repos, sent
+}
+
+func TestVerifyEmailOnlyOnce(t *testing.T) {
+	accounts, repos, sent := newTestAccounts(t)
+
+	user, _ := repos.User.GetByID("alice")
+	if err := accounts.SendVerification(user); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	token := sent.lastToken(t)
+
+	// A verification token is no good as a reset token
+	if err := accounts.ResetPassword(token, "new-password"); !errors.Is(err, repository.ErrInvalidEmailToken) {
+		t.Errorf("reset with verification token = %v, want invalid", err)
+	}
+
+	user, err := accounts.VerifyEmail(token)
+	if err != nil || !user.EmailVerified {
+		t.Fatalf("verify = %+v, %v", user, err)
+	}
+	if _, err := accounts.VerifyEmail(token); !errors.Is(err, repository.ErrInvalidEmailToken) {
+		t.Errorf("second verify = %v, want invalid", err)
+	}
+	if err := accounts.SendVerification(user); !errors.Is(err, ErrEmailAlreadyVerified) {
+		t.Errorf("send to verified user = %v", err)
+	}
+}
+
+func TestResetPasswordSignsOutEverywhere(t *testing.T) {
+	accounts, repos, sent := newTestAccounts(t)
+	sessions := NewSessionService(repos.Session, repos.User, time.Hour)
+	user, _ := repos.User.GetByID("alice")
+	tokens, _ := sessions.Start(user, "test", "127.0.0.1")
+
+	if err := accounts.RequestPasswordReset("nobody@example.com"); err != nil || len(*sent) != 0 {
+		t.Fatalf("reset for unknown email = %v with %d mails", err, len(*sent))
+	}
+	if err := accounts.RequestPasswordReset("alice@example.com"); err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	token := sent.lastToken(t)
+
+	if err := accounts.ResetPassword(token+"x", "new-password"); !errors.Is(err, repository.ErrInvalidEmailToken) {
+		t.Errorf("tampered token = %v, want invalid", err)
+	}
+	if err := accounts.ResetPassword(token, "new-password"); err != nil {
+		t.Fatalf("reset: %v", err)
+	}
+	if err := accounts.ResetPassword(token, "another-password"); !errors.Is(err, repository.ErrInvalidEmailToken) {
+		t.Errorf("second reset = %v, want invalid", err)
+	}
+
+	authModel, _ := repos.Auth.GetByUserID("alice")
+	if !auth.CheckPassword(authModel.PasswordHash, "new-password") {
+		t.Error("password not changed")
+	}
+	if _, _, err := sessions.Refresh(tokens.RefreshToken); err == nil {
+		t.Error("session survived password reset")
+	}
+}
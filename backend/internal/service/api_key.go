@@ -0,0 +1,214 @@
+package service
+
+import (
+	"crypto/subtle"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/v-backend/internal/auth"
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
+)
+
+var (
+	ErrInvalidAPIKey  = errors.New("invalid, expired or revoked API key")
+	ErrInvalidScope   = errors.New("unknown API key scope")
+	ErrNoScopes       = errors.New("an API key needs at least one scope")
+	ErrNotBotOwner    = errors.New("you can only manage your own account and bots")
+	ErrAPIKeyNotFound = errors.New("API key not found")
+	ErrTooManyAPIKeys = errors.New("too many API keys; revoke one first")
+	ErrTooManyBots    = errors.New("too many bots")
+	ErrBotOwner       = errors.New("bots cannot own bots")
+	ErrHandleTaken    = errors.New("handle already exists")
+)
+
+const (
+	maxAPIKeysPerUser = 20
+	maxBotsPerUser    = 10
+	// Last use is only recorded this often, so busy keys don't write on every request
+	apiKeyTouchInterval = time.Minute
+)
+
+// APIKeyService issues API keys that let integrations act as a user or one of
+// their bots, within the scopes the key was given
+type APIKeyService struct {
+	repo     repository.APIKeyRepository
+	userRepo repository.UserRepository
+}
+
+func NewAPIKeyService(repo repository.APIKeyRepository, userRepo repository.UserRepository) *APIKeyService {
+	return &APIKeyService{repo: repo, userRepo: userRepo}
+}
+
+// account returns userID's account if actorID may manage its keys: it is the
+// actor's own account or one of their bots
+func (s *APIKeyService) account(actorID, userID string) (*models.User, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	if user.ID != actorID && !(user.IsBot && user.BotOwnerID == actorID) {
+		return nil, ErrNotBotOwner
+	}
+	return user, nil
+}
+
+// Create issues a key for userID. The key itself is only returned here; just
+// its hash is stored.
+func (s *APIKeyService) Create(actorID, userID string, req models.CreateAPIKeyRequest) (*models.CreateAPIKeyResponse, error) {
+	if _, err := s.account(actorID, userID); err != nil {
+		return nil, err
+	}
+	if len(req.Scopes) == 0 {
+		return nil, ErrNoScopes
+	}
+	var scopes []models.APIScope
+	seen := make(map[models.APIScope]bool)
+	for _, scope := range req.Scopes {
+		if !scope.Valid() {
+			return nil, ErrInvalidScope
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+
+	existing, err := s.repo.ListByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= maxAPIKeysPerUser {
+		return nil, ErrTooManyAPIKeys
+	}
+
+	id := uuid.New().String()
+	key, hash, err := auth.NewAPIKey(id)
+	if err != nil {
+		return nil, err
+	}
+	apiKey := &models.APIKey{
+		ID:        id,
+		UserID:    userID,
+		Name:      strings.TrimSpace(req.Name),
+		KeyHash:   hash,
+		Scopes:    scopes,
+		CreatedAt: time.Now(),
+	}
+	if req.ExpiresInDays > 0 {
+		expiresAt := apiKey.CreatedAt.AddDate(0, 0, req.ExpiresInDays)
+		apiKey.ExpiresAt = &expiresAt
+	}
+	if err := s.repo.Create(apiKey); err != nil {
+		return nil, err
+	}
+
+	log.Printf("[APIKeys] User %s created key %s for %s with scopes %v", actorID, id, userID, scopes)
+	return &models.CreateAPIKeyResponse{Key: key, APIKey: apiKey}, nil
+}
+
+// List returns userID's keys that have not been revoked
+func (s *APIKeyService) List(actorID, userID string) ([]*models.APIKey, error) {
+	if _, err := s.account(actorID, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListByUser(userID)
+}
+
+// Revoke stops one of userID's keys from working
+func (s *APIKeyService) Revoke(actorID, userID, keyID string) error {
+	if _, err := s.account(actorID, userID); err != nil {
+		return err
+	}
+	if err := s.repo.Revoke(userID, keyID); err != nil {
+		return ErrAPIKeyNotFound
+	}
+	log.Printf("[APIKeys] User %s revoked key %s of %s", actorID, keyID, userID)
+	return nil
+}
+
+// Authenticate returns the key and the account it acts as
+func (s *APIKeyService) Authenticate(key string) (*models.APIKey, *models.User, error) {
+	keyID, hash, err := auth.ParseAPIKey(key)
+	if err != nil {
+		return nil, nil, ErrInvalidAPIKey
+	}
+	apiKey, err := s.repo.GetByID(keyID)
+	if err != nil {
+		return nil, nil, ErrInvalidAPIKey
+	}
+	if subtle.ConstantTimeCompare([]byte(apiKey.KeyHash), []byte(hash)) != 1 {
+		return nil, nil, ErrInvalidAPIKey
+	}
+	now := time.Now()
+	if apiKey.RevokedAt != nil || (apiKey.ExpiresAt != nil && now.After(*apiKey.ExpiresAt)) {
+		return nil, nil, ErrInvalidAPIKey
+	}
+	user, err := s.userRepo.GetByID(apiKey.UserID)
+	if err != nil {
+		return nil, nil, ErrInvalidAPIKey
+	}
+
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= apiKeyTouchInterval {
+		if err := s.repo.Touch(apiKey.ID, now); err != nil {
+			log.Printf("[APIKeys] Failed to record use of key %s: %v", apiKey.ID, err)
+		}
+		apiKey.LastUsedAt = &now
+	}
+	return apiKey, user, nil
+}
+
+// CreateBot creates a bot account owned by ownerID. Bots have no password and
+// can only act through API keys.
+func (s *APIKeyService) CreateBot(ownerID string, req models.CreateBotRequest) (*models.User, error) {
+	owner, err := s.userRepo.GetByID(ownerID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	if owner.IsBot {
+		return nil, ErrBotOwner
+	}
+	bots, err := s.userRepo.ListBots(ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if len(bots) >= maxBotsPerUser {
+		return nil, ErrTooManyBots
+	}
+	if existing, _ := s.userRepo.GetByHandle(req.Handle); existing != nil {
+		return nil, ErrHandleTaken
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		name = req.Handle
+	}
+	bot := &models.User{
+		ID:         uuid.New().String(),
+		Name:       name,
+		Handle:     req.Handle,
+		Bio:        req.Bio,
+		Languages:  []string{"English"},
+		AvatarURL:  "https://api.dicebear.com/9.x/bottts/svg?seed=" + req.Handle + "&backgroundColor=b6e3f4,c0aede,ffd5dc,ffdfbf",
+		Role:       models.PlatformRoleUser,
+		IsBot:      true,
+		BotOwnerID: ownerID,
+		Tier:       models.TierSilver,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+	if err := s.userRepo.Create(bot); err != nil {
+		return nil, err
+	}
+
+	log.Printf("[APIKeys] User %s created bot %s (@%s)", ownerID, bot.ID, bot.Handle)
+	return bot, nil
+}
+
+// Bots returns the bots ownerID owns
+func (s *APIKeyService) Bots(ownerID string) ([]*models.User, error) {
+	return s.userRepo.ListBots(ownerID)
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository/memory"
+)
+
+func newTestAPIKeys(t *testing.T) *APIKeyService {
+	t.Helper()
+	repos := memory.NewRepositories()
+	for _, user := range []*models.User{
+		{ID: "alice", Handle: "alice"},
+		{ID: "bob", Handle: "bob"},
+	} {
+		if err := repos.User.Create(user); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return NewAPIKeyService(repos.APIKey, repos.User)
+}
+
+func TestAPIKeyAuthenticateAndRevoke(t *testing.T) {
+	s := newTestAPIKeys(t)
+
+	created, err := s.Create("alice", "alice", models.CreateAPIKeyRequest{
+		Name:   "feed reader",
+		Scopes: []models.APIScope{models.ScopeReadPosts, models.ScopeReadPosts},
+	})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if len(created.APIKey.Scopes) != 1 {
+		t.Errorf("scopes = %v, want duplicates dropped", created.APIKey.Scopes)
+	}
+
+	key, user, err := s.Authenticate(created.Key)
+	if err != nil || user.ID != "alice" || !key.HasScope(models.ScopeReadPosts) {
+		t.Fatalf("authenticate = %v, %v, %v", key, user, err)
+	}
+	if key.LastUsedAt == nil {
+		t.Error("last use not recorded")
+	}
+	if _, _, err := s.Authenticate(created.Key + "x"); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("tampered key = %v", err)
+	}
+
+	if err := s.Revoke("bob", "alice", created.APIKey.ID); !errors.Is(err, ErrNotBotOwner) {
+		t.Errorf("revoke by someone else = %v", err)
+	}
+	if err := s.Revoke("alice", "alice", created.APIKey.ID); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+	if _, _, err := s.Authenticate(created.Key); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("revoked key = %v", err)
+	}
+
+	if _, err := s.Create("alice", "alice", models.CreateAPIKeyRequest{Scopes: []models.APIScope{"admin"}}); !errors.Is(err, ErrInvalidScope) {
+		t.Errorf("unknown scope = %v", err)
+	}
+}
+
+func TestBotKeysBelongToOwner(t *testing.T) {
+	s := newTestAPIKeys(t)
+
+	bot, err := s.CreateBot("alice", models.CreateBotRequest{Name: "Digest", Handle: "digestbot"})
+	if err != nil {
+		t.Fatalf("create bot: %v", err)
+	}
+	if !bot.IsBot || bot.BotOwnerID != "alice" {
+		t.Errorf("bot = %+v", bot)
+	}
+	if _, err := s.CreateBot("bob", models.CreateBotRequest{Handle: "digestbot"}); !errors.Is(err, ErrHandleTaken) {
+		t.Errorf("taken handle = %v", err)
+	}
+	if _, err := s.CreateBot(bot.ID, models.CreateBotRequest{Handle: "botbot"}); !errors.Is(err, ErrBotOwner) {
+		t.Errorf("bot owning a bot = %v", err)
+	}
+
+	if _, err := s.Create("bob", bot.ID, models.CreateAPIKeyRequest{Scopes: []models.APIScope{models.ScopeWritePosts}}); !errors.Is(err, ErrNotBotOwner) {
+		t.Errorf("key for someone else's bot = %v", err)
+	}
+	created, err := s.Create("alice", bot.ID, models.CreateAPIKeyRequest{Scopes: []models.APIScope{models.ScopeWritePosts}})
+	if err != nil {
+		t.Fatalf("create bot key: %v", err)
+	}
+	if _, user, err := s.Authenticate(created.Key); err != nil || user.ID != bot.ID {
+		t.Errorf("bot key acts as %v, %v", user, err)
+	}
+}
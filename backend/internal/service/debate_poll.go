@@ -0,0 +1,243 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
+)
+
+var (
+	ErrInvalidPollPhase = errors.New("phase must be 'pre' or 'post'")
+	ErrInvalidVote      = errors.New("side must be 'agree', 'disagree' or 'undecided'")
+	ErrPollClosed       = errors.New("this poll is not open")
+	ErrBotVote          = errors.New("bots cannot vote in debate polls")
+)
+
+const (
+	// The pre poll stays open this long after the debate starts, for the
+	// audience that arrives on time
+	prePollGrace = 5 * time.Minute
+	// The post poll stays open this long after the debate ends
+	postPollWindow = 10 * time.Minute
+)
+
+// DebatePollService runs the audience polls held before and after a debate,
+// and decides the winner from how the votes moved
+type DebatePollService struct {
+	repo          repository.DebateRepository
+	userRepo      repository.UserRepository
+	access        *DebateRoomAccess
+	pointsService *PointsService
+}
+
+func NewDebatePollService(repo repository.DebateRepository, userRepo repository.UserRepository, access *DebateRoomAccess, pointsService *PointsService) *DebatePollService {
+	return &DebatePollService{
+		repo:          repo,
+		userRepo:      userRepo,
+		access:        access,
+		pointsService: pointsService,
+	}
+}
+
+// PollOpen reports whether debate's poll for phase takes votes at now
+func PollOpen(debate *models.Debate, phase string, now time.Time) bool {
+	switch phase {
+	case models.PollPre:
+		return debate.Status != "ENDED" && now.Before(debate.StartTime.Add(prePollGrace))
+	case models.PollPost:
+		return debate.Status == "ENDED" && debate.Result == nil &&
+			(debate.PollClosesAt == nil || now.Before(*debate.PollClosesAt))
+	default:
+		return false
+	}
+}
+
+// Vote records userID's answer to one of a debate's polls. Each user votes
+// once per poll, and only in debates they may take part in. Bots don't vote.
+func (s *DebatePollService) Vote(debateID, userID, phase, side string) error {
+	if phase != models.PollPre && phase != models.PollPost {
+		return ErrInvalidPollPhase
+	}
+	side = strings.ToLower(strings.TrimSpace(side))
+	if side != "agree" && side != "disagree" && side != "undecided" {
+		return ErrInvalidVote
+	}
+
+	debate, err := s.repo.GetByID(debateID)
+	if err != nil {
+		return ErrDebateNotFound
+	}
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+	if user.IsBot {
+		return ErrBotVote
+	}
+	if err := s.access.CanJoinDebate(debate, userID); err != nil {
+		return err
+	}
+	if !PollOpen(debate, phase, time.Now()) {
+		return ErrPollClosed
+	}
+
+	return s.repo.CastVote(&models.DebateVote{
+		DebateID: debateID,
+		UserID:   userID,
+		Phase:    phase,
+		Side:     side,
+	})
+}
+
+// Poll returns the count of a debate's polls so far. With a userID it also
+// says which polls that user voted in.
+func (s *DebatePollService) Poll(debateID, userID string) (*models.DebatePoll, error) {
+	debate, err := s.repo.GetByID(debateID)
+	if err != nil {
+		return nil, ErrDebateNotFound
+	}
+	votes, err := s.repo.GetVotes(debateID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	poll := &models.DebatePoll{
+		DebateID: debateID,
+		PreOpen:  PollOpen(debate, models.PollPre, now),
+		PostOpen: PollOpen(debate, models.PollPost, now),
+		Result:   debate.Result,
+	}
+	poll.Pre, poll.Post = tally(votes)
+	for _, vote := range votes {
+		if userID != "" && vote.UserID == userID {
+			poll.Voted = append(poll.Voted, vote.Phase)
+		}
+	}
+	return poll, nil
+}
+
+// paired keeps the votes of users who voted in both polls
+func paired(votes []*models.DebateVote) []*models.DebateVote {
+	polls := make(map[string]int)
+	for _, vote := range votes {
+		polls[vote.UserID]++
+	}
+	kept := make([]*models.DebateVote, 0, len(votes))
+	for _, vote := range votes {
+		if polls[vote.UserID] == 2 {
+			kept = append(kept, vote)
+		}
+	}
+	return kept
+}
+
+// tally counts the votes of each poll
+func tally(votes []*models.DebateVote) (pre, post models.VoteTally) {
+	for _, vote := range votes {
+		count := &pre
+		if vote.Phase == models.PollPost {
+			count = &post
+		}
+		switch vote.Side {
+		case "agree":
+			count.Agree++
+		case "disagree":
+			count.Disagree++
+		default:
+			count.Undecided++
+		}
+	}
+	return pre, post
+}
+
+// share is votes as a percentage of total
+func share(votes, total int) float64 {
+	return float64(votes) * 100 / float64(total)
+}
+
+// Decide works out a debate's result from its polls. Only users who voted in
+// both polls count, so the swing measures minds changed rather than who turned
+// up. The side whose share of the vote grew the most wins; without such
+// voters, or with equal swings, it is a draw. The winners are the winning
+// side's speakers: everyone on that side in a structured format, or who had
+// the floor in an open one.
+func (s *DebatePollService) Decide(debate *models.Debate, now time.Time) (*models.DebateResult, error) {
+	votes, err := s.repo.GetVotes(debate.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.DebateResult{Winners: make([]string, 0), DecidedAt: now}
+	result.Pre, result.Post = tally(paired(votes))
+	if result.Pre.Total() == 0 || result.Post.Total() == 0 {
+		return result, nil
+	}
+
+	swing := func(pre, post int) float64 {
+		change := share(post, result.Post.Total()) - share(pre, result.Pre.Total())
+		return math.Round(change*100) / 100
+	}
+	result.AgreeSwing = swing(result.Pre.Agree, result.Post.Agree)
+	result.DisagreeSwing = swing(result.Pre.Disagree, result.Post.Disagree)
+	switch {
+	case result.AgreeSwing > result.DisagreeSwing:
+		result.WinningSide = "agree"
+	case result.DisagreeSwing > result.AgreeSwing:
+		result.WinningSide = "disagree"
+	default:
+		return result, nil
+	}
+
+	if result.Winners, err = s.speakers(debate, result.WinningSide); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// speakers returns who spoke for side in a debate, leaving out the host
+func (s *DebatePollService) speakers(debate *models.Debate, side string) ([]string, error) {
+	participants, err := s.repo.GetAllParticipants(debate.ID)
+	if err != nil {
+		return nil, err
+	}
+	requests, err := s.repo.GetSpeakRequests(debate.ID)
+	if err != nil {
+		return nil, err
+	}
+	hadFloor := make(map[string]bool)
+	for _, request := range requests {
+		if request.TurnStartedAt != nil {
+			hadFloor[request.UserID] = true
+		}
+	}
+	structured := len(rounds(debate)) > 0
+
+	speakers := make([]string, 0)
+	for _, p := range participants {
+		if p.UserID == debate.HostID || strings.ToLower(strings.TrimSpace(p.Side)) != side {
+			continue
+		}
+		if (structured || hadFloor[p.UserID]) && !slices.Contains(speakers, p.UserID) {
+			speakers = append(speakers, p.UserID)
+		}
+	}
+	slices.Sort(speakers)
+	return speakers, nil
+}
+
+// Award gives a debate's winners their points. It is called once, after the
+// result that names them is saved.
+func (s *DebatePollService) Award(debate *models.Debate) {
+	for _, userID := range debate.Result.Winners {
+		if err := s.pointsService.UpdateUserPoints(userID, ActionDebateWin); err != nil {
+			log.Printf("[DebatePoll] Failed to award the win in debate %s to %s: %v", debate.ID, userID, err)
+		}
+	}
+}
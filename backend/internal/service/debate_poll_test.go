@@ -0,0 +1,61 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository/memory"
+)
+
+func TestDebatePollVoteNeedsAccess(t *testing.T) {
+	repos := memory.NewRepositories()
+	for _, user := range []*models.User{
+		{ID: "host", Handle: "host"},
+		{ID: "fan", Handle: "fan"},
+		{ID: "stranger", Handle: "stranger"},
+		{ID: "bot", Handle: "bot", IsBot: true, BotOwnerID: "fan"},
+	} {
+		if err := repos.User.Create(user); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, follower := range []string{"fan", "bot"} {
+		if err := repos.User.Follow(follower, "host"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	start := time.Now().Add(time.Hour)
+	for _, debate := range []*models.Debate{
+		{ID: "private", HostID: "host", Type: "PRIVATE", Status: "SCHEDULED", StartTime: start},
+		{ID: "locked", HostID: "host", Type: "PUBLIC", Status: "SCHEDULED", StartTime: start,
+			IsLocked: true, UnlockPhase: models.UnlockPhaseEarlyAccess, EarlyAccessRoles: []string{"followers"}},
+	} {
+		if err := repos.Debate.Create(debate); err != nil {
+			t.Fatal(err)
+		}
+	}
+	poll := newDebatePoll(repos)
+
+	for _, tc := range []struct {
+		debate, user string
+		want         error
+	}{
+		{"private", "fan", nil},
+		{"private", "stranger", ErrRoomForbidden},
+		{"private", "bot", ErrBotVote},
+		{"locked", "fan", nil},
+		{"locked", "stranger", ErrDebateLocked},
+		{"locked", "bot", ErrBotVote},
+	} {
+		if err := poll.Vote(tc.debate, tc.user, models.PollPre, "agree"); !errors.Is(err, tc.want) {
+			t.Errorf("%s voting in %s = %v, want %v", tc.user, tc.debate, err, tc.want)
+		}
+	}
+
+	votes, _ := repos.Debate.GetVotes("private")
+	if len(votes) != 1 || votes[0].UserID != "fan" {
+		t.Errorf("votes = %+v, want fan's only", votes)
+	}
+}
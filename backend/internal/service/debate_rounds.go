@@ -0,0 +1,201 @@
+package service
+
+import (
+	"encoding/json"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/yourusername/v-backend/internal/models"
+)
+
+// rounds returns the rounds of debate's format; none for an open floor
+func rounds(debate *models.Debate) []models.DebateRound {
+	format, ok := models.FindDebateFormat(debate.Format)
+	if !ok {
+		return nil
+	}
+	return format.Rounds
+}
+
+// CurrentRound returns the round debate is in at now, if it is in one
+func CurrentRound(debate *models.Debate, now time.Time) (*models.RoundStatus, bool) {
+	all := rounds(debate)
+	if debate.Status != "ACTIVE" || debate.RoundStartedAt == nil || debate.CurrentRound >= len(all) {
+		return nil, false
+	}
+	round := all[debate.CurrentRound]
+	endsAt := debate.RoundStartedAt.Add(time.Duration(round.Seconds) * time.Second)
+	remaining := int(endsAt.Sub(now).Round(time.Second).Seconds())
+	if remaining < 0 {
+		remaining = 0
+	}
+	return &models.RoundStatus{
+		DebateID:         debate.ID,
+		Index:            debate.CurrentRound,
+		TotalRounds:      len(all),
+		Round:            round,
+		StartedAt:        *debate.RoundStartedAt,
+		EndsAt:           endsAt,
+		RemainingSeconds: remaining,
+	}, true
+}
+
+// nextTransition returns when debate next needs the scheduler: its start, the
+// end of its current round, its end, the close of its post poll, or the time a
+// locked debate opens to everyone
+func nextTransition(debate *models.Debate) (time.Time, bool) {
+	at, ok := nextStatusTransition(debate)
+	if debate.IsLocked && debate.UnlockAt != nil && debate.Status != "ENDED" && (!ok || debate.UnlockAt.Before(at)) {
+		return *debate.UnlockAt, true
+	}
+	return at, ok
+}
+
+// nextStatusTransition returns when debate's status, round or poll next changes
+func nextStatusTransition(debate *models.Debate) (time.Time, bool) {
+	switch debate.Status {
+	case "SCHEDULED":
+		return debate.StartTime, true
+	case "ACTIVE":
+		var at time.Time
+		scheduled := false
+		if debate.EndTime != nil {
+			at, scheduled = *debate.EndTime, true
+		}
+		if len(rounds(debate)) > 0 {
+			roundEnd := time.Now() // Started early, so the first round is due now
+			if round, ok := CurrentRound(debate, time.Now()); ok {
+				roundEnd = round.EndsAt
+			}
+			if !scheduled || roundEnd.Before(at) {
+				at, scheduled = roundEnd, true
+			}
+		}
+		return at, scheduled
+	case "ENDED":
+		if debate.Result == nil && debate.PollClosesAt != nil {
+			return *debate.PollClosesAt, true
+		}
+		return time.Time{}, false
+	default:
+		return time.Time{}, false
+	}
+}
+
+// roundKey identifies the round a debate is in, to tell when it moved on
+func roundKey(debate *models.Debate) string {
+	if debate.RoundStartedAt == nil {
+		return ""
+	}
+	return debate.RoundStartedAt.UTC().Format(time.RFC3339Nano)
+}
+
+// advanceRounds moves an active debate through the rounds that are over at
+// now. The first round starts at the debate's start time, or now if the host
+// started it early, and the debate ends with its last round.
+func advanceRounds(debate *models.Debate, now time.Time) {
+	all := rounds(debate)
+	if debate.Status != "ACTIVE" || len(all) == 0 {
+		return
+	}
+	if debate.RoundStartedAt == nil {
+		start := debate.StartTime
+		if start.After(now) {
+			start = now
+		}
+		debate.CurrentRound = 0
+		debate.RoundStartedAt = &start
+	}
+	for debate.CurrentRound < len(all) {
+		end := debate.RoundStartedAt.Add(time.Duration(all[debate.CurrentRound].Seconds) * time.Second)
+		if now.Before(end) {
+			return
+		}
+		debate.CurrentRound++
+		debate.RoundStartedAt = &end
+	}
+	debate.Status = "ENDED"
+}
+
+// roundChanged mutes whoever is out of turn in the new round and tells the
+// room about it
+func (s *DebateScheduler) roundChanged(debate *models.Debate, now time.Time) {
+	status, ok := CurrentRound(debate, now)
+	if !ok {
+		return
+	}
+
+	changed, err := s.EnforceTurn(debate, "")
+	if err != nil {
+		log.Printf("[DebateScheduler] Failed to enforce turns in debate %s: %v", debate.ID, err)
+	}
+	if changed && s.participantsChanged != nil {
+		s.participantsChanged(debate.ID)
+	}
+
+	payload, _ := json.Marshal(struct {
+		Type string `json:"type"`
+		*models.RoundStatus
+	}{"debate:round_changed", status})
+	s.hub.Broadcast <- Message{RoomID: debate.ID, Payload: payload}
+}
+
+// EnforceTurn host-mutes the participants who may not speak in the current
+// round and unmutes those who may, leaving the host alone. Once the debate has
+// a speaker queue, only those holding the floor are unmuted; the queue gives
+// it to the others. With a userID it only looks at that participant, as when
+// someone joins mid-round, so the host's own mutes of others stand. It reports
+// whether anyone changed.
+func (s *DebateScheduler) EnforceTurn(debate *models.Debate, userID string) (bool, error) {
+	status, ok := CurrentRound(debate, time.Now())
+	if !ok {
+		return false, nil
+	}
+
+	// Hold the queue still, so no one is given the floor while this mutes
+	if s.queue != nil {
+		s.queue.mu.Lock()
+		defer s.queue.mu.Unlock()
+	}
+	requests, err := s.repo.GetSpeakRequests(debate.ID)
+	if err != nil {
+		return false, err
+	}
+	speaking := make(map[string]bool)
+	for _, request := range requests {
+		if request.Status == "approved" {
+			speaking[request.UserID] = true
+		}
+	}
+
+	participants, err := s.repo.GetParticipants(debate.ID)
+	if err != nil {
+		return false, err
+	}
+	changed := false
+	for _, p := range participants {
+		if p.UserID == debate.HostID || (userID != "" && p.UserID != userID) {
+			continue
+		}
+		muted := !inTurn(status.Round, p.Side)
+		if p.IsMutedByHost == muted || (!muted && len(requests) > 0 && !speaking[p.UserID]) {
+			continue
+		}
+		p.IsMutedByHost = muted
+		if err := s.repo.UpdateParticipant(p); err != nil {
+			return changed, err
+		}
+		changed = true
+	}
+	return changed, nil
+}
+
+// inTurn reports whether someone on side may speak in round
+func inTurn(round models.DebateRound, side string) bool {
+	side = strings.ToLower(strings.TrimSpace(side))
+	if round.Side == "" {
+		return side == "agree" || side == "disagree"
+	}
+	return side == round.Side
+}
@@ -0,0 +1,253 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
+)
+
+// DebateScheduler starts and ends debates on time, runs the round clock of
+// debates held in a format, closes the post poll of debates that ended, and
+// opens locked debates to everyone when their early access is over.
+// Each pending debate has a timer for its next transition; on startup the
+// pending debates are scanned again, so transitions missed while the server
+// was down happen right away.
+type DebateScheduler struct {
+	repo      repository.DebateRepository
+	statsRepo repository.DebateStatsRepository
+	poll      *DebatePollService
+	hub       *Hub
+	queue     *SpeakerQueueService
+
+	// Called when the round clock mutes or unmutes participants, so the room
+	// gets the new participant list
+	participantsChanged func(debateID string)
+
+	mu     sync.Mutex
+	timers map[string]*time.Timer
+}
+
+func NewDebateScheduler(repo repository.DebateRepository, statsRepo repository.DebateStatsRepository, poll *DebatePollService, hub *Hub) *DebateScheduler {
+	return &DebateScheduler{
+		repo:      repo,
+		statsRepo: statsRepo,
+		poll:      poll,
+		hub:       hub,
+		timers:    make(map[string]*time.Timer),
+	}
+}
+
+// Start schedules every scheduled or active debate
+func (s *DebateScheduler) Start() error {
+	debates, err := s.repo.ListPending()
+	if err != nil {
+		return err
+	}
+	for _, debate := range debates {
+		s.Schedule(debate)
+	}
+	log.Printf("[DebateScheduler] Scheduled %d pending debate(s)", len(debates))
+	return nil
+}
+
+// SetParticipantsNotifier sets what is called when the round clock changes
+// who is muted in a debate
+func (s *DebateScheduler) SetParticipantsNotifier(notify func(debateID string)) {
+	s.participantsChanged = notify
+}
+
+// SetSpeakerQueue sets the queue that gives speakers the floor, so the round
+// clock leaves unmuting them to it
+func (s *DebateScheduler) SetSpeakerQueue(queue *SpeakerQueueService) {
+	s.queue = queue
+}
+
+// Schedule sets the timer for debate's next transition. It is called when a
+// debate is created or its times change.
+func (s *DebateScheduler) Schedule(debate *models.Debate) {
+	at, ok := nextTransition(debate)
+	if !ok {
+		s.Cancel(debate.ID)
+		return
+	}
+
+	id := debate.ID
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if timer, ok := s.timers[id]; ok {
+		timer.Stop()
+	}
+	s.timers[id] = time.AfterFunc(time.Until(at), func() { s.advance(id) })
+}
+
+// Cancel drops the timer of a debate that was deleted or has nothing left to
+// schedule
+func (s *DebateScheduler) Cancel(debateID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if timer, ok := s.timers[debateID]; ok {
+		timer.Stop()
+		delete(s.timers, debateID)
+	}
+}
+
+// CancelAll drops every timer, once all debates were cleared
+func (s *DebateScheduler) CancelAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, timer := range s.timers {
+		timer.Stop()
+		delete(s.timers, id)
+	}
+}
+
+// advance moves a debate through every transition that is due and schedules
+// the next one
+func (s *DebateScheduler) advance(debateID string) {
+	s.mu.Lock()
+	delete(s.timers, debateID)
+	s.mu.Unlock()
+
+	debate, err := s.transition(debateID, time.Now())
+	if err != nil {
+		log.Printf("[DebateScheduler] Failed to advance debate %s: %v", debateID, err)
+		return
+	}
+	if debate != nil {
+		s.Schedule(debate)
+	}
+}
+
+// transition applies the transitions due at now. When another writer changed
+// the debate first it is read again, so a transition is never applied twice.
+func (s *DebateScheduler) transition(debateID string, now time.Time) (*models.Debate, error) {
+	for attempt := 0; attempt < 3; attempt++ {
+		debate, err := s.repo.GetByID(debateID)
+		if err != nil {
+			// Deleted since it was scheduled
+			return nil, nil
+		}
+
+		oldStatus, oldRound, decided := debate.Status, roundKey(debate), debate.Result != nil
+		if debate.Status == "SCHEDULED" && !now.Before(debate.StartTime) {
+			debate.Status = "ACTIVE"
+		}
+		advanceRounds(debate, now)
+		if debate.Status == "ACTIVE" && debate.EndTime != nil && !now.Before(*debate.EndTime) {
+			debate.Status = "ENDED"
+		}
+		roundChanged := roundKey(debate) != oldRound
+
+		unlocked := false
+		if debate.IsLocked && debate.UnlockAt != nil && !now.Before(*debate.UnlockAt) {
+			debate.IsLocked = false
+			debate.UnlockPhase = models.UnlockPhasePublic
+			unlocked = true
+		}
+
+		// The post poll opens when the debate ends and decides its result
+		// when it closes
+		pollChanged := false
+		if debate.Status == "ENDED" && debate.Result == nil {
+			if debate.PollClosesAt == nil {
+				closes := now.Add(postPollWindow)
+				debate.PollClosesAt = &closes
+				pollChanged = true
+			} else if !now.Before(*debate.PollClosesAt) {
+				if debate.Result, err = s.poll.Decide(debate, now); err != nil {
+					return nil, err
+				}
+				pollChanged = true
+			}
+		}
+		if debate.Status == oldStatus && !roundChanged && !pollChanged && !unlocked {
+			return debate, nil
+		}
+
+		err = s.repo.Update(debate)
+		var conflict *repository.ConflictError
+		if errors.As(err, &conflict) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		log.Printf("[DebateScheduler] Debate %s: %s -> %s, round %d", debate.ID, oldStatus, debate.Status, debate.CurrentRound)
+		if unlocked {
+			s.broadcast("debate:unlocked", debate)
+		}
+		s.announce(debate, oldStatus)
+		if roundChanged && debate.Status == "ACTIVE" {
+			s.roundChanged(debate, now)
+		}
+		// Saving the result first means only one writer awards it
+		if debate.Result != nil && !decided {
+			log.Printf("[DebateScheduler] Debate %s decided: %q won", debate.ID, debate.Result.WinningSide)
+			s.poll.Award(debate)
+			s.broadcast("debate:result", debate)
+		}
+		return debate, nil
+	}
+	return nil, errors.New("debate kept changing while advancing it")
+}
+
+// Changed handles a debate written outside the scheduler, such as a host
+// starting or ending it early, opening it to everyone or moving its times: the
+// lifecycle events go out, a debate started early gets its first round, one
+// ended early opens its post poll, one whose unlock time came is opened, and
+// its timer is set again
+func (s *DebateScheduler) Changed(debate *models.Debate, oldStatus string) {
+	s.announce(debate, oldStatus)
+	s.advance(debate.ID)
+}
+
+// announce broadcasts the lifecycle events for a status change, and records
+// the final stats of a debate that ended
+func (s *DebateScheduler) announce(debate *models.Debate, oldStatus string) {
+	if oldStatus == "SCHEDULED" && debate.Status != "SCHEDULED" {
+		s.broadcast("debate:started", debate)
+	}
+	if oldStatus != "ENDED" && debate.Status == "ENDED" {
+		s.broadcast("debate:ended", debate)
+		if err := s.recordStats(debate); err != nil {
+			log.Printf("[DebateScheduler] Failed to record stats for debate %s: %v", debate.ID, err)
+		}
+	}
+}
+
+// recordStats adds the debate's final sides to its topic's stats. The stats
+// repository counts each debate once.
+func (s *DebateScheduler) recordStats(debate *models.Debate) error {
+	participants, err := s.repo.GetAllParticipants(debate.ID)
+	if err != nil {
+		return err
+	}
+	users := make(map[string]bool)
+	for _, p := range participants {
+		if p.UserID != debate.HostID {
+			users[p.UserID] = true
+		}
+	}
+
+	_, err = s.statsRepo.RecordStats(debate.Title, debate.AgreeCount, debate.DisagreeCount, len(users), debate.ID)
+	return err
+}
+
+// broadcast sends a lifecycle event to the debate's room and the debates list
+func (s *DebateScheduler) broadcast(msgType string, debate *models.Debate) {
+	payload, _ := json.Marshal(map[string]interface{}{
+		"type":     msgType,
+		"debateId": debate.ID,
+		"status":   debate.Status,
+		"debate":   debate,
+	})
+	for _, room := range []string{debate.ID, RoomDebatesList} {
+		s.hub.Broadcast <- Message{RoomID: room, Payload: payload}
+	}
+}
@@ -0,0 +1,278 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
+	"github.com/yourusername/v-backend/internal/repository/memory"
+)
+
+func newDebatePoll(repos *repository.Repositories) *DebatePollService {
+	access := NewDebateRoomAccess(repos.Debate, repos.User, repos.Community)
+	return NewDebatePollService(repos.Debate, repos.User, access, NewPointsService(repos.User))
+}
+
+// nextEvent reads the next lifecycle event the scheduler broadcast. The hub is
+// not running, so broadcasts wait in its channel.
+func nextEvent(t *testing.T, hub *Hub) (string, string) {
+	t.Helper()
+	select {
+	case msg := <-hub.Broadcast:
+		var event struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal(msg.Payload, &event); err != nil {
+			t.Fatal(err)
+		}
+		return msg.RoomID, event.Type
+	case <-time.After(2 * time.Second):
+		t.Fatal("no event broadcast")
+		return "", ""
+	}
+}
+
+func TestDebateSchedulerRunsLifecycle(t *testing.T) {
+	repos := memory.NewRepositories()
+	hub := NewHub(NewDebateRoomAccess(repos.Debate, repos.User, repos.Community))
+	scheduler := NewDebateScheduler(repos.Debate, repos.DebateStats, newDebatePoll(repos), hub)
+
+	start := time.Now().Add(50 * time.Millisecond)
+	end := start.Add(50 * time.Millisecond)
+	debate := &models.Debate{ID: "d1", Title: "Cats vs dogs", HostID: "host", Status: "SCHEDULED", StartTime: start, EndTime: &end}
+	if err := repos.Debate.Create(debate); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []*models.DebateParticipant{
+		{DebateID: "d1", UserID: "host"},
+		{DebateID: "d1", UserID: "alice", Side: "agree"},
+		{DebateID: "d1", UserID: "bob", Side: "disagree"},
+	} {
+		if err := repos.Debate.AddParticipant(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := scheduler.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []struct{ room, event string }{
+		{"d1", "debate:started"},
+		{RoomDebatesList, "debate:started"},
+		{"d1", "debate:ended"},
+		{RoomDebatesList, "debate:ended"},
+	} {
+		if room, event := nextEvent(t, hub); room != want.room || event != want.event {
+			t.Fatalf("got %s in %s, want %s in %s", event, room, want.event, want.room)
+		}
+	}
+
+	got, _ := repos.Debate.GetByID("d1")
+	if got.Status != "ENDED" {
+		t.Errorf("status = %s, want ENDED", got.Status)
+	}
+	stats, err := repos.DebateStats.GetByTopic("Cats vs dogs")
+	if err != nil || stats.SessionsCount != 1 || stats.TotalParticipants != 2 || stats.TotalAgree != 1 || stats.TotalDisagree != 1 {
+		t.Errorf("stats = %+v, %v", stats, err)
+	}
+}
+
+func TestDebateSchedulerCancelsTimers(t *testing.T) {
+	repos := memory.NewRepositories()
+	hub := NewHub(NewDebateRoomAccess(repos.Debate, repos.User, repos.Community))
+	scheduler := NewDebateScheduler(repos.Debate, repos.DebateStats, newDebatePoll(repos), hub)
+
+	start := time.Now().Add(time.Hour)
+	for _, id := range []string{"d1", "d2", "d3"} {
+		if err := repos.Debate.Create(&models.Debate{ID: id, HostID: "host", Status: "SCHEDULED", StartTime: start}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := scheduler.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	scheduler.Cancel("d1")
+	if _, ok := scheduler.timers["d1"]; ok || len(scheduler.timers) != 2 {
+		t.Fatalf("timers = %v, want d2's and d3's", scheduler.timers)
+	}
+	scheduler.CancelAll()
+	if len(scheduler.timers) != 0 {
+		t.Errorf("timers = %v, want none after cancelling all", scheduler.timers)
+	}
+}
+
+func TestDebateSchedulerCatchesUpOnStart(t *testing.T) {
+	repos := memory.NewRepositories()
+	hub := NewHub(NewDebateRoomAccess(repos.Debate, repos.User, repos.Community))
+
+	// Both its start and end passed while the server was down
+	end := time.Now().Add(-time.Minute)
+	debate := &models.Debate{ID: "d1", Title: "Missed", HostID: "host", Status: "SCHEDULED", StartTime: end.Add(-time.Hour), EndTime: &end}
+	if err := repos.Debate.Create(debate); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewDebateScheduler(repos.Debate, repos.DebateStats, newDebatePoll(repos), hub).Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	var events []string
+	for i := 0; i < 4; i++ {
+		_, event := nextEvent(t, hub)
+		events = append(events, event)
+	}
+	if events[0] != "debate:started" || events[3] != "debate:ended" {
+		t.Errorf("events = %v", events)
+	}
+	if got, _ := repos.Debate.GetByID("d1"); got.Status != "ENDED" {
+		t.Errorf("status = %s, want ENDED", got.Status)
+	}
+}
+
+func TestDebateSchedulerRunsRounds(t *testing.T) {
+	repos := memory.NewRepositories()
+	hub := NewHub(NewDebateRoomAccess(repos.Debate, repos.User, repos.Community))
+	scheduler := NewDebateScheduler(repos.Debate, repos.DebateStats, newDebatePoll(repos), hub)
+	notified := make(chan string, 1)
+	scheduler.SetParticipantsNotifier(func(debateID string) { notified <- debateID })
+
+	// Eight minutes in, an Oxford debate is in its second round, the opening
+	// against the motion
+	debate := &models.Debate{ID: "d1", HostID: "host", Status: "ACTIVE", Format: models.FormatOxford, StartTime: time.Now().Add(-8 * time.Minute)}
+	if err := repos.Debate.Create(debate); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []*models.DebateParticipant{
+		{DebateID: "d1", UserID: "host", Side: "agree"},
+		{DebateID: "d1", UserID: "alice", Side: "agree"},
+		{DebateID: "d1", UserID: "bob", Side: "disagree"},
+	} {
+		if err := repos.Debate.AddParticipant(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := scheduler.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	if room, event := nextEvent(t, hub); room != "d1" || event != "debate:round_changed" {
+		t.Fatalf("got %s in %s, want debate:round_changed in d1", event, room)
+	}
+	if id := <-notified; id != "d1" {
+		t.Errorf("notified about %s", id)
+	}
+
+	got, _ := repos.Debate.GetByID("d1")
+	round, ok := CurrentRound(got, time.Now())
+	if !ok || round.Index != 1 || round.Round.Side != "disagree" || round.RemainingSeconds > 6*60 {
+		t.Fatalf("round = %+v, %v", round, ok)
+	}
+	participants, _ := repos.Debate.GetParticipants("d1")
+	for _, p := range participants {
+		if want := p.UserID == "alice"; p.IsMutedByHost != want {
+			t.Errorf("%s muted by host = %v, want %v", p.UserID, p.IsMutedByHost, want)
+		}
+	}
+}
+
+func TestDebateSchedulerDecidesWinner(t *testing.T) {
+	repos := memory.NewRepositories()
+	hub := NewHub(NewDebateRoomAccess(repos.Debate, repos.User, repos.Community))
+	poll := newDebatePoll(repos)
+	for _, id := range []string{"host", "alice", "bob"} {
+		if err := repos.User.Create(&models.User{ID: id, Handle: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The post poll closed while the server was down
+	closed := time.Now().Add(-time.Minute)
+	debate := &models.Debate{ID: "d1", HostID: "host", Status: "ENDED", Format: models.FormatOxford, StartTime: closed.Add(-time.Hour), PollClosesAt: &closed}
+	if err := repos.Debate.Create(debate); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []*models.DebateParticipant{
+		{DebateID: "d1", UserID: "host", Side: "agree"},
+		{DebateID: "d1", UserID: "alice", Side: "agree"},
+		{DebateID: "d1", UserID: "bob", Side: "disagree"},
+	} {
+		if err := repos.Debate.AddParticipant(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Agree goes from a quarter of the vote to three quarters
+	for i, side := range []string{"agree", "disagree", "disagree", "undecided"} {
+		if err := repos.Debate.CastVote(&models.DebateVote{DebateID: "d1", UserID: string(rune('a' + i)), Phase: models.PollPre, Side: side}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i, side := range []string{"agree", "agree", "agree", "disagree"} {
+		if err := repos.Debate.CastVote(&models.DebateVote{DebateID: "d1", UserID: string(rune('a' + i)), Phase: models.PollPost, Side: side}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Voters of one poll only don't count
+	for _, vote := range []*models.DebateVote{
+		{DebateID: "d1", UserID: "e", Phase: models.PollPre, Side: "agree"},
+		{DebateID: "d1", UserID: "f", Phase: models.PollPost, Side: "disagree"},
+	} {
+		if err := repos.Debate.CastVote(vote); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := NewDebateScheduler(repos.Debate, repos.DebateStats, poll, hub).Start(); err != nil {
+		t.Fatal(err)
+	}
+	if room, event := nextEvent(t, hub); room != "d1" || event != "debate:result" {
+		t.Fatalf("got %s in %s, want debate:result in d1", event, room)
+	}
+
+	got, _ := repos.Debate.GetByID("d1")
+	if got.Result == nil || got.Result.WinningSide != "agree" || got.Result.AgreeSwing != 50 || got.Result.DisagreeSwing != -25 ||
+		len(got.Result.Winners) != 1 || got.Result.Winners[0] != "alice" {
+		t.Fatalf("result = %+v", got.Result)
+	}
+
+	// Decided debates are not scheduled again, so the win is awarded once
+	if err := NewDebateScheduler(repos.Debate, repos.DebateStats, poll, hub).Start(); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	for _, id := range []string{"alice", "bob", "host"} {
+		user, _ := repos.User.GetByID(id)
+		if want := map[string]int{"alice": 10}[id]; user.Points != want {
+			t.Errorf("%s has %d points, want %d", id, user.Points, want)
+		}
+	}
+}
+
+func TestDebateSchedulerUnlocks(t *testing.T) {
+	repos := memory.NewRepositories()
+	hub := NewHub(NewDebateRoomAccess(repos.Debate, repos.User, repos.Community))
+	scheduler := NewDebateScheduler(repos.Debate, repos.DebateStats, newDebatePoll(repos), hub)
+
+	unlockAt := time.Now().Add(50 * time.Millisecond)
+	debate := &models.Debate{
+		ID: "d1", HostID: "host", Status: "SCHEDULED", StartTime: time.Now().Add(time.Hour),
+		IsLocked: true, UnlockPhase: models.UnlockPhaseEarlyAccess, EarlyAccessRoles: []string{models.EarlyAccessFollowers}, UnlockAt: &unlockAt,
+	}
+	if err := repos.Debate.Create(debate); err != nil {
+		t.Fatal(err)
+	}
+	if err := scheduler.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, room := range []string{"d1", RoomDebatesList} {
+		if got, event := nextEvent(t, hub); got != room || event != "debate:unlocked" {
+			t.Fatalf("got %s in %s, want debate:unlocked in %s", event, got, room)
+		}
+	}
+	got, _ := repos.Debate.GetByID("d1")
+	if got.IsLocked || got.UnlockPhase != models.UnlockPhasePublic || got.Status != "SCHEDULED" {
+		t.Errorf("debate = locked %v, phase %d, %s; want unlocked, public, SCHEDULED", got.IsLocked, got.UnlockPhase, got.Status)
+	}
+}
@@ -38,6 +38,9 @@ type Hub struct {
 	// Message handlers for specific message types
 	messageHandlers map[string]MessageHandler
 
+	// Who may join each room and what they may send there
+	access RoomAuthorizer
+
 	mu sync.RWMutex
 }
 
@@ -45,18 +48,28 @@ type Message struct {
 	RoomID  string
 	Payload []byte
 	Sender  *Client
+
+	fromClient bool // Read from Sender's connection rather than made by the server
 }
 
-func NewHub() *Hub {
+func NewHub(access RoomAuthorizer) *Hub {
 	return &Hub{
 		rooms:           make(map[string]map[*Client]bool),
 		Register:        make(chan *Client),
 		Unregister:      make(chan *Client),
 		Broadcast:       make(chan Message, 100), // Buffered channel to prevent blocking
 		messageHandlers: make(map[string]MessageHandler),
+		access:          access,
 	}
 }
 
+// Authorize reports why userID may not join roomID, if they may not. The
+// WebSocket handshake checks it so refused clients get a proper status, and
+// Run checks it again on register.
+func (h *Hub) Authorize(roomID, userID string) error {
+	return h.access.CanJoin(roomID, userID)
+}
+
 // RegisterMessageHandler registers a handler for a specific message type
 func (h *Hub) RegisterMessageHandler(msgType string, handler MessageHandler) {
 	h.mu.Lock()
@@ -68,6 +81,11 @@ func (h *Hub) Run() {
 	for {
 		select {
 		case client := <-h.Register:
+			if err := h.Authorize(client.RoomID, client.UserID); err != nil {
+				log.Printf("[Hub] Refused client %s in room %s: %v", client.UserID, client.RoomID, err)
+				close(client.Send)
+				continue
+			}
 			h.mu.Lock()
 			if h.rooms[client.RoomID] == nil {
 				h.rooms[client.RoomID] = make(map[*Client]bool)
@@ -103,6 +121,16 @@ func (h *Hub) Run() {
 			}, client)
 
 		case message := <-h.Broadcast:
+			// Drop what clients send to rooms they were refused or have left
+			if message.fromClient {
+				h.mu.RLock()
+				member := h.rooms[message.RoomID][message.Sender]
+				h.mu.RUnlock()
+				if !member {
+					continue
+				}
+			}
+
 			// Check if this message needs special handling before broadcasting
 			var msgData map[string]interface{}
 			if err := json.Unmarshal(message.Payload, &msgData); err == nil {
@@ -186,26 +214,29 @@ func (c *Client) ReadPump() {
 			// Add senderId to the message
 			msgData["senderId"] = c.UserID
 			
-			// Check if this is a debate room message that needs special handling
+			// Clients may only send what the room allows, so they cannot
+			// fake server events or host actions
 			msgType, _ := msgData["type"].(string)
-			if msgType != "" && (msgType == "debate:join_room" || msgType == "debate:leave_room" || 
-				msgType == "debate:self_mute_change" || msgType == "debate:mute_change") {
-				// Store handler in client for debate-specific handling
-				// We'll handle this in the websocket handler
-				// For now, just add senderId and broadcast
+			if !c.Hub.access.CanSend(c.RoomID, c.UserID, msgType) {
+				log.Printf("[Hub] Dropped %q message from %s in room %s", msgType, c.UserID, c.RoomID)
+				continue
 			}
-			
+
 			// Re-marshal with senderId
 			if newPayload, err := json.Marshal(msgData); err == nil {
 				message = newPayload
 			}
+		} else {
+			log.Printf("[Hub] Dropped malformed message from %s in room %s", c.UserID, c.RoomID)
+			continue
 		}
 
 		// Broadcast to room
 		c.Hub.Broadcast <- Message{
-			RoomID:  c.RoomID,
-			Payload: message,
-			Sender:  c,
+			RoomID:     c.RoomID,
+			Payload:    message,
+			Sender:     c,
+			fromClient: true,
 		}
 	}
 }
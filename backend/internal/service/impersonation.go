@@ -0,0 +1,56 @@
+package service
+
+import (
+	"errors"
+	"log"
+
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
+)
+
+var ErrImpersonationDisabled = errors.New("impersonation is only available in development")
+
+// ImpersonationService signs developers in as any user without a password, for
+// trying the app out against seeded accounts. It only works when the
+// environment is development and impersonation was turned on explicitly.
+type ImpersonationService struct {
+	enabled        bool
+	userRepo       repository.UserRepository
+	sessionService *SessionService
+}
+
+func NewImpersonationService(environment string, enabled bool, userRepo repository.UserRepository, sessionService *SessionService) *ImpersonationService {
+	return &ImpersonationService{
+		enabled:        enabled && environment == "development",
+		userRepo:       userRepo,
+		sessionService: sessionService,
+	}
+}
+
+func (s *ImpersonationService) Enabled() bool {
+	return s.enabled
+}
+
+// Impersonate opens a session as the user with ID or handle userRef. It is an
+// ordinary session, so it shows up in the user's session list and can be
+// revoked.
+func (s *ImpersonationService) Impersonate(userRef, userAgent, ipAddress string) (*models.User, *Tokens, error) {
+	if !s.enabled {
+		log.Printf("[Impersonation] Refused impersonation of %q from %s", userRef, ipAddress)
+		return nil, nil, ErrImpersonationDisabled
+	}
+
+	user, err := s.userRepo.GetByID(userRef)
+	if err != nil {
+		if user, err = s.userRepo.GetByHandle(userRef); err != nil {
+			return nil, nil, ErrUserNotFound
+		}
+	}
+
+	tokens, err := s.sessionService.Start(user, userAgent, ipAddress)
+	if err != nil {
+		return nil, nil, err
+	}
+	log.Printf("[Impersonation] WARNING: IMPERSONATING user %s (@%s) for %s", user.ID, user.Handle, ipAddress)
+	return user, tokens, nil
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/yourusername/v-backend/internal/auth"
+)
+
+func TestImpersonationOnlyInDevelopment(t *testing.T) {
+	sessions, user := newTestSessions(t)
+
+	for _, tc := range []struct {
+		environment string
+		enabled     bool
+	}{
+		{"production", true},
+		{"staging", true},
+		{"development", false},
+	} {
+		s := NewImpersonationService(tc.environment, tc.enabled, sessions.userRepo, sessions)
+		if s.Enabled() {
+			t.Errorf("%s (enabled=%v): impersonation is on", tc.environment, tc.enabled)
+		}
+		if _, _, err := s.Impersonate(user.ID, "test", "127.0.0.1"); !errors.Is(err, ErrImpersonationDisabled) {
+			t.Errorf("%s (enabled=%v): impersonate = %v, want disabled", tc.environment, tc.enabled, err)
+		}
+	}
+
+	s := NewImpersonationService("development", true, sessions.userRepo, sessions)
+	got, tokens, err := s.Impersonate(user.Handle, "test", "127.0.0.1")
+	if err != nil || got.ID != user.ID {
+		t.Fatalf("impersonate by handle = %v, %v", got, err)
+	}
+	claims, err := auth.ValidateToken(tokens.AccessToken)
+	if err != nil || claims.UserID != user.ID || !sessions.IsActive(claims.SessionID) {
+		t.Errorf("impersonated token = %+v, %v", claims, err)
+	}
+	if _, _, err := s.Impersonate("nobody", "test", "127.0.0.1"); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("impersonate unknown user = %v", err)
+	}
+}
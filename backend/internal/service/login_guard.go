@@ -0,0 +1,246 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
+)
+
+// LoginBlockedError is returned by LoginGuard.Begin while an account or IP
+// address may not try to sign in
+type LoginBlockedError struct {
+	RetryAfter time.Duration
+	Locked     bool // The account or address is locked out, not just backing off
+}
+
+func (e *LoginBlockedError) Error() string {
+	if e.Locked {
+		return fmt.Sprintf("too many failed sign-ins; locked for %s", e.RetryAfter.Round(time.Second))
+	}
+	return fmt.Sprintf("too many failed sign-ins; retry in %s", e.RetryAfter.Round(time.Second))
+}
+
+// LoginGuard slows down password guessing. Failed sign-ins are counted per
+// account and per IP address; past half the limit each attempt has to wait
+// twice as long as the last, and at the limit the account or address is locked
+// out for a while. Users are notified when their account gets locked.
+type LoginGuard struct {
+	repo          repository.LoginThrottleRepository
+	authRepo      repository.AuthRepository
+	notifRepo     repository.NotificationRepository
+	maxFailures   int // Per account, before a lockout
+	ipMaxFailures int // Per IP address, before a lockout
+	lockout       time.Duration
+
+	mu      sync.Mutex
+	pending map[string]int // Throttle key -> sign-ins under way
+}
+
+func NewLoginGuard(repo repository.LoginThrottleRepository, authRepo repository.AuthRepository, notifRepo repository.NotificationRepository, maxFailures, ipMaxFailures int, lockout time.Duration) *LoginGuard {
+	return &LoginGuard{
+		repo:          repo,
+		authRepo:      authRepo,
+		notifRepo:     notifRepo,
+		maxFailures:   maxFailures,
+		ipMaxFailures: ipMaxFailures,
+		lockout:       lockout,
+		pending:       make(map[string]int),
+	}
+}
+
+func accountThrottleKey(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipThrottleKey(ip string) string {
+	return "ip:" + ip
+}
+
+// LoginAttempt is a sign-in under way. Until it ends it counts as a failure,
+// so sign-ins sent in parallel cannot all be checked before any of them fails.
+type LoginAttempt struct {
+	guard *LoginGuard
+	email string
+	ip    string
+	ended bool
+}
+
+// Begin starts a sign-in to email from ip, or returns a *LoginBlockedError if
+// it has to wait. Checking and counting the attempt happen in one step.
+func (g *LoginGuard) Begin(email, ip string) (*LoginAttempt, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := time.Now()
+	keys := []string{accountThrottleKey(email), ipThrottleKey(ip)}
+	var blocked *LoginBlockedError
+	for i, maxFailures := range []int{g.maxFailures, g.ipMaxFailures} {
+		if wait, locked := g.blockedFor(keys[i], maxFailures, now); wait > 0 && (blocked == nil || wait > blocked.RetryAfter) {
+			blocked = &LoginBlockedError{RetryAfter: wait, Locked: locked}
+		}
+	}
+	if blocked != nil {
+		return nil, blocked
+	}
+
+	for _, key := range keys {
+		g.pending[key]++
+	}
+	return &LoginAttempt{guard: g, email: email, ip: ip}, nil
+}
+
+// blockedFor returns how long sign-ins counted under key have to wait, and
+// whether that is a lockout
+func (g *LoginGuard) blockedFor(key string, maxFailures int, now time.Time) (time.Duration, bool) {
+	failures := 0
+	throttle, err := g.repo.Get(key)
+	if err == nil {
+		if throttle.BlockedUntil != nil && throttle.BlockedUntil.After(now) {
+			return throttle.BlockedUntil.Sub(now), throttle.Failures >= maxFailures
+		}
+		if !throttle.LastFailureAt.Before(now.Add(-g.lockout)) {
+			failures = throttle.Failures
+		}
+	}
+	// Past the free attempts sign-ins wait for each other, as they would if
+	// the ones under way failed
+	if pending := g.pending[key]; pending > 0 && failures+pending >= maxFailures/2 {
+		return time.Second, false
+	}
+	return 0, false
+}
+
+// Failed ends the attempt as a failed sign-in
+func (a *LoginAttempt) Failed() {
+	if !a.ended {
+		a.guard.failed(a.email, a.ip)
+	}
+	a.End()
+}
+
+// Succeeded ends the attempt and clears the failed sign-ins of its account.
+// Those of the IP address are kept, so an attacker cannot reset them by
+// signing in to their own account.
+func (a *LoginAttempt) Succeeded() {
+	if !a.ended {
+		if err := a.guard.repo.Reset(accountThrottleKey(a.email)); err != nil {
+			log.Printf("[LoginGuard] Failed to reset failed sign-ins for %s: %v", a.email, err)
+		}
+	}
+	a.End()
+}
+
+// End ends the attempt without counting it either way, such as when the
+// password was right but the second factor is still to come. Ending an
+// attempt again does nothing.
+func (a *LoginAttempt) End() {
+	if a.ended {
+		return
+	}
+	a.ended = true
+
+	g := a.guard
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, key := range []string{accountThrottleKey(a.email), ipThrottleKey(a.ip)} {
+		if g.pending[key]--; g.pending[key] <= 0 {
+			delete(g.pending, key)
+		}
+	}
+}
+
+// failed records a failed sign-in to email from ip
+func (g *LoginGuard) failed(email, ip string) {
+	now := time.Now()
+	since := now.Add(-g.lockout)
+
+	account, err := g.repo.RecordFailure(accountThrottleKey(email), now, since)
+	if err != nil {
+		log.Printf("[LoginGuard] Failed to record failed sign-in for %s: %v", email, err)
+	} else if g.throttle(account, g.maxFailures, now) {
+		log.Printf("[LoginGuard] Account %s locked for %s after %d failed sign-ins", email, g.lockout, account.Failures)
+		g.notifyLockout(email, now.Add(g.lockout))
+	}
+
+	address, err := g.repo.RecordFailure(ipThrottleKey(ip), now, since)
+	if err != nil {
+		log.Printf("[LoginGuard] Failed to record failed sign-in from %s: %v", ip, err)
+	} else if g.throttle(address, g.ipMaxFailures, now) {
+		log.Printf("[LoginGuard] IP %s locked for %s after %d failed sign-ins", ip, g.lockout, address.Failures)
+	}
+}
+
+// Unlock lifts the lockout and backoff of a user's account
+func (g *LoginGuard) Unlock(userID string) error {
+	authModel, err := g.authRepo.GetByUserID(userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+	return g.repo.Reset(accountThrottleKey(authModel.Email))
+}
+
+// Run prunes throttles with no recent failures every interval until the
+// process exits
+func (g *LoginGuard) Run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	for range ticker.C {
+		pruned, err := g.repo.Prune(time.Now().Add(-g.lockout))
+		if err != nil {
+			log.Printf("[LoginGuard] Failed to prune login throttles: %v", err)
+			continue
+		}
+		if pruned > 0 {
+			log.Printf("[LoginGuard] Pruned %d login throttle(s)", pruned)
+		}
+	}
+}
+
+// throttle blocks further attempts after a failure and reports whether this
+// failure is the one that locked it out
+func (g *LoginGuard) throttle(throttle *models.LoginThrottle, maxFailures int, now time.Time) bool {
+	freeAttempts := maxFailures / 2
+	if throttle.Failures < freeAttempts {
+		return false
+	}
+
+	wait := g.lockout
+	if throttle.Failures < maxFailures {
+		// 1s, 2s, 4s, ... but never longer than a lockout
+		if shift := throttle.Failures - freeAttempts; shift < 30 && time.Second<<shift < g.lockout {
+			wait = time.Second << shift
+		}
+	}
+	if err := g.repo.Block(throttle.Key, now.Add(wait)); err != nil {
+		log.Printf("[LoginGuard] Failed to block %s: %v", throttle.Key, err)
+	}
+	return throttle.Failures == maxFailures
+}
+
+func (g *LoginGuard) notifyLockout(email string, until time.Time) {
+	// Nobody to tell when the email has no account
+	authModel, err := g.authRepo.GetByEmail(email)
+	if err != nil {
+		return
+	}
+
+	notification := &models.Notification{
+		ID:     uuid.New().String(),
+		UserID: authModel.UserID,
+		Type:   "security",
+		Title:  "Your account was temporarily locked",
+		Message: fmt.Sprintf(
+			"After %d failed sign-in attempts, signing in is blocked until %s UTC. If this wasn't you, change your password and turn on two-factor authentication.",
+			g.maxFailures, until.UTC().Format("15:04"),
+		),
+		CreatedAt: time.Now(),
+	}
+	if err := g.notifRepo.Create(notification); err != nil {
+		log.Printf("[LoginGuard] Failed to notify %s of lockout: %v", authModel.UserID, err)
+	}
+}
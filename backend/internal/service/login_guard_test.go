@@ -0,0 +1,103 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
+	"github.com/yourusername/v-backend/internal/repository/memory"
+)
+
+// check reports whether signing in to email from ip has to wait
+func check(guard *LoginGuard, email, ip string) error {
+	attempt, err := guard.Begin(email, ip)
+	if err == nil {
+		attempt.End()
+	}
+	return err
+}
+
+func TestLoginGuardBacksOffThenLocks(t *testing.T) {
+	repos := memory.NewRepositories()
+	if err := repos.Auth.CreateAuth(&models.Auth{UserID: "alice", Email: "alice@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	guard := NewLoginGuard(repos.LoginThrottle, repos.Auth, repos.Notification, 6, 100, 15*time.Minute)
+
+	// Three failures are free, then the wait doubles until the lockout at six
+	var blocked *LoginBlockedError
+	for i := 1; i <= 6; i++ {
+		guard.failed("alice@example.com", "10.0.0.1")
+		err := check(guard, "Alice@example.com", "10.0.0.2")
+		switch {
+		case i < 3:
+			if err != nil {
+				t.Fatalf("blocked after %d failures: %v", i, err)
+			}
+		case i < 6:
+			if !errors.As(err, &blocked) || blocked.Locked || blocked.RetryAfter > time.Second<<(i-3) {
+				t.Fatalf("after %d failures got %v, want backoff", i, err)
+			}
+		default:
+			if !errors.As(err, &blocked) || !blocked.Locked || blocked.RetryAfter < 14*time.Minute {
+				t.Fatalf("after %d failures got %v, want lockout", i, err)
+			}
+		}
+	}
+
+	// Locked once, so notified once
+	notifications, _, _ := repos.Notification.GetByUserID("alice", repository.Page{Limit: 10})
+	if len(notifications) != 1 || notifications[0].Type != "security" {
+		t.Errorf("notifications = %+v", notifications)
+	}
+
+	// The IP address is under its own limit, so another account can sign in from it
+	if err := check(guard, "bob@example.com", "10.0.0.1"); err != nil {
+		t.Errorf("other account blocked: %v", err)
+	}
+
+	if err := guard.Unlock("alice"); err != nil {
+		t.Fatalf("unlock: %v", err)
+	}
+	if err := check(guard, "alice@example.com", "10.0.0.2"); err != nil {
+		t.Errorf("blocked after unlock: %v", err)
+	}
+}
+
+func TestLoginGuardCountsAttemptsUnderWay(t *testing.T) {
+	repos := memory.NewRepositories()
+	guard := NewLoginGuard(repos.LoginThrottle, repos.Auth, repos.Notification, 6, 100, 15*time.Minute)
+
+	// Three attempts are free, so three can be under way at once
+	var attempts []*LoginAttempt
+	for i := 0; i < 3; i++ {
+		attempt, err := guard.Begin("alice@example.com", "10.0.0.1")
+		if err != nil {
+			t.Fatalf("attempt %d blocked: %v", i+1, err)
+		}
+		attempts = append(attempts, attempt)
+	}
+	var blocked *LoginBlockedError
+	if _, err := guard.Begin("alice@example.com", "10.0.0.2"); !errors.As(err, &blocked) || blocked.Locked {
+		t.Fatalf("fourth attempt under way = %v, want backoff", err)
+	}
+
+	// An attempt that ends without failing frees its place
+	attempts[0].End()
+	attempts[0].End()
+	attempt, err := guard.Begin("alice@example.com", "10.0.0.2")
+	if err != nil {
+		t.Fatalf("blocked after an attempt ended: %v", err)
+	}
+	attempts[0] = attempt
+
+	// Three failures use up the free attempts
+	for _, attempt := range attempts {
+		attempt.Failed()
+	}
+	if _, err := guard.Begin("alice@example.com", "10.0.0.3"); !errors.As(err, &blocked) || blocked.Locked {
+		t.Errorf("after three failures got %v, want backoff", err)
+	}
+}
@@ -41,25 +41,28 @@ func (s *ModerationService) ReportPost(postID, reporterID string) error {
 	}
 	s.reports[postID][reporterID] = true
 
-	// Try to get the post from backend
-	post, err := s.postRepo.GetByID(postID)
-	if err != nil {
-		// Post doesn't exist in backend yet - that's okay, we've tracked the report
-		// When the post is created later, it will have the report count
-		// For now, just return success since we've tracked the report
-		return nil
-	}
+	// Reactions and comments bump the post's version too, so reapply on conflict
+	return repository.RetryOnConflict(maxConflictRetries, func() error {
+		// Try to get the post from backend
+		post, err := s.postRepo.GetByID(postID)
+		if err != nil {
+			// Post doesn't exist in backend yet - that's okay, we've tracked the report
+			// When the post is created later, it will have the report count
+			// For now, just return success since we've tracked the report
+			return nil
+		}
 
-	// Post exists - increment report count
-	post.ReportCount++
-	
-	// Check if report count reaches threshold
-	if post.ReportCount >= 100 {
-		post.Status = models.PostStatusTempHidden
-		post.InModerationQueue = true
-	}
+		// Post exists - increment report count
+		post.ReportCount++
 
-	return s.postRepo.Update(post)
+		// Check if report count reaches threshold
+		if post.ReportCount >= 100 {
+			post.Status = models.PostStatusTempHidden
+			post.InModerationQueue = true
+		}
+
+		return s.postRepo.Update(post)
+	})
 }
 
 // Admin: Approve post (remove from moderation)
@@ -101,7 +104,7 @@ func (s *ModerationService) RejectPost(postID string, applyPenalty bool) error {
 func (s *ModerationService) GetModerationQueue(limit, offset int) ([]*models.Post, error) {
 	// This would need to be added to PostRepository interface
 	// For now, we'll filter in memory
-	allPosts, err := s.postRepo.List(10000, 0) // Get all posts (in production, use proper query)
+	allPosts, _, err := s.postRepo.List(repository.Page{Limit: 10000}) // Get all posts (in production, use proper query)
 	if err != nil {
 		return nil, err
 	}
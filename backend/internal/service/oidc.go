@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"sort"
+	"strings"
+
+	"github.com/yourusername/v-backend/internal/auth"
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/oidc"
+	"github.com/yourusername/v-backend/internal/repository"
+)
+
+var (
+	ErrUnknownProvider  = errors.New("unknown sign-in provider")
+	ErrInvalidOIDCFlow  = errors.New("invalid or expired sign-in; start again")
+	ErrOIDCNoEmail      = errors.New("the provider did not share an email address")
+	ErrOIDCEmailInUse   = errors.New("an account with this email already exists; sign in to it and link the provider from your settings")
+	ErrLastSignInMethod = errors.New("this is the only way to sign in to the account; set a password first")
+)
+
+// OIDCService signs users in with OpenID Connect providers and keeps track of
+// which provider accounts belong to which users
+type OIDCService struct {
+	providers    map[string]*oidc.Provider
+	identityRepo repository.IdentityRepository
+	authRepo     repository.AuthRepository
+	userRepo     repository.UserRepository
+}
+
+func NewOIDCService(providers []*oidc.Provider, identityRepo repository.IdentityRepository, authRepo repository.AuthRepository, userRepo repository.UserRepository) *OIDCService {
+	s := &OIDCService{
+		providers:    make(map[string]*oidc.Provider, len(providers)),
+		identityRepo: identityRepo,
+		authRepo:     authRepo,
+		userRepo:     userRepo,
+	}
+	for _, p := range providers {
+		s.providers[p.Name()] = p
+	}
+	return s
+}
+
+// Providers returns the names of the configured providers
+func (s *OIDCService) Providers() []string {
+	names := make([]string, 0, len(s.providers))
+	for name := range s.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Begin returns the URL to send the user to and a flow token the client must
+// hand back along with the code the provider redirects with
+func (s *OIDCService) Begin(ctx context.Context, provider string) (*models.OIDCAuthorizeResponse, error) {
+	p, ok := s.providers[provider]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+
+	flow := auth.OIDCFlow{Provider: provider}
+	for _, value := range []*string{&flow.State, &flow.Nonce, &flow.Verifier} {
+		random, err := oidc.RandomString()
+		if err != nil {
+			return nil, err
+		}
+		*value = random
+	}
+
+	authURL, err := p.AuthCodeURL(ctx, flow.State, flow.Nonce, oidc.CodeChallenge(flow.Verifier))
+	if err != nil {
+		return nil, err
+	}
+	flowToken, err := auth.GenerateOIDCFlowToken(flow)
+	if err != nil {
+		return nil, err
+	}
+	return &models.OIDCAuthorizeResponse{AuthorizationURL: authURL, FlowToken: flowToken}, nil
+}
+
+// Authenticate finishes a flow started by Begin and returns who the provider
+// says the user is
+func (s *OIDCService) Authenticate(ctx context.Context, provider string, req models.OIDCCallbackRequest) (*oidc.Claims, error) {
+	p, ok := s.providers[provider]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+
+	flow, err := auth.ParseOIDCFlowToken(req.FlowToken)
+	if err != nil || flow.Provider != provider {
+		return nil, ErrInvalidOIDCFlow
+	}
+	// The state ties the redirect to the browser that started the flow
+	if subtle.ConstantTimeCompare([]byte(flow.State), []byte(req.State)) != 1 {
+		return nil, ErrInvalidOIDCFlow
+	}
+
+	claims, err := p.Exchange(ctx, req.Code, flow.Verifier, flow.Nonce)
+	if err != nil {
+		log.Printf("[OIDC] Sign-in with %s failed: %v", provider, err)
+		return nil, ErrInvalidOIDCFlow
+	}
+	return claims, nil
+}
+
+// Resolve returns the user an identity belongs to. An identity seen for the
+// first time is linked to the account with the same email when both the
+// provider and the account have verified it; an address nobody has verified
+// proves nothing. Otherwise, if nobody has the email, signup is returned so
+// the user can pick a handle.
+func (s *OIDCService) Resolve(provider string, claims *oidc.Claims) (userID string, signup *models.OIDCSignupRequired, err error) {
+	if identity, err := s.identityRepo.Get(provider, claims.Subject); err == nil {
+		return identity.UserID, nil, nil
+	}
+	if claims.Email == "" {
+		return "", nil, ErrOIDCNoEmail
+	}
+
+	if authModel, err := s.authRepo.GetByEmail(claims.Email); err == nil {
+		user, err := s.userRepo.GetByID(authModel.UserID)
+		if err != nil {
+			return "", nil, err
+		}
+		if !claims.EmailVerified || !user.EmailVerified {
+			return "", nil, ErrOIDCEmailInUse
+		}
+		if err := s.Link(user.ID, provider, claims); err != nil {
+			return "", nil, err
+		}
+		return user.ID, nil, nil
+	}
+
+	token, expiresIn, err := auth.GenerateOIDCSignupToken(auth.OIDCSignup{
+		Provider:      provider,
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		Name:          claims.Name,
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	return "", &models.OIDCSignupRequired{
+		SignupRequired:  true,
+		SignupToken:     token,
+		ExpiresIn:       expiresIn,
+		SuggestedHandle: s.suggestHandle(claims),
+		Name:            claims.Name,
+		Email:           claims.Email,
+	}, nil
+}
+
+// Link connects an identity at provider to the user's account
+func (s *OIDCService) Link(userID, provider string, claims *oidc.Claims) error {
+	err := s.identityRepo.Create(&models.Identity{
+		Provider: provider,
+		Subject:  claims.Subject,
+		UserID:   userID,
+		Email:    claims.Email,
+	})
+	if err != nil {
+		return err
+	}
+	log.Printf("[OIDC] Linked %s identity %s to user %s", provider, claims.Subject, userID)
+	return nil
+}
+
+// Identities returns the provider accounts linked to the user
+func (s *OIDCService) Identities(userID string) ([]*models.Identity, error) {
+	return s.identityRepo.ListByUser(userID)
+}
+
+// Unlink removes the user's identity at provider, unless it is the only way
+// left to sign in
+func (s *OIDCService) Unlink(userID, provider string) error {
+	identities, err := s.identityRepo.ListByUser(userID)
+	if err != nil {
+		return err
+	}
+	authModel, err := s.authRepo.GetByUserID(userID)
+	if err != nil {
+		return err
+	}
+	if authModel.PasswordHash == "" && len(identities) <= 1 {
+		return ErrLastSignInMethod
+	}
+	return s.identityRepo.Delete(userID, provider)
+}
+
+// suggestHandle turns the provider's username or the email's local part into
+// a free handle
+func (s *OIDCService) suggestHandle(claims *oidc.Claims) string {
+	base := claims.PreferredUsername
+	if base == "" {
+		base, _, _ = strings.Cut(claims.Email, "@")
+	}
+
+	var b strings.Builder
+	for _, c := range base {
+		if (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '_' {
+			b.WriteRune(c)
+		}
+	}
+	handle := b.String()
+	if len(handle) > 24 {
+		handle = handle[:24]
+	}
+	for len(handle) < 3 {
+		handle += "_"
+	}
+
+	candidate := handle
+	for i := 0; i < 10; i++ {
+		if existing, _ := s.userRepo.GetByHandle(candidate); existing == nil {
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s%d", handle, rand.IntN(10000))
+	}
+	return candidate
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/oidc"
+	"github.com/yourusername/v-backend/internal/oidc/oidctest"
+	"github.com/yourusername/v-backend/internal/repository"
+	"github.com/yourusername/v-backend/internal/repository/memory"
+)
+
+func newTestOIDC(t *testing.T) (*OIDCService, *oidctest.Server, *repository.Repositories) {
+	t.Helper()
+	server := oidctest.NewServer("client", "secret")
+	t.Cleanup(server.Close)
+
+	provider := oidc.NewProvider(oidc.Config{
+		Name:         "mock",
+		Issuer:       server.URL,
+		ClientID:     "client",
+		ClientSecret: "secret",
+		RedirectURL:  "http://localhost:3000/auth/callback/mock",
+	}, nil)
+	repos := memory.NewRepositories()
+	return NewOIDCService([]*oidc.Provider{provider}, repos.Identity, repos.Auth, repos.User), server, repos
+}
+
+// signIn runs a sign-in with the mock provider the way a browser would
+func signIn(t *testing.T, s *OIDCService, server *oidctest.Server, user oidctest.User) (*oidc.Claims, error) {
+	t.Helper()
+	server.SetUser(user)
+
+	begin, err := s.Begin(context.Background(), "mock")
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	code, state, err := server.Authorize(begin.AuthorizationURL)
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+	return s.Authenticate(context.Background(), "mock", models.OIDCCallbackRequest{Code: code, State: state, FlowToken: begin.FlowToken})
+}
+
+func TestOIDCFirstSignInNeedsHandle(t *testing.T) {
+	s, server, repos := newTestOIDC(t)
+	if err := repos.User.Create(&models.User{ID: "taken", Handle: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := signIn(t, s, server, oidctest.User{Subject: "g-1", Email: "alice@example.com", EmailVerified: true, PreferredUsername: "alice"})
+	if err != nil {
+		t.Fatalf("authenticate: %v", err)
+	}
+	userID, signup, err := s.Resolve("mock", claims)
+	if err != nil || userID != "" || signup == nil || signup.SignupToken == "" {
+		t.Fatalf("resolve = %q, %+v, %v", userID, signup, err)
+	}
+	if signup.SuggestedHandle == "alice" || signup.Email != "alice@example.com" {
+		t.Errorf("signup = %+v, want a free handle", signup)
+	}
+
+	// Once linked, the same identity signs straight in
+	if err := s.Link("alice-id", "mock", claims); err != nil {
+		t.Fatalf("link: %v", err)
+	}
+	if userID, signup, err := s.Resolve("mock", claims); err != nil || userID != "alice-id" || signup != nil {
+		t.Errorf("resolve after link = %q, %+v, %v", userID, signup, err)
+	}
+}
+
+func TestOIDCLinksOnlyVerifiedEmails(t *testing.T) {
+	s, server, repos := newTestOIDC(t)
+	repos.User.Create(&models.User{ID: "bob", Handle: "bob", Email: "bob@example.com"})
+	repos.Auth.CreateAuth(&models.Auth{UserID: "bob", Email: "bob@example.com", PasswordHash: "hash"})
+
+	// Bob never verified his address, so whoever controls it at the provider
+	// does not get his account
+	claims, err := signIn(t, s, server, oidctest.User{Subject: "g-2", Email: "bob@example.com", EmailVerified: true})
+	if err != nil {
+		t.Fatalf("authenticate: %v", err)
+	}
+	if _, _, err := s.Resolve("mock", claims); !errors.Is(err, ErrOIDCEmailInUse) {
+		t.Fatalf("resolve unverified account = %v, want email in use", err)
+	}
+
+	bob, _ := repos.User.GetByID("bob")
+	bob.EmailVerified = true
+	repos.User.Update(bob)
+	if userID, _, err := s.Resolve("mock", claims); err != nil || userID != "bob" {
+		t.Fatalf("resolve verified account = %q, %v", userID, err)
+	}
+
+	// Bob has a password, so the identity can go
+	if err := s.Unlink("bob", "mock"); err != nil {
+		t.Errorf("unlink: %v", err)
+	}
+}
+
+func TestOIDCRejectsForgedCallback(t *testing.T) {
+	s, server, _ := newTestOIDC(t)
+	server.SetUser(oidctest.User{Subject: "g-3", Email: "carol@example.com"})
+
+	begin, _ := s.Begin(context.Background(), "mock")
+	code, _, _ := server.Authorize(begin.AuthorizationURL)
+
+	// A code delivered with another browser's state is refused
+	_, err := s.Authenticate(context.Background(), "mock", models.OIDCCallbackRequest{Code: code, State: "attacker", FlowToken: begin.FlowToken})
+	if !errors.Is(err, ErrInvalidOIDCFlow) {
+		t.Errorf("wrong state = %v, want invalid flow", err)
+	}
+	if _, err := s.Begin(context.Background(), "unknown"); !errors.Is(err, ErrUnknownProvider) {
+		t.Errorf("unknown provider = %v", err)
+	}
+}
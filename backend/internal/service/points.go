@@ -18,6 +18,7 @@ const (
 	ActionAbusivePost       PointsAction = "ABUSIVE_POST"
 	ActionDailyStreak       PointsAction = "DAILY_STREAK"
 	ActionDeletePost        PointsAction = "DELETE_POST"
+	ActionRestorePost       PointsAction = "RESTORE_POST"
 	ActionDeleteHashtagPost PointsAction = "DELETE_HASHTAG_POST"
 	ActionCommunityJoin     PointsAction = "COMMUNITY_JOIN"
 	ActionCommunityCreate   PointsAction = "COMMUNITY_CREATE"
@@ -33,8 +34,24 @@ func NewPointsService(userRepo repository.UserRepository) *PointsService {
 	}
 }
 
+// WithTx returns a PointsService that reads and writes users through the
+// repositories of a unit of work
+func (s *PointsService) WithTx(tx *repository.Repositories) *PointsService {
+	return NewPointsService(tx.User)
+}
+
+// maxConflictRetries bounds how often a change is reapplied after losing a
+// race with another write to the same user
+const maxConflictRetries = 3
+
 // Update user points based on action type
 func (s *PointsService) UpdateUserPoints(userID string, actionType PointsAction) error {
+	return repository.RetryOnConflict(maxConflictRetries, func() error {
+		return s.updateUserPoints(userID, actionType)
+	})
+}
+
+func (s *PointsService) updateUserPoints(userID string, actionType PointsAction) error {
 	user, err := s.userRepo.GetByID(userID)
 	if err != nil {
 		return err
@@ -111,6 +128,10 @@ func (s *PointsService) UpdateUserPoints(userID string, actionType PointsAction)
 	case ActionDeletePost:
 		pointsDelta = -2
 
+	case ActionRestorePost:
+		// Gives back what ActionDeletePost took
+		pointsDelta = 2
+
 	case ActionDeleteHashtagPost:
 		pointsDelta = -3
 
@@ -191,6 +212,12 @@ func (s *PointsService) CanHostDebate(userID string) (bool, error) {
 
 // Record debate hosting
 func (s *PointsService) RecordDebateHost(userID string) error {
+	return repository.RetryOnConflict(maxConflictRetries, func() error {
+		return s.recordDebateHost(userID)
+	})
+}
+
+func (s *PointsService) recordDebateHost(userID string) error {
 	user, err := s.userRepo.GetByID(userID)
 	if err != nil {
 		return err
@@ -213,6 +240,12 @@ func (s *PointsService) RecordDebateHost(userID string) error {
 
 // Refund debate hosting (called when a scheduled debate is deleted)
 func (s *PointsService) RefundDebateHost(userID string) error {
+	return repository.RetryOnConflict(maxConflictRetries, func() error {
+		return s.refundDebateHost(userID)
+	})
+}
+
+func (s *PointsService) refundDebateHost(userID string) error {
 	user, err := s.userRepo.GetByID(userID)
 	if err != nil {
 		return err
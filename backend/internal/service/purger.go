@@ -0,0 +1,52 @@
+package service
+
+import (
+	"log"
+	"time"
+
+	"github.com/yourusername/v-backend/internal/repository"
+)
+
+// Purger hard-deletes posts, comments and communities once they have been
+// soft-deleted for longer than the retention period
+type Purger struct {
+	postRepo      repository.PostRepository
+	communityRepo repository.CommunityRepository
+	retention     time.Duration
+}
+
+func NewPurger(postRepo repository.PostRepository, communityRepo repository.CommunityRepository, retention time.Duration) *Purger {
+	return &Purger{
+		postRepo:      postRepo,
+		communityRepo: communityRepo,
+		retention:     retention,
+	}
+}
+
+// Purge removes everything deleted more than the retention period ago and
+// returns how many posts, comments and communities went
+func (p *Purger) Purge() (int, error) {
+	cutoff := time.Now().Add(-p.retention)
+
+	posts, err := p.postRepo.Purge(cutoff)
+	if err != nil {
+		return posts, err
+	}
+	communities, err := p.communityRepo.Purge(cutoff)
+	return posts + communities, err
+}
+
+// Run purges every interval until the process exits
+func (p *Purger) Run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	for range ticker.C {
+		purged, err := p.Purge()
+		if err != nil {
+			log.Printf("[Purger] Failed to purge deleted content: %v", err)
+			continue
+		}
+		if purged > 0 {
+			log.Printf("[Purger] Purged %d item(s) deleted more than %s ago", purged, p.retention)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"errors"
+	"log"
+
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
+)
+
+var (
+	ErrInvalidRole  = errors.New("role must be user, moderator, admin or support")
+	ErrUserNotFound = errors.New("user not found")
+)
+
+// RoleService grants and revokes platform roles
+type RoleService struct {
+	userRepo    repository.UserRepository
+	sessionRepo repository.SessionRepository
+}
+
+func NewRoleService(userRepo repository.UserRepository, sessionRepo repository.SessionRepository) *RoleService {
+	return &RoleService{
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+	}
+}
+
+// SetRole gives a user a role. Access tokens carry the role, so taking a role
+// away also signs the user out everywhere; otherwise their tokens would keep
+// it until they expire.
+func (s *RoleService) SetRole(userID string, role models.PlatformRole) (*models.User, error) {
+	if !role.Valid() {
+		return nil, ErrInvalidRole
+	}
+
+	var user *models.User
+	var previous models.PlatformRole
+	err := repository.RetryOnConflict(maxConflictRetries, func() error {
+		var err error
+		if user, err = s.userRepo.GetByID(userID); err != nil {
+			return ErrUserNotFound
+		}
+		previous = user.Role
+		if previous == role {
+			return nil
+		}
+		user.Role = role
+		return s.userRepo.Update(user)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if previous != role && previous != models.PlatformRoleUser && previous != "" {
+		if err := s.sessionRepo.RevokeAllForUser(userID, ""); err != nil {
+			log.Printf("[Roles] Failed to revoke sessions of %s after role change: %v", userID, err)
+		}
+	}
+	log.Printf("[Roles] User %s is now %s (was %s)", userID, role, previous)
+	return user, nil
+}
+
+// BootstrapAdmins makes the users with the given emails admins, so a new
+// deployment has someone who can grant roles. Only verified emails count, so
+// whoever signs up first with an admin address cannot claim it.
+func (s *RoleService) BootstrapAdmins(emails []string) {
+	for _, email := range emails {
+		user, err := s.userRepo.GetByEmail(email)
+		if err != nil {
+			log.Printf("[Roles] No user with admin email %s yet", email)
+			continue
+		}
+		if user.Role == models.PlatformRoleAdmin {
+			continue
+		}
+		if !user.EmailVerified {
+			log.Printf("[Roles] Not making %s an admin until the email is verified", email)
+			continue
+		}
+		if _, err := s.SetRole(user.ID, models.PlatformRoleAdmin); err != nil {
+			log.Printf("[Roles] Failed to make %s an admin: %v", email, err)
+		}
+	}
+}
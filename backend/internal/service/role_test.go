@@ -0,0 +1,54 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yourusername/v-backend/internal/auth"
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository/memory"
+)
+
+func TestRolesReachTokensAndDemotionSignsOut(t *testing.T) {
+	repos := memory.NewRepositories()
+	for _, user := range []*models.User{
+		{ID: "alice", Handle: "alice", Email: "alice@example.com", EmailVerified: true},
+		{ID: "mallory", Handle: "mallory", Email: "root@example.com"},
+	} {
+		if err := repos.User.Create(user); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sessions := NewSessionService(repos.Session, repos.User, time.Hour)
+	roles := NewRoleService(repos.User, repos.Session)
+
+	roles.BootstrapAdmins([]string{"alice@example.com", "root@example.com", "nobody@example.com"})
+	if unverified, _ := repos.User.GetByID("mallory"); unverified.Role == models.PlatformRoleAdmin {
+		t.Error("unverified admin email made an admin")
+	}
+	user, _ := repos.User.GetByID("alice")
+	tokens, err := sessions.Start(user, "test", "127.0.0.1")
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	claims, _ := auth.ValidateToken(tokens.AccessToken)
+	if claims.Role != string(models.PlatformRoleAdmin) {
+		t.Fatalf("role claim = %q, want admin", claims.Role)
+	}
+
+	if _, err := roles.SetRole("alice", "superuser"); !errors.Is(err, ErrInvalidRole) {
+		t.Errorf("unknown role = %v, want invalid", err)
+	}
+	if _, err := roles.SetRole("nobody", models.PlatformRoleModerator); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("missing user = %v, want not found", err)
+	}
+
+	// The old token still says admin, so it must stop working
+	if _, err := roles.SetRole("alice", models.PlatformRoleUser); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+	if sessions.IsActive(claims.SessionID) {
+		t.Error("session still active after losing the admin role")
+	}
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
+)
+
+var (
+	ErrRoomNotFound  = errors.New("room not found")
+	ErrRoomForbidden = errors.New("you cannot join this room")
+	ErrDebateLocked  = errors.New("this debate is open to early access only")
+)
+
+// Rooms any signed-in user may listen to for list updates. Only the server
+// posts in them.
+const (
+	RoomDebatesList  = "debates-list"
+	RoomHashtagsList = "hashtags-list"
+)
+
+// Message types clients may send in a debate room. The rest, such as
+// participant updates and status changes, only come from the server.
+var (
+	clientMessageTypes = map[string]bool{
+		"debate:join_room":  true,
+		"debate:leave_room": true,
+	}
+	// Only for participants who have not left
+	participantMessageTypes = map[string]bool{
+		"debate:self_mute_change": true,
+		// WebRTC signaling between participants
+		"offer":         true,
+		"answer":        true,
+		"ice-candidate": true,
+	}
+	hostMessageTypes = map[string]bool{
+		"debate:mute_change": true,
+	}
+)
+
+// RoomAuthorizer decides who may join a hub room and what they may send there
+type RoomAuthorizer interface {
+	CanJoin(roomID, userID string) error
+	CanSend(roomID, userID, msgType string) bool
+}
+
+// DebateRoomAccess lets users into the list rooms and the rooms of debates
+// they can see
+type DebateRoomAccess struct {
+	debateRepo    repository.DebateRepository
+	userRepo      repository.UserRepository
+	communityRepo repository.CommunityRepository
+}
+
+func NewDebateRoomAccess(debateRepo repository.DebateRepository, userRepo repository.UserRepository, communityRepo repository.CommunityRepository) *DebateRoomAccess {
+	return &DebateRoomAccess{debateRepo: debateRepo, userRepo: userRepo, communityRepo: communityRepo}
+}
+
+// CanJoin reports why userID may not join roomID, if they may not
+func (a *DebateRoomAccess) CanJoin(roomID, userID string) error {
+	if roomID == RoomDebatesList || roomID == RoomHashtagsList {
+		return nil
+	}
+	debate, err := a.debateRepo.GetByID(roomID)
+	if err != nil {
+		return ErrRoomNotFound
+	}
+	if !a.CanAccessDebate(debate, userID) {
+		return ErrRoomForbidden
+	}
+	return nil
+}
+
+// CanSend reports whether userID may send a msgType message to roomID
+func (a *DebateRoomAccess) CanSend(roomID, userID, msgType string) bool {
+	if roomID == RoomDebatesList || roomID == RoomHashtagsList {
+		return false
+	}
+	if clientMessageTypes[msgType] {
+		return true
+	}
+	if participantMessageTypes[msgType] {
+		return a.isParticipant(roomID, userID)
+	}
+	if hostMessageTypes[msgType] {
+		debate, err := a.debateRepo.GetByID(roomID)
+		return err == nil && debate.HostID == userID
+	}
+	return false
+}
+
+// isParticipant reports whether userID has joined debateID and not left.
+// GetParticipants leaves out those who left.
+func (a *DebateRoomAccess) isParticipant(debateID, userID string) bool {
+	participants, err := a.debateRepo.GetParticipants(debateID)
+	if err != nil {
+		return false
+	}
+	for _, p := range participants {
+		if p.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// CanAccessDebate reports whether userID can see debate. Private debates are
+// only open to the host and the host's followers.
+func (a *DebateRoomAccess) CanAccessDebate(debate *models.Debate, userID string) bool {
+	if debate.Type != "PRIVATE" || debate.HostID == userID {
+		return true
+	}
+	following, err := a.userRepo.IsFollowing(userID, debate.HostID)
+	return err == nil && following
+}
+
+// CanJoinDebate reports why userID may not take part in debate, if they may
+// not. Besides seeing it, while the debate is locked they need one of its early
+// access roles. Users kept out can still listen in its room, to hear when it
+// opens.
+func (a *DebateRoomAccess) CanJoinDebate(debate *models.Debate, userID string) error {
+	if !a.CanAccessDebate(debate, userID) {
+		return ErrRoomForbidden
+	}
+	if !debate.IsLocked || debate.HostID == userID {
+		return nil
+	}
+	for _, role := range debate.EarlyAccessRoles {
+		if a.hasRole(debate, userID, role) {
+			return nil
+		}
+	}
+	return ErrDebateLocked
+}
+
+// hasRole reports whether userID holds an early access role of debate
+func (a *DebateRoomAccess) hasRole(debate *models.Debate, userID, role string) bool {
+	switch role {
+	case models.EarlyAccessFollowers:
+		following, err := a.userRepo.IsFollowing(userID, debate.HostID)
+		return err == nil && following
+	case models.EarlyAccessPlatinum:
+		user, err := a.userRepo.GetByID(userID)
+		return err == nil && user.Tier == models.TierPlatinum
+	}
+	if communityID, ok := models.EarlyAccessCommunity(role); ok {
+		member, err := a.communityRepo.GetMember(communityID, userID)
+		return err == nil && member.Status == "active"
+	}
+	return false
+}
+
+// ValidateEarlyAccessRoles checks that each role is known and that the
+// communities named exist
+func (a *DebateRoomAccess) ValidateEarlyAccessRoles(roles []string) error {
+	for _, role := range roles {
+		if role == models.EarlyAccessFollowers || role == models.EarlyAccessPlatinum {
+			continue
+		}
+		communityID, ok := models.EarlyAccessCommunity(role)
+		if !ok {
+			return fmt.Errorf("unknown early access role %q: use %q, %q or %q<id>", role,
+				models.EarlyAccessFollowers, models.EarlyAccessPlatinum, models.EarlyAccessCommunityPrefix)
+		}
+		if _, err := a.communityRepo.GetByID(communityID); err != nil {
+			return fmt.Errorf("community %s not found", communityID)
+		}
+	}
+	return nil
+}
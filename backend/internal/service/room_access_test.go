@@ -0,0 +1,144 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository/memory"
+)
+
+func TestDebateRoomAccess(t *testing.T) {
+	repos := memory.NewRepositories()
+	for _, id := range []string{"host", "fan", "stranger"} {
+		if err := repos.User.Create(&models.User{ID: id, Handle: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := repos.User.Follow("fan", "host"); err != nil {
+		t.Fatal(err)
+	}
+	for _, debate := range []*models.Debate{
+		{ID: "open", HostID: "host", Type: "PUBLIC"},
+		{ID: "closed", HostID: "host", Type: "PRIVATE"},
+	} {
+		if err := repos.Debate.Create(debate); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, p := range []*models.DebateParticipant{
+		{DebateID: "open", UserID: "fan", Side: "agree"},
+		{DebateID: "open", UserID: "host", Side: "disagree"},
+	} {
+		if err := repos.Debate.AddParticipant(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := repos.Debate.RemoveParticipant("open", "host"); err != nil {
+		t.Fatal(err)
+	}
+	access := NewDebateRoomAccess(repos.Debate, repos.User, repos.Community)
+
+	for _, tc := range []struct {
+		room, user string
+		want       error
+	}{
+		{RoomDebatesList, "stranger", nil},
+		{"open", "stranger", nil},
+		{"closed", "host", nil},
+		{"closed", "fan", nil},
+		{"closed", "stranger", ErrRoomForbidden},
+		{"missing", "host", ErrRoomNotFound},
+	} {
+		if err := access.CanJoin(tc.room, tc.user); !errors.Is(err, tc.want) {
+			t.Errorf("%s joining %s = %v, want %v", tc.user, tc.room, err, tc.want)
+		}
+	}
+
+	for _, tc := range []struct {
+		room, user, msgType string
+		want                bool
+	}{
+		{"open", "stranger", "debate:join_room", true},
+		{"open", "stranger", "debate:self_mute_change", false},
+		{"open", "stranger", "offer", false},
+		{"open", "fan", "debate:self_mute_change", true},
+		{"open", "fan", "ice-candidate", true},
+		{"open", "host", "answer", false}, // Left the debate
+		{"open", "stranger", "debate:mute_change", false},
+		{"open", "host", "debate:mute_change", true},
+		{"open", "host", "debate:participants_updated", false},
+		{"open", "host", "", false},
+		{RoomDebatesList, "host", "debate:created", false},
+	} {
+		if got := access.CanSend(tc.room, tc.user, tc.msgType); got != tc.want {
+			t.Errorf("%s sending %q to %s = %v, want %v", tc.user, tc.msgType, tc.room, got, tc.want)
+		}
+	}
+}
+
+func TestDebateEarlyAccess(t *testing.T) {
+	repos := memory.NewRepositories()
+	for _, user := range []*models.User{
+		{ID: "host", Handle: "host"},
+		{ID: "fan", Handle: "fan"},
+		{ID: "vip", Handle: "vip", Tier: models.TierPlatinum},
+		{ID: "member", Handle: "member"},
+		{ID: "applicant", Handle: "applicant"},
+		{ID: "stranger", Handle: "stranger"},
+	} {
+		if err := repos.User.Create(user); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := repos.User.Follow("fan", "host"); err != nil {
+		t.Fatal(err)
+	}
+	if err := repos.Community.Create(&models.Community{ID: "c1", Name: "Debaters"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range []*models.CommunityMember{
+		{CommunityID: "c1", UserID: "member", Status: "active"},
+		{CommunityID: "c1", UserID: "applicant", Status: "pending"},
+	} {
+		if err := repos.Community.AddMember(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	access := NewDebateRoomAccess(repos.Debate, repos.User, repos.Community)
+
+	if err := access.ValidateEarlyAccessRoles([]string{"followers", "platinum", "community:c1"}); err != nil {
+		t.Errorf("valid roles: %v", err)
+	}
+	for _, roles := range [][]string{{"admins"}, {"community:"}, {"community:missing"}} {
+		if err := access.ValidateEarlyAccessRoles(roles); err == nil {
+			t.Errorf("roles %v accepted", roles)
+		}
+	}
+
+	debate := &models.Debate{
+		ID: "d1", HostID: "host", Type: "PUBLIC", IsLocked: true, UnlockPhase: models.UnlockPhaseEarlyAccess,
+		EarlyAccessRoles: []string{"followers", "platinum", "community:c1"},
+	}
+	for _, tc := range []struct {
+		user string
+		want error
+	}{
+		{"host", nil},
+		{"fan", nil},
+		{"vip", nil},
+		{"member", nil},
+		{"applicant", ErrDebateLocked},
+		{"stranger", ErrDebateLocked},
+	} {
+		if err := access.CanJoinDebate(debate, tc.user); !errors.Is(err, tc.want) {
+			t.Errorf("%s joining while locked = %v, want %v", tc.user, err, tc.want)
+		}
+	}
+
+	debate.IsLocked = false
+	debate.UnlockPhase = models.UnlockPhasePublic
+	if err := access.CanJoinDebate(debate, "stranger"); err != nil {
+		t.Errorf("stranger joining once public = %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/v-backend/internal/auth"
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrSessionNotFound     = errors.New("session not found")
+)
+
+// Tokens is what a client gets when it signs in or refreshes
+type Tokens struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    int // Seconds until AccessToken expires
+}
+
+// SessionService issues access and refresh tokens for sessions. Refresh tokens
+// are single use: each refresh hands out a new one, and presenting the one it
+// replaced again revokes the session, since either the client or an attacker
+// holds a stolen copy. Older or made up tokens are only rejected.
+type SessionService struct {
+	sessionRepo repository.SessionRepository
+	userRepo    repository.UserRepository
+	refreshTTL  time.Duration
+}
+
+func NewSessionService(sessionRepo repository.SessionRepository, userRepo repository.UserRepository, refreshTTL time.Duration) *SessionService {
+	return &SessionService{
+		sessionRepo: sessionRepo,
+		userRepo:    userRepo,
+		refreshTTL:  refreshTTL,
+	}
+}
+
+// Start opens a new session for a user who just signed in
+func (s *SessionService) Start(user *models.User, userAgent, ipAddress string) (*Tokens, error) {
+	sessionID := uuid.New().String()
+	refreshToken, hash, err := auth.NewRefreshToken(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	session := &models.Session{
+		ID:        sessionID,
+		UserID:    user.ID,
+		TokenHash: hash,
+		UserAgent: userAgent,
+		IPAddress: ipAddress,
+		ExpiresAt: time.Now().Add(s.refreshTTL),
+	}
+	if err := s.sessionRepo.Create(session); err != nil {
+		return nil, err
+	}
+
+	return s.issue(user, sessionID, refreshToken)
+}
+
+// Refresh trades a refresh token for a new access and refresh token pair
+func (s *SessionService) Refresh(refreshToken string) (*Tokens, *models.User, error) {
+	sessionID, hash, err := auth.ParseRefreshToken(refreshToken)
+	if err != nil {
+		return nil, nil, ErrInvalidRefreshToken
+	}
+	session, err := s.sessionRepo.GetByID(sessionID)
+	if err != nil || !session.Active(time.Now()) {
+		return nil, nil, ErrInvalidRefreshToken
+	}
+
+	newToken, newHash, err := auth.NewRefreshToken(sessionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.sessionRepo.Rotate(sessionID, hash, newHash, time.Now().Add(s.refreshTTL)); err != nil {
+		if errors.Is(err, repository.ErrRefreshTokenReused) {
+			log.Printf("[Auth] Refresh token reused on session %s of user %s, revoking session", sessionID, session.UserID)
+			if err := s.sessionRepo.Revoke(sessionID); err != nil {
+				log.Printf("[Auth] Failed to revoke session %s: %v", sessionID, err)
+			}
+		}
+		// Anyone can make up a token for a session they know the ID of, so
+		// only the previous token proves reuse
+		if errors.Is(err, repository.ErrRefreshTokenInvalid) {
+			return nil, nil, ErrInvalidRefreshToken
+		}
+		return nil, nil, err
+	}
+
+	user, err := s.userRepo.GetByID(session.UserID)
+	if err != nil {
+		return nil, nil, ErrInvalidRefreshToken
+	}
+	tokens, err := s.issue(user, sessionID, newToken)
+	return tokens, user, err
+}
+
+// IsActive reports whether access tokens of a session are still accepted
+func (s *SessionService) IsActive(sessionID string) bool {
+	session, err := s.sessionRepo.GetByID(sessionID)
+	return err == nil && session.Active(time.Now())
+}
+
+// List returns the user's active sessions, flagging the one with currentID
+func (s *SessionService) List(userID, currentID string) ([]*models.Session, error) {
+	sessions, err := s.sessionRepo.ListActive(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, session := range sessions {
+		session.Current = session.ID == currentID
+	}
+	return sessions, nil
+}
+
+// Revoke ends one of the user's sessions
+func (s *SessionService) Revoke(userID, sessionID string) error {
+	session, err := s.sessionRepo.GetByID(sessionID)
+	if err != nil || session.UserID != userID {
+		return ErrSessionNotFound
+	}
+	return s.sessionRepo.Revoke(sessionID)
+}
+
+// RevokeOthers ends every session of the user except keepID
+func (s *SessionService) RevokeOthers(userID, keepID string) error {
+	return s.sessionRepo.RevokeAllForUser(userID, keepID)
+}
+
+func (s *SessionService) issue(user *models.User, sessionID, refreshToken string) (*Tokens, error) {
+	accessToken, err := auth.GenerateToken(user.ID, user.Email, user.Handle, string(user.Role), sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return &Tokens{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(auth.AccessTokenTTL().Seconds()),
+	}, nil
+}
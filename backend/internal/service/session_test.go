@@ -0,0 +1,109 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yourusername/v-backend/internal/auth"
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
+	"github.com/yourusername/v-backend/internal/repository/memory"
+)
+
+func newTestSessions(t *testing.T) (*SessionService, *models.User) {
+	t.Helper()
+	repos := memory.NewRepositories()
+	user := &models.User{ID: "alice", Handle: "alice", Email: "alice@example.com"}
+	if err := repos.User.Create(user); err != nil {
+		t.Fatal(err)
+	}
+	return NewSessionService(repos.Session, repos.User, time.Hour), user
+}
+
+func TestRefreshRotatesTokens(t *testing.T) {
+	sessions, user := newTestSessions(t)
+
+	first, err := sessions.Start(user, "test", "127.0.0.1")
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	second, _, err := sessions.Refresh(first.RefreshToken)
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if second.RefreshToken == first.RefreshToken {
+		t.Error("refresh token was not rotated")
+	}
+
+	claims, err := auth.ValidateToken(second.AccessToken)
+	if err != nil || claims.UserID != "alice" || claims.SessionID == "" {
+		t.Fatalf("claims = %+v, %v", claims, err)
+	}
+	if !sessions.IsActive(claims.SessionID) {
+		t.Error("session inactive after refresh")
+	}
+}
+
+func TestRefreshTokenReuseRevokesSession(t *testing.T) {
+	sessions, user := newTestSessions(t)
+
+	first, _ := sessions.Start(user, "test", "127.0.0.1")
+	second, _, err := sessions.Refresh(first.RefreshToken)
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+
+	// Replaying the first token kills the whole session, including the token
+	// the legitimate client got from the rotation
+	if _, _, err := sessions.Refresh(first.RefreshToken); !errors.Is(err, repository.ErrRefreshTokenReused) {
+		t.Fatalf("replay = %v, want reuse", err)
+	}
+	if _, _, err := sessions.Refresh(second.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("refresh after reuse = %v, want invalid", err)
+	}
+	claims, _ := auth.ValidateToken(second.AccessToken)
+	if sessions.IsActive(claims.SessionID) {
+		t.Error("session still active after reuse")
+	}
+}
+
+func TestForgedRefreshTokenKeepsSession(t *testing.T) {
+	sessions, user := newTestSessions(t)
+
+	first, _ := sessions.Start(user, "test", "127.0.0.1")
+	second, _, err := sessions.Refresh(first.RefreshToken)
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+
+	// Session IDs are no secret, so a made up token for one is only rejected
+	claims, _ := auth.ValidateToken(second.AccessToken)
+	forged, _, _ := auth.NewRefreshToken(claims.SessionID)
+	if _, _, err := sessions.Refresh(forged); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("forged token = %v, want invalid", err)
+	}
+	if _, _, err := sessions.Refresh(second.RefreshToken); err != nil {
+		t.Errorf("refresh after forged token: %v", err)
+	}
+}
+
+func TestRevokeOnlyOwnSessions(t *testing.T) {
+	sessions, user := newTestSessions(t)
+
+	tokens, _ := sessions.Start(user, "test", "127.0.0.1")
+	claims, _ := auth.ValidateToken(tokens.AccessToken)
+
+	if err := sessions.Revoke("mallory", claims.SessionID); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("revoke by another user = %v, want not found", err)
+	}
+	if err := sessions.Revoke("alice", claims.SessionID); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+	if sessions.IsActive(claims.SessionID) {
+		t.Error("session active after revoke")
+	}
+	if list, _ := sessions.List("alice", ""); len(list) != 0 {
+		t.Errorf("%d active sessions after revoke", len(list))
+	}
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"log"
+	"time"
+
+	"github.com/yourusername/v-backend/internal/auth"
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
+)
+
+// How long a key is published before it signs anything, so services that
+// cache our key set have seen it by the time tokens signed with it turn up
+const keyPublishAhead = time.Hour
+
+// SigningKeyService rotates the keys access tokens are signed with. Keys are
+// stored, so every instance signs with the same one and tokens survive a
+// restart.
+type SigningKeyService struct {
+	repo      repository.SigningKeyRepository
+	algorithm string
+	rotation  time.Duration // How long each key signs tokens
+}
+
+func NewSigningKeyService(repo repository.SigningKeyRepository, algorithm string, rotation time.Duration) *SigningKeyService {
+	return &SigningKeyService{
+		repo:      repo,
+		algorithm: algorithm,
+		rotation:  rotation,
+	}
+}
+
+// Rotate drops expired keys, makes the next key when the current one is close
+// to retiring, and loads the stored keys into auth
+func (s *SigningKeyService) Rotate() error {
+	now := time.Now()
+	if pruned, err := s.repo.Prune(now); err != nil {
+		log.Printf("[SigningKeys] Failed to prune expired keys: %v", err)
+	} else if pruned > 0 {
+		log.Printf("[SigningKeys] Pruned %d expired key(s)", pruned)
+	}
+
+	stored, err := s.repo.List()
+	if err != nil {
+		return err
+	}
+
+	var keys []*auth.Key
+	var signing *auth.Key
+	var latest *models.SigningKey
+	for _, sk := range stored {
+		key, err := auth.OpenKey(sk.ID, sk.Algorithm, sk.PrivateKey)
+		if err != nil {
+			log.Printf("[SigningKeys] Skipping key %s: %v", sk.ID, err)
+			continue
+		}
+		keys = append(keys, key)
+		// Keys are listed oldest activation first, so the last active one wins
+		if !sk.ActivatesAt.After(now) && now.Before(sk.RetiresAt) {
+			signing = key
+		}
+		latest = sk
+	}
+
+	if signing == nil {
+		// First start, or nothing ran to rotate in time
+		key, err := s.create(now)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, key)
+		signing = key
+	} else if now.After(latest.RetiresAt.Add(-s.publishAhead())) {
+		key, err := s.create(latest.RetiresAt)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, key)
+	}
+
+	auth.SetKeys(signing, keys)
+	return nil
+}
+
+// Run rotates keys every interval
+func (s *SigningKeyService) Run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	for range ticker.C {
+		if err := s.Rotate(); err != nil {
+			log.Printf("[SigningKeys] Failed to rotate keys: %v", err)
+		}
+	}
+}
+
+// create stores a new key that signs from activatesAt for one rotation and is
+// published until the last token it signed has expired
+func (s *SigningKeyService) create(activatesAt time.Time) (*auth.Key, error) {
+	key, err := auth.GenerateKey(s.algorithm)
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := key.Seal()
+	if err != nil {
+		return nil, err
+	}
+
+	retiresAt := activatesAt.Add(s.rotation)
+	err = s.repo.Create(&models.SigningKey{
+		ID:          key.ID,
+		Algorithm:   key.Algorithm,
+		PrivateKey:  sealed,
+		ActivatesAt: activatesAt,
+		RetiresAt:   retiresAt,
+		ExpiresAt:   retiresAt.Add(auth.AccessTokenTTL() + time.Minute),
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[SigningKeys] Created %s key %s, signing from %s", key.Algorithm, key.ID, activatesAt.Format(time.RFC3339))
+	return key, nil
+}
+
+func (s *SigningKeyService) publishAhead() time.Duration {
+	if s.rotation/2 < keyPublishAhead {
+		return s.rotation / 2
+	}
+	return keyPublishAhead
+}
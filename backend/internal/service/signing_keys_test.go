@@ -0,0 +1,69 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/yourusername/v-backend/internal/auth"
+	"github.com/yourusername/v-backend/internal/repository/memory"
+)
+
+func TestSigningKeysSurviveRestart(t *testing.T) {
+	repo := memory.NewSigningKeyMemoryRepository()
+	if err := NewSigningKeyService(repo, auth.AlgorithmEdDSA, 30*24*time.Hour).Rotate(); err != nil {
+		t.Fatalf("rotate: %v", err)
+	}
+	token, err := auth.GenerateToken("user-1", "a@example.com", "alice", "", "session-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Another instance, or this one after a restart, loads the same key
+	if err := NewSigningKeyService(repo, auth.AlgorithmEdDSA, 30*24*time.Hour).Rotate(); err != nil {
+		t.Fatalf("rotate again: %v", err)
+	}
+	if keys, _ := repo.List(); len(keys) != 1 {
+		t.Errorf("%d keys stored, want 1", len(keys))
+	}
+	if _, err := auth.ValidateToken(token); err != nil {
+		t.Errorf("token from before the restart: %v", err)
+	}
+}
+
+func TestSigningKeysPublishNextKeyEarly(t *testing.T) {
+	repo := memory.NewSigningKeyMemoryRepository()
+	// Rotating every hour, the next key is published half an hour ahead
+	s := NewSigningKeyService(repo, auth.AlgorithmRS256, time.Hour)
+	if err := s.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+	keys, _ := repo.List()
+	if len(keys) != 1 {
+		t.Fatalf("%d keys after first rotation", len(keys))
+	}
+	keys[0].ActivatesAt = time.Now().Add(-40 * time.Minute)
+	keys[0].RetiresAt = keys[0].ActivatesAt.Add(time.Hour)
+	repo.Prune(time.Now().Add(24 * time.Hour))
+	if err := repo.Create(keys[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+	keys, _ = repo.List()
+	if len(keys) != 2 || !keys[1].ActivatesAt.Equal(keys[0].RetiresAt) {
+		t.Fatalf("keys after rotation = %+v", keys)
+	}
+	if len(auth.PublicKeys().Keys) != 2 {
+		t.Error("next key is not published")
+	}
+
+	// The current key still signs until it retires
+	token, _ := auth.GenerateToken("user-1", "a@example.com", "alice", "", "session-1")
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, &jwt.RegisteredClaims{})
+	if err != nil || parsed.Header["kid"] != keys[0].ID {
+		t.Errorf("signed with %v, want the current key %s", parsed.Header["kid"], keys[0].ID)
+	}
+}
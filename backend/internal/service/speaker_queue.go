@@ -0,0 +1,521 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
+)
+
+var (
+	ErrSpeakRequestNotFound = errors.New("speak request not found")
+	ErrDebateNotFound       = errors.New("debate not found")
+	ErrNotInDebate          = errors.New("join the debate before asking to speak")
+	ErrAlreadyQueued        = errors.New("you already asked to speak in this debate")
+	ErrNotWaiting           = errors.New("speak request is not waiting in the queue")
+	ErrNotSpeaking          = errors.New("speak request does not have the floor")
+	ErrOutOfTurn            = errors.New("it is not this speaker's side's turn in the current round")
+	ErrQueueOrder           = errors.New("the new order must list every waiting request once")
+	ErrInvalidQueueOrder    = errors.New("queue order must be 'fifo' or 'alternating'")
+	ErrInvalidTurnLimit     = errors.New("max turn time must be between 0 and 3600 seconds")
+)
+
+// Longest turn a host can allow, in seconds
+const maxTurnSeconds = 60 * 60
+
+// ValidateQueueSettings checks a debate's queue order and turn limit
+func ValidateQueueSettings(order string, turnSeconds int) error {
+	if order != "" && order != models.QueueOrderFIFO && order != models.QueueOrderAlternating {
+		return ErrInvalidQueueOrder
+	}
+	if turnSeconds < 0 || turnSeconds > maxTurnSeconds {
+		return ErrInvalidTurnLimit
+	}
+	return nil
+}
+
+// SpeakerQueueService runs each debate's speaker queue. Approving a request
+// gives the speaker the floor, unmuted; when the turn ends, by hand or when
+// the debate's turn limit runs out, they are muted again. Every change is
+// broadcast to the room as debate:queue_updated.
+type SpeakerQueueService struct {
+	repo repository.DebateRepository
+	hub  *Hub
+
+	// Called when the queue mutes or unmutes a speaker, so the room gets the
+	// new participant list
+	participantsChanged func(debateID string)
+
+	mu     sync.Mutex            // Serializes queue changes
+	timers map[string]*turnTimer // By speak request ID
+}
+
+// turnTimer ends a turn in a debate when its time runs out
+type turnTimer struct {
+	debateID string
+	*time.Timer
+}
+
+func NewSpeakerQueueService(repo repository.DebateRepository, hub *Hub) *SpeakerQueueService {
+	return &SpeakerQueueService{
+		repo:   repo,
+		hub:    hub,
+		timers: make(map[string]*turnTimer),
+	}
+}
+
+// SetParticipantsNotifier sets what is called when the queue changes who is
+// muted in a debate
+func (q *SpeakerQueueService) SetParticipantsNotifier(notify func(debateID string)) {
+	q.participantsChanged = notify
+}
+
+// Start sets the timers of the turns in progress, so turns that ran out while
+// the server was down end right away
+func (q *SpeakerQueueService) Start() error {
+	debates, err := q.repo.ListPending()
+	if err != nil {
+		return err
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	turns := 0
+	for _, debate := range debates {
+		requests, err := q.repo.GetSpeakRequests(debate.ID)
+		if err != nil {
+			return err
+		}
+		for _, request := range requests {
+			if request.Status == "approved" && request.TurnEndsAt != nil {
+				q.schedule(request)
+				turns++
+			}
+		}
+	}
+	log.Printf("[SpeakerQueue] Scheduled %d turn(s) in progress", turns)
+	return nil
+}
+
+// Get returns a debate's speakers and who is up next
+func (q *SpeakerQueueService) Get(debateID string) (*models.SpeakerQueue, error) {
+	debate, err := q.repo.GetByID(debateID)
+	if err != nil {
+		return nil, ErrDebateNotFound
+	}
+	requests, err := q.repo.GetSpeakRequests(debateID)
+	if err != nil {
+		return nil, err
+	}
+
+	order := debate.QueueOrder
+	if order == "" {
+		order = models.QueueOrderFIFO
+	}
+	queue := &models.SpeakerQueue{
+		DebateID:       debateID,
+		Order:          order,
+		MaxTurnSeconds: debate.MaxTurnSeconds,
+		Speaking:       make([]*models.SpeakRequest, 0),
+	}
+
+	// The host's order comes first; requests made since follow it in the
+	// debate's order
+	var ordered, unordered []*models.SpeakRequest
+	lastSide := ""
+	var lastTurn time.Time
+	for _, request := range requests {
+		switch request.Status {
+		case "approved":
+			queue.Speaking = append(queue.Speaking, request)
+		case "pending":
+			if request.Position > 0 {
+				ordered = append(ordered, request)
+			} else {
+				unordered = append(unordered, request)
+			}
+		}
+		if request.TurnStartedAt != nil && request.TurnStartedAt.After(lastTurn) {
+			lastSide, lastTurn = request.Side, *request.TurnStartedAt
+		}
+	}
+	slices.SortStableFunc(ordered, func(a, b *models.SpeakRequest) int { return a.Position - b.Position })
+	if len(ordered) > 0 {
+		lastSide = ordered[len(ordered)-1].Side
+	}
+	if order == models.QueueOrderAlternating {
+		unordered = alternate(unordered, lastSide)
+	}
+	queue.Waiting = append(ordered, unordered...)
+	if queue.Waiting == nil {
+		queue.Waiting = make([]*models.SpeakRequest, 0)
+	}
+	return queue, nil
+}
+
+// alternate interleaves the agree and disagree requests, starting with the
+// side that did not speak last. Requests from neither side go at the end.
+func alternate(requests []*models.SpeakRequest, lastSide string) []*models.SpeakRequest {
+	var agree, disagree, rest []*models.SpeakRequest
+	for _, request := range requests {
+		switch strings.ToLower(strings.TrimSpace(request.Side)) {
+		case "agree":
+			agree = append(agree, request)
+		case "disagree":
+			disagree = append(disagree, request)
+		default:
+			rest = append(rest, request)
+		}
+	}
+
+	out := make([]*models.SpeakRequest, 0, len(requests))
+	nextAgree := strings.ToLower(strings.TrimSpace(lastSide)) != "agree"
+	for len(agree) > 0 || len(disagree) > 0 {
+		if (nextAgree && len(agree) > 0) || len(disagree) == 0 {
+			out, agree = append(out, agree[0]), agree[1:]
+			nextAgree = false
+		} else {
+			out, disagree = append(out, disagree[0]), disagree[1:]
+			nextAgree = true
+		}
+	}
+	return append(out, rest...)
+}
+
+// Request puts a participant in the queue, from the side they are on
+func (q *SpeakerQueueService) Request(debateID, userID string) (*models.SpeakRequest, error) {
+	q.mu.Lock()
+	request, err := q.request(debateID, userID)
+	q.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	q.changed(debateID, false)
+	return request, nil
+}
+
+func (q *SpeakerQueueService) request(debateID, userID string) (*models.SpeakRequest, error) {
+	if _, err := q.repo.GetByID(debateID); err != nil {
+		return nil, ErrDebateNotFound
+	}
+	participant, err := q.participant(debateID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if participant == nil {
+		return nil, ErrNotInDebate
+	}
+
+	requests, err := q.repo.GetSpeakRequests(debateID)
+	if err != nil {
+		return nil, err
+	}
+	for _, request := range requests {
+		if request.UserID == userID && (request.Status == "pending" || request.Status == "approved") {
+			return nil, ErrAlreadyQueued
+		}
+	}
+
+	request := &models.SpeakRequest{
+		ID:       uuid.New().String(),
+		DebateID: debateID,
+		UserID:   userID,
+		Side:     participant.Side,
+		Status:   "pending",
+	}
+	if err := q.repo.CreateSpeakRequest(request); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// Approve gives a waiting request the floor and unmutes its speaker. In a
+// debate held in a format only speakers whose side is in turn can be approved.
+func (q *SpeakerQueueService) Approve(requestID string) (*models.SpeakRequest, error) {
+	q.mu.Lock()
+	request, err := q.approve(requestID)
+	q.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	q.changed(request.DebateID, true)
+	return request, nil
+}
+
+func (q *SpeakerQueueService) approve(requestID string) (*models.SpeakRequest, error) {
+	request, err := q.waiting(requestID)
+	if err != nil {
+		return nil, err
+	}
+	debate, err := q.repo.GetByID(request.DebateID)
+	if err != nil {
+		return nil, ErrDebateNotFound
+	}
+
+	// The round clock would only mute them again. It waits for q.mu before
+	// muting, so a round that starts meanwhile still mutes them after this.
+	now := time.Now()
+	if round, ok := CurrentRound(debate, now); ok && !inTurn(round.Round, request.Side) {
+		return nil, ErrOutOfTurn
+	}
+
+	request.Status = "approved"
+	request.Position = 0
+	request.TurnStartedAt = &now
+	if debate.MaxTurnSeconds > 0 {
+		ends := now.Add(time.Duration(debate.MaxTurnSeconds) * time.Second)
+		request.TurnEndsAt = &ends
+	}
+	if err := q.repo.UpdateSpeakRequest(request); err != nil {
+		return nil, err
+	}
+	if err := q.setMuted(request, false); err != nil {
+		return nil, err
+	}
+	if request.TurnEndsAt != nil {
+		q.schedule(request)
+	}
+	return request, nil
+}
+
+// Deny turns down a waiting request
+func (q *SpeakerQueueService) Deny(requestID string) (*models.SpeakRequest, error) {
+	q.mu.Lock()
+	request, err := q.waiting(requestID)
+	if err == nil {
+		request.Status = "denied"
+		err = q.repo.UpdateSpeakRequest(request)
+	}
+	q.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	q.changed(request.DebateID, false)
+	return request, nil
+}
+
+// EndTurn takes the floor back from a speaker and mutes them
+func (q *SpeakerQueueService) EndTurn(requestID string) (*models.SpeakRequest, error) {
+	q.mu.Lock()
+	request, err := q.endTurn(requestID)
+	q.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	q.changed(request.DebateID, true)
+	return request, nil
+}
+
+func (q *SpeakerQueueService) endTurn(requestID string) (*models.SpeakRequest, error) {
+	request, err := q.repo.GetSpeakRequest(requestID)
+	if err != nil {
+		return nil, ErrSpeakRequestNotFound
+	}
+	if request.Status != "approved" {
+		return nil, ErrNotSpeaking
+	}
+
+	q.cancel(requestID)
+	now := time.Now()
+	request.Status = "done"
+	request.TurnEndsAt = &now
+	if err := q.repo.UpdateSpeakRequest(request); err != nil {
+		return nil, err
+	}
+	if err := q.setMuted(request, true); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// Withdraw drops a request from the queue, ending its turn first if it has
+// the floor
+func (q *SpeakerQueueService) Withdraw(requestID string) error {
+	q.mu.Lock()
+	request, err := q.repo.GetSpeakRequest(requestID)
+	if err != nil {
+		q.mu.Unlock()
+		return ErrSpeakRequestNotFound
+	}
+	speaking := request.Status == "approved"
+	if speaking {
+		_, err = q.endTurn(requestID)
+	}
+	if err == nil {
+		err = q.repo.DeleteSpeakRequest(requestID)
+	}
+	q.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	q.changed(request.DebateID, speaking)
+	return nil
+}
+
+// Reorder sets the order of a debate's waiting requests, which must all be
+// listed
+func (q *SpeakerQueueService) Reorder(debateID string, requestIDs []string) error {
+	q.mu.Lock()
+	err := q.reorder(debateID, requestIDs)
+	q.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	q.changed(debateID, false)
+	return nil
+}
+
+func (q *SpeakerQueueService) reorder(debateID string, requestIDs []string) error {
+	requests, err := q.repo.GetSpeakRequests(debateID)
+	if err != nil {
+		return ErrDebateNotFound
+	}
+	waiting := make(map[string]*models.SpeakRequest)
+	for _, request := range requests {
+		if request.Status == "pending" {
+			waiting[request.ID] = request
+		}
+	}
+	if len(requestIDs) != len(waiting) {
+		return ErrQueueOrder
+	}
+
+	seen := make(map[string]bool, len(requestIDs))
+	for _, id := range requestIDs {
+		if waiting[id] == nil || seen[id] {
+			return ErrQueueOrder
+		}
+		seen[id] = true
+	}
+	for i, id := range requestIDs {
+		request := waiting[id]
+		request.Position = i + 1
+		if err := q.repo.UpdateSpeakRequest(request); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Changed broadcasts a debate's queue again, as when its order or turn limit
+// changed
+func (q *SpeakerQueueService) Changed(debateID string) {
+	q.changed(debateID, false)
+}
+
+// waiting returns a request that is still in the queue
+func (q *SpeakerQueueService) waiting(requestID string) (*models.SpeakRequest, error) {
+	request, err := q.repo.GetSpeakRequest(requestID)
+	if err != nil {
+		return nil, ErrSpeakRequestNotFound
+	}
+	if request.Status != "pending" {
+		return nil, ErrNotWaiting
+	}
+	return request, nil
+}
+
+// participant returns userID's place in a debate, or nil if they are not in it
+func (q *SpeakerQueueService) participant(debateID, userID string) (*models.DebateParticipant, error) {
+	participants, err := q.repo.GetParticipants(debateID)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range participants {
+		if p.UserID == userID && p.LeftAt == nil {
+			return p, nil
+		}
+	}
+	return nil, nil
+}
+
+// setMuted host-mutes or unmutes a request's speaker. A speaker given the
+// floor is unmuted outright; one who left in the meantime is skipped.
+func (q *SpeakerQueueService) setMuted(request *models.SpeakRequest, muted bool) error {
+	p, err := q.participant(request.DebateID, request.UserID)
+	if err != nil || p == nil {
+		return err
+	}
+	p.IsMutedByHost = muted
+	if !muted {
+		p.IsSelfMuted = false
+	}
+	return q.repo.UpdateParticipant(p)
+}
+
+// schedule ends request's turn when its time runs out. The caller holds q.mu.
+func (q *SpeakerQueueService) schedule(request *models.SpeakRequest) {
+	id := request.ID
+	if timer, ok := q.timers[id]; ok {
+		timer.Stop()
+	}
+	q.timers[id] = &turnTimer{request.DebateID, time.AfterFunc(time.Until(*request.TurnEndsAt), func() { q.expire(id) })}
+}
+
+// cancel drops the timer of a turn that ended. The caller holds q.mu.
+func (q *SpeakerQueueService) cancel(requestID string) {
+	if timer, ok := q.timers[requestID]; ok {
+		timer.Stop()
+		delete(q.timers, requestID)
+	}
+}
+
+// CancelDebate drops the turn timers of a debate that was deleted
+func (q *SpeakerQueueService) CancelDebate(debateID string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for id, timer := range q.timers {
+		if timer.debateID == debateID {
+			timer.Stop()
+			delete(q.timers, id)
+		}
+	}
+}
+
+// CancelAll drops every turn timer, once all debates were cleared
+func (q *SpeakerQueueService) CancelAll() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for id, timer := range q.timers {
+		timer.Stop()
+		delete(q.timers, id)
+	}
+}
+
+// expire ends a turn whose time ran out, unless it already ended
+func (q *SpeakerQueueService) expire(requestID string) {
+	request, err := q.EndTurn(requestID)
+	if errors.Is(err, ErrNotSpeaking) || errors.Is(err, ErrSpeakRequestNotFound) {
+		return
+	}
+	if err != nil {
+		log.Printf("[SpeakerQueue] Failed to end turn %s: %v", requestID, err)
+		return
+	}
+	log.Printf("[SpeakerQueue] Turn of %s in debate %s ran out", request.UserID, request.DebateID)
+}
+
+// changed broadcasts a debate's queue, and its participants when the queue
+// muted or unmuted someone
+func (q *SpeakerQueueService) changed(debateID string, participants bool) {
+	if participants && q.participantsChanged != nil {
+		q.participantsChanged(debateID)
+	}
+
+	queue, err := q.Get(debateID)
+	if err != nil {
+		log.Printf("[SpeakerQueue] Failed to load queue of debate %s: %v", debateID, err)
+		return
+	}
+	payload, _ := json.Marshal(map[string]interface{}{
+		"type":  "debate:queue_updated",
+		"queue": queue,
+	})
+	q.hub.Broadcast <- Message{RoomID: debateID, Payload: payload}
+}
@@ -0,0 +1,242 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
+	"github.com/yourusername/v-backend/internal/repository/memory"
+)
+
+func waitingUsers(t *testing.T, queue *SpeakerQueueService) []string {
+	t.Helper()
+	got, err := queue.Get("d1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var users []string
+	for _, request := range got.Waiting {
+		users = append(users, request.UserID)
+	}
+	return users
+}
+
+func participantFor(t *testing.T, repo repository.DebateRepository, userID string) *models.DebateParticipant {
+	t.Helper()
+	participants, _ := repo.GetParticipants("d1")
+	for _, p := range participants {
+		if p.UserID == userID {
+			return p
+		}
+	}
+	t.Fatalf("%s is not in the debate", userID)
+	return nil
+}
+
+func TestSpeakerQueue(t *testing.T) {
+	repos := memory.NewRepositories()
+	hub := NewHub(NewDebateRoomAccess(repos.Debate, repos.User, repos.Community))
+	queue := NewSpeakerQueueService(repos.Debate, hub)
+
+	debate := &models.Debate{ID: "d1", HostID: "host", Status: "ACTIVE", StartTime: time.Now(), QueueOrder: models.QueueOrderAlternating}
+	if err := repos.Debate.Create(debate); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []*models.DebateParticipant{
+		{DebateID: "d1", UserID: "alice", Side: "agree", IsSelfMuted: true},
+		{DebateID: "d1", UserID: "amir", Side: "agree", IsSelfMuted: true},
+		{DebateID: "d1", UserID: "bob", Side: "disagree", IsSelfMuted: true},
+	} {
+		if err := repos.Debate.AddParticipant(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := queue.Request("d1", "stranger"); !errors.Is(err, ErrNotInDebate) {
+		t.Errorf("stranger asking = %v, want ErrNotInDebate", err)
+	}
+	requests := make(map[string]*models.SpeakRequest)
+	for _, user := range []string{"alice", "amir", "bob"} {
+		request, err := queue.Request("d1", user)
+		if err != nil {
+			t.Fatal(err)
+		}
+		requests[user] = request
+		time.Sleep(time.Millisecond) // Keep the requests in order
+	}
+	if _, err := queue.Request("d1", "alice"); !errors.Is(err, ErrAlreadyQueued) {
+		t.Errorf("asking twice = %v, want ErrAlreadyQueued", err)
+	}
+	if room, event := nextEvent(t, hub); room != "d1" || event != "debate:queue_updated" {
+		t.Errorf("got %s in %s, want debate:queue_updated in d1", event, room)
+	}
+
+	// Sides take turns, first come first served within a side
+	if got := waitingUsers(t, queue); len(got) != 3 || got[0] != "alice" || got[1] != "bob" || got[2] != "amir" {
+		t.Errorf("waiting = %v, want [alice bob amir]", got)
+	}
+
+	if _, err := queue.Approve(requests["alice"].ID); err != nil {
+		t.Fatal(err)
+	}
+	if p := participantFor(t, repos.Debate, "alice"); p.IsSelfMuted || p.IsMutedByHost {
+		t.Errorf("approved speaker muted: %+v", p)
+	}
+	if _, err := queue.Approve(requests["alice"].ID); !errors.Is(err, ErrNotWaiting) {
+		t.Errorf("approving twice = %v, want ErrNotWaiting", err)
+	}
+
+	// The host's order wins over the debate's
+	if err := queue.Reorder("d1", []string{requests["bob"].ID}); !errors.Is(err, ErrQueueOrder) {
+		t.Errorf("partial reorder = %v, want ErrQueueOrder", err)
+	}
+	if err := queue.Reorder("d1", []string{requests["amir"].ID, requests["bob"].ID}); err != nil {
+		t.Fatal(err)
+	}
+	if got := waitingUsers(t, queue); len(got) != 2 || got[0] != "amir" || got[1] != "bob" {
+		t.Errorf("waiting = %v, want [amir bob]", got)
+	}
+
+	if _, err := queue.EndTurn(requests["alice"].ID); err != nil {
+		t.Fatal(err)
+	}
+	if p := participantFor(t, repos.Debate, "alice"); !p.IsMutedByHost {
+		t.Error("speaker not muted when their turn ended")
+	}
+	if _, err := queue.EndTurn(requests["alice"].ID); !errors.Is(err, ErrNotSpeaking) {
+		t.Errorf("ending twice = %v, want ErrNotSpeaking", err)
+	}
+}
+
+func TestSpeakerQueueEndsTurnsThatRanOut(t *testing.T) {
+	repos := memory.NewRepositories()
+	hub := NewHub(NewDebateRoomAccess(repos.Debate, repos.User, repos.Community))
+
+	debate := &models.Debate{ID: "d1", HostID: "host", Status: "ACTIVE", StartTime: time.Now(), MaxTurnSeconds: 60}
+	if err := repos.Debate.Create(debate); err != nil {
+		t.Fatal(err)
+	}
+	if err := repos.Debate.AddParticipant(&models.DebateParticipant{DebateID: "d1", UserID: "alice", Side: "agree"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The turn ran out while the server was down
+	started, ends := time.Now().Add(-2*time.Minute), time.Now().Add(-time.Minute)
+	request := &models.SpeakRequest{ID: "r1", DebateID: "d1", UserID: "alice", Side: "agree", Status: "approved", TurnStartedAt: &started, TurnEndsAt: &ends}
+	if err := repos.Debate.CreateSpeakRequest(request); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewSpeakerQueueService(repos.Debate, hub).Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	if room, event := nextEvent(t, hub); room != "d1" || event != "debate:queue_updated" {
+		t.Fatalf("got %s in %s, want debate:queue_updated in d1", event, room)
+	}
+	if got, _ := repos.Debate.GetSpeakRequest("r1"); got.Status != "done" {
+		t.Errorf("status = %s, want done", got.Status)
+	}
+	if p := participantFor(t, repos.Debate, "alice"); !p.IsMutedByHost {
+		t.Error("speaker not muted when their turn ran out")
+	}
+}
+
+func TestSpeakerQueueCancelsTurnsOfDeletedDebates(t *testing.T) {
+	repos := memory.NewRepositories()
+	hub := NewHub(NewDebateRoomAccess(repos.Debate, repos.User, repos.Community))
+	queue := NewSpeakerQueueService(repos.Debate, hub)
+
+	for _, id := range []string{"d1", "d2"} {
+		if err := repos.Debate.Create(&models.Debate{ID: id, HostID: "host", Status: "ACTIVE", StartTime: time.Now(), MaxTurnSeconds: 60}); err != nil {
+			t.Fatal(err)
+		}
+		if err := repos.Debate.AddParticipant(&models.DebateParticipant{DebateID: id, UserID: "alice", Side: "agree"}); err != nil {
+			t.Fatal(err)
+		}
+		request, err := queue.Request(id, "alice")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := queue.Approve(request.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	queue.CancelDebate("d1")
+	if len(queue.timers) != 1 {
+		t.Fatalf("%d turn timers left, want d2's only", len(queue.timers))
+	}
+	for _, timer := range queue.timers {
+		if timer.debateID != "d2" {
+			t.Errorf("timer of %s left, want d2's", timer.debateID)
+		}
+	}
+
+	queue.CancelAll()
+	if len(queue.timers) != 0 {
+		t.Errorf("%d turn timers left after cancelling all", len(queue.timers))
+	}
+}
+
+func TestSpeakerQueueApproveDuringRoundChange(t *testing.T) {
+	repos := memory.NewRepositories()
+	hub := NewHub(NewDebateRoomAccess(repos.Debate, repos.User, repos.Community))
+	queue := NewSpeakerQueueService(repos.Debate, hub)
+	scheduler := NewDebateScheduler(repos.Debate, repos.DebateStats, newDebatePoll(repos), hub)
+	scheduler.SetSpeakerQueue(queue)
+
+	// The opening for the motion is about to hand over to the opening against
+	roundStarted := time.Now().Add(-7*time.Minute + 50*time.Millisecond)
+	debate := &models.Debate{ID: "d1", HostID: "host", Status: "ACTIVE", Format: models.FormatOxford,
+		StartTime: roundStarted, RoundStartedAt: &roundStarted}
+	if err := repos.Debate.Create(debate); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []*models.DebateParticipant{
+		{DebateID: "d1", UserID: "alice", Side: "agree"},
+		{DebateID: "d1", UserID: "bob", Side: "disagree", IsMutedByHost: true},
+	} {
+		if err := repos.Debate.AddParticipant(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	request, err := queue.Request("d1", "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := queue.Request("d1", "bob"); err != nil {
+		t.Fatal(err)
+	}
+	if err := scheduler.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Approve alice as the round ends. Whichever goes first, she ends up muted:
+	// either she is approved and the new round mutes her, or she is refused.
+	approved := make(chan error, 1)
+	time.AfterFunc(50*time.Millisecond, func() {
+		_, err := queue.Approve(request.ID)
+		approved <- err
+	})
+	for {
+		if room, event := nextEvent(t, hub); room == "d1" && event == "debate:round_changed" {
+			break
+		}
+	}
+	if err := <-approved; err != nil && !errors.Is(err, ErrOutOfTurn) {
+		t.Fatalf("approve = %v, want nil or ErrOutOfTurn", err)
+	}
+
+	if p := participantFor(t, repos.Debate, "alice"); !p.IsMutedByHost {
+		t.Error("alice unmuted out of turn")
+	}
+	// bob's side is in turn, but the queue has not given him the floor
+	if p := participantFor(t, repos.Debate, "bob"); !p.IsMutedByHost {
+		t.Error("bob unmuted without the floor")
+	}
+	if _, err := queue.Approve(request.ID); !errors.Is(err, ErrOutOfTurn) && !errors.Is(err, ErrNotWaiting) {
+		t.Errorf("approving alice in the new round = %v, want her refused", err)
+	}
+}
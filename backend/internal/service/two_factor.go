@@ -0,0 +1,213 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/yourusername/v-backend/internal/auth"
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository"
+)
+
+var (
+	ErrTwoFactorNotSetUp       = errors.New("two-factor authentication has not been set up")
+	ErrTwoFactorNotEnabled     = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrInvalidTwoFactorCode    = errors.New("invalid two-factor code")
+)
+
+const recoveryCodeCount = 8
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// TwoFactorService enrolls users in TOTP two-factor authentication and checks
+// their codes. Users who lose their authenticator can sign in with one of the
+// recovery codes handed out when they enabled it; each works once.
+type TwoFactorService struct {
+	repo   repository.TwoFactorRepository
+	issuer string // Name authenticator apps list the account under
+}
+
+func NewTwoFactorService(repo repository.TwoFactorRepository, issuer string) *TwoFactorService {
+	return &TwoFactorService{
+		repo:   repo,
+		issuer: issuer,
+	}
+}
+
+// Setup starts an enrollment with a new secret, replacing one that was never
+// confirmed. It does nothing until Enable is called with a code.
+func (s *TwoFactorService) Setup(user *models.User) (*models.TwoFactorSetupResponse, error) {
+	if existing, err := s.repo.Get(user.ID); err == nil && existing.Enabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := auth.NewTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Save(&models.TwoFactor{UserID: user.ID, Secret: secret}); err != nil {
+		return nil, err
+	}
+	return &models.TwoFactorSetupResponse{
+		Secret:     secret,
+		OTPAuthURI: auth.TOTPURI(s.issuer, user.Email, secret),
+	}, nil
+}
+
+// Enable turns two-factor authentication on once the user proves their
+// authenticator works, and returns their recovery codes
+func (s *TwoFactorService) Enable(userID, code string) ([]string, error) {
+	tf, err := s.repo.Get(userID)
+	if err != nil {
+		return nil, ErrTwoFactorNotSetUp
+	}
+	if tf.Enabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+	step, ok := auth.ValidateTOTP(tf.Secret, code, time.Now())
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	enabledAt := time.Now()
+	tf.Enabled = true
+	tf.EnabledAt = &enabledAt
+	tf.LastUsedStep = step
+	tf.RecoveryCodes = hashes
+	if err := s.repo.Save(tf); err != nil {
+		return nil, err
+	}
+	log.Printf("[2FA] Enabled for user %s", userID)
+	return codes, nil
+}
+
+// Enabled reports whether signing in as the user needs a second factor
+func (s *TwoFactorService) Enabled(userID string) bool {
+	tf, err := s.repo.Get(userID)
+	return err == nil && tf.Enabled
+}
+
+// Status reports whether the user has two-factor authentication on and how
+// many recovery codes they have left
+func (s *TwoFactorService) Status(userID string) models.TwoFactorStatus {
+	tf, err := s.repo.Get(userID)
+	if err != nil || !tf.Enabled {
+		return models.TwoFactorStatus{}
+	}
+	return models.TwoFactorStatus{Enabled: true, RecoveryCodesLeft: len(tf.RecoveryCodes)}
+}
+
+// Verify checks a code from the user's authenticator or one of their recovery
+// codes. Either is spent by a successful check.
+func (s *TwoFactorService) Verify(userID, code string) error {
+	tf, err := s.repo.Get(userID)
+	if err != nil || !tf.Enabled {
+		return ErrTwoFactorNotEnabled
+	}
+
+	code = strings.TrimSpace(code)
+	if step, ok := auth.ValidateTOTP(tf.Secret, code, time.Now()); ok {
+		return spendCode(s.repo.UseStep(userID, step))
+	}
+
+	// Each recovery code is a bcrypt check, so only codes shaped like one are tried
+	normalized := normalizeRecoveryCode(code)
+	if !isRecoveryCode(normalized) {
+		return ErrInvalidTwoFactorCode
+	}
+	for _, hash := range tf.RecoveryCodes {
+		if auth.CheckPassword(hash, normalized) {
+			if err := spendCode(s.repo.UseRecoveryCode(userID, hash)); err != nil {
+				return err
+			}
+			log.Printf("[2FA] User %s used a recovery code, %d left", userID, len(tf.RecoveryCodes)-1)
+			return nil
+		}
+	}
+	return ErrInvalidTwoFactorCode
+}
+
+// RegenerateRecoveryCodes replaces the user's recovery codes with new ones
+func (s *TwoFactorService) RegenerateRecoveryCodes(userID, code string) ([]string, error) {
+	if err := s.Verify(userID, code); err != nil {
+		return nil, err
+	}
+
+	tf, err := s.repo.Get(userID)
+	if err != nil {
+		return nil, err
+	}
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	tf.RecoveryCodes = hashes
+	if err := s.repo.Save(tf); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Disable turns two-factor authentication off and forgets the secret
+func (s *TwoFactorService) Disable(userID, code string) error {
+	if err := s.Verify(userID, code); err != nil {
+		return err
+	}
+	if err := s.repo.Delete(userID); err != nil {
+		return err
+	}
+	log.Printf("[2FA] Disabled for user %s", userID)
+	return nil
+}
+
+// spendCode maps a code that was already used to an invalid one, so replaying
+// a code looks no different from guessing one
+func spendCode(err error) error {
+	if errors.Is(err, repository.ErrTwoFactorCodeUsed) {
+		return ErrInvalidTwoFactorCode
+	}
+	return err
+}
+
+// newRecoveryCodes returns recovery codes formatted as xxxxx-xxxxx along with
+// the bcrypt hashes to store
+func newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		raw := make([]byte, 7)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, nil, err
+		}
+		code := strings.ToLower(recoveryCodeEncoding.EncodeToString(raw))[:10]
+		hash, err := auth.HashPassword(code)
+		if err != nil {
+			return nil, nil, err
+		}
+		codes[i] = code[:5] + "-" + code[5:]
+		hashes[i] = hash
+	}
+	return codes, hashes, nil
+}
+
+// normalizeRecoveryCode accepts recovery codes typed without the dash or in
+// upper case
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
+
+// isRecoveryCode reports whether a normalized code has the shape of a
+// recovery code: ten characters of the base32 alphabet
+func isRecoveryCode(code string) bool {
+	return len(code) == 10 && strings.Trim(code, "abcdefghijklmnopqrstuvwxyz234567") == ""
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yourusername/v-backend/internal/auth"
+	"github.com/yourusername/v-backend/internal/models"
+	"github.com/yourusername/v-backend/internal/repository/memory"
+)
+
+func TestTwoFactorEnrollAndVerify(t *testing.T) {
+	twoFactor := NewTwoFactorService(memory.NewRepositories().TwoFactor, "V")
+	user := &models.User{ID: "alice", Email: "alice@example.com"}
+
+	setup, err := twoFactor.Setup(user)
+	if err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+	if twoFactor.Enabled("alice") {
+		t.Fatal("enabled before confirming a code")
+	}
+	if _, err := twoFactor.Enable("alice", "000000"); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Fatalf("enable with wrong code = %v", err)
+	}
+
+	// The code that enabled two-factor cannot be used again to sign in
+	code, _ := auth.TOTPCode(setup.Secret, auth.TOTPStep(time.Now()))
+	recoveryCodes, err := twoFactor.Enable("alice", code)
+	if err != nil || len(recoveryCodes) != recoveryCodeCount {
+		t.Fatalf("enable = %v, %v", recoveryCodes, err)
+	}
+	if err := twoFactor.Verify("alice", code); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Errorf("replayed code = %v, want invalid", err)
+	}
+	if _, err := twoFactor.Setup(user); !errors.Is(err, ErrTwoFactorAlreadyEnabled) {
+		t.Errorf("setup while enabled = %v", err)
+	}
+
+	// Only codes shaped like recovery codes are checked against them
+	for _, code := range recoveryCodes {
+		if !isRecoveryCode(normalizeRecoveryCode(code)) {
+			t.Errorf("recovery code %q not recognised", code)
+		}
+	}
+	for _, code := range []string{"123456", "abcde-fghi1", "abcde-fghij-k", ""} {
+		if isRecoveryCode(normalizeRecoveryCode(code)) {
+			t.Errorf("%q taken for a recovery code", code)
+		}
+	}
+
+	// Recovery codes work once, with or without the dash
+	if err := twoFactor.Verify("alice", "  "+recoveryCodes[0]+" "); err != nil {
+		t.Fatalf("recovery code: %v", err)
+	}
+	if err := twoFactor.Verify("alice", recoveryCodes[0]); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Errorf("reused recovery code = %v, want invalid", err)
+	}
+	undashed := recoveryCodes[1][:5] + recoveryCodes[1][6:]
+	if err := twoFactor.Verify("alice", undashed); err != nil {
+		t.Errorf("recovery code without dash: %v", err)
+	}
+	if status := twoFactor.Status("alice"); !status.Enabled || status.RecoveryCodesLeft != recoveryCodeCount-2 {
+		t.Errorf("status = %+v", status)
+	}
+
+	if err := twoFactor.Disable("alice", recoveryCodes[2]); err != nil {
+		t.Fatalf("disable: %v", err)
+	}
+	if twoFactor.Enabled("alice") {
+		t.Error("still enabled after disable")
+	}
+}
@@ -327,7 +327,7 @@ export default function CommunityFeedPage() {
 
         setJoinLoading(true);
         try {
-            const token = localStorage.getItem('auth_token') || 'demo-token';
+            const token = localStorage.getItem('auth_token');
             const res = await fetch(`http://localhost:8080/api/communities/${id}/join`, {
                 method: 'POST',
                 headers: {
@@ -363,7 +363,7 @@ export default function CommunityFeedPage() {
         if (!confirm('Are you sure you want to leave this community?')) return;
         setJoinLoading(true);
         try {
-            const token = localStorage.getItem('auth_token') || 'demo-token';
+            const token = localStorage.getItem('auth_token');
             const res = await fetch(`http://localhost:8080/api/communities/${id}/leave`, {
                 method: 'POST',
                 headers: {
@@ -424,7 +424,7 @@ export default function CommunityFeedPage() {
                 payload.responseToPostId = replyingToPost.id;
             }
 
-            const token = localStorage.getItem('auth_token') || 'demo-token';
+            const token = localStorage.getItem('auth_token');
             const res = await fetch('http://localhost:8080/api/posts', {
                 method: 'POST',
                 headers: {
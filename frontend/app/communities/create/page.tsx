@@ -45,7 +45,7 @@ export default function CreateCommunityPage() {
     if (token) {
         console.log('DEBUG: Found auth token in cookie');
     } else {
-        console.log('DEBUG: No auth token found in cookie - falling back to demo logic?');
+        console.log('DEBUG: No auth token found in cookie');
     }
 
     const handleSubmit = async (e: React.FormEvent) => {
@@ -64,7 +64,7 @@ export default function CreateCommunityPage() {
                 method: 'POST',
                 headers: {
                     'Content-Type': 'application/json',
-                    'Authorization': `Bearer ${token || 'demo-token'}`, // Mock token handling
+                    'Authorization': `Bearer ${token}`,
                 },
                 body: JSON.stringify(formData),
             });
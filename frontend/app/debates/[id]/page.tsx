@@ -8,7 +8,7 @@ import { useDebateRoomStore, useDebateStore, JoinDebateModal, MicrophonePermissi
 import { useStore } from '@/lib/store';
 import { useToast } from '@/hooks/useToast';
 import { useAuth } from '@/features/auth';
-import { debateAPI, debateStatsAPI } from '@v/api-client';
+import { debateAPI } from '@v/api-client';
 import { generateMockParticipants, getDebateById } from '@/lib/mock-debates';
 import { useAudioStream } from '@/hooks/useAudioStream';
 import { AudioPlayer } from '@/components/AudioPlayer';
@@ -169,11 +169,10 @@ export default function DebateRoomPage({ params }: { params: Promise<{ id: strin
         // Update participants list in real-time - this should happen instantly
         setParticipants(message.participants);
         console.log('[WebSocket] ✅ UI updated with new participants list');
-      } else if (message.type === 'debate:status_changed') {
+      } else if (message.type === 'debate:started' || message.type === 'debate:ended') {
         console.log('[WebSocket] 📢 Debate status changed:', {
           debateId: message.debateId,
           status: message.status,
-          oldStatus: message.oldStatus,
         });
 
         // If debate was ended, redirect all users
@@ -187,14 +186,6 @@ export default function DebateRoomPage({ params }: { params: Promise<{ id: strin
             return;
           }
 
-          // Record stats before ending (if not already recorded)
-          // Use current debate state from closure
-          if (debate && debate.id === (message.debateId || debateIdForWS)) {
-            recordDebateStats(debate).catch(err => {
-              console.error('[WebSocket] Failed to record stats:', err);
-            });
-          }
-
           // Leave room and end debate
           endDebate(message.debateId || debateIdForWS);
 
@@ -316,9 +307,6 @@ export default function DebateRoomPage({ params }: { params: Promise<{ id: strin
 
           console.log('[Debate] Debate already ended, cleaning up and redirecting...');
 
-          // Record stats before ending (if not already recorded)
-          await recordDebateStats(data);
-
           endDebate(debateId);
 
           // CRITICAL: Only refresh if user hasn't joined (if they have joined, they're actively in the room)
@@ -912,121 +900,6 @@ export default function DebateRoomPage({ params }: { params: Promise<{ id: strin
   const hasShownWarning = useRef(false);
   const hasShown1MinWarning = useRef(false);
   const hasAutoEnded = useRef(false);
-  const hasStatsRecorded = useRef(false); // Track if stats have been recorded for this debate
-
-  // Helper function to record debate stats (can be called from multiple places)
-  const recordDebateStats = useCallback(async (debateData: any) => {
-    if (!debateData || !debateData.title) {
-      return; // No title or invalid data
-    }
-
-    // Check if stats have already been recorded for this debate (persist across refreshes)
-    const statsRecordedKey = `debate_stats_recorded_${debateData.id}`;
-    const alreadyRecorded = localStorage.getItem(statsRecordedKey) === 'true';
-
-    if (hasStatsRecorded.current || alreadyRecorded) {
-      console.log('[Debate Stats] Stats already recorded for this debate, skipping...');
-      return; // Already recorded
-    }
-
-    try {
-      // Fetch the latest debate data to get accurate counts
-      let updatedDebate = debateData;
-      try {
-        updatedDebate = await debateAPI.get(debateData.id);
-        console.log('[Debate Stats] Fetched updated debate:', {
-          agreeCount: updatedDebate.agreeCount,
-          disagreeCount: updatedDebate.disagreeCount,
-        });
-      } catch (fetchError) {
-        console.warn('[Debate Stats] Failed to fetch updated debate, using current debate object:', fetchError);
-      }
-
-      let agreeCount = updatedDebate.agreeCount || 0;
-      let disagreeCount = updatedDebate.disagreeCount || 0;
-
-      // If backend counts are 0, try counting from participants as fallback
-      if (agreeCount === 0 && disagreeCount === 0) {
-        console.log('[Debate Stats] Backend counts are 0, counting from participants...');
-
-        // Try fetching from API
-        try {
-          const participantsList = await debateAPI.getParticipants(debateData.id) || [];
-          console.log('[Debate Stats] Fetched participants from API:', participantsList.length);
-
-          participantsList.forEach((p: any) => {
-            const userId = p.userId || p.id;
-            const role = (p.role || '').toUpperCase();
-            const side = (p.side || '').toLowerCase().trim();
-
-            // Skip host
-            if (role === 'HOST' || userId === debateData.hostId) {
-              return;
-            }
-
-            // Skip empty/neutral sides
-            if (!side || side === '' || side === 'neutral' || side === 'spectator') {
-              return;
-            }
-
-            if (side === 'agree') {
-              agreeCount++;
-            } else if (side === 'disagree') {
-              disagreeCount++;
-            }
-          });
-        } catch (fetchError) {
-          console.warn('[Debate Stats] Failed to fetch participants from API:', fetchError);
-        }
-      }
-
-      const totalParticipants = agreeCount + disagreeCount;
-
-      console.log('[Debate Stats] Final counts before recording:', {
-        topic: debateData.title,
-        agreeCount,
-        disagreeCount,
-        totalParticipants,
-      });
-
-      // Only record if we have at least some participants
-      if (totalParticipants > 0 || agreeCount > 0 || disagreeCount > 0) {
-        try {
-          await debateStatsAPI.record({
-            topic: debateData.title,
-            agreeCount,
-            disagreeCount,
-            participants: totalParticipants,
-            debateId: debateData.id, // Include debate ID to prevent duplicates
-          });
-          hasStatsRecorded.current = true; // Mark as recorded to prevent duplicates
-          // Persist in localStorage to survive page refreshes
-          localStorage.setItem(statsRecordedKey, 'true');
-          console.log('[Debate Stats] ✅ Recorded stats for debate:', debateData.title, {
-            agreeCount,
-            disagreeCount,
-            totalParticipants,
-          });
-        } catch (recordError: any) {
-          // If it's a 400 error, it might be a duplicate - mark as recorded anyway
-          if (recordError?.status === 400 || recordError?.message?.includes('400')) {
-            console.log('[Debate Stats] Stats may already be recorded (400 error), marking as recorded...');
-            hasStatsRecorded.current = true;
-            localStorage.setItem(statsRecordedKey, 'true');
-          } else {
-            // Re-throw other errors to be caught by outer catch
-            throw recordError;
-          }
-        }
-      } else {
-        console.log('[Debate Stats] ⚠️ Skipping stats recording: no active participants found.');
-      }
-    } catch (statsError) {
-      // Don't fail if stats recording fails - just log it
-      console.error('[Debate Stats] Failed to record stats:', statsError);
-      // Don't show error to user - stats recording is a background operation
-    }
-  }, []);
 
   useEffect(() => {
     if (!debate) return;
@@ -1036,11 +909,6 @@ export default function DebateRoomPage({ params }: { params: Promise<{ id: strin
     hasShown1MinWarning.current = false;
     hasAutoEnded.current = false;
 
-    // Check if stats have already been recorded for this debate (persist across refreshes)
-    const statsRecordedKey = `debate_stats_recorded_${debate.id}`;
-    const alreadyRecorded = localStorage.getItem(statsRecordedKey) === 'true';
-    hasStatsRecorded.current = alreadyRecorded;
-
     // Log debate timing info for debugging
     console.log('[Debate Timer] Debate info:', {
       id: debate.id,
@@ -1106,118 +974,6 @@ export default function DebateRoomPage({ params }: { params: Promise<{ id: strin
         try {
           await debateAPI.update(debate.id, { status: 'ENDED' });
 
-          // Record stats for this debate (if not already recorded)
-          await recordDebateStats(debate);
-
-          // Old code removed - using helper function instead
-          if (false && isHost && debate.title && !hasStatsRecorded.current) {
-            try {
-              // First, try to use the backend's maintained counts (most reliable)
-              let updatedDebate = debate;
-              try {
-                updatedDebate = await debateAPI.get(debate.id);
-                console.log('[Debate Stats] Fetched updated debate (auto-end):', {
-                  agreeCount: updatedDebate.agreeCount,
-                  disagreeCount: updatedDebate.disagreeCount,
-                });
-              } catch (fetchError) {
-                console.warn('[Debate Stats] Failed to fetch updated debate (auto-end):', fetchError);
-              }
-
-              let agreeCount = updatedDebate.agreeCount || 0;
-              let disagreeCount = updatedDebate.disagreeCount || 0;
-
-              // If backend counts are 0, try counting from participants as fallback
-              if (agreeCount === 0 && disagreeCount === 0) {
-                console.log('[Debate Stats] Backend counts are 0, counting from participants (auto-end)...');
-
-                if (participants && Array.isArray(participants) && participants.length > 0) {
-                  participants.forEach((p: any) => {
-                    const userId = p.userId || p.id;
-                    if (removedUsers.has(userId) || p.leftAt) {
-                      return;
-                    }
-
-                    const role = (p.role || '').toUpperCase();
-                    const side = (p.side || '').toLowerCase().trim();
-
-                    if (role === 'HOST' || userId === debate.hostId) {
-                      return;
-                    }
-
-                    if (!side || side === '' || side === 'neutral' || side === 'spectator') {
-                      return;
-                    }
-
-                    if (side === 'agree') {
-                      agreeCount++;
-                    } else if (side === 'disagree') {
-                      disagreeCount++;
-                    }
-                  });
-                }
-
-                if (agreeCount === 0 && disagreeCount === 0) {
-                  try {
-                    const participantsList = await debateAPI.getParticipants(debate.id) || [];
-                    participantsList.forEach((p: any) => {
-                      const userId = p.userId || p.id;
-                      const role = (p.role || '').toUpperCase();
-                      const side = (p.side || '').toLowerCase().trim();
-
-                      if (role === 'HOST' || userId === debate.hostId) {
-                        return;
-                      }
-
-                      if (!side || side === '' || side === 'neutral' || side === 'spectator') {
-                        return;
-                      }
-
-                      if (side === 'agree') {
-                        agreeCount++;
-                      } else if (side === 'disagree') {
-                        disagreeCount++;
-                      }
-                    });
-                  } catch (fetchError) {
-                    console.warn('[Debate Stats] Failed to fetch participants from API (auto-end):', fetchError);
-                  }
-                }
-              }
-
-              const totalParticipants = agreeCount + disagreeCount;
-
-              console.log('[Debate Stats] Final counts before recording (auto-end):', {
-                topic: debate.title,
-                agreeCount,
-                disagreeCount,
-                totalParticipants,
-              });
-
-              // Only record if we have at least some participants
-              if (totalParticipants > 0 || agreeCount > 0 || disagreeCount > 0) {
-                await debateStatsAPI.record({
-                  topic: debate.title,
-                  agreeCount,
-                  disagreeCount,
-                  participants: totalParticipants,
-                  debateId: debate.id, // Include debate ID to prevent duplicates
-                });
-                hasStatsRecorded.current = true; // Mark as recorded to prevent duplicates
-                console.log('[Debate Stats] ✅ Recorded stats for auto-ended debate:', debate.title, {
-                  agreeCount,
-                  disagreeCount,
-                  totalParticipants,
-                });
-              } else {
-                console.log('[Debate Stats] ⚠️ Skipping stats recording (auto-end): no active participants found.');
-              }
-            } catch (statsError) {
-              // Don't fail the debate end if stats recording fails
-              console.error('[Debate Stats] Failed to record stats:', statsError);
-            }
-          }
-
           endDebate(debate.id);
           console.log('[Debate] Auto-ended debate due to time expiry');
         } catch (error) {
@@ -1898,9 +1654,6 @@ export default function DebateRoomPage({ params }: { params: Promise<{ id: strin
 
       console.log('[End Debate] ✅ Debate ended successfully');
 
-      // Record stats for this debate (if not already recorded)
-      await recordDebateStats(debate);
-
       endDebate(debate.id);
       setShowEndDebateModal(false);
       showToast('Debate ended', 'info');
@@ -1945,7 +1698,7 @@ export default function DebateRoomPage({ params }: { params: Promise<{ id: strin
       // Don't close modal on error so user can try again
       // setShowEndDebateModal(false);
     }
-  }, [debate, currentUser, isAuthenticated, endDebate, showToast, performOneTimeRefresh, recordDebateStats, hasRefreshedRef, isLeavingRef, hasJoined]);
+  }, [debate, currentUser, isAuthenticated, endDebate, showToast, performOneTimeRefresh, hasRefreshedRef, isLeavingRef, hasJoined]);
 
   const handleDeleteDebate = useCallback(async () => {
     if (!confirm('Are you sure you want to delete this debate? This action cannot be undone.')) return;
@@ -120,7 +120,7 @@ export default function DebatesPage() {
         console.log('[Debates Page] New debate created, refreshing list...', message.debate);
         // Refresh the debates list to include the new debate
         loadData();
-      } else if (message.type === 'debate:status_changed') {
+      } else if (message.type === 'debate:started' || message.type === 'debate:ended') {
         console.log('[Debates Page] Debate status changed, refreshing list...', message);
         // Refresh when debate status changes (e.g., started, ended)
         loadData();
@@ -148,6 +148,9 @@ export default function LoginPage() {
       }
 
       const response = await authAPI.login({ email, password });
+      if ('twoFactorRequired' in response) {
+        throw new Error('This account uses two-factor authentication');
+      }
       // Token is automatically set by authAPI.login()
 
       // Set cookie for middleware - use Lax for better Safari compatibility
@@ -29,6 +29,9 @@ export function AuthCard() {
 
     try {
       const response = await authAPI.login({ email: loginEmail, password: loginPassword });
+      if ('twoFactorRequired' in response) {
+        throw new Error('This account uses two-factor authentication');
+      }
       setAuthToken(response.token);
       const expires = new Date();
       expires.setTime(expires.getTime() + 7 * 24 * 60 * 60 * 1000); // 7 days
@@ -1,4 +1,5 @@
 import { useState, useEffect, useRef, useCallback } from 'react';
+import { getAuthToken } from '@v/api-client';
 
 // Types for WebSocket messages
 export interface SignalingMessage {
@@ -37,6 +38,14 @@ export function useSignaling(roomId: string, userId: string): UseSignalingReturn
       return;
     }
 
+    // The server reads the user from the access token, sent as a subprotocol
+    // so it stays out of URLs and logs
+    const token = getAuthToken();
+    if (!token) {
+      console.log('[WebSocket] Not signed in, skipping connection');
+      return;
+    }
+
     // Get WebSocket URL from API base URL
     // API_BASE_URL is like 'http://localhost:8080/api'
     // WebSocket endpoint is at '/api/ws' (under the /api route)
@@ -51,7 +60,7 @@ export function useSignaling(roomId: string, userId: string): UseSignalingReturn
     const wsHost = baseUrl.replace(/^https?:\/\//, '');
     
     // WebSocket is at /api/ws (same route group as other API endpoints)
-    const wsUrl = `${wsProtocol}://${wsHost}/api/ws?roomId=${roomId}&userId=${userId}`;
+    const wsUrl = `${wsProtocol}://${wsHost}/api/ws?roomId=${encodeURIComponent(roomId)}`;
 
     console.log('[WebSocket] Connecting to:', wsUrl, {
       apiUrl,
@@ -62,7 +71,7 @@ export function useSignaling(roomId: string, userId: string): UseSignalingReturn
     });
 
     try {
-        const ws = new WebSocket(wsUrl);
+        const ws = new WebSocket(wsUrl, ['bearer', token]);
       wsRef.current = ws;
 
         ws.onopen = () => {
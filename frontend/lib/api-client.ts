@@ -185,9 +185,9 @@ export const authAPI = {
 // ============================================================================
 
 export const postAPI = {
-  list: async (params?: { limit?: number; offset?: number; authorId?: string }) => {
+  list: async (params?: { limit?: number; cursor?: string; authorId?: string }) => {
     const data = await request<any>(`/posts?${new URLSearchParams(params as any)}`);
-    // Backend returns { posts: [...], limit: ..., offset: ... }
+    // Backend returns { posts: [...], limit: ..., nextCursor: ... }
     if (Array.isArray(data)) {
       return data;
     } else if (data && Array.isArray(data.posts)) {
@@ -354,7 +354,7 @@ export const hashtagAPI = {
 // ============================================================================
 
 export const notificationAPI = {
-  list: async (params?: { limit?: number; offset?: number }) => {
+  list: async (params?: { limit?: number; cursor?: string }) => {
     try {
       const data = await request<any>(`/notifications?${new URLSearchParams(params as any)}`);
       console.log('[Notification API] Raw response:', data);
@@ -27,11 +27,67 @@ export function getAuthToken(): string | null {
   return authToken;
 }
 
+// Refresh token management. Access tokens are short-lived; the refresh token
+// is traded for a new pair when a request comes back 401.
+let refreshToken: string | null = null;
+let refreshing: Promise<boolean> | null = null;
+
+export function setRefreshToken(token: string | null) {
+  refreshToken = token;
+  if (token && typeof window !== 'undefined') {
+    localStorage.setItem('refresh_token', token);
+  } else if (typeof window !== 'undefined') {
+    localStorage.removeItem('refresh_token');
+  }
+}
+
+export function getRefreshToken(): string | null {
+  if (refreshToken) return refreshToken;
+  if (typeof window !== 'undefined') {
+    refreshToken = localStorage.getItem('refresh_token');
+  }
+  return refreshToken;
+}
+
+// Refresh tokens are single use, so concurrent 401s share one refresh
+async function refreshSession(): Promise<boolean> {
+  const token = getRefreshToken();
+  if (!token) return false;
+
+  if (!refreshing) {
+    refreshing = (async () => {
+      try {
+        const response = await fetch(`${getApiBaseUrl()}/auth/refresh`, {
+          method: 'POST',
+          headers: { 'Content-Type': 'application/json' },
+          body: JSON.stringify({ refreshToken: token }),
+        });
+        if (!response.ok) {
+          setAuthToken(null);
+          setRefreshToken(null);
+          return false;
+        }
+        const data = await response.json();
+        const body = data && typeof data === 'object' && 'data' in data ? data.data : data;
+        setAuthToken(body.token);
+        setRefreshToken(body.refreshToken);
+        return true;
+      } catch (e) {
+        return false;
+      } finally {
+        refreshing = null;
+      }
+    })();
+  }
+  return refreshing;
+}
+
 // Request helper
 export async function request<T>(
   endpoint: string,
   options: RequestInit = {},
-  timeout: number = 10000
+  timeout: number = 10000,
+  retried: boolean = false
 ): Promise<T> {
   const token = getAuthToken();
   const headers: Record<string, string> = {
@@ -89,6 +145,10 @@ export async function request<T>(
       }
 
       if (response.status === 401) {
+        // The access token may just have expired; retry once with a fresh one
+        if (!retried && await refreshSession()) {
+          return request<T>(endpoint, options, timeout, true);
+        }
         const authError = new Error(errorMessage || 'Unauthorized');
         (authError as any).status = 401;
         (authError as any).isUnauthorized = true;
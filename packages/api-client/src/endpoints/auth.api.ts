@@ -4,8 +4,8 @@
  * API client methods for authentication operations.
  */
 
-import { LoginRequest, SignupRequest, AuthResponse, ChangePasswordRequest } from '@v/shared';
-import { apiClient, setAuthToken } from '../client';
+import { LoginRequest, SignupRequest, AuthResponse, ChangePasswordRequest, Session, TwoFactorChallenge, TwoFactorSetup, TwoFactorStatus, Identity, OIDCAuthorize, OIDCCallback, OIDCSignupRequired, APIKey, CreateAPIKeyRequest, CreatedAPIKey, CreateBotRequest, User } from '@v/shared';
+import { apiClient, setAuthToken, setRefreshToken } from '../client';
 
 export const authAPI = {
   /**
@@ -17,25 +17,178 @@ export const authAPI = {
     // Automatically set auth token on successful signup
     if (response.token) {
       setAuthToken(response.token);
+      setRefreshToken(response.refreshToken);
     }
     
     return response;
   },
 
   /**
-   * Log in an existing user
+   * Log in an existing user. Accounts with 2FA get a challenge to pass to
+   * verifyTwoFactor instead of tokens.
    */
-  login: async (data: LoginRequest): Promise<AuthResponse> => {
-    const response = await apiClient.post<AuthResponse>('/auth/login', data);
+  login: async (data: LoginRequest): Promise<AuthResponse | TwoFactorChallenge> => {
+    const response = await apiClient.post<AuthResponse | TwoFactorChallenge>('/auth/login', data);
     
     // Automatically set auth token on successful login
-    if (response.token) {
+    if ('token' in response && response.token) {
       setAuthToken(response.token);
+      setRefreshToken(response.refreshToken);
     }
     
     return response;
   },
 
+  /**
+   * Finish a 2FA login with an authenticator or recovery code
+   */
+  verifyTwoFactor: async (challengeToken: string, code: string): Promise<AuthResponse> => {
+    const response = await apiClient.post<AuthResponse>('/auth/2fa/verify', { challengeToken, code });
+
+    if (response.token) {
+      setAuthToken(response.token);
+      setRefreshToken(response.refreshToken);
+    }
+
+    return response;
+  },
+
+  /**
+   * Whether 2FA is on for the current user
+   */
+  getTwoFactorStatus: (): Promise<TwoFactorStatus> => {
+    return apiClient.get<TwoFactorStatus>('/auth/2fa');
+  },
+
+  /**
+   * Start 2FA enrollment; returns the secret for the authenticator app
+   */
+  setupTwoFactor: (): Promise<TwoFactorSetup> => {
+    return apiClient.post<TwoFactorSetup>('/auth/2fa/setup');
+  },
+
+  /**
+   * Turn 2FA on with a code from the authenticator app. The recovery codes
+   * are only shown this once.
+   */
+  enableTwoFactor: (code: string): Promise<{ recoveryCodes: string[] }> => {
+    return apiClient.post<{ recoveryCodes: string[] }>('/auth/2fa/enable', { code });
+  },
+
+  /**
+   * Turn 2FA off
+   */
+  disableTwoFactor: (password: string, code: string): Promise<void> => {
+    return apiClient.post<void>('/auth/2fa/disable', { password, code });
+  },
+
+  /**
+   * Replace the recovery codes
+   */
+  regenerateRecoveryCodes: (code: string): Promise<{ recoveryCodes: string[] }> => {
+    return apiClient.post<{ recoveryCodes: string[] }>('/auth/2fa/recovery-codes', { code });
+  },
+
+  /**
+   * Names of the providers users can sign in with
+   */
+  oidcProviders: (): Promise<string[]> => {
+    return apiClient.get<string[]>('/auth/oidc/providers');
+  },
+
+  /**
+   * Start signing in with a provider. Keep the flowToken and send the user to
+   * authorizationUrl.
+   */
+  beginOidc: (provider: string): Promise<OIDCAuthorize> => {
+    return apiClient.get<OIDCAuthorize>(`/auth/oidc/${provider}/authorize`);
+  },
+
+  /**
+   * Finish signing in with the code and state the provider redirected back
+   * with. A new identity gets a signup token to pass to oidcSignup.
+   */
+  oidcCallback: async (provider: string, data: OIDCCallback): Promise<AuthResponse | TwoFactorChallenge | OIDCSignupRequired> => {
+    const response = await apiClient.post<AuthResponse | TwoFactorChallenge | OIDCSignupRequired>(`/auth/oidc/${provider}/callback`, data);
+
+    if ('token' in response && response.token) {
+      setAuthToken(response.token);
+      setRefreshToken(response.refreshToken);
+    }
+
+    return response;
+  },
+
+  /**
+   * Create the account for a new identity with the handle the user picked
+   */
+  oidcSignup: async (signupToken: string, handle: string): Promise<AuthResponse> => {
+    const response = await apiClient.post<AuthResponse>('/auth/oidc/signup', { signupToken, handle });
+
+    if (response.token) {
+      setAuthToken(response.token);
+      setRefreshToken(response.refreshToken);
+    }
+
+    return response;
+  },
+
+  /**
+   * Provider accounts linked to the current user
+   */
+  listIdentities: (): Promise<Identity[]> => {
+    return apiClient.get<Identity[]>('/auth/identities');
+  },
+
+  /**
+   * Link a provider account to the current user, finishing a flow from beginOidc
+   */
+  linkOidc: (provider: string, data: OIDCCallback): Promise<Identity[]> => {
+    return apiClient.post<Identity[]>(`/auth/oidc/${provider}/link`, data);
+  },
+
+  /**
+   * Unlink a provider account
+   */
+  unlinkOidc: (provider: string): Promise<void> => {
+    return apiClient.delete<void>(`/auth/identities/${provider}`);
+  },
+
+  /**
+   * List API keys for the current user, or for one of their bots
+   */
+  listApiKeys: (botId?: string): Promise<APIKey[]> => {
+    return apiClient.get<APIKey[]>(botId ? `/bots/${botId}/api-keys` : '/auth/api-keys');
+  },
+
+  /**
+   * Create an API key. The key is only shown in this response.
+   */
+  createApiKey: (data: CreateAPIKeyRequest, botId?: string): Promise<CreatedAPIKey> => {
+    return apiClient.post<CreatedAPIKey>(botId ? `/bots/${botId}/api-keys` : '/auth/api-keys', data);
+  },
+
+  /**
+   * Revoke an API key
+   */
+  revokeApiKey: (keyId: string, botId?: string): Promise<void> => {
+    return apiClient.delete<void>(botId ? `/bots/${botId}/api-keys/${keyId}` : `/auth/api-keys/${keyId}`);
+  },
+
+  /**
+   * List the current user's bots
+   */
+  listBots: (): Promise<User[]> => {
+    return apiClient.get<User[]>('/bots');
+  },
+
+  /**
+   * Create a bot account
+   */
+  createBot: (data: CreateBotRequest): Promise<User> => {
+    return apiClient.post<User>('/bots', data);
+  },
+
   /**
    * Get current authenticated user
    */
@@ -51,9 +204,64 @@ export const authAPI = {
   },
 
   /**
-   * Logout (clear token)
+   * Confirm an email address with the token from a verification email
    */
-  logout: (): void => {
+  verifyEmail: (token: string): Promise<any> => {
+    return apiClient.post<any>('/auth/verify-email', { token });
+  },
+
+  /**
+   * Send the current user a new verification email
+   */
+  resendVerification: (): Promise<void> => {
+    return apiClient.post<void>('/auth/verify-email/resend');
+  },
+
+  /**
+   * Email a password reset link
+   */
+  forgotPassword: (email: string): Promise<void> => {
+    return apiClient.post<void>('/auth/forgot-password', { email });
+  },
+
+  /**
+   * Set a new password with the token from a reset email
+   */
+  resetPassword: (token: string, newPassword: string): Promise<void> => {
+    return apiClient.post<void>('/auth/reset-password', { token, newPassword });
+  },
+
+  /**
+   * List active sessions (signed-in devices)
+   */
+  listSessions: (): Promise<Session[]> => {
+    return apiClient.get<Session[]>('/auth/sessions');
+  },
+
+  /**
+   * Sign out one session
+   */
+  revokeSession: (id: string): Promise<void> => {
+    return apiClient.delete<void>(`/auth/sessions/${id}`);
+  },
+
+  /**
+   * Sign out every session except this one
+   */
+  revokeOtherSessions: (): Promise<void> => {
+    return apiClient.delete<void>('/auth/sessions');
+  },
+
+  /**
+   * Logout (revoke the session and clear tokens)
+   */
+  logout: async (): Promise<void> => {
+    try {
+      await apiClient.post<void>('/auth/logout');
+    } catch (e) {
+      // Already signed out server-side; clearing local tokens is enough
+    }
     setAuthToken(null);
+    setRefreshToken(null);
   },
 };
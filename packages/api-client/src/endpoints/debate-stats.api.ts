@@ -5,22 +5,7 @@
  */
 
 import { request } from '../client';
-import type {
-  DebateTopicStats,
-  RecordDebateStatsRequest,
-} from '@v/shared';
-
-/**
- * Record debate stats
- */
-export async function recordDebateStats(
-  data: RecordDebateStatsRequest
-): Promise<DebateTopicStats> {
-  return request<DebateTopicStats>('/debate-stats', {
-    method: 'POST',
-    body: JSON.stringify(data),
-  });
-}
+import type { DebateTopicStats } from '@v/shared';
 
 /**
  * List all debate stats
@@ -34,6 +19,5 @@ export async function listDebateStats(): Promise<DebateTopicStats[]> {
  * Debate Stats API object
  */
 export const debateStatsAPI = {
-  record: recordDebateStats,
   list: listDebateStats,
 };
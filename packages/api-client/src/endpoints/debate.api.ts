@@ -12,11 +12,15 @@ import type {
   JoinDebateRequest,
   UpdateParticipantRequest,
   UpdateSelfMuteRequest,
-  AwardWinRequest,
   DebateParticipant,
   SpeakRequest,
   UpdateSpeakRequestRequest,
   DebateListParams,
+  DebateFormat,
+  RoundStatus,
+  SpeakerQueue,
+  VoteRequest,
+  DebatePoll,
 } from '@v/shared';
 
 /**
@@ -74,6 +78,20 @@ export async function getDebate(id: string): Promise<Debate> {
   return request<Debate>(`/debates/${id}`);
 }
 
+/**
+ * List the formats a debate can be held in
+ */
+export async function getDebateFormats(): Promise<DebateFormat[]> {
+  return request<DebateFormat[]>('/debates/formats');
+}
+
+/**
+ * Get the round a debate is in
+ */
+export async function getDebateRound(id: string): Promise<RoundStatus> {
+  return request<RoundStatus>(`/debates/${id}/round`);
+}
+
 /**
  * Create debate
  */
@@ -108,6 +126,15 @@ export async function deleteDebate(id: string): Promise<void> {
   });
 }
 
+/**
+ * Open a locked debate to everyone (host only)
+ */
+export async function unlockDebate(id: string): Promise<Debate> {
+  return request<Debate>(`/debates/${id}/unlock`, {
+    method: 'POST',
+  });
+}
+
 /**
  * Join debate
  */
@@ -220,8 +247,8 @@ export async function getSpeakRequests(debateId: string): Promise<SpeakRequest[]
 export async function updateSpeakRequest(
   requestId: string,
   data: UpdateSpeakRequestRequest
-): Promise<void> {
-  return request<void>(`/debates/speak-requests/${requestId}`, {
+): Promise<SpeakRequest> {
+  return request<SpeakRequest>(`/debates/speak-requests/${requestId}`, {
     method: 'PATCH',
     body: JSON.stringify(data),
   });
@@ -244,27 +271,57 @@ export async function getDebugParticipants(debateId: string): Promise<{ debateId
 }
 
 /**
- * Award win (host only)
+ * Vote in a debate's pre or post poll (once per poll)
  */
-export async function awardWin(
+export async function vote(
   debateId: string,
-  data: AwardWinRequest
-): Promise<void> {
-  return request<void>(`/debates/${debateId}/award-win`, {
+  data: VoteRequest
+): Promise<DebatePoll> {
+  return request<DebatePoll>(`/debates/${debateId}/votes`, {
     method: 'POST',
     body: JSON.stringify(data),
   });
 }
 
+/**
+ * Get the count of a debate's polls and, once decided, its result
+ */
+export async function getPoll(debateId: string): Promise<DebatePoll> {
+  return request<DebatePoll>(`/debates/${debateId}/poll`);
+}
+
+/**
+ * Get who has the floor and who is up next
+ */
+export async function getSpeakerQueue(debateId: string): Promise<SpeakerQueue> {
+  return request<SpeakerQueue>(`/debates/${debateId}/speaker-queue`);
+}
+
+/**
+ * Reorder the waiting speak requests (host only)
+ */
+export async function reorderSpeakerQueue(
+  debateId: string,
+  requestIds: string[]
+): Promise<SpeakerQueue> {
+  return request<SpeakerQueue>(`/debates/${debateId}/speaker-queue`, {
+    method: 'PUT',
+    body: JSON.stringify({ requestIds }),
+  });
+}
+
 /**
  * Debate API object
  */
 export const debateAPI = {
   list: listDebates,
   get: getDebate,
+  formats: getDebateFormats,
+  round: getDebateRound,
   create: createDebate,
   update: updateDebate,
   delete: deleteDebate,
+  unlock: unlockDebate,
   join: joinDebate,
   leave: leaveDebate,
   getParticipants: getDebateParticipants,
@@ -275,5 +332,8 @@ export const debateAPI = {
   getSpeakRequests: getSpeakRequests,
   updateSpeakRequest: updateSpeakRequest,
   deleteSpeakRequest: deleteSpeakRequest,
-  awardWin: awardWin,
+  getSpeakerQueue: getSpeakerQueue,
+  reorderSpeakerQueue: reorderSpeakerQueue,
+  vote: vote,
+  getPoll: getPoll,
 };
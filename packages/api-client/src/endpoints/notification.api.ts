@@ -48,7 +48,7 @@ export const notificationAPI = {
   },
 
   /**
-   * Create a new notification
+   * Send a notification to a user (moderators and admins; the sender is the caller)
    */
   create: (notification: CreateNotificationRequest): Promise<Notification> => {
     return apiClient.post<Notification>('/notifications', notification);
@@ -51,7 +51,7 @@ export async function getUserByHandle(handle: string): Promise<User> {
 }
 
 /**
- * Create user
+ * Create user (admin only; everyone else signs up)
  */
 export async function createUser(data: CreateUserRequest): Promise<User> {
   return request<User>('/users', {
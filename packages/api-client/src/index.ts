@@ -13,4 +13,4 @@ export * from './client';
 export * from './endpoints';
 
 // Export auth token management functions
-export { setAuthToken, getAuthToken } from './client';
+export { setAuthToken, getAuthToken, setRefreshToken, getRefreshToken } from './client';
@@ -71,7 +71,7 @@ export default function DebateRoomScreen() {
     setOnMessage((message: any) => {
       console.log('[Debate Room] WebSocket message:', message.type);
       
-      if (message.type === 'debate:status_changed') {
+      if (message.type === 'debate:started' || message.type === 'debate:ended') {
         if (message.status === 'ENDED') {
           Alert.alert('Debate Ended', 'This debate has ended', [
             { text: 'OK', onPress: () => router.back() },
@@ -262,7 +262,7 @@ export default function DebateRoomScreen() {
 
     try {
       const wsUrl = getLiveKitWSUrl();
-      const token = await fetchLiveKitToken(debate.id, user.id);
+      const token = await fetchLiveKitToken(debate.id);
       
       const room = new Room();
       await room.connect(wsUrl, token);
@@ -56,6 +56,9 @@ export const useAuthStore = create<AuthState>((set, get) => ({
       set({ isLoading: true, error: null });
       
       const response = await authAPI.login({ email, password });
+      if ('twoFactorRequired' in response) {
+        throw new Error('This account uses two-factor authentication');
+      }
       
       // Store token in AsyncStorage
       await AsyncStorage.setItem(AUTH_TOKEN_KEY, response.token);
@@ -5,25 +5,17 @@
  * See LIVEKIT_SETUP.md for installation instructions.
  */
 
-const API_BASE_URL = process.env.EXPO_PUBLIC_API_URL || 'http://localhost:8080/api';
+import { request } from '@v/api-client';
 
-export async function fetchLiveKitToken(
-  roomName: string,
-  userId: string
-): Promise<string> {
-  // Use the backend API to get LiveKit token
-  // The backend should have a similar endpoint to /api/livekit-token
-  const res = await fetch(
-    `${API_BASE_URL}/livekit-token?roomName=${encodeURIComponent(roomName)}&userId=${encodeURIComponent(userId)}`
+/**
+ * Get a LiveKit token for the signed-in user to join a debate's room, which is
+ * named after the debate
+ */
+export async function fetchLiveKitToken(debateId: string): Promise<string> {
+  const data = await request<{ token: string }>(
+    `/livekit-token?roomName=${encodeURIComponent(debateId)}`
   );
-  
-  if (!res.ok) {
-    const errorText = await res.text();
-    throw new Error(`Failed to fetch LiveKit token: ${res.status} ${errorText}`);
-  }
-  
-  const data = await res.json();
-  return data.token as string;
+  return data.token;
 }
 
 /**
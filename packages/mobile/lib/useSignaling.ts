@@ -1,4 +1,5 @@
 import { useState, useEffect, useRef, useCallback } from 'react';
+import { getAuthToken } from '@v/api-client';
 
 // Types for WebSocket messages
 export interface SignalingMessage {
@@ -37,6 +38,14 @@ export function useSignaling(roomId: string, userId: string): UseSignalingReturn
       return;
     }
 
+    // The server reads the user from the access token, sent as a subprotocol
+    // so it stays out of URLs and logs
+    const token = getAuthToken();
+    if (!token) {
+      console.log('[WebSocket] Not signed in, skipping connection');
+      return;
+    }
+
     // Get WebSocket URL from API base URL
     const apiUrl = process.env.EXPO_PUBLIC_API_URL || 'http://localhost:8080/api';
     const wsProtocol = apiUrl.startsWith('https') ? 'wss' : 'ws';
@@ -48,12 +57,12 @@ export function useSignaling(roomId: string, userId: string): UseSignalingReturn
     }
     const wsHost = baseUrl.replace(/^https?:\/\//, '');
     
-    const wsUrl = `${wsProtocol}://${wsHost}/api/ws?roomId=${roomId}&userId=${userId}`;
+    const wsUrl = `${wsProtocol}://${wsHost}/api/ws?roomId=${encodeURIComponent(roomId)}`;
 
     console.log('[WebSocket] Connecting to:', wsUrl);
 
     try {
-      const ws = new WebSocket(wsUrl);
+      const ws = new WebSocket(wsUrl, ['bearer', token]);
       wsRef.current = ws;
 
       ws.onopen = () => {
@@ -22,10 +22,107 @@ export interface SignupRequest {
 }
 
 export interface AuthResponse {
-  token: string;
+  token: string; // Short-lived access token
+  refreshToken: string;
+  expiresIn: number; // Seconds until token expires
   user: User;
 }
 
+// Returned by login instead of an AuthResponse when the account has 2FA on
+export interface TwoFactorChallenge {
+  twoFactorRequired: true;
+  challengeToken: string;
+  expiresIn: number; // Seconds until challengeToken expires
+}
+
+export interface TwoFactorSetup {
+  secret: string;
+  otpauthUri: string; // Show as a QR code for the authenticator app
+}
+
+export interface TwoFactorStatus {
+  enabled: boolean;
+  recoveryCodesLeft: number;
+}
+
+// A provider account linked for OpenID Connect sign-in
+export interface Identity {
+  provider: string;
+  subject: string;
+  userId: string;
+  email: string;
+  createdAt: string;
+}
+
+export interface OIDCAuthorize {
+  authorizationUrl: string; // Send the user here
+  flowToken: string; // Keep until the provider redirects back
+}
+
+export interface OIDCCallback {
+  code: string;
+  state: string;
+  flowToken: string;
+}
+
+// Returned by the OIDC callback when the identity has no account yet
+export interface OIDCSignupRequired {
+  signupRequired: true;
+  signupToken: string;
+  expiresIn: number; // Seconds until signupToken expires
+  suggestedHandle: string;
+  name: string;
+  email: string;
+}
+
+export type APIScope =
+  | 'read:posts'
+  | 'write:posts'
+  | 'read:debates'
+  | 'write:debates'
+  | 'read:hashtags'
+  | 'write:hashtags'
+  | 'read:users'
+  | 'read:notifications';
+
+export interface APIKey {
+  id: string;
+  userId: string;
+  name: string;
+  scopes: APIScope[];
+  createdAt: string;
+  lastUsedAt?: string;
+  expiresAt?: string;
+}
+
+export interface CreateAPIKeyRequest {
+  name: string;
+  scopes: APIScope[];
+  expiresInDays?: number; // Never expires when omitted
+}
+
+// The key itself is only ever returned when it is created
+export interface CreatedAPIKey {
+  key: string;
+  apiKey: APIKey;
+}
+
+export interface CreateBotRequest {
+  name: string;
+  handle: string;
+  bio?: string;
+}
+
+export interface Session {
+  id: string;
+  userAgent: string;
+  ipAddress: string;
+  createdAt: string;
+  lastUsedAt: string;
+  expiresAt: string;
+  current: boolean;
+}
+
 export interface ChangePasswordRequest {
   currentPassword: string;
   newPassword: string;
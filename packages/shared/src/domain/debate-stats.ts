@@ -12,11 +12,3 @@ export interface DebateTopicStats {
   sessionsCount: number;
   lastUpdated: Date | string;
 }
-
-export interface RecordDebateStatsRequest {
-  topic: string;
-  agreeCount: number;
-  disagreeCount: number;
-  participants: number;
-  debateId?: string; // Optional: track which debate this is for (prevents duplicates)
-}
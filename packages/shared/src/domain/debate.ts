@@ -8,7 +8,34 @@ export type DebateType = 'PUBLIC' | 'PRIVATE';
 export type DebateStatus = 'SCHEDULED' | 'ACTIVE' | 'ENDED';
 export type DebateRole = 'HOST' | 'USER';
 export type DebateSide = 'agree' | 'disagree' | 'neutral' | 'spectator' | '';
-export type SpeakRequestStatus = 'pending' | 'approved' | 'denied';
+export type SpeakRequestStatus = 'pending' | 'approved' | 'denied' | 'done';
+export type QueueOrder = 'fifo' | 'alternating';
+export type DebateFormatId = 'open_floor' | 'oxford' | 'lincoln_douglas';
+// "followers", "platinum" or "community:<id>"
+export type EarlyAccessRole = 'followers' | 'platinum' | `community:${string}`;
+
+export interface DebateRound {
+  name: string;
+  side: 'agree' | 'disagree' | ''; // '' when both sides may speak
+  seconds: number;
+}
+
+export interface DebateFormat {
+  id: DebateFormatId;
+  name: string;
+  rounds: DebateRound[]; // Empty for an open floor
+}
+
+// Sent as debate:round_changed and by GET /debates/{id}/round
+export interface RoundStatus {
+  debateId: string;
+  index: number;
+  totalRounds: number;
+  round: DebateRound;
+  startedAt: Date | string;
+  endsAt: Date | string;
+  remainingSeconds: number;
+}
 
 export interface Debate {
   id: string;
@@ -21,14 +48,22 @@ export interface Debate {
   startTime: Date | string;
   endTime?: Date | string | null;
   durationMinutes: number; // 30, 60, 360, 1440
+  format: DebateFormatId | '';
+  currentRound: number;
+  roundStartedAt?: Date | string | null;
+  queueOrder: QueueOrder | '';
+  maxTurnSeconds: number; // 0 for no limit
+  pollClosesAt?: Date | string | null; // Set once the debate ends
+  result?: DebateResult | null;
   showInPulse: boolean; // Only applies to PUBLIC debates
   agreeCount: number;
   disagreeCount: number;
   createdAt: Date | string;
   updatedAt: Date | string;
-  isLocked: boolean;
-  unlockPhase: number;
-  earlyAccessRoles?: string[];
+  isLocked: boolean; // Only early access may join
+  unlockPhase: number; // 1 early access, 2 public; 0 without early access
+  earlyAccessRoles?: EarlyAccessRole[];
+  unlockAt?: Date | string | null; // When a locked debate opens to everyone
   // Frontend-only fields (populated from backend)
   host?: {
     id: string;
@@ -63,10 +98,23 @@ export interface SpeakRequest {
   id: string;
   debateId: string;
   userId: string;
-  status: SpeakRequestStatus;
+  side: DebateSide; // The requester's side when they asked
+  status: SpeakRequestStatus; // 'approved' while speaking, 'done' once the turn ended
+  position: number; // Set when the host reorders the queue
+  turnStartedAt?: Date | string | null;
+  turnEndsAt?: Date | string | null;
   createdAt: Date | string;
 }
 
+// Sent as debate:queue_updated and by GET /debates/{id}/speaker-queue
+export interface SpeakerQueue {
+  debateId: string;
+  order: QueueOrder;
+  maxTurnSeconds: number;
+  speaking: SpeakRequest[];
+  waiting: SpeakRequest[]; // Up next first
+}
+
 export interface CreateDebateRequest {
   title: string;
   description?: string;
@@ -74,8 +122,13 @@ export interface CreateDebateRequest {
   hostId: string;
   type: DebateType;
   startTime: string; // RFC3339 format
-  durationMinutes: number; // 30, 60, 360, 1440
+  durationMinutes: number; // 30, 60, 360, 1440; set from the rounds for a structured format
+  format?: DebateFormatId;
+  queueOrder?: QueueOrder;
+  maxTurnSeconds?: number;
   showInPulse?: boolean;
+  earlyAccessRoles?: EarlyAccessRole[]; // Starts locked to these when set
+  unlockAt?: string; // RFC3339; the start time by default
 }
 
 export interface UpdateDebateRequest {
@@ -83,6 +136,8 @@ export interface UpdateDebateRequest {
   description?: string;
   category?: string;
   status?: DebateStatus;
+  queueOrder?: QueueOrder;
+  maxTurnSeconds?: number; // Applies from the next turn
 }
 
 export interface JoinDebateRequest {
@@ -100,8 +155,40 @@ export interface UpdateSelfMuteRequest {
   isSelfMuted: boolean;
 }
 
-export interface AwardWinRequest {
-  winnerId: string;
+export type PollPhase = 'pre' | 'post';
+export type VoteSide = 'agree' | 'disagree' | 'undecided';
+
+export interface VoteRequest {
+  phase: PollPhase;
+  side: VoteSide;
+}
+
+export interface VoteTally {
+  agree: number;
+  disagree: number;
+  undecided: number;
+}
+
+// Decided server-side when the post poll closes, from how each side's share
+// of the vote moved (in percentage points) among users who voted in both polls
+export interface DebateResult {
+  winningSide: 'agree' | 'disagree' | ''; // '' for a draw
+  pre: VoteTally;
+  post: VoteTally;
+  agreeSwing: number;
+  disagreeSwing: number;
+  winners: string[]; // Speakers on the winning side, awarded points
+  decidedAt: Date | string;
+}
+
+export interface DebatePoll {
+  debateId: string;
+  pre: VoteTally;
+  post: VoteTally;
+  preOpen: boolean;
+  postOpen: boolean;
+  voted?: PollPhase[]; // Phases the signed-in user voted in
+  result?: DebateResult;
 }
 
 export interface UpdateSpeakRequestRequest {
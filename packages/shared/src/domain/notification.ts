@@ -37,7 +37,6 @@ export interface CreateNotificationRequest {
   debateId?: string;
   debateTitle?: string;
   postId?: string;
-  actorId?: string;
 }
 
 export interface NotificationListParams {
@@ -11,6 +11,7 @@ export type MediaType = 'image' | 'video';
 export interface Post {
   id: string;
   authorId: string;
+  authorIsBot?: boolean;
   content: string;
   mediaType?: MediaType;
   mediaUrl?: string;
@@ -6,12 +6,15 @@
 
 export type UserTier = 'SILVER' | 'PLATINUM';
 
+export type UserRole = 'user' | 'moderator' | 'admin' | 'support';
+
 export interface User {
   id: string;
   name: string;
   displayName?: string;
   handle: string;
   email: string;
+  emailVerified?: boolean;
   phoneNumber?: string;
   languages?: string[];
   bio?: string;
@@ -24,6 +27,13 @@ export interface User {
   followingCount?: number;
   postsCount?: number;
 
+  // Platform role
+  role?: UserRole;
+
+  // Bots act through API keys on behalf of their owner
+  isBot?: boolean;
+  botOwnerId?: string;
+
   // Tier and Points System
   tier?: UserTier;
   points?: number;